@@ -2,6 +2,7 @@ package app
 
 import (
 	"census_mcp/census"
+	"census_mcp/i18n"
 	"census_mcp/mcp"
 	"context"
 	"fmt"
@@ -26,6 +27,8 @@ const (
 	key_variables   = "variables"
 	key_geo_level   = "geo_level"
 	key_uptime      = "uptime"
+	key_format      = "format"
+	key_locale      = "locale"
 )
 
 // ServerConfig содержит конфигурацию сервера
@@ -33,6 +36,23 @@ type ServerConfig struct {
 	Transport string
 	TestMode  bool
 	APIKey    string
+	Format    string      // Формат результатов по умолчанию из реестра census.FormatterRegistry (по умолчанию "markdown")
+	Locale    i18n.Locale // Язык описаний инструментов, результатов и сообщений по умолчанию
+	// MaxOutputTokens - примерный бюджет токенов результата табличных инструментов;
+	// более длинные результаты выдаются по страницам (0 - без ограничения)
+	MaxOutputTokens int
+	// Resource - формат встроенного ресурса с полными данными в результатах инструментов
+	// (json, csv, tsv, geojson или none - без ресурса)
+	Resource string
+	// Boundaries - каталог файлов границ TIGER/Line или картографических границ (shapefile
+	// или GeoJSON) для формата geojson; пусто - объекты GeoJSON выводятся без геометрии
+	Boundaries string
+	// CBSADelineation - файл делимитации CBSA OMB (list1, сохраненный в CSV); пусто - встроенная
+	// выдержка для крупнейших ареалов
+	CBSADelineation string
+	// NAICS - файл кодов NAICS 2017 Census Bureau (2-6 знаков, сохраненный в CSV); пусто -
+	// встроенная таблица секторов, подсекторов и часто используемых отраслей
+	NAICS string
 }
 
 // Server инкапсулирует логику запуска и настройки MCP сервера
@@ -47,11 +67,58 @@ type Server struct {
 func NewServer(config ServerConfig) (*Server, error) {
 	slog.Debug("Создание нового сервера",
 		key_test_mode, config.TestMode,
-		key_transport, config.Transport)
+		key_transport, config.Transport,
+		key_format, config.Format)
 
-	// Создаем форматтер
-	formatter := census.NewTextFormatter()
-	slog.Debug("Создан текстовый форматтер для данных Census API")
+	// Границы географий для формата geojson загружаются из локального каталога
+	options := []mcp.HandlerOption{
+		mcp.WithMaxOutputTokens(config.MaxOutputTokens),
+		mcp.WithEmbeddedResource(config.Resource),
+	}
+	registry := census.NewFormatterRegistry(nil)
+	if config.Boundaries != "" {
+		boundaries, err := census.NewBoundaryStore(config.Boundaries)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка в параметре каталога границ: %w", err)
+		}
+		registry.Register(census.FormatGeoJSON, census.NewGeoJSONFormatter(boundaries))
+		options = append(options, mcp.WithBoundaries(boundaries))
+	}
+
+	// Полная делимитация CBSA заменяет встроенную выдержку для поиска и состава ареалов
+	if config.CBSADelineation != "" {
+		if _, err := census.LoadCBSADelineationFile(config.CBSADelineation); err != nil {
+			return nil, fmt.Errorf("ошибка в параметре файла делимитации CBSA: %w", err)
+		}
+	}
+
+	// Полный классификатор NAICS заменяет встроенную таблицу для поиска и проверки кодов отраслей
+	if config.NAICS != "" {
+		if _, err := census.LoadNAICSFile(config.NAICS); err != nil {
+			return nil, fmt.Errorf("ошибка в параметре файла кодов NAICS: %w", err)
+		}
+	}
+
+	// Создаем форматтер по умолчанию; инструменты могут выбрать другой формат аргументом "format"
+	formatter, err := registry.Lookup(config.Format)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка в параметре формата результатов: %w", err)
+	}
+	slog.Debug("Выбран формат результатов по умолчанию",
+		key_format, config.Format)
+
+	// Язык описаний инструментов и сообщений; отдельный запрос может выбрать язык аргументом "language"
+	locale, err := i18n.ParseLocale(string(config.Locale))
+	if err != nil {
+		return nil, fmt.Errorf("ошибка в параметре языка: %w", err)
+	}
+	slog.Debug("Выбран язык по умолчанию",
+		key_locale, locale)
+
+	// Встроенный ресурс с данными; отдельный запрос может выбрать его аргументом "resource"
+	if err := mcp.ValidateResourceFormat(config.Resource); err != nil {
+		return nil, fmt.Errorf("ошибка в параметре встроенного ресурса: %w", err)
+	}
 
 	var api census.CensusAPIClient
 	var tools mcp.CensusToolHandler
@@ -63,13 +130,12 @@ func NewServer(config ServerConfig) (*Server, error) {
 		slog.Info("Инициализация тестового режима с мок-данными")
 		mockAPI := census.NewMockCensusAPI()
 		api = mockAPI
-		tools = mcp.NewCensusToolHandler(mockAPI, formatter)
+		tools = mcp.NewCensusToolHandler(mockAPI, formatter, options...)
 		slog.Info("Используется тестовый клиент Census API (мок-данные)")
 	} else {
 		// Создаем реальный клиент Census API
 		slog.Info("Инициализация режима работы с реальным Census API")
 		var censusAPI *census.CensusAPI
-		var err error
 
 		if config.APIKey != "" {
 			slog.Debug("Использование ключа API из конфигурации")
@@ -85,7 +151,7 @@ func NewServer(config ServerConfig) (*Server, error) {
 		}
 
 		api = censusAPI
-		tools = mcp.NewCensusToolHandler(censusAPI, formatter)
+		tools = mcp.NewCensusToolHandler(censusAPI, formatter, options...)
 		slog.Info("Клиент Census API успешно инициализирован")
 	}
 
@@ -103,7 +169,7 @@ func NewServer(config ServerConfig) (*Server, error) {
 
 	// Регистрируем инструменты
 	slog.Debug("Регистрация инструментов Census API")
-	mcp.RegisterCensusTools(mcpServer, tools)
+	mcp.RegisterCensusTools(mcpServer, tools, locale)
 
 	slog.Info("Инструменты Census API добавлены")
 
@@ -0,0 +1,363 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_geoids    = "geoids"
+	key_geo_level = "geo_level"
+)
+
+// geoIDLayout описывает разбиение GEOID географического уровня на коды вложенных уровней
+type geoIDLayout struct {
+	levels  []string
+	lengths []int
+}
+
+// geoIDLayouts - поддерживаемые для агрегации географические уровни и структура их GEOID
+var geoIDLayouts = map[string]geoIDLayout{
+	"state":       {levels: []string{"state"}, lengths: []int{2}},
+	"county":      {levels: []string{"state", "county"}, lengths: []int{2, 3}},
+	"tract":       {levels: []string{"state", "county", "tract"}, lengths: []int{2, 3, 6}},
+	"block group": {levels: []string{"state", "county", "tract", "block group"}, lengths: []int{2, 3, 6, 1}},
+	"block":       {levels: []string{"state", "county", "tract", "block"}, lengths: []int{2, 3, 6, 4}},
+	"place":       {levels: []string{"state", "place"}, lengths: []int{2, 5}},
+	GeoLevelZCTA:  {levels: []string{GeoLevelZCTA}, lengths: []int{5}},
+	GeoLevelCBSA:  {levels: []string{GeoLevelCBSA}, lengths: []int{5}},
+}
+
+// AggregationRequest представляет запрос на объединение географий в пользовательский регион
+type AggregationRequest struct {
+	GeoIDs    []string // Список GEOID объединяемых географий
+	GeoLevel  string   // Географический уровень GEOID (county, tract и т.д.)
+	Variables []string // Список суммируемых переменных
+	Dataset   string   // Набор данных (например, "acs/acs5")
+	Year      string   // Год данных
+	// SkipNonAdditive разрешает исключить медианы и коэффициенты вместо отказа в агрегации
+	SkipNonAdditive bool
+	// DesignFactor - коэффициент дизайна выборки ACS для MOE переоцененных медиан;
+	// 0 - MOE медиан не вычисляется
+	DesignFactor float64
+}
+
+// AggregatedVariable содержит сумму переменной по региону и ее MOE
+type AggregatedVariable struct {
+	Variable string  `json:"variable"`
+	Label    string  `json:"label,omitempty"`
+	Value    float64 `json:"value"`
+	MOE      float64 `json:"moe"`
+	HasMOE   bool    `json:"has_moe"`
+	// Components - количество географий с числовым значением переменной
+	Components int `json:"components"`
+}
+
+// AggregatedMedian содержит медиану, переоцененную по суммированной интервальной таблице
+type AggregatedMedian struct {
+	Variable string           `json:"variable"`
+	Label    string           `json:"label,omitempty"`
+	Table    string           `json:"table"`
+	Estimate QuantileEstimate `json:"estimate"`
+}
+
+// NonAdditiveVariable описывает переменную, которую нельзя суммировать
+type NonAdditiveVariable struct {
+	Variable string `json:"variable"`
+	Label    string `json:"label,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// AggregationResult содержит итоги по пользовательскому региону
+type AggregationResult struct {
+	Dataset       string                `json:"dataset"`
+	Year          string                `json:"year"`
+	GeoLevel      string                `json:"geo_level"`
+	GeoIDs        []string              `json:"geoids"`
+	Totals        []AggregatedVariable  `json:"totals"`
+	Medians       []AggregatedMedian    `json:"medians,omitempty"`
+	Components    []map[string]string   `json:"components"`
+	Skipped       []NonAdditiveVariable `json:"skipped,omitempty"`
+	MissingGeoIDs []string              `json:"missing_geoids,omitempty"`
+	Warnings      []error               `json:"warnings,omitempty"`
+}
+
+// GeoIDFilters разбивает список GEOID на фильтры Census API, группируя географии
+// с общим родительским уровнем в один запрос
+func GeoIDFilters(geoLevel string, geoIDs []string) ([]map[string]string, error) {
+	layout, ok := geoIDLayouts[geoLevel]
+	if !ok {
+		return nil, i18n.Errorf("агрегация не поддерживается для географического уровня %q", geoLevel)
+	}
+
+	expected := 0
+	for _, length := range layout.lengths {
+		expected += length
+	}
+
+	// parent -> коды географий уровня geoLevel
+	groups := make(map[string][]string)
+	parents := make(map[string]map[string]string)
+
+	for _, geoID := range geoIDs {
+		geoID = strings.TrimSpace(geoID)
+		if len(geoID) != expected {
+			return nil, i18n.Errorf("GEOID %q не соответствует уровню %s (ожидается %d символов)", geoID, geoLevel, expected)
+		}
+
+		filter := make(map[string]string, len(layout.levels))
+		pos := 0
+		for i, level := range layout.levels {
+			filter[level] = geoID[pos : pos+layout.lengths[i]]
+			pos += layout.lengths[i]
+		}
+
+		parentKey := geoID[:expected-layout.lengths[len(layout.lengths)-1]]
+		code := filter[geoLevel]
+		delete(filter, geoLevel)
+
+		if !containsString(groups[parentKey], code) {
+			groups[parentKey] = append(groups[parentKey], code)
+		}
+		parents[parentKey] = filter
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	filters := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		filter := make(map[string]string, len(parents[key])+1)
+		for level, code := range parents[key] {
+			filter[level] = code
+		}
+		filter[geoLevel] = strings.Join(groups[key], ",")
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// NonAdditiveReason определяет, является ли переменная неаддитивной (медианой, процентом,
+// коэффициентом), по ее имени и подписи. Возвращает причину и true для неаддитивных переменных.
+func NonAdditiveReason(variable string, info VariableInfo) (string, bool) {
+	// Процентные столбцы профилей (DP03_0009PE). Процентные столбцы тематических таблиц
+	// (S1701_C03_001E) оканчиваются на E, как и оценки, и распознаются по подписи.
+	if strings.HasSuffix(variable, "PE") {
+		return "процент", true
+	}
+
+	if kind, reason := labelMeasure(info.Label); kind != ValueKindCount {
+		return reason, true
+	}
+
+	return "", false
+}
+
+// AggregateGeographies запрашивает переменные для списка географий и суммирует их в один регион.
+// MOE суммы вычисляется как корень из суммы квадратов MOE слагаемых. Медианы из MedianSources
+// переоцениваются по суммированной интервальной таблице. Остальные медианы, проценты и
+// коэффициенты суммировать нельзя: в зависимости от SkipNonAdditive они вызывают ошибку
+// или исключаются с предупреждением.
+func AggregateGeographies(api CensusAPIClient, request AggregationRequest) (*AggregationResult, error) {
+	slog.Info("Агрегация географий",
+		key_geo_level, request.GeoLevel,
+		key_geoids, request.GeoIDs)
+
+	if len(request.GeoIDs) == 0 {
+		return nil, i18n.Errorf("необходимо указать хотя бы один GEOID")
+	}
+
+	variables := withoutName(request.Variables)
+	if len(variables) == 0 {
+		return nil, i18n.Errorf("необходимо указать хотя бы одну переменную помимо NAME")
+	}
+
+	filters, err := GeoIDFilters(request.GeoLevel, request.GeoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AggregationResult{
+		Dataset:  request.Dataset,
+		Year:     request.Year,
+		GeoLevel: request.GeoLevel,
+		GeoIDs:   request.GeoIDs,
+	}
+
+	// Проверяем аддитивность переменных по их описаниям
+	definitions, err := api.GetVariables(request.Dataset, request.Year)
+	if err != nil {
+		slog.Warn("Не удалось получить описания переменных для проверки аддитивности",
+			key_err, err)
+		result.Warnings = append(result.Warnings, i18n.Errorf(
+			"Не удалось получить описания переменных: проверка на медианы и коэффициенты выполнена только по именам переменных"))
+	}
+
+	var additive, medians []string
+	labels := make(map[string]string)
+	for _, variable := range variables {
+		info := definitions[variable]
+		labels[variable] = info.Label
+		if _, ok := MedianSources[variable]; ok {
+			medians = append(medians, variable)
+			continue
+		}
+		if reason, ok := NonAdditiveReason(variable, info); ok {
+			result.Skipped = append(result.Skipped, NonAdditiveVariable{
+				Variable: variable,
+				Label:    info.Label,
+				Reason:   reason,
+			})
+			continue
+		}
+		additive = append(additive, variable)
+	}
+
+	if len(result.Skipped) > 0 && !request.SkipNonAdditive {
+		names := make([]string, 0, len(result.Skipped))
+		for _, skipped := range result.Skipped {
+			names = append(names, fmt.Sprintf("%s (%s)", skipped.Variable, skipped.Reason))
+		}
+		return nil, i18n.Errorf("нельзя суммировать неаддитивные переменные: %s", strings.Join(names, ", "))
+	}
+
+	if len(additive) == 0 && len(medians) == 0 {
+		return nil, i18n.Errorf("среди запрошенных переменных нет суммируемых")
+	}
+
+	// Для наборов ACS запрашиваем MOE суммируемых переменных
+	requested := append([]string{"NAME"}, additive...)
+	if strings.HasPrefix(request.Dataset, "acs/") {
+		for _, variable := range additive {
+			if moe, ok := MOEVariable(variable); ok {
+				requested = append(requested, moe)
+			}
+		}
+	}
+
+	// Для переоценки медиан запрашиваем интервалы соответствующих таблиц
+	for _, variable := range medians {
+		for _, binVariable := range BinnedTables[MedianSources[variable]].Variables() {
+			if !containsString(requested, binVariable) {
+				requested = append(requested, binVariable)
+			}
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, filter := range filters {
+		data, err := api.GetCustomData(CustomDataRequest{
+			Variables: requested,
+			Dataset:   request.Dataset,
+			Year:      request.Year,
+			GeoLevel:  request.GeoLevel,
+			GeoFilter: filter,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range data {
+			geoID := GeoID(row)
+			if !containsString(request.GeoIDs, geoID) || found[geoID] {
+				continue
+			}
+			found[geoID] = true
+			result.Components = append(result.Components, row)
+		}
+	}
+
+	for _, geoID := range request.GeoIDs {
+		if !found[geoID] {
+			result.MissingGeoIDs = append(result.MissingGeoIDs, geoID)
+		}
+	}
+
+	if len(result.Components) == 0 {
+		return nil, i18n.Errorf("не удалось получить данные ни для одной из указанных географий")
+	}
+
+	for _, variable := range additive {
+		var estimates []Estimate
+		for _, row := range result.Components {
+			if estimate, ok := EstimateFromRow(row, variable); ok {
+				estimates = append(estimates, estimate)
+			}
+		}
+
+		if len(estimates) < len(result.Components) {
+			result.Warnings = append(result.Warnings, i18n.Errorf(
+				"Переменная %s подавлена или отсутствует для %d из %d географий: сумма неполная",
+				variable, len(result.Components)-len(estimates), len(result.Components)))
+		}
+
+		total := SumEstimates(estimates...)
+		result.Totals = append(result.Totals, AggregatedVariable{
+			Variable:   variable,
+			Label:      labels[variable],
+			Value:      total.Value,
+			MOE:        total.MOE,
+			HasMOE:     total.HasMOE && len(estimates) > 0,
+			Components: len(estimates),
+		})
+	}
+
+	for _, variable := range medians {
+		table := BinnedTables[MedianSources[variable]]
+
+		bins, ok := BinsFromRows(table, result.Components...)
+		if !ok {
+			result.Warnings = append(result.Warnings, i18n.Errorf(
+				"Медиану %s не удалось переоценить: интервалы таблицы %s подавлены или отсутствуют",
+				variable, table.Table))
+			continue
+		}
+
+		// Как и Census Bureau, для широких интервалов используем интерполяцию Парето
+		estimate, err := EstimateQuantile(bins, 0.5, MethodAuto)
+		if err != nil {
+			result.Warnings = append(result.Warnings, i18n.Errorf(
+				"Медиану %s не удалось переоценить: %w", variable, err))
+			continue
+		}
+
+		if request.DesignFactor > 0 && !estimate.TopCoded {
+			if moe, err := QuantileMOE(bins, 0.5, MethodAuto, request.DesignFactor); err == nil {
+				estimate.MOE = moe
+				estimate.HasMOE = true
+			}
+		}
+		if !estimate.HasMOE {
+			result.Warnings = append(result.Warnings, i18n.Errorf(
+				"Медиана %s переоценена по интервалам таблицы %s без MOE: для ее расчета укажите коэффициент дизайна ACS",
+				variable, table.Table))
+		}
+
+		result.Medians = append(result.Medians, AggregatedMedian{
+			Variable: variable,
+			Label:    labels[variable],
+			Table:    table.Table,
+			Estimate: estimate,
+		})
+	}
+
+	return result, nil
+}
+
+// containsString проверяет наличие строки в списке
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// aggregateStubAPI - тестовый клиент, возвращающий фиксированные строки и определения переменных
+type aggregateStubAPI struct {
+	*MockCensusAPI
+	rows      []map[string]string
+	variables map[string]VariableInfo
+	requests  []CustomDataRequest
+}
+
+func (s *aggregateStubAPI) GetCustomData(request CustomDataRequest) ([]map[string]string, error) {
+	s.requests = append(s.requests, request)
+	return s.rows, nil
+}
+
+func (s *aggregateStubAPI) GetVariables(dataset, year string) (map[string]VariableInfo, error) {
+	return s.variables, nil
+}
+
+func TestGeoIDFilters(t *testing.T) {
+	filters, err := GeoIDFilters("county", []string{"06059", "06037", "04013", "06037"})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"state": "04", "county": "013"},
+		{"state": "06", "county": "059,037"},
+	}, filters)
+
+	filters, err = GeoIDFilters("tract", []string{"06037101110", "06037101122"})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]string{
+		{"state": "06", "county": "037", "tract": "101110,101122"},
+	}, filters)
+
+	_, err = GeoIDFilters("county", []string{"0603"})
+	assert.Error(t, err)
+
+	_, err = GeoIDFilters("region", []string{"1"})
+	assert.Error(t, err)
+}
+
+func TestNonAdditiveReason(t *testing.T) {
+	reason, ok := NonAdditiveReason("B19013_001E", VariableInfo{Label: "Estimate!!Median household income in the past 12 months"})
+	assert.True(t, ok)
+	assert.Equal(t, "медиана", reason)
+
+	_, ok = NonAdditiveReason("DP03_0009PE", VariableInfo{})
+	assert.True(t, ok)
+
+	_, ok = NonAdditiveReason("B23025_005E", VariableInfo{Label: "Estimate!!Total:!!In labor force:!!Civilian labor force:!!Unemployed"})
+	assert.False(t, ok)
+
+	// "Separated" не должно распознаваться как коэффициент ("rate")
+	_, ok = NonAdditiveReason("B12001_006E", VariableInfo{Label: "Estimate!!Total:!!Male:!!Now married:!!Separated"})
+	assert.False(t, ok)
+
+	// Процентный столбец тематической таблицы распознается по подписи
+	reason, ok = NonAdditiveReason("S1701_C03_001E", VariableInfo{Label: "Estimate!!Percent below poverty level!!Population for whom poverty status is determined"})
+	assert.True(t, ok)
+	assert.Equal(t, "процент", reason)
+
+	// Интервальные таблицы с отношением или процентом в концепции аддитивны
+	_, ok = NonAdditiveReason("C17002_002E", VariableInfo{Label: "Estimate!!Total:!!Under .50", Concept: "RATIO OF INCOME TO POVERTY LEVEL IN THE PAST 12 MONTHS"})
+	assert.False(t, ok)
+	_, ok = NonAdditiveReason("B25070_002E", VariableInfo{Label: "Estimate!!Total:!!Less than 10.0 percent", Concept: "GROSS RENT AS A PERCENTAGE OF HOUSEHOLD INCOME IN THE PAST 12 MONTHS"})
+	assert.False(t, ok)
+
+	reason, ok = NonAdditiveReason("B25071_001E", VariableInfo{Label: "Estimate!!Median gross rent as a percentage of household income", Concept: "MEDIAN GROSS RENT AS A PERCENTAGE OF HOUSEHOLD INCOME IN THE PAST 12 MONTHS (DOLLARS)"})
+	assert.True(t, ok)
+	assert.Equal(t, "процент", reason)
+}
+
+func TestAggregateGeographies(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{"NAME": "Los Angeles County", "B01001_001E": "1000", "B01001_001M": "30", "state": "06", "county": "037"},
+			{"NAME": "Orange County", "B01001_001E": "500", "B01001_001M": "40", "state": "06", "county": "059"},
+			{"NAME": "Kern County", "B01001_001E": "300", "B01001_001M": "10", "state": "06", "county": "029"},
+		},
+		variables: map[string]VariableInfo{
+			"B01001_001E": {Label: "Estimate!!Total:", Concept: "SEX BY AGE"},
+			"B01002_001E": {Label: "Estimate!!Median age --!!Total:", Concept: "MEDIAN AGE BY SEX"},
+		},
+	}
+
+	result, err := AggregateGeographies(api, AggregationRequest{
+		GeoIDs:    []string{"06037", "06059", "06111"},
+		GeoLevel:  "county",
+		Variables: []string{"B01001_001E"},
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NAME", "B01001_001E", "B01001_001M"}, api.requests[0].Variables)
+	assert.Len(t, result.Components, 2)
+	assert.Equal(t, []string{"06111"}, result.MissingGeoIDs)
+	assert.Equal(t, float64(1500), result.Totals[0].Value)
+	assert.True(t, result.Totals[0].HasMOE)
+	assert.InDelta(t, 50, result.Totals[0].MOE, 1e-9)
+
+	// Медиану суммировать нельзя
+	_, err = AggregateGeographies(api, AggregationRequest{
+		GeoIDs:    []string{"06037", "06059"},
+		GeoLevel:  "county",
+		Variables: []string{"B01001_001E", "B01002_001E"},
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+	})
+	assert.ErrorContains(t, err, "B01002_001E")
+
+	// С SkipNonAdditive медиана исключается из итогов
+	result, err = AggregateGeographies(api, AggregationRequest{
+		GeoIDs:          []string{"06037", "06059"},
+		GeoLevel:        "county",
+		Variables:       []string{"B01001_001E", "B01002_001E"},
+		Dataset:         "acs/acs5",
+		Year:            "2021",
+		SkipNonAdditive: true,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.Totals, 1)
+	assert.Equal(t, "B01002_001E", result.Skipped[0].Variable)
+	assert.Equal(t, "медиана", result.Skipped[0].Reason)
+}
+
+func TestAggregateGeographies_Medians(t *testing.T) {
+	table := BinnedTables["B19001"]
+	first := map[string]string{"NAME": "A", "state": "06", "county": "001"}
+	second := map[string]string{"NAME": "B", "state": "06", "county": "003"}
+	for _, bin := range table.Bins {
+		first[bin.Variable] = "0"
+		second[bin.Variable] = "0"
+	}
+	first["B19001_002E"] = "50"  // до 10 000
+	second["B19001_003E"] = "50" // 10 000 - 15 000
+
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows:          []map[string]string{first, second},
+		variables: map[string]VariableInfo{
+			"B19013_001E": {Label: "Estimate!!Median household income in the past 12 months"},
+		},
+	}
+
+	// Медиана дохода переоценивается по суммированной таблице B19001, а не отклоняется
+	result, err := AggregateGeographies(api, AggregationRequest{
+		GeoIDs:    []string{"06001", "06003"},
+		GeoLevel:  "county",
+		Variables: []string{"B19013_001E"},
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Skipped)
+	assert.Equal(t, "B19001", result.Medians[0].Table)
+	assert.Equal(t, float64(10000), result.Medians[0].Estimate.Value)
+	assert.Contains(t, api.requests[0].Variables, "B19001_017E")
+
+	// Без коэффициента дизайна MOE медианы не вычисляется, о чем сообщает предупреждение
+	assert.False(t, result.Medians[0].Estimate.HasMOE)
+	if assert.Len(t, result.Warnings, 1) {
+		assert.Contains(t, result.Warnings[0].Error(), "без MOE")
+	}
+
+	// С коэффициентом дизайна медиана получает MOE, как в estimate_quantiles
+	result, err = AggregateGeographies(api, AggregationRequest{
+		GeoIDs:       []string{"06001", "06003"},
+		GeoLevel:     "county",
+		Variables:    []string{"B19013_001E"},
+		Dataset:      "acs/acs5",
+		Year:         "2021",
+		DesignFactor: 1.5,
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Warnings)
+	assert.True(t, result.Medians[0].Estimate.HasMOE)
+	assert.Greater(t, result.Medians[0].Estimate.MOE, float64(0))
+}
+
+func TestAggregateGeographies_MedianUsesPareto(t *testing.T) {
+	table := BinnedTables["B19001"]
+	row := map[string]string{"NAME": "A", "state": "06", "county": "001"}
+	for _, bin := range table.Bins {
+		row[bin.Variable] = "0"
+	}
+	row["B19001_002E"] = "40" // до 10 000
+	row["B19001_003E"] = "30" // 10 000 - 15 000, шире $2,500
+	row["B19001_004E"] = "30" // 15 000 - 20 000
+
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows:          []map[string]string{row},
+		variables: map[string]VariableInfo{
+			"B19013_001E": {Label: "Estimate!!Median household income in the past 12 months"},
+		},
+	}
+
+	result, err := AggregateGeographies(api, AggregationRequest{
+		GeoIDs:    []string{"06001"},
+		GeoLevel:  "county",
+		Variables: []string{"B19013_001E"},
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, MethodPareto, result.Medians[0].Estimate.Method)
+}
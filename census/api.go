@@ -1,6 +1,7 @@
 package census
 
 import (
+	"census_mcp/i18n"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -45,7 +46,7 @@ func NewCensusAPIFromEnv() (*CensusAPI, error) {
 	apiKey := os.Getenv("CENSUS_API_KEY")
 	if apiKey == "" {
 		slog.Error("Переменная окружения CENSUS_API_KEY не установлена")
-		return nil, fmt.Errorf("переменная окружения CENSUS_API_KEY не установлена")
+		return nil, i18n.Errorf("переменная окружения CENSUS_API_KEY не установлена")
 	}
 	slog.Debug("Ключ API получен из переменной окружения")
 	return NewCensusAPI(apiKey), nil
@@ -66,6 +67,9 @@ type CustomDataRequest struct {
 	Year      string            // Год данных
 	GeoLevel  string            // Географический уровень (state, county, tract и т.д.)
 	GeoFilter map[string]string // Фильтр географии (например, {"state": "06", "county": "*"})
+	// Predicates - дополнительные параметры запроса (например, {"time": "2005"} для наборов timeseries
+	// или {"SEX": "1"} для фильтрации по значению переменной)
+	Predicates map[string]string
 }
 
 // DatasetInfo содержит информацию о доступном наборе данных
@@ -83,6 +87,8 @@ type VariableInfo struct {
 	Description string `json:"description,omitempty"`
 	Concept     string `json:"concept,omitempty"`
 	Group       string `json:"group,omitempty"`
+	// PredicateType - тип значения в Census API: "int", "float" или "string" (например, NAME)
+	PredicateType string `json:"predicate_type,omitempty"`
 }
 
 // GeographyLevel содержит информацию о доступном географическом уровне
@@ -109,6 +115,12 @@ type CensusAPIClient interface {
 	GetGeographyLevels(dataset, year string) ([]GeographyLevel, error)
 	// GetCustomData позволяет запросить пользовательские данные
 	GetCustomData(request CustomDataRequest) ([]map[string]string, error)
+	// GetZCTAData возвращает данные по ZCTA (приближению почтовых индексов)
+	GetZCTAData(request ZCTARequest) ([]map[string]string, error)
+	// GetDistrictData возвращает данные по округам Конгресса или легислатур штатов
+	GetDistrictData(request DistrictRequest) ([]map[string]string, error)
+	// GetCBSAData возвращает данные по статистическим ареалам (CBSA)
+	GetCBSAData(request CBSARequest) ([]map[string]string, error)
 }
 
 // GetStatePopulation возвращает данные о населении для указанного штата
@@ -136,7 +148,7 @@ func (c *CensusAPI) GetStatePopulation(stateID string) ([]PopulationData, error)
 		slog.Error("Ошибка при отправке запроса",
 			key_err, err,
 			key_endpoint, endpoint)
-		return nil, fmt.Errorf("ошибка при отправке запроса: %w", err)
+		return nil, i18n.Errorf("ошибка при отправке запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -144,7 +156,7 @@ func (c *CensusAPI) GetStatePopulation(stateID string) ([]PopulationData, error)
 		slog.Error("API вернул неуспешный статус",
 			key_status_code, resp.StatusCode,
 			key_endpoint, endpoint)
-		return nil, fmt.Errorf("API вернул статус %d", resp.StatusCode)
+		return nil, i18n.Errorf("API вернул статус %d", resp.StatusCode)
 	}
 
 	// Census API возвращает массив массивов, первый массив содержит заголовки
@@ -153,13 +165,13 @@ func (c *CensusAPI) GetStatePopulation(stateID string) ([]PopulationData, error)
 		slog.Error("Ошибка при декодировании ответа",
 			key_err, err,
 			key_endpoint, endpoint)
-		return nil, fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		return nil, i18n.Errorf("ошибка при декодировании ответа: %w", err)
 	}
 
 	if len(rawData) < 2 {
 		slog.Error("API вернул пустой результат",
 			key_endpoint, endpoint)
-		return nil, fmt.Errorf("API вернул пустой результат")
+		return nil, i18n.Errorf("API вернул пустой результат")
 	}
 
 	slog.Debug("Получены данные из Census API",
@@ -221,7 +233,7 @@ func (c *CensusAPI) GetCountyPopulation(stateID string) ([]PopulationData, error
 		slog.Error("Ошибка при отправке запроса",
 			key_err, err,
 			key_endpoint, endpoint)
-		return nil, fmt.Errorf("ошибка при отправке запроса: %w", err)
+		return nil, i18n.Errorf("ошибка при отправке запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -229,16 +241,16 @@ func (c *CensusAPI) GetCountyPopulation(stateID string) ([]PopulationData, error
 		slog.Error("API вернул неуспешный статус",
 			key_status_code, resp.StatusCode,
 			key_endpoint, endpoint)
-		return nil, fmt.Errorf("API вернул статус %d", resp.StatusCode)
+		return nil, i18n.Errorf("API вернул статус %d", resp.StatusCode)
 	}
 
 	var rawData [][]string
 	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
-		return nil, fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		return nil, i18n.Errorf("ошибка при декодировании ответа: %w", err)
 	}
 
 	if len(rawData) < 2 {
-		return nil, fmt.Errorf("API вернул пустой результат")
+		return nil, i18n.Errorf("API вернул пустой результат")
 	}
 
 	headers := rawData[0]
@@ -299,12 +311,12 @@ func (c *CensusAPI) GetAvailableDatasets() ([]DatasetInfo, error) {
 
 	resp, err := c.client.Get(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при отправке запроса: %w", err)
+		return nil, i18n.Errorf("ошибка при отправке запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API вернул статус %d", resp.StatusCode)
+		return nil, i18n.Errorf("API вернул статус %d", resp.StatusCode)
 	}
 
 	type apiResponse struct {
@@ -322,7 +334,7 @@ func (c *CensusAPI) GetAvailableDatasets() ([]DatasetInfo, error) {
 
 	var response apiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		return nil, i18n.Errorf("ошибка при декодировании ответа: %w", err)
 	}
 
 	var result []DatasetInfo
@@ -380,43 +392,45 @@ func (c *CensusAPI) GetVariables(dataset, year string) (map[string]VariableInfo,
 		key_year, year)
 
 	if dataset == "" || year == "" {
-		return nil, fmt.Errorf("необходимо указать набор данных и год")
+		return nil, i18n.Errorf("необходимо указать набор данных и год")
 	}
 
 	endpoint := fmt.Sprintf("https://api.census.gov/data/%s/%s/variables.json", year, dataset)
 
 	resp, err := c.client.Get(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при отправке запроса: %w", err)
+		return nil, i18n.Errorf("ошибка при отправке запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API вернул статус %d для %s", resp.StatusCode, endpoint)
+		return nil, i18n.Errorf("API вернул статус %d для %s", resp.StatusCode, endpoint)
 	}
 
 	type apiResponse struct {
 		Variables map[string]struct {
-			Label       string `json:"label"`
-			Concept     string `json:"concept"`
-			Description string `json:"description,omitempty"`
-			Group       string `json:"group,omitempty"`
+			Label         string `json:"label"`
+			Concept       string `json:"concept"`
+			Description   string `json:"description,omitempty"`
+			Group         string `json:"group,omitempty"`
+			PredicateType string `json:"predicateType,omitempty"`
 		} `json:"variables"`
 	}
 
 	var response apiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		return nil, i18n.Errorf("ошибка при декодировании ответа: %w", err)
 	}
 
 	result := make(map[string]VariableInfo)
 	for name, info := range response.Variables {
 		result[name] = VariableInfo{
-			Name:        name,
-			Label:       info.Label,
-			Concept:     info.Concept,
-			Description: info.Description,
-			Group:       info.Group,
+			Name:          name,
+			Label:         info.Label,
+			Concept:       info.Concept,
+			Description:   info.Description,
+			Group:         info.Group,
+			PredicateType: info.PredicateType,
 		}
 	}
 
@@ -430,19 +444,19 @@ func (c *CensusAPI) GetGeographyLevels(dataset, year string) ([]GeographyLevel,
 		key_year, year)
 
 	if dataset == "" || year == "" {
-		return nil, fmt.Errorf("необходимо указать набор данных и год")
+		return nil, i18n.Errorf("необходимо указать набор данных и год")
 	}
 
 	endpoint := fmt.Sprintf("https://api.census.gov/data/%s/%s/geography.json", year, dataset)
 
 	resp, err := c.client.Get(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при отправке запроса: %w", err)
+		return nil, i18n.Errorf("ошибка при отправке запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API вернул статус %d для %s", resp.StatusCode, endpoint)
+		return nil, i18n.Errorf("API вернул статус %d для %s", resp.StatusCode, endpoint)
 	}
 
 	type apiResponse struct {
@@ -456,7 +470,7 @@ func (c *CensusAPI) GetGeographyLevels(dataset, year string) ([]GeographyLevel,
 
 	var response apiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		return nil, i18n.Errorf("ошибка при декодировании ответа: %w", err)
 	}
 
 	var result []GeographyLevel
@@ -478,19 +492,19 @@ func (c *CensusAPI) GetCustomData(request CustomDataRequest) ([]map[string]strin
 		key_request, request)
 
 	if len(request.Variables) == 0 {
-		return nil, fmt.Errorf("необходимо указать хотя бы одну переменную")
+		return nil, i18n.Errorf("необходимо указать хотя бы одну переменную")
 	}
 
 	if request.Dataset == "" {
-		return nil, fmt.Errorf("необходимо указать набор данных")
+		return nil, i18n.Errorf("необходимо указать набор данных")
 	}
 
 	if request.Year == "" {
-		return nil, fmt.Errorf("необходимо указать год")
+		return nil, i18n.Errorf("необходимо указать год")
 	}
 
 	if request.GeoLevel == "" {
-		return nil, fmt.Errorf("необходимо указать географический уровень")
+		return nil, i18n.Errorf("необходимо указать географический уровень")
 	}
 
 	endpoint := fmt.Sprintf("https://api.census.gov/data/%s/%s", request.Year, request.Dataset)
@@ -505,27 +519,31 @@ func (c *CensusAPI) GetCustomData(request CustomDataRequest) ([]map[string]strin
 		}
 	}
 
+	for key, value := range request.Predicates {
+		params.Add(key, value)
+	}
+
 	params.Add("key", c.apiKey)
 
 	requestURL := fmt.Sprintf("%s?%s", endpoint, params.Encode())
 
 	resp, err := c.client.Get(requestURL)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при отправке запроса: %w", err)
+		return nil, i18n.Errorf("ошибка при отправке запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API вернул статус %d", resp.StatusCode)
+		return nil, i18n.Errorf("API вернул статус %d", resp.StatusCode)
 	}
 
 	var rawData [][]string
 	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
-		return nil, fmt.Errorf("ошибка при декодировании ответа: %w", err)
+		return nil, i18n.Errorf("ошибка при декодировании ответа: %w", err)
 	}
 
 	if len(rawData) < 2 {
-		return nil, fmt.Errorf("API вернул пустой результат")
+		return nil, i18n.Errorf("API вернул пустой результат")
 	}
 
 	headers := rawData[0]
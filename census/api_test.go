@@ -119,9 +119,9 @@ func TestTextFormatter_Format(t *testing.T) {
 		"Регион",
 		"Население",
 		"California (штат 06)",
-		"39538223",
+		"39\u00a0538\u00a0223",
 		"Los Angeles County (округ 037, штат 06)",
-		"9818605",
+		"9\u00a0818\u00a0605",
 	}
 
 	for _, str := range expectedStrings {
@@ -0,0 +1,460 @@
+package census
+
+import (
+	"archive/zip"
+	"census_mcp/i18n"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Константы для ключей логирования
+const (
+	key_path    = "path"
+	key_member  = "member"
+	key_layer   = "layer"
+	key_missing = "missing"
+)
+
+// boundaryLayers - слои файлов границ TIGER/Line и картографических границ по географическим
+// столбцам ответа Census API. Имя слоя совпадает с обозначением в именах файлов Census Bureau
+// (например, tl_2021_us_county.shp, cb_2021_06_tract_500k.zip).
+var boundaryLayers = map[string]string{
+	"region":                       "region",
+	"division":                     "division",
+	"state":                        "state",
+	"county":                       "county",
+	"county subdivision":           "cousub",
+	"place":                        "place",
+	"tract":                        "tract",
+	"block group":                  "bg",
+	"block":                        "tabblock",
+	GeoLevelZCTA:                   "zcta",
+	GeoLevelCongressionalDistrict:  "cd",
+	GeoLevelStateLegislativeUpper:  "sldu",
+	GeoLevelStateLegislativeLower:  "sldl",
+	GeoLevelCBSA:                   "cbsa",
+	"public use microdata area":    "puma",
+	"school district (elementary)": "elsd",
+	"school district (secondary)":  "scsd",
+	"school district (unified)":    "unsd",
+	"american indian area/alaska native area/hawaiian home land": "aiannh",
+	"combined statistical area":                                  "csa",
+	"metropolitan division":                                      "metdiv",
+	"new england city and town area":                             "necta",
+	"urban area":                                                 "uac",
+}
+
+// boundaryNationalLayers - слои, GEOID которых не включает коды родительских географий ответа
+var boundaryNationalLayers = map[string]bool{
+	GeoLevelZCTA: true,
+	GeoLevelCBSA: true,
+	"american indian area/alaska native area/hawaiian home land": true,
+	"combined statistical area":                                  true,
+	"new england city and town area":                             true,
+	"urban area":                                                 true,
+}
+
+// boundaryIDProperties - атрибуты файлов границ с GEOID в порядке предпочтения.
+// Полные идентификаторы ("0500000US06037") сокращаются до GEOID ("06037").
+var boundaryIDProperties = []string{"GEOID", "GEOID20", "GEOID10", "GEOIDFQ", "AFFGEOID", "AFFGEOID20", "AFFGEOID10", "GEO_ID"}
+
+// Форматы файлов границ
+const (
+	boundaryGeoJSON   = "geojson"
+	boundaryShapefile = "shapefile"
+)
+
+// BoundaryStore загружает границы географий из локального каталога с файлами TIGER/Line
+// или картографических границ Census Bureau и сопоставляет их строкам результатов по GEOID.
+//
+// Поддерживаются shapefile (.shp с .dbf рядом или внутри архива .zip, как их публикует
+// Census Bureau) и GeoJSON (.geojson, .json). Слой файла (state, county, tract, bg, place,
+// cousub, zcta, cd, sldu, sldl, cbsa) и код штата определяются по имени файла. Координаты
+// должны быть географическими (долгота и широта NAD83 или WGS84, как в файлах Census Bureau).
+// Файлы читаются при первом обращении к их слою и штату и остаются в памяти.
+type BoundaryStore struct {
+	mu      sync.Mutex
+	sources map[string][]*boundarySource
+}
+
+// boundarySource - файл границ одного слоя
+type boundarySource struct {
+	path   string // путь к файлу .shp, .geojson или архиву .zip
+	member string // путь к файлу .shp внутри архива
+	format string
+	layer  string
+	state  string // код штата для файлов одного штата, пусто для общенациональных
+
+	loaded     bool
+	geometries map[string]json.RawMessage
+}
+
+// NewBoundaryStore находит файлы границ в каталоге dir и его подкаталогах. Файлы, слой
+// которых не удалось определить по имени, пропускаются. Если подходящих файлов нет,
+// возвращается ошибка.
+func NewBoundaryStore(dir string) (*BoundaryStore, error) {
+	slog.Debug("Поиск файлов границ",
+		key_path, dir)
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, i18n.Errorf("каталог границ недоступен: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, i18n.Errorf("%s не является каталогом", dir)
+	}
+
+	store := &BoundaryStore{
+		sources: make(map[string][]*boundarySource),
+	}
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".geojson", ".json":
+			store.add(&boundarySource{path: path, format: boundaryGeoJSON})
+		case ".shp":
+			store.add(&boundarySource{path: path, format: boundaryShapefile})
+		case ".zip":
+			members, err := zipShapefiles(path)
+			if err != nil {
+				slog.Warn("Не удалось прочитать архив границ",
+					key_path, path,
+					key_err, err)
+				return nil
+			}
+			for _, member := range members {
+				store.add(&boundarySource{path: path, member: member, format: boundaryShapefile})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, i18n.Errorf("ошибка при поиске файлов границ: %w", err)
+	}
+	if len(store.sources) == 0 {
+		return nil, i18n.Errorf("в каталоге %s нет файлов границ TIGER/Line (shapefile или GeoJSON)", dir)
+	}
+
+	slog.Info("Найдены файлы границ",
+		key_path, dir,
+		key_layer, strings.Join(store.Layers(), ","))
+	return store, nil
+}
+
+// add регистрирует файл границ, определяя его слой и штат по имени
+func (s *BoundaryStore) add(source *boundarySource) {
+	name := source.path
+	if source.member != "" {
+		name = source.member
+	}
+	source.layer, source.state = boundaryFileLayer(name)
+	if source.layer == "" {
+		slog.Debug("Пропущен файл с неизвестным слоем границ",
+			key_path, source.path,
+			key_member, source.member)
+		return
+	}
+	s.sources[source.layer] = append(s.sources[source.layer], source)
+}
+
+// Layers возвращает отсортированный список слоев с файлами границ
+func (s *BoundaryStore) Layers() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	layers := make([]string, 0, len(s.sources))
+	for layer := range s.sources {
+		layers = append(layers, layer)
+	}
+	sort.Strings(layers)
+	return layers
+}
+
+// Lookup возвращает геометрию GeoJSON географии слоя layer с идентификатором geoid
+func (s *BoundaryStore) Lookup(ctx context.Context, layer, geoid string) (json.RawMessage, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, source := range s.sources[layer] {
+		if source.state != "" && !strings.HasPrefix(geoid, source.state) {
+			continue
+		}
+		if !source.loaded {
+			source.load(ctx)
+		}
+		if geometry, ok := source.geometries[geoid]; ok {
+			return geometry, true
+		}
+	}
+	return nil, false
+}
+
+// load читает геометрии файла. Ошибка чтения записывается в журнал, а файл считается пустым,
+// чтобы не повторять чтение при каждом запросе.
+func (source *boundarySource) load(ctx context.Context) {
+	source.loaded = true
+
+	var err error
+	switch source.format {
+	case boundaryGeoJSON:
+		source.geometries, err = readGeoJSONBoundaries(source.path)
+	default:
+		source.geometries, err = source.readShapefile()
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "Не удалось загрузить файл границ",
+			key_path, source.path,
+			key_member, source.member,
+			key_err, err)
+		source.geometries = nil
+		return
+	}
+
+	slog.InfoContext(ctx, "Загружен файл границ",
+		key_path, source.path,
+		key_member, source.member,
+		key_layer, source.layer,
+		key_count, len(source.geometries))
+}
+
+// readShapefile читает фигуры и атрибуты shapefile и сопоставляет геометрии с GEOID записей
+func (source *boundarySource) readShapefile() (map[string]json.RawMessage, error) {
+	base := strings.TrimSuffix(source.path, filepath.Ext(source.path))
+	read := func(extension string) ([]byte, error) {
+		return os.ReadFile(base + extension)
+	}
+	if source.member != "" {
+		archive, err := zip.OpenReader(source.path)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+
+		base = strings.TrimSuffix(source.member, filepath.Ext(source.member))
+		read = func(extension string) ([]byte, error) {
+			return readZipMember(&archive.Reader, base+extension)
+		}
+	}
+
+	// Файл проекции необязателен; спроецированные координаты нельзя вывести в GeoJSON
+	if projection, err := read(".prj"); err == nil && strings.Contains(strings.ToUpper(string(projection)), "PROJCS") {
+		return nil, i18n.Errorf("координаты спроецированы, ожидаются долгота и широта")
+	}
+
+	shp, err := read(".shp")
+	if err != nil {
+		return nil, err
+	}
+	dbf, err := read(".dbf")
+	if err != nil {
+		return nil, i18n.Errorf("нет файла атрибутов .dbf: %w", err)
+	}
+
+	shapes, err := readShapes(shp)
+	if err != nil {
+		return nil, err
+	}
+	records, err := readDBF(dbf)
+	if err != nil {
+		return nil, err
+	}
+	if len(shapes) != len(records) {
+		return nil, i18n.Errorf("число фигур (%d) не совпадает с числом записей атрибутов (%d)", len(shapes), len(records))
+	}
+
+	geometries := make(map[string]json.RawMessage, len(shapes))
+	for i, shape := range shapes {
+		if shape == nil || records[i] == nil {
+			continue
+		}
+		if geoid := boundaryGeoID(records[i]); geoid != "" {
+			geometries[geoid] = shape
+		}
+	}
+	return geometries, nil
+}
+
+// readGeoJSONBoundaries читает геометрии объектов FeatureCollection и сопоставляет их с GEOID
+// из свойств объектов
+func readGeoJSONBoundaries(path string) (map[string]json.RawMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var collection struct {
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+			Geometry   json.RawMessage        `json:"geometry"`
+		} `json:"features"`
+	}
+	decoder := json.NewDecoder(file)
+	// Числовые GEOID сохраняются без экспоненциальной записи
+	decoder.UseNumber()
+	if err := decoder.Decode(&collection); err != nil {
+		return nil, i18n.Errorf("ошибка разбора GeoJSON: %w", err)
+	}
+
+	geometries := make(map[string]json.RawMessage, len(collection.Features))
+	for _, feature := range collection.Features {
+		if len(feature.Geometry) == 0 || string(feature.Geometry) == "null" {
+			continue
+		}
+		properties := make(map[string]string, len(feature.Properties))
+		for name, value := range feature.Properties {
+			if value != nil {
+				properties[name] = fmt.Sprint(value)
+			}
+		}
+		if geoid := boundaryGeoID(properties); geoid != "" {
+			geometries[geoid] = feature.Geometry
+		}
+	}
+	return geometries, nil
+}
+
+// zipShapefiles возвращает пути файлов .shp внутри архива
+func zipShapefiles(path string) ([]string, error) {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var members []string
+	for _, file := range archive.File {
+		if strings.EqualFold(filepath.Ext(file.Name), ".shp") {
+			members = append(members, file.Name)
+		}
+	}
+	return members, nil
+}
+
+// readZipMember читает файл архива; расширение сравнивается без учета регистра
+func readZipMember(archive *zip.Reader, name string) ([]byte, error) {
+	for _, file := range archive.File {
+		if !strings.EqualFold(file.Name, name) {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	}
+	return nil, i18n.Errorf("в архиве нет файла %s", name)
+}
+
+// boundaryFileLayer определяет слой границ и код штата по имени файла Census Bureau:
+// "tl_2021_06_tract.shp" - слой tract штата 06, "cb_2021_us_cd116_500k.shp" - слой cd
+func boundaryFileLayer(name string) (string, string) {
+	base := strings.ToLower(filepath.Base(name))
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+
+	layer, state := "", ""
+	for _, token := range strings.FieldsFunc(base, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		switch {
+		case len(token) == 2 && isDigits(token):
+			state = token
+		case layer == "":
+			layer = boundaryLayerToken(token)
+		}
+	}
+	return layer, state
+}
+
+// boundaryLayerToken возвращает слой границ по части имени файла: "county", "zcta520" (zcta),
+// "cd118" (cd), "tabblock20" (tabblock), "puma20" (puma). Для прочих частей возвращается пустая строка.
+func boundaryLayerToken(token string) string {
+	for _, layer := range boundaryLayers {
+		if token == layer {
+			return layer
+		}
+	}
+	switch {
+	case strings.HasPrefix(token, "zcta"):
+		return boundaryLayers[GeoLevelZCTA]
+	case strings.HasPrefix(token, "tabblock"):
+		return boundaryLayers["block"]
+	}
+	// Номер созыва или год границ в имени слоя: "cd118", "puma20", "uac20"
+	if trimmed := strings.TrimRight(token, "0123456789"); trimmed != token {
+		for _, layer := range boundaryLayers {
+			if trimmed == layer {
+				return layer
+			}
+		}
+	}
+	return ""
+}
+
+// isDigits сообщает, состоит ли непустая строка только из цифр
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// boundaryGeoID возвращает GEOID из атрибутов файла границ без учета регистра имен атрибутов
+func boundaryGeoID(properties map[string]string) string {
+	upper := make(map[string]string, len(properties))
+	for name, value := range properties {
+		upper[strings.ToUpper(name)] = strings.TrimSpace(value)
+	}
+	for _, name := range boundaryIDProperties {
+		value := upper[name]
+		if value == "" {
+			continue
+		}
+		if _, code, ok := strings.Cut(value, "US"); ok {
+			return code
+		}
+		return value
+	}
+	return ""
+}
+
+// BoundaryKey возвращает слой границ и GEOID строки ответа Census API по самому детальному
+// географическому столбцу строки. GEOID ZCTA и статистических ареалов не включает код штата.
+// Если в строке нет географии с границами, возвращается false.
+func BoundaryKey(row map[string]string) (string, string, bool) {
+	for i := len(geoIDColumns) - 1; i >= 0; i-- {
+		column := geoIDColumns[i]
+		code, ok := row[column]
+		if !ok {
+			continue
+		}
+		layer, ok := boundaryLayers[column]
+		if !ok {
+			return "", "", false
+		}
+		if boundaryNationalLayers[column] {
+			return layer, code, true
+		}
+		return layer, GeoID(row), true
+	}
+	return "", "", false
+}
@@ -0,0 +1,287 @@
+package census
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testShapefile собирает файлы .shp и .dbf с полигонами и атрибутом GEOID.
+// Пустой набор колец записывается пустой фигурой.
+func testShapefile(geoids []string, shapes [][][][2]float64) ([]byte, []byte) {
+	var records bytes.Buffer
+	for i, rings := range shapes {
+		var content bytes.Buffer
+		if len(rings) == 0 {
+			binary.Write(&content, binary.LittleEndian, uint32(shapeNull))
+		} else {
+			binary.Write(&content, binary.LittleEndian, uint32(shapePolygon))
+			content.Write(make([]byte, 32))
+			points := 0
+			for _, ring := range rings {
+				points += len(ring)
+			}
+			binary.Write(&content, binary.LittleEndian, uint32(len(rings)))
+			binary.Write(&content, binary.LittleEndian, uint32(points))
+			first := 0
+			for _, ring := range rings {
+				binary.Write(&content, binary.LittleEndian, uint32(first))
+				first += len(ring)
+			}
+			for _, ring := range rings {
+				for _, point := range ring {
+					binary.Write(&content, binary.LittleEndian, math.Float64bits(point[0]))
+					binary.Write(&content, binary.LittleEndian, math.Float64bits(point[1]))
+				}
+			}
+		}
+		binary.Write(&records, binary.BigEndian, uint32(i+1))
+		binary.Write(&records, binary.BigEndian, uint32(content.Len()/2))
+		records.Write(content.Bytes())
+	}
+
+	header := make([]byte, shpHeaderSize)
+	binary.BigEndian.PutUint32(header[0:4], shpFileCode)
+	binary.BigEndian.PutUint32(header[24:28], uint32((shpHeaderSize+records.Len())/2))
+	binary.LittleEndian.PutUint32(header[28:32], 1000)
+	binary.LittleEndian.PutUint32(header[32:36], shapePolygon)
+	shp := append(header, records.Bytes()...)
+
+	const fieldLength = 12
+	var dbf bytes.Buffer
+	dbfHeader := make([]byte, dbfHeaderSize)
+	dbfHeader[0] = 0x03
+	binary.LittleEndian.PutUint32(dbfHeader[4:8], uint32(len(geoids)))
+	binary.LittleEndian.PutUint16(dbfHeader[8:10], dbfHeaderSize+dbfFieldSize+1)
+	binary.LittleEndian.PutUint16(dbfHeader[10:12], 1+fieldLength)
+	dbf.Write(dbfHeader)
+	field := make([]byte, dbfFieldSize)
+	copy(field, "GEOID")
+	field[11] = 'C'
+	field[dbfFieldLengthPos] = fieldLength
+	dbf.Write(field)
+	dbf.WriteByte(dbfFieldsEnd)
+	for _, geoid := range geoids {
+		record := bytes.Repeat([]byte{' '}, 1+fieldLength)
+		copy(record[1:], geoid)
+		dbf.Write(record)
+	}
+	dbf.WriteByte(0x1A)
+
+	return shp, dbf.Bytes()
+}
+
+// Квадраты с обходом по часовой стрелке (внешние кольца shapefile) и против нее (дыры)
+var (
+	clockwiseSquare        = [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}}
+	counterClockwiseSquare = [][2]float64{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}}
+	clockwiseIsland        = [][2]float64{{20, 0}, {20, 1}, {21, 1}, {21, 0}, {20, 0}}
+)
+
+// testGeometry разбирает геометрию GeoJSON
+type testGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func TestBoundaryFileLayer(t *testing.T) {
+	tests := []struct {
+		name  string
+		layer string
+		state string
+	}{
+		{"tl_2021_us_county.shp", "county", ""},
+		{"cb_2021_06_tract_500k.shp", "tract", "06"},
+		{"tl_2022_48_bg.zip", "bg", "48"},
+		{"tl_2020_us_zcta520.shp", "zcta", ""},
+		{"cb_2022_us_cd118_500k.shp", "cd", ""},
+		{"tl_2022_06_sldu.shp", "sldu", "06"},
+		{"tl_2020_06_tabblock20.shp", "tabblock", "06"},
+		{"cb_2021_us_cbsa_500k.geojson", "cbsa", ""},
+		{"tl_2022_06_puma20.zip", "puma", "06"},
+		{"tl_2020_us_uac20.shp", "uac", ""},
+		{"cb_2021_48_unsd_500k.shp", "unsd", "48"},
+		{"boundaries/us-states.json", "", ""},
+	}
+
+	for _, tt := range tests {
+		layer, state := boundaryFileLayer(tt.name)
+		assert.Equal(t, tt.layer, layer, tt.name)
+		assert.Equal(t, tt.state, state, tt.name)
+	}
+}
+
+func TestBoundaryKey(t *testing.T) {
+	tests := []struct {
+		row   map[string]string
+		layer string
+		geoid string
+	}{
+		{map[string]string{"NAME": "California", "state": "06"}, "state", "06"},
+		{map[string]string{"state": "06", "county": "037"}, "county", "06037"},
+		{map[string]string{"state": "06", "county": "037", "tract": "101110"}, "tract", "06037101110"},
+		{map[string]string{"state": "06", GeoLevelZCTA: "94110"}, "zcta", "94110"},
+		{map[string]string{"state": "06", GeoLevelCongressionalDistrict: "12"}, "cd", "0612"},
+		{map[string]string{GeoLevelCBSA: "41860"}, "cbsa", "41860"},
+		{map[string]string{"state": "06", "public use microdata area": "03701"}, "puma", "0603701"},
+		{map[string]string{GeoLevelCBSA: "35620", "metropolitan division": "35614"}, "metdiv", "3562035614"},
+		{map[string]string{"urban area": "51445"}, "uac", "51445"},
+	}
+
+	for _, tt := range tests {
+		layer, geoid, ok := BoundaryKey(tt.row)
+		require.True(t, ok, tt.geoid)
+		assert.Equal(t, tt.layer, layer)
+		assert.Equal(t, tt.geoid, geoid)
+	}
+
+	_, _, ok := BoundaryKey(map[string]string{"NAME": "United States", "us": "1"})
+	assert.False(t, ok)
+}
+
+func TestBoundaryStore_Shapefile(t *testing.T) {
+	dir := t.TempDir()
+	shp, dbf := testShapefile(
+		[]string{"06037", "06075", "06001"},
+		[][][][2]float64{
+			{clockwiseSquare, counterClockwiseSquare},
+			{clockwiseSquare, clockwiseIsland},
+			nil,
+		})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tl_2021_us_county.shp"), shp, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tl_2021_us_county.dbf"), dbf, 0o644))
+
+	store, err := NewBoundaryStore(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"county"}, store.Layers())
+
+	t.Run("Полигон с дырой", func(t *testing.T) {
+		raw, ok := store.Lookup(context.Background(), "county", "06037")
+		require.True(t, ok)
+
+		var geometry testGeometry
+		require.NoError(t, json.Unmarshal(raw, &geometry))
+		assert.Equal(t, "Polygon", geometry.Type)
+
+		var rings [][][2]float64
+		require.NoError(t, json.Unmarshal(geometry.Coordinates, &rings))
+		require.Len(t, rings, 2)
+		// По RFC 7946 внешнее кольцо обходится против часовой стрелки, дыра - по часовой
+		assert.Positive(t, ringArea(rings[0]))
+		assert.Negative(t, ringArea(rings[1]))
+	})
+
+	t.Run("Несколько внешних колец", func(t *testing.T) {
+		raw, ok := store.Lookup(context.Background(), "county", "06075")
+		require.True(t, ok)
+
+		var geometry testGeometry
+		require.NoError(t, json.Unmarshal(raw, &geometry))
+		assert.Equal(t, "MultiPolygon", geometry.Type)
+
+		var polygons [][][][2]float64
+		require.NoError(t, json.Unmarshal(geometry.Coordinates, &polygons))
+		assert.Len(t, polygons, 2)
+	})
+
+	t.Run("Пустая фигура и неизвестный GEOID", func(t *testing.T) {
+		_, ok := store.Lookup(context.Background(), "county", "06001")
+		assert.False(t, ok)
+		_, ok = store.Lookup(context.Background(), "county", "48201")
+		assert.False(t, ok)
+		_, ok = store.Lookup(context.Background(), "tract", "06037101110")
+		assert.False(t, ok)
+	})
+}
+
+func TestBoundaryStore_Zip(t *testing.T) {
+	dir := t.TempDir()
+	shp, dbf := testShapefile([]string{"06"}, [][][][2]float64{{clockwiseSquare}})
+
+	var archive bytes.Buffer
+	writer := zip.NewWriter(&archive)
+	for name, content := range map[string][]byte{
+		"cb_2021_us_state_500k.shp": shp,
+		"cb_2021_us_state_500k.dbf": dbf,
+	} {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cb_2021_us_state_500k.zip"), archive.Bytes(), 0o644))
+
+	store, err := NewBoundaryStore(dir)
+	require.NoError(t, err)
+
+	raw, ok := store.Lookup(context.Background(), "state", "06")
+	require.True(t, ok)
+	assert.Contains(t, string(raw), `"type":"Polygon"`)
+}
+
+func TestBoundaryStore_GeoJSON(t *testing.T) {
+	dir := t.TempDir()
+	collection := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "properties": {"AFFGEOID": "1400000US06037101110", "NAME": "1011.10"},
+		 "geometry": {"type": "Point", "coordinates": [-118.3, 34.26]}},
+		{"type": "Feature", "properties": {"geoid": "06037101122"}, "geometry": null}
+	]}`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tracts"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tracts", "tl_2021_06_tract.geojson"), []byte(collection), 0o644))
+
+	store, err := NewBoundaryStore(dir)
+	require.NoError(t, err)
+
+	raw, ok := store.Lookup(context.Background(), "tract", "06037101110")
+	require.True(t, ok)
+	assert.JSONEq(t, `{"type": "Point", "coordinates": [-118.3, 34.26]}`, string(raw))
+
+	_, ok = store.Lookup(context.Background(), "tract", "06037101122")
+	assert.False(t, ok)
+	// Файл штата 06 не читается для GEOID другого штата
+	_, ok = store.Lookup(context.Background(), "tract", "48201100000")
+	assert.False(t, ok)
+}
+
+func TestBoundaryStore_ProjectedShapefile(t *testing.T) {
+	dir := t.TempDir()
+	shp, dbf := testShapefile([]string{"06"}, [][][][2]float64{{clockwiseSquare}})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tl_2021_us_state.shp"), shp, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tl_2021_us_state.dbf"), dbf, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tl_2021_us_state.prj"), []byte(`PROJCS["NAD83 / Conus Albers"]`), 0o644))
+
+	store, err := NewBoundaryStore(dir)
+	require.NoError(t, err)
+
+	_, ok := store.Lookup(context.Background(), "state", "06")
+	assert.False(t, ok)
+}
+
+func TestNewBoundaryStore_Errors(t *testing.T) {
+	_, err := NewBoundaryStore(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorContains(t, err, "каталог границ недоступен")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "readme.txt"), []byte("TIGER/Line"), 0o644))
+	_, err = NewBoundaryStore(dir)
+	assert.ErrorContains(t, err, "нет файлов границ")
+}
+
+func TestReadShapes_Invalid(t *testing.T) {
+	_, err := readShapes([]byte("not a shapefile"))
+	assert.Error(t, err)
+
+	shp, _ := testShapefile([]string{"06"}, [][][][2]float64{{clockwiseSquare}})
+	_, err = readShapes(shp[:len(shp)-8])
+	assert.ErrorContains(t, err, "повреждена")
+}
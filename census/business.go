@@ -0,0 +1,173 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"sort"
+	"strconv"
+)
+
+// Константы для ключей логирования
+const (
+	key_naics = "naics"
+)
+
+// Наборы данных статистики предприятий
+const (
+	// DatasetCBP - County Business Patterns, ежегодная статистика предприятий
+	DatasetCBP = "cbp"
+	// DatasetEconomicCensus - базовые показатели экономической переписи (проводится раз в 5 лет)
+	DatasetEconomicCensus = "ecnbasic"
+)
+
+// Годы по умолчанию для наборов данных статистики предприятий
+const (
+	DefaultCBPYear            = "2021"
+	DefaultEconomicCensusYear = "2017"
+)
+
+// naics2017FirstYear - первый год, в котором CBP и экономическая перепись используют NAICS 2017
+const naics2017FirstYear = 2017
+
+// NAICSVariable возвращает имя переменной-предиката NAICS для года данных
+// (NAICS2017 начиная с 2017 года, NAICS2012 - для 2012-2016)
+func NAICSVariable(year string) (string, error) {
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return "", i18n.Errorf("некорректный год: %q", year)
+	}
+	if y >= naics2017FirstYear {
+		return "NAICS2017", nil
+	}
+	if y >= 2012 {
+		return "NAICS2012", nil
+	}
+	return "", i18n.Errorf("данные по отраслям NAICS поддерживаются с 2012 года, запрошен %s", year)
+}
+
+// BusinessRequest представляет запрос статистики предприятий по отраслям
+type BusinessRequest struct {
+	Dataset   string            // "cbp" или "ecnbasic"
+	Year      string            // Год данных
+	GeoLevel  string            // Географический уровень
+	GeoFilter map[string]string // Фильтр географии
+	NAICS     []string          // Коды отраслей NAICS (по умолчанию "00" - все секторы)
+}
+
+// BusinessRow содержит показатели предприятий отрасли в географии. Пустые значения означают,
+// что показатель не публикуется для набора данных или скрыт для защиты конфиденциальности.
+type BusinessRow struct {
+	GeoID          string `json:"geoid"`
+	Name           string `json:"name"`
+	NAICS          string `json:"naics"`
+	Industry       string `json:"industry"`
+	Establishments string `json:"establishments"`
+	Employment     string `json:"employment"`
+	// Payroll - годовой фонд оплаты труда, тыс. долларов
+	Payroll string `json:"payroll"`
+	// Receipts - выручка, тыс. долларов (только экономическая перепись)
+	Receipts string `json:"receipts,omitempty"`
+}
+
+// BusinessResult содержит статистику предприятий по отраслям
+type BusinessResult struct {
+	Dataset       string        `json:"dataset"`
+	Year          string        `json:"year"`
+	GeoLevel      string        `json:"geo_level"`
+	NAICSVariable string        `json:"naics_variable"`
+	Rows          []BusinessRow `json:"rows"`
+}
+
+// GetBusinessPatterns запрашивает число предприятий, занятость и фонд оплаты труда по отраслям
+// NAICS из County Business Patterns или экономической переписи. Для каждого кода выполняется
+// отдельный запрос с предикатом NAICS.
+func GetBusinessPatterns(api CensusAPIClient, request BusinessRequest) (*BusinessResult, error) {
+	slog.Info("Получение статистики предприятий",
+		key_dataset, request.Dataset,
+		key_year, request.Year,
+		key_naics, request.NAICS)
+
+	if request.Dataset != DatasetCBP && request.Dataset != DatasetEconomicCensus {
+		return nil, i18n.Errorf("набор данных %q не поддерживается; доступны: %s, %s", request.Dataset, DatasetCBP, DatasetEconomicCensus)
+	}
+
+	naicsVariable, err := NAICSVariable(request.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := request.NAICS
+	if len(codes) == 0 {
+		codes = []string{"00"}
+	}
+	for _, code := range codes {
+		if err := ValidateNAICSCode(code); err != nil {
+			return nil, err
+		}
+	}
+
+	labelVariable := naicsVariable + "_LABEL"
+	variables := []string{"NAME", labelVariable, "ESTAB", "EMP", "PAYANN"}
+	if request.Dataset == DatasetEconomicCensus {
+		variables = append(variables, "RCPTOT")
+	}
+
+	result := &BusinessResult{
+		Dataset:       request.Dataset,
+		Year:          request.Year,
+		GeoLevel:      request.GeoLevel,
+		NAICSVariable: naicsVariable,
+	}
+
+	for _, code := range codes {
+		data, err := api.GetCustomData(CustomDataRequest{
+			Variables:  variables,
+			Dataset:    request.Dataset,
+			Year:       request.Year,
+			GeoLevel:   request.GeoLevel,
+			GeoFilter:  WildcardGeoFilter(request.GeoLevel, request.GeoFilter),
+			Predicates: map[string]string{naicsVariable: code},
+		})
+		if err != nil {
+			return nil, i18n.Errorf("NAICS %s: %w", code, err)
+		}
+
+		for _, row := range data {
+			industry := row[labelVariable]
+			if industry == "" {
+				if known, ok := LookupNAICS(code); ok {
+					industry = known.Title
+				}
+			}
+
+			business := BusinessRow{
+				GeoID:          GeoID(row),
+				Name:           row["NAME"],
+				NAICS:          code,
+				Industry:       industry,
+				Establishments: businessNumber(row["ESTAB"]),
+				Employment:     businessNumber(row["EMP"]),
+				Payroll:        businessNumber(row["PAYANN"]),
+			}
+			if request.Dataset == DatasetEconomicCensus {
+				business.Receipts = businessNumber(row["RCPTOT"])
+			}
+			result.Rows = append(result.Rows, business)
+		}
+	}
+
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		return result.Rows[i].GeoID < result.Rows[j].GeoID
+	})
+
+	return result, nil
+}
+
+// businessNumber нормализует числовое значение; служебные и нечисловые значения заменяются пустой строкой
+func businessNumber(value string) string {
+	number, ok := ParseEstimate(value)
+	if !ok {
+		return ""
+	}
+	return FormatNumber(number)
+}
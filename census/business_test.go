@@ -0,0 +1,55 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNAICSVariable(t *testing.T) {
+	variable, err := NAICSVariable("2021")
+	assert.NoError(t, err)
+	assert.Equal(t, "NAICS2017", variable)
+
+	variable, err = NAICSVariable("2015")
+	assert.NoError(t, err)
+	assert.Equal(t, "NAICS2012", variable)
+
+	_, err = NAICSVariable("2007")
+	assert.Error(t, err)
+}
+
+func TestGetBusinessPatterns(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{"NAME": "Travis County, Texas", "state": "48", "county": "453", "ESTAB": "3410", "EMP": "71234", "PAYANN": "1650000"},
+		},
+	}
+
+	result, err := GetBusinessPatterns(api, BusinessRequest{
+		Dataset:   DatasetCBP,
+		Year:      "2021",
+		GeoLevel:  "county",
+		GeoFilter: map[string]string{"state": "48", "county": "453"},
+		NAICS:     []string{"7225", "44-45"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, api.requests, 2)
+	assert.Equal(t, map[string]string{"NAICS2017": "7225"}, api.requests[0].Predicates)
+	assert.Equal(t, []string{"NAME", "NAICS2017_LABEL", "ESTAB", "EMP", "PAYANN"}, api.requests[0].Variables)
+
+	// Без подписи из API используется название из таблицы NAICS
+	assert.Equal(t, "Restaurants and Other Eating Places", result.Rows[0].Industry)
+	assert.Equal(t, "3410", result.Rows[0].Establishments)
+	assert.Equal(t, "", result.Rows[0].Receipts)
+
+	// Экономическая перепись дополнительно запрашивает выручку
+	_, err = GetBusinessPatterns(api, BusinessRequest{Dataset: DatasetEconomicCensus, Year: "2017", GeoLevel: "state"})
+	assert.NoError(t, err)
+	assert.Contains(t, api.requests[2].Variables, "RCPTOT")
+	assert.Equal(t, map[string]string{"NAICS2017": "00"}, api.requests[2].Predicates)
+
+	_, err = GetBusinessPatterns(api, BusinessRequest{Dataset: "acs/acs5", Year: "2021", GeoLevel: "state"})
+	assert.Error(t, err)
+}
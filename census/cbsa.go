@@ -0,0 +1,350 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Константы для ключей логирования
+const (
+	key_cbsas = "cbsas"
+)
+
+// GeoLevelCBSA - название географического уровня CBSA в Census API
+const GeoLevelCBSA = "metropolitan statistical area/micropolitan statistical area"
+
+// Типы статистических ареалов (CBSA)
+const (
+	CBSATypeMetropolitan = "Metropolitan Statistical Area"
+	CBSATypeMicropolitan = "Micropolitan Statistical Area"
+)
+
+// cbsaDelineationCSV - выдержка из делимитации CBSA (OMB Bulletin 20-01), используемой в ACS 2021-2023.
+// Содержит крупнейшие метрополитенские ареалы и примеры микрополитенских; данные по ареалам,
+// отсутствующим в файле, можно запросить по пятизначному коду CBSA. Полную делимитацию
+// загружает LoadCBSADelineationFile.
+//
+//go:embed data/cbsa_delineation.csv
+var cbsaDelineationCSV string
+
+// CBSACounty представляет округ, входящий в статистический ареал
+type CBSACounty struct {
+	StateFIPS  string `json:"state_fips"`
+	CountyFIPS string `json:"county_fips"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+}
+
+// CBSAInfo содержит сведения о статистическом ареале (CBSA) и его составе
+type CBSAInfo struct {
+	Code     string       `json:"code"`
+	Title    string       `json:"title"`
+	Type     string       `json:"type"`
+	Counties []CBSACounty `json:"counties,omitempty"`
+}
+
+// CBSARequest представляет запрос данных по статистическим ареалам
+type CBSARequest struct {
+	CBSAs     []string // Коды CBSA, названия (например, "Seattle metro") или "*" для всех
+	Variables []string // Список переменных для запроса
+	Dataset   string   // Набор данных (например, "acs/acs1")
+	Year      string   // Год данных
+}
+
+var (
+	cbsaMu    sync.Mutex
+	cbsaList  []CBSAInfo
+	cbsaError error
+)
+
+// cbsaColumns - заголовки столбцов делимитации: встроенного файла и файла OMB
+// (list1 делимитации, сохраненный в CSV)
+var cbsaColumns = [...][]string{
+	{"cbsa_code", "CBSA Code"},
+	{"cbsa_title", "CBSA Title"},
+	{"cbsa_type", "Metropolitan/Micropolitan Statistical Area"},
+	{"state_fips", "FIPS State Code"},
+	{"county_fips", "FIPS County Code"},
+	{"county_name", "County/County Equivalent"},
+	{"state_name", "State Name"},
+}
+
+// loadCBSADelineation возвращает делимитацию CBSA: загруженную LoadCBSADelineationFile
+// или встроенную выдержку
+func loadCBSADelineation() ([]CBSAInfo, error) {
+	cbsaMu.Lock()
+	defer cbsaMu.Unlock()
+
+	if cbsaList == nil && cbsaError == nil {
+		cbsaList, cbsaError = ReadCBSADelineation(strings.NewReader(cbsaDelineationCSV))
+	}
+	return cbsaList, cbsaError
+}
+
+// LoadCBSADelineationFile заменяет встроенную выдержку делимитацией CBSA из файла CSV
+// и возвращает число ареалов. Подходит файл list1 делимитации OMB, сохраненный в CSV.
+func LoadCBSADelineationFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, i18n.Errorf("ошибка при чтении файла делимитации CBSA: %w", err)
+	}
+	defer file.Close()
+
+	list, err := ReadCBSADelineation(file)
+	if err != nil {
+		return 0, err
+	}
+
+	cbsaMu.Lock()
+	defer cbsaMu.Unlock()
+	cbsaList, cbsaError = list, nil
+
+	slog.Info("Загружена делимитация CBSA",
+		key_cbsas, len(list))
+	return len(list), nil
+}
+
+// ReadCBSADelineation разбирает делимитацию CBSA в формате CSV. Строки до заголовка
+// (название и дата бюллетеня OMB) и после данных (примечания) пропускаются; коды FIPS,
+// потерявшие ведущие нули при сохранении из электронной таблицы, дополняются нулями.
+func ReadCBSADelineation(r io.Reader) ([]CBSAInfo, error) {
+	reader := csv.NewReader(r)
+	// Строки заголовка и примечаний файла OMB содержат другое число полей
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, i18n.Errorf("ошибка при чтении файла делимитации CBSA: %w", err)
+	}
+
+	header := -1
+	var positions [len(cbsaColumns)]int
+	for i, record := range records {
+		if cbsaHeaderPositions(record, &positions) {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil, i18n.Errorf("в файле делимитации CBSA нет строки заголовков")
+	}
+
+	var list []CBSAInfo
+	index := make(map[string]int)
+	for i, record := range records[header+1:] {
+		// Строки без пятизначного кода CBSA - примечания в конце файла OMB
+		if len(record) == 0 || !isCBSACode(strings.TrimSpace(record[positions[0]])) {
+			continue
+		}
+
+		values := make([]string, len(positions))
+		for j, pos := range positions {
+			if pos >= len(record) {
+				return nil, i18n.Errorf("некорректная строка %d в файле делимитации CBSA", header+i+2)
+			}
+			values[j] = strings.TrimSpace(record[pos])
+		}
+
+		code := values[0]
+		pos, ok := index[code]
+		if !ok {
+			pos = len(list)
+			index[code] = pos
+			list = append(list, CBSAInfo{
+				Code:  code,
+				Title: values[1],
+				Type:  values[2],
+			})
+		}
+
+		list[pos].Counties = append(list[pos].Counties, CBSACounty{
+			StateFIPS:  padFIPS(values[3], 2),
+			CountyFIPS: padFIPS(values[4], 3),
+			Name:       values[5],
+			State:      values[6],
+		})
+	}
+
+	return list, nil
+}
+
+// cbsaHeaderPositions находит в строке заголовки столбцов cbsaColumns и записывает их номера
+func cbsaHeaderPositions(record []string, positions *[len(cbsaColumns)]int) bool {
+	for i, names := range cbsaColumns {
+		positions[i] = -1
+		for j, value := range record {
+			value = strings.TrimSpace(value)
+			for _, name := range names {
+				if strings.EqualFold(value, name) {
+					positions[i] = j
+				}
+			}
+		}
+		if positions[i] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// padFIPS дополняет код FIPS ведущими нулями до длины width
+func padFIPS(code string, width int) string {
+	if len(code) >= width {
+		return code
+	}
+	return strings.Repeat("0", width-len(code)) + code
+}
+
+// LookupCBSA ищет статистический ареал по пятизначному коду в делимитации CBSA
+func LookupCBSA(code string) (CBSAInfo, bool) {
+	list, err := loadCBSADelineation()
+	if err != nil {
+		return CBSAInfo{}, false
+	}
+
+	for _, cbsa := range list {
+		if cbsa.Code == code {
+			return cbsa, true
+		}
+	}
+
+	return CBSAInfo{}, false
+}
+
+// cbsaNoiseWords - слова запроса, не несущие информации о названии ареала
+var cbsaNoiseWords = map[string]bool{
+	"metro": true, "metropolitan": true, "micro": true, "micropolitan": true,
+	"area": true, "msa": true, "cbsa": true, "statistical": true, "region": true,
+}
+
+// SearchCBSAByName ищет статистические ареалы по названию (например, "Seattle metro").
+// Ареал подходит, если каждое значимое слово запроса встречается в его названии.
+func SearchCBSAByName(name string) ([]CBSAInfo, error) {
+	list, err := loadCBSADelineation()
+	if err != nil {
+		return nil, err
+	}
+
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(name)) {
+		word = strings.Trim(word, ",.")
+		if word != "" && !cbsaNoiseWords[word] {
+			words = append(words, word)
+		}
+	}
+
+	if len(words) == 0 {
+		return nil, i18n.Errorf("необходимо указать название статистического ареала")
+	}
+
+	var result []CBSAInfo
+	for _, cbsa := range list {
+		title := strings.ToLower(cbsa.Title)
+		matched := true
+		for _, word := range words {
+			if !strings.Contains(title, word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, cbsa)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+
+	return result, nil
+}
+
+// ResolveCBSACodes преобразует коды и названия ареалов в список кодов CBSA
+func ResolveCBSACodes(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, i18n.Errorf("необходимо указать хотя бы один статистический ареал")
+	}
+
+	var codes []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "*" || isCBSACode(value) {
+			codes = append(codes, value)
+			continue
+		}
+
+		matches, err := SearchCBSAByName(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, i18n.Errorf("статистический ареал не найден: %q", value)
+		}
+		if len(matches) > 1 {
+			titles := make([]string, 0, len(matches))
+			for _, match := range matches {
+				titles = append(titles, fmt.Sprintf("%s (%s)", match.Title, match.Code))
+			}
+			return nil, i18n.Errorf("запросу %q соответствует несколько ареалов: %s", value, strings.Join(titles, "; "))
+		}
+		codes = append(codes, matches[0].Code)
+	}
+
+	return codes, nil
+}
+
+// isCBSACode проверяет, что значение является пятизначным кодом CBSA
+func isCBSACode(value string) bool {
+	if len(value) != 5 {
+		return false
+	}
+	for _, ch := range value {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildCBSADataRequest преобразует запрос по статистическим ареалам в пользовательский запрос Census API
+func BuildCBSADataRequest(request CBSARequest) (CustomDataRequest, error) {
+	codes, err := ResolveCBSACodes(request.CBSAs)
+	if err != nil {
+		return CustomDataRequest{}, err
+	}
+
+	for _, code := range codes {
+		if code == "*" {
+			codes = []string{"*"}
+			break
+		}
+	}
+
+	return CustomDataRequest{
+		Variables: request.Variables,
+		Dataset:   request.Dataset,
+		Year:      request.Year,
+		GeoLevel:  GeoLevelCBSA,
+		GeoFilter: map[string]string{GeoLevelCBSA: strings.Join(codes, ",")},
+	}, nil
+}
+
+// GetCBSAData возвращает данные по статистическим ареалам (CBSA)
+func (c *CensusAPI) GetCBSAData(request CBSARequest) ([]map[string]string, error) {
+	slog.Info("Получение данных по статистическим ареалам",
+		key_cbsas, request.CBSAs)
+
+	customRequest, err := BuildCBSADataRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCustomData(customRequest)
+}
@@ -0,0 +1,149 @@
+package census
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCBSADelineation(t *testing.T) {
+	list, err := loadCBSADelineation()
+	assert.NoError(t, err)
+	// Встроенная выдержка содержит 22 ареала; полную делимитацию загружает LoadCBSADelineationFile
+	assert.Len(t, list, 22)
+
+	cbsa, ok := LookupCBSA("42660")
+	assert.True(t, ok)
+	assert.Equal(t, "Seattle-Tacoma-Bellevue, WA", cbsa.Title)
+	assert.Equal(t, CBSATypeMetropolitan, cbsa.Type)
+	assert.Len(t, cbsa.Counties, 3)
+	assert.Equal(t, CBSACounty{StateFIPS: "53", CountyFIPS: "033", Name: "King County", State: "Washington"}, cbsa.Counties[0])
+
+	_, ok = LookupCBSA("99999")
+	assert.False(t, ok)
+}
+
+// ombDelineationCSV - фрагмент list1 делимитации OMB, сохраненный из электронной таблицы в CSV
+const ombDelineationCSV = `List 1. CORE BASED STATISTICAL AREAS (CBSAs), METROPOLITAN DIVISIONS, AND COMBINED STATISTICAL AREAS (CSAs),,,,,,,,,,,
+March 2020,,,,,,,,,,,
+CBSA Code,Metropolitan Division Code,CSA Code,CBSA Title,Metropolitan/Micropolitan Statistical Area,Metropolitan Division Title,CSA Title,County/County Equivalent,State Name,FIPS State Code,FIPS County Code,Central/Outlying County
+10100,,,"Aberdeen, SD",Micropolitan Statistical Area,,,Brown County,South Dakota,46,013,Central
+10100,,,"Aberdeen, SD",Micropolitan Statistical Area,,,Edmunds County,South Dakota,46,045,Outlying
+11500,,,"Anniston-Oxford, AL",Metropolitan Statistical Area,,,Calhoun County,Alabama,1,15,Central
+42660,42644,500,"Seattle-Tacoma-Bellevue, WA",Metropolitan Statistical Area,"Seattle-Bellevue-Kent, WA","Seattle-Tacoma, WA",King County,Washington,53,033,Central
+42660,45104,500,"Seattle-Tacoma-Bellevue, WA",Metropolitan Statistical Area,"Tacoma-Lakewood, WA","Seattle-Tacoma, WA",Pierce County,Washington,53,053,Central
+,,,,,,,,,,,
+"Note: The 2010 Standards for Delineating Metropolitan and Micropolitan Statistical Areas are at https://www.govinfo.gov/content/pkg/FR-2010-06-28/pdf/2010-15605.pdf",,,,,,,,,,,
+Internet Release Date: April 2020,,,,,,,,,,,
+`
+
+func TestReadCBSADelineation_OMBFile(t *testing.T) {
+	list, err := ReadCBSADelineation(strings.NewReader(ombDelineationCSV))
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+
+	assert.Equal(t, "10100", list[0].Code)
+	assert.Equal(t, CBSATypeMicropolitan, list[0].Type)
+	assert.Len(t, list[0].Counties, 2)
+
+	// Ведущие нули кодов FIPS восстанавливаются
+	assert.Equal(t, CBSACounty{StateFIPS: "01", CountyFIPS: "015", Name: "Calhoun County", State: "Alabama"}, list[1].Counties[0])
+
+	// Округа метрополитенских дивизионов объединяются в один ареал
+	assert.Equal(t, "Seattle-Tacoma-Bellevue, WA", list[2].Title)
+	assert.Len(t, list[2].Counties, 2)
+
+	_, err = ReadCBSADelineation(strings.NewReader("code,title\n42660,Seattle\n"))
+	assert.Error(t, err)
+}
+
+func TestLoadCBSADelineationFile(t *testing.T) {
+	embedded, err := loadCBSADelineation()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		cbsaMu.Lock()
+		cbsaList, cbsaError = embedded, nil
+		cbsaMu.Unlock()
+	})
+
+	path := filepath.Join(t.TempDir(), "list1_2020.csv")
+	require.NoError(t, os.WriteFile(path, []byte(ombDelineationCSV), 0o644))
+
+	count, err := LoadCBSADelineationFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// Загруженная делимитация заменяет встроенную выдержку
+	cbsa, ok := LookupCBSA("10100")
+	assert.True(t, ok)
+	assert.Equal(t, "Aberdeen, SD", cbsa.Title)
+	_, ok = LookupCBSA("31080")
+	assert.False(t, ok)
+
+	_, err = LoadCBSADelineationFile(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
+
+func TestSearchCBSAByName(t *testing.T) {
+	result, err := SearchCBSAByName("Seattle metro")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "42660", result[0].Code)
+
+	result, err = SearchCBSAByName("bozeman micropolitan area")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, CBSATypeMicropolitan, result[0].Type)
+
+	result, err = SearchCBSAByName("Atlantis")
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	_, err = SearchCBSAByName("metro area")
+	assert.Error(t, err)
+}
+
+func TestResolveCBSACodes(t *testing.T) {
+	codes, err := ResolveCBSACodes([]string{"Seattle metro", "31080", "*"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"42660", "31080", "*"}, codes)
+
+	// Несколько совпадений требуют уточнения
+	_, err = ResolveCBSACodes([]string{"San"})
+	assert.Error(t, err)
+
+	_, err = ResolveCBSACodes([]string{"Atlantis"})
+	assert.Error(t, err)
+}
+
+func TestBuildCBSADataRequest(t *testing.T) {
+	request, err := BuildCBSADataRequest(CBSARequest{
+		CBSAs:     []string{"Seattle", "31080"},
+		Variables: []string{"NAME"},
+		Dataset:   "acs/acs1",
+		Year:      "2021",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, GeoLevelCBSA, request.GeoLevel)
+	assert.Equal(t, map[string]string{GeoLevelCBSA: "42660,31080"}, request.GeoFilter)
+}
+
+func TestTextFormatter_Format_CBSAInfo(t *testing.T) {
+	cbsa, _ := LookupCBSA("42660")
+	result := NewTextFormatter().Format(context.Background(), []CBSAInfo{cbsa})
+
+	for _, str := range []string{
+		"# Статистические ареалы (CBSA)",
+		"## Seattle-Tacoma-Bellevue, WA",
+		"**Код CBSA**: 42660",
+		"**Округа** (3)",
+		"King County, Washington (штат 53, округ 033)",
+	} {
+		assert.Contains(t, result, str)
+	}
+}
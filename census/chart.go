@@ -0,0 +1,644 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChartKind - вид диаграммы
+type ChartKind string
+
+// Виды диаграмм
+const (
+	ChartBar     ChartKind = "bar"     // горизонтальные столбцы по географиям
+	ChartLine    ChartKind = "line"    // линии временного ряда
+	ChartPyramid ChartKind = "pyramid" // половозрастная пирамида по таблице B01001
+)
+
+// ChartKinds - все виды диаграмм в порядке документации
+var ChartKinds = []ChartKind{ChartBar, ChartLine, ChartPyramid}
+
+// ChartMIMEType - MIME-тип диаграмм
+const ChartMIMEType = "image/svg+xml"
+
+// Ограничения размера диаграмм: столбчатая диаграмма показывает наибольшие значения,
+// линейная - первые ряды
+const (
+	maxChartBars   = 30
+	maxChartSeries = 10
+)
+
+// Размеры диаграмм в пикселях
+const (
+	chartWidth     = 800
+	chartTitleY    = 28
+	chartTop       = 50
+	chartRowHeight = 22
+	chartBarHeight = 16
+	chartMaxLabel  = 32  // наибольшая длина подписи категории или ряда в символах
+	chartMaxTitle  = 110 // наибольшая длина заголовка в символах
+)
+
+// chartPalette - цвета рядов диаграмм
+var chartPalette = []string{
+	"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f",
+	"#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac",
+}
+
+// chartTimeColumns - столбцы ответа Census API, задающие время для линейной диаграммы
+var chartTimeColumns = []string{"YEAR", "year", "time", "DATE_CODE"}
+
+// pyramidGroups - пятилетние возрастные группы половозрастной пирамиды и номера строк
+// таблицы B01001 для мужчин; строки для женщин смещены на 24 (B01001_003E - B01001_027E)
+var pyramidGroups = []struct {
+	label string
+	lines []int
+}{
+	{"0-4", []int{3}},
+	{"5-9", []int{4}},
+	{"10-14", []int{5}},
+	{"15-19", []int{6, 7}},
+	{"20-24", []int{8, 9, 10}},
+	{"25-29", []int{11}},
+	{"30-34", []int{12}},
+	{"35-39", []int{13}},
+	{"40-44", []int{14}},
+	{"45-49", []int{15}},
+	{"50-54", []int{16}},
+	{"55-59", []int{17}},
+	{"60-64", []int{18, 19}},
+	{"65-69", []int{20, 21}},
+	{"70-74", []int{22}},
+	{"75-79", []int{23}},
+	{"80-84", []int{24}},
+	{"85+", []int{25}},
+}
+
+// pyramidFemaleOffset - смещение номеров строк таблицы B01001 для женщин
+const pyramidFemaleOffset = 24
+
+// ChartSeries - ряд значений диаграммы по категориям; недоступные значения равны NaN
+type ChartSeries struct {
+	Name   string
+	Values []float64
+}
+
+// Chart описывает диаграмму: категории (географии, годы или возрастные группы) и ряды значений.
+// Столбчатая диаграмма имеет один ряд, пирамида - ряды мужчин и женщин.
+type Chart struct {
+	Kind       ChartKind
+	Title      string
+	ValueKind  ValueKind
+	Categories []string
+	Series     []ChartSeries
+}
+
+// ParseChartKind проверяет вид диаграммы
+func ParseChartKind(value string) (ChartKind, error) {
+	for _, kind := range ChartKinds {
+		if string(kind) == value {
+			return kind, nil
+		}
+	}
+	return "", i18n.Errorf("неизвестный вид диаграммы %q; доступны: bar, line, pyramid", value)
+}
+
+// NewChart строит диаграмму вида kind по результату инструмента: данным о населении,
+// строкам ответа Census API или сравнению выпусков. column - столбец или переменная значений;
+// если он не задан, используется первый числовой столбец.
+func NewChart(ctx context.Context, data interface{}, kind ChartKind, column string) (*Chart, error) {
+	slog.DebugContext(ctx, "Построение диаграммы",
+		key_kind, kind,
+		key_type, fmt.Sprintf("%T", data))
+
+	switch v := data.(type) {
+	case []PopulationData:
+		if kind != ChartBar {
+			return nil, i18n.Errorf("для данных о населении доступна только столбчатая диаграмма")
+		}
+		return populationChart(ctx, v), nil
+	case []map[string]string:
+		switch kind {
+		case ChartBar:
+			return rowsBarChart(ctx, v, column)
+		case ChartLine:
+			return rowsLineChart(ctx, v, column)
+		case ChartPyramid:
+			return pyramidChart(ctx, v)
+		}
+	case *YearComparison:
+		if kind != ChartLine {
+			return nil, i18n.Errorf("для сравнения выпусков доступна только линейная диаграмма")
+		}
+		return yearComparisonChart(ctx, v, column)
+	}
+	return nil, i18n.Errorf("диаграмма %q недоступна для этого результата", kind)
+}
+
+// populationChart строит столбчатую диаграмму населения
+func populationChart(ctx context.Context, data []PopulationData) *Chart {
+	chart := &Chart{Kind: ChartBar, Title: i18n.T(ctx, "Население"), ValueKind: ValueKindCount}
+	series := ChartSeries{Name: chart.Title}
+	for _, item := range data {
+		chart.Categories = append(chart.Categories, item.Name)
+		series.Values = append(series.Values, chartValue(item.Population))
+	}
+	chart.Series = []ChartSeries{series}
+	return chart
+}
+
+// rowsBarChart строит столбчатую диаграмму значений столбца по географиям
+func rowsBarChart(ctx context.Context, data []map[string]string, column string) (*Chart, error) {
+	column, err := chartColumn(ctx, data, column)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := &Chart{Kind: ChartBar, Title: columnLabel(ctx, column), ValueKind: chartValueKind(ctx, column)}
+	series := ChartSeries{Name: chart.Title}
+	for _, row := range data {
+		chart.Categories = append(chart.Categories, chartRowName(row))
+		series.Values = append(series.Values, chartValue(row[column]))
+	}
+	chart.Series = []ChartSeries{series}
+	return chart, nil
+}
+
+// rowsLineChart строит линейную диаграмму по столбцу времени с рядом на каждую географию
+func rowsLineChart(ctx context.Context, data []map[string]string, column string) (*Chart, error) {
+	timeColumn := ""
+	for _, candidate := range chartTimeColumns {
+		if len(data) > 0 && data[0][candidate] != "" {
+			timeColumn = candidate
+			break
+		}
+	}
+	if timeColumn == "" {
+		return nil, i18n.Errorf("для линейной диаграммы нужен столбец времени (%s)", strings.Join(chartTimeColumns, ", "))
+	}
+
+	column, err := chartColumn(ctx, data, column, timeColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Категории - значения времени по возрастанию, ряды - географии в порядке появления
+	times := make(map[string]bool)
+	var names []string
+	values := make(map[string]map[string]float64)
+	for _, row := range data {
+		name := chartRowName(row)
+		if values[name] == nil {
+			values[name] = make(map[string]float64)
+			names = append(names, name)
+		}
+		times[row[timeColumn]] = true
+		values[name][row[timeColumn]] = chartValue(row[column])
+	}
+
+	chart := &Chart{Kind: ChartLine, Title: columnLabel(ctx, column), ValueKind: chartValueKind(ctx, column)}
+	for t := range times {
+		chart.Categories = append(chart.Categories, t)
+	}
+	sort.Slice(chart.Categories, func(i, j int) bool {
+		return chartTimeLess(chart.Categories[i], chart.Categories[j])
+	})
+
+	for _, name := range names {
+		chart.Series = append(chart.Series, chartSeries(name, chart.Categories, values[name]))
+	}
+	return chart, nil
+}
+
+// yearComparisonChart строит линейную диаграмму переменной по выпускам
+func yearComparisonChart(ctx context.Context, data *YearComparison, variable string) (*Chart, error) {
+	if variable == "" && len(data.Variables) > 0 {
+		variable = data.Variables[0]
+	}
+	found := false
+	for _, v := range data.Variables {
+		found = found || v == variable
+	}
+	if !found {
+		return nil, i18n.Errorf("переменная %q отсутствует в сравнении выпусков", variable)
+	}
+
+	chart := &Chart{
+		Kind:       ChartLine,
+		Title:      columnLabel(ctx, variable),
+		ValueKind:  variableKind(ctx, variable, VariableKind(variable, VariableInfo{})),
+		Categories: data.Years,
+	}
+	for _, row := range data.Rows {
+		values := make(map[string]float64, len(data.Years))
+		for year, value := range row.Values[variable] {
+			values[year] = chartValue(value)
+		}
+		chart.Series = append(chart.Series, chartSeries(row.Name, data.Years, values))
+	}
+	return chart, nil
+}
+
+// pyramidChart строит половозрастную пирамиду по переменным таблицы B01001 одной географии
+func pyramidChart(ctx context.Context, data []map[string]string) (*Chart, error) {
+	if len(data) != 1 {
+		return nil, i18n.Errorf("половозрастная пирамида строится для одной географии, получено строк: %d", len(data))
+	}
+	row := data[0]
+
+	male := ChartSeries{Name: i18n.T(ctx, "Мужчины")}
+	female := ChartSeries{Name: i18n.T(ctx, "Женщины")}
+	chart := &Chart{
+		Kind:      ChartPyramid,
+		Title:     i18n.Sprintf(ctx, "Половозрастная пирамида: %s", chartRowName(row)),
+		ValueKind: ValueKindCount,
+	}
+	for _, group := range pyramidGroups {
+		var maleTotal, femaleTotal float64
+		for _, line := range group.lines {
+			maleValue, ok1 := ParseEstimate(row[fmt.Sprintf("B01001_%03dE", line)])
+			femaleValue, ok2 := ParseEstimate(row[fmt.Sprintf("B01001_%03dE", line+pyramidFemaleOffset)])
+			if !ok1 || !ok2 {
+				return nil, i18n.Errorf("для половозрастной пирамиды нужны переменные B01001_003E-B01001_049E (например, group(B01001))")
+			}
+			maleTotal += maleValue
+			femaleTotal += femaleValue
+		}
+		chart.Categories = append(chart.Categories, group.label)
+		male.Values = append(male.Values, maleTotal)
+		female.Values = append(female.Values, femaleTotal)
+	}
+	chart.Series = []ChartSeries{male, female}
+	return chart, nil
+}
+
+// chartColumn возвращает столбец значений диаграммы: заданный столбец или первый числовой
+// столбец ответа, кроме исключенных
+func chartColumn(ctx context.Context, data []map[string]string, column string, excluded ...string) (string, error) {
+	if len(data) == 0 {
+		return "", i18n.Errorf("нет данных для диаграммы")
+	}
+	if column != "" {
+		if customColumnType(ctx, column) != JSONColumnNumber || !hasColumn(data, column) {
+			return "", i18n.Errorf("столбец %q отсутствует или не является числовым", column)
+		}
+		return column, nil
+	}
+
+	for _, candidate := range responseColumns(ctx, data) {
+		skip := false
+		for _, e := range excluded {
+			skip = skip || e == candidate
+		}
+		if !skip && customColumnType(ctx, candidate) == JSONColumnNumber {
+			return candidate, nil
+		}
+	}
+	return "", i18n.Errorf("в данных нет числовых столбцов для диаграммы")
+}
+
+// hasColumn сообщает, есть ли столбец хотя бы в одной строке данных
+func hasColumn(data []map[string]string, column string) bool {
+	for _, row := range data {
+		if _, ok := row[column]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// chartValueKind возвращает вид значений столбца для подписей осей
+func chartValueKind(ctx context.Context, column string) ValueKind {
+	if kind, ok := dataColumnKind(ctx, column); ok {
+		return kind
+	}
+	return ValueKindNumber
+}
+
+// chartRowName возвращает подпись строки ответа: название географии или ее GEOID
+func chartRowName(row map[string]string) string {
+	if name := row["NAME"]; name != "" {
+		return name
+	}
+	return GeoID(row)
+}
+
+// chartValue разбирает значение для диаграммы; недоступные значения равны NaN
+func chartValue(raw string) float64 {
+	if value, ok := ParseEstimate(raw); ok {
+		return value
+	}
+	return math.NaN()
+}
+
+// chartSeries строит ряд значений по категориям
+func chartSeries(name string, categories []string, values map[string]float64) ChartSeries {
+	series := ChartSeries{Name: name, Values: make([]float64, len(categories))}
+	for i, category := range categories {
+		value, ok := values[category]
+		if !ok {
+			value = math.NaN()
+		}
+		series.Values[i] = value
+	}
+	return series
+}
+
+// chartTimeLess сравнивает значения времени численно, если это возможно
+func chartTimeLess(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
+
+// SVG отрисовывает диаграмму в SVG без внешних зависимостей. Подписи чисел оформляются
+// по языку и точности из контекста.
+func (c *Chart) SVG(ctx context.Context) string {
+	switch c.Kind {
+	case ChartLine:
+		return c.lineSVG(ctx)
+	case ChartPyramid:
+		return c.pyramidSVG(ctx)
+	default:
+		return c.barSVG(ctx)
+	}
+}
+
+// barSVG отрисовывает горизонтальные столбцы, упорядоченные по убыванию значения
+func (c *Chart) barSVG(ctx context.Context) string {
+	type bar struct {
+		label string
+		value float64
+	}
+	var bars []bar
+	for i, category := range c.Categories {
+		if value := c.Series[0].Values[i]; !math.IsNaN(value) {
+			bars = append(bars, bar{category, value})
+		}
+	}
+	sort.SliceStable(bars, func(i, j int) bool { return bars[i].value > bars[j].value })
+
+	title := c.Title
+	if len(bars) > maxChartBars {
+		title = i18n.Sprintf(ctx, "%s (наибольшие %d из %d)", c.Title, maxChartBars, len(bars))
+		bars = bars[:maxChartBars]
+	}
+
+	const left, right = 220, 110
+	height := chartTop + len(bars)*chartRowHeight + 20
+	svg := newSVG(height, title)
+
+	low, high := 0.0, 0.0
+	for _, b := range bars {
+		low, high = math.Min(low, b.value), math.Max(high, b.value)
+	}
+	scale := linearScale(low, high, left, chartWidth-right)
+
+	zero := scale(0)
+	for i, b := range bars {
+		y := chartTop + i*chartRowHeight
+		x0, x1 := math.Min(zero, scale(b.value)), math.Max(zero, scale(b.value))
+		svg.text(left-8, float64(y+chartBarHeight-4), "end", "", truncateLabel(b.label, chartMaxLabel))
+		svg.rect(x0, float64(y), x1-x0, chartBarHeight, chartPalette[0])
+		svg.text(x1+4, float64(y+chartBarHeight-4), "start", "", FormatValue(ctx, b.value, c.ValueKind))
+	}
+	svg.line(zero, chartTop-4, zero, float64(height-16), "#333333")
+	return svg.close()
+}
+
+// lineSVG отрисовывает линии рядов по категориям с осями и легендой
+func (c *Chart) lineSVG(ctx context.Context) string {
+	series := c.Series
+	title := c.Title
+	if len(series) > maxChartSeries {
+		title = i18n.Sprintf(ctx, "%s (первые %d рядов из %d)", c.Title, maxChartSeries, len(series))
+		series = series[:maxChartSeries]
+	}
+
+	const left, right, bottom, height = 90, 200, 50, 420
+	svg := newSVG(height, title)
+
+	low, high := 0.0, 0.0
+	for _, s := range series {
+		for _, value := range s.Values {
+			if !math.IsNaN(value) {
+				low, high = math.Min(low, value), math.Max(high, value)
+			}
+		}
+	}
+	ticks := niceTicks(low, high)
+	yScale := linearScale(ticks[0], ticks[len(ticks)-1], height-bottom, chartTop)
+
+	// Сетка и подписи оси значений
+	for _, tick := range ticks {
+		y := yScale(tick)
+		svg.line(left, y, chartWidth-right, y, "#dddddd")
+		svg.text(left-6, y+4, "end", "", FormatValue(ctx, tick, c.ValueKind))
+	}
+
+	// Подписи категорий
+	xOf := func(i int) float64 {
+		if len(c.Categories) == 1 {
+			return float64(left+chartWidth-right) / 2
+		}
+		return left + float64(i)*float64(chartWidth-right-left)/float64(len(c.Categories)-1)
+	}
+	for i, category := range c.Categories {
+		svg.text(xOf(i), height-bottom+18, "middle", "", category)
+	}
+	svg.line(left, height-bottom, chartWidth-right, height-bottom, "#333333")
+
+	// Ряды с разрывами на недоступных значениях и легенда
+	for n, s := range series {
+		color := chartPalette[n%len(chartPalette)]
+		var points []string
+		flush := func() {
+			if len(points) > 1 {
+				svg.polyline(points, color)
+			}
+			points = nil
+		}
+		for i, value := range s.Values {
+			if math.IsNaN(value) {
+				flush()
+				continue
+			}
+			points = append(points, svgNumber(xOf(i))+","+svgNumber(yScale(value)))
+		}
+		flush()
+		// Точки поверх линий, чтобы были видны и одиночные значения между разрывами
+		for i, value := range s.Values {
+			if !math.IsNaN(value) {
+				svg.circle(xOf(i), yScale(value), 3, color)
+			}
+		}
+
+		legendY := float64(chartTop + n*chartRowHeight)
+		svg.rect(chartWidth-right+16, legendY, 12, 12, color)
+		svg.text(chartWidth-right+34, legendY+10, "start", "", truncateLabel(s.Name, chartMaxLabel))
+	}
+	return svg.close()
+}
+
+// pyramidSVG отрисовывает половозрастную пирамиду: мужчины слева, женщины справа,
+// старшие возрастные группы сверху
+func (c *Chart) pyramidSVG(ctx context.Context) string {
+	const center, labelWidth, bottom = chartWidth / 2, 60, 40
+	rows := len(c.Categories)
+	height := chartTop + 20 + rows*chartRowHeight + bottom
+	svg := newSVG(height, c.Title)
+
+	high := 0.0
+	for _, s := range c.Series {
+		for _, value := range s.Values {
+			if !math.IsNaN(value) {
+				high = math.Max(high, value)
+			}
+		}
+	}
+	ticks := niceTicks(0, high)
+	width := float64(center - labelWidth/2 - 20)
+	scale := linearScale(0, ticks[len(ticks)-1], 0, width)
+
+	top := chartTop + 20
+	svg.text(center-labelWidth/2-width/2, chartTop+6, "middle", "bold", c.Series[0].Name)
+	svg.text(center+labelWidth/2+width/2, chartTop+6, "middle", "bold", c.Series[1].Name)
+
+	for i := range c.Categories {
+		// Старшие группы сверху
+		index := rows - 1 - i
+		y := float64(top + i*chartRowHeight)
+		svg.text(center, y+chartBarHeight-4, "middle", "", c.Categories[index])
+		if value := c.Series[0].Values[index]; !math.IsNaN(value) {
+			svg.rect(center-labelWidth/2-scale(value), y, scale(value), chartBarHeight, chartPalette[0])
+		}
+		if value := c.Series[1].Values[index]; !math.IsNaN(value) {
+			svg.rect(center+labelWidth/2, y, scale(value), chartBarHeight, chartPalette[1])
+		}
+	}
+
+	// Шкала значений в обе стороны от центра
+	axisY := float64(top + rows*chartRowHeight + 4)
+	for _, tick := range ticks {
+		label := FormatValue(ctx, tick, c.ValueKind)
+		svg.text(center-labelWidth/2-scale(tick), axisY+14, "middle", "", label)
+		svg.text(center+labelWidth/2+scale(tick), axisY+14, "middle", "", label)
+	}
+	svg.line(center-labelWidth/2-width, axisY, center-labelWidth/2, axisY, "#333333")
+	svg.line(center+labelWidth/2, axisY, center+labelWidth/2+width, axisY, "#333333")
+	return svg.close()
+}
+
+// linearScale возвращает линейное отображение отрезка значений [low, high] на отрезок [from, to]
+func linearScale(low, high, from, to float64) func(float64) float64 {
+	if high == low {
+		high = low + 1
+	}
+	return func(value float64) float64 {
+		return from + (value-low)/(high-low)*(to-from)
+	}
+}
+
+// niceTicks возвращает деления оси с округленным шагом (1, 2 или 5 на степень десяти),
+// покрывающие отрезок [low, high]
+func niceTicks(low, high float64) []float64 {
+	if high <= low {
+		high = low + 1
+	}
+	raw := (high - low) / 4
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := magnitude * 10
+	for _, factor := range []float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			step = factor * magnitude
+			break
+		}
+	}
+
+	var ticks []float64
+	for tick := math.Floor(low/step) * step; tick < high+step/2; tick += step {
+		ticks = append(ticks, tick)
+	}
+	if ticks[len(ticks)-1] < high {
+		ticks = append(ticks, ticks[len(ticks)-1]+step)
+	}
+	return ticks
+}
+
+// truncateLabel сокращает подпись до limit символов
+func truncateLabel(label string, limit int) string {
+	runes := []rune(label)
+	if len(runes) <= limit {
+		return label
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// svgNumber форматирует координату SVG
+func svgNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
+
+// svgBuilder собирает документ SVG
+type svgBuilder struct {
+	sb strings.Builder
+}
+
+// newSVG начинает документ SVG заданной высоты с белым фоном и заголовком
+func newSVG(height int, title string) *svgBuilder {
+	svg := &svgBuilder{}
+	fmt.Fprintf(&svg.sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		chartWidth, height, chartWidth, height)
+	fmt.Fprintf(&svg.sb, `<rect width="%d" height="%d" fill="#ffffff"/>`+"\n", chartWidth, height)
+	svg.text(chartWidth/2, chartTitleY, "middle", "bold", truncateLabel(title, chartMaxTitle))
+	return svg
+}
+
+// text добавляет подпись; anchor - выравнивание (start, middle, end), weight - насыщенность шрифта
+func (s *svgBuilder) text(x, y float64, anchor, weight, text string) {
+	fmt.Fprintf(&s.sb, `<text x="%s" y="%s" text-anchor="%s"`, svgNumber(x), svgNumber(y), anchor)
+	if weight != "" {
+		fmt.Fprintf(&s.sb, ` font-weight="%s"`, weight)
+	}
+	fmt.Fprintf(&s.sb, ">%s</text>\n", html.EscapeString(text))
+}
+
+// rect добавляет прямоугольник
+func (s *svgBuilder) rect(x, y, width, height float64, fill string) {
+	fmt.Fprintf(&s.sb, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+		svgNumber(x), svgNumber(y), svgNumber(width), svgNumber(height), fill)
+}
+
+// line добавляет отрезок
+func (s *svgBuilder) line(x1, y1, x2, y2 float64, stroke string) {
+	fmt.Fprintf(&s.sb, `<line x1="%s" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n",
+		svgNumber(x1), svgNumber(y1), svgNumber(x2), svgNumber(y2), stroke)
+}
+
+// polyline добавляет ломаную линию ряда
+func (s *svgBuilder) polyline(points []string, stroke string) {
+	fmt.Fprintf(&s.sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+		strings.Join(points, " "), stroke)
+}
+
+// circle добавляет точку ряда
+func (s *svgBuilder) circle(x, y, r float64, fill string) {
+	fmt.Fprintf(&s.sb, `<circle cx="%s" cy="%s" r="%s" fill="%s"/>`+"\n",
+		svgNumber(x), svgNumber(y), svgNumber(r), fill)
+}
+
+// close завершает документ SVG
+func (s *svgBuilder) close() string {
+	s.sb.WriteString("</svg>\n")
+	return s.sb.String()
+}
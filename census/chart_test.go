@@ -0,0 +1,178 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertValidSVG проверяет, что документ является корректным XML с корневым элементом svg
+func assertValidSVG(t *testing.T, svg string) {
+	t.Helper()
+
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg"`))
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		_, err := decoder.Token()
+		if err == io.EOF {
+			return
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestNewChart_Bar(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	rows := make([]map[string]string, 40)
+	for i := range rows {
+		rows[i] = map[string]string{
+			"NAME":        fmt.Sprintf("County %02d", i+1),
+			"B01001_001E": strconv.Itoa(1000 * (i + 1)),
+			"state":       "48",
+		}
+	}
+	rows[0]["B01001_001E"] = "-666666666"
+
+	chart, err := NewChart(ctx, rows, ChartBar, "")
+	require.NoError(t, err)
+	assert.Equal(t, "B01001_001E", chart.Title)
+	assert.Equal(t, ValueKindCount, chart.ValueKind)
+	assert.Len(t, chart.Categories, 40)
+	assert.True(t, math.IsNaN(chart.Series[0].Values[0]))
+
+	svg := chart.SVG(ctx)
+	assertValidSVG(t, svg)
+	assert.Contains(t, svg, ">B01001_001E (top 30 of 39)</text>")
+	assert.Contains(t, svg, ">County 40</text>")
+	assert.Contains(t, svg, ">40,000</text>")
+	// В диаграмму попадают наибольшие значения
+	assert.NotContains(t, svg, ">County 09</text>")
+}
+
+func TestNewChart_Population(t *testing.T) {
+	chart, err := NewChart(context.Background(), []PopulationData{
+		{Name: "California", Population: "39538223", State: "06"},
+		{Name: "Texas & Co <test>", Population: "29145505", State: "48"},
+	}, ChartBar, "")
+	require.NoError(t, err)
+
+	svg := chart.SVG(context.Background())
+	assertValidSVG(t, svg)
+	assert.Contains(t, svg, ">Население</text>")
+	assert.Contains(t, svg, ">Texas &amp; Co &lt;test&gt;</text>")
+	assert.Contains(t, svg, ">39\u00a0538\u00a0223</text>")
+
+	_, err = NewChart(context.Background(), []PopulationData{}, ChartLine, "")
+	assert.Error(t, err)
+}
+
+func TestNewChart_Line(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	t.Run("Строки со столбцом времени", func(t *testing.T) {
+		rows := []map[string]string{
+			{"NAME": "Texas", "YEAR": "2021", "POP": "29527941", "state": "48"},
+			{"NAME": "Texas", "YEAR": "2019", "POP": "28995881", "state": "48"},
+			{"NAME": "Texas", "YEAR": "2020", "POP": "29232474", "state": "48"},
+			{"NAME": "Utah", "YEAR": "2019", "POP": "3205958", "state": "49"},
+		}
+
+		chart, err := NewChart(ctx, rows, ChartLine, "")
+		require.NoError(t, err)
+		assert.Equal(t, "POP", chart.Title)
+		assert.Equal(t, []string{"2019", "2020", "2021"}, chart.Categories)
+		require.Len(t, chart.Series, 2)
+		assert.Equal(t, []float64{28995881, 29232474, 29527941}, chart.Series[0].Values)
+		assert.True(t, math.IsNaN(chart.Series[1].Values[2]))
+
+		svg := chart.SVG(ctx)
+		assertValidSVG(t, svg)
+		assert.Contains(t, svg, "<polyline")
+		assert.Contains(t, svg, ">Utah</text>")
+		assert.Contains(t, svg, ">2020</text>")
+	})
+
+	t.Run("Без столбца времени", func(t *testing.T) {
+		_, err := NewChart(ctx, []map[string]string{{"NAME": "Texas", "B01001_001E": "1"}}, ChartLine, "")
+		assert.Error(t, err)
+	})
+
+	t.Run("Сравнение выпусков", func(t *testing.T) {
+		comparison := &YearComparison{
+			Years:     []string{"2019", "2021"},
+			Variables: []string{"B19013_001E"},
+			Rows: []YearComparisonRow{
+				{Name: "Texas", Values: map[string]map[string]string{"B19013_001E": {"2019": "64034", "2021": "66963"}}},
+			},
+		}
+		ctx := WithNumberFormat(ctx, NumberFormat{Precision: 0, Kinds: map[string]ValueKind{"B19013_001E": ValueKindMoney}})
+
+		chart, err := NewChart(ctx, comparison, ChartLine, "")
+		require.NoError(t, err)
+		assert.Equal(t, ValueKindMoney, chart.ValueKind)
+		assert.Contains(t, chart.SVG(ctx), ">$80,000</text>")
+
+		_, err = NewChart(ctx, comparison, ChartLine, "B01001_001E")
+		assert.Error(t, err)
+		_, err = NewChart(ctx, comparison, ChartBar, "")
+		assert.Error(t, err)
+	})
+}
+
+func TestNewChart_Pyramid(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	row := map[string]string{"NAME": "Travis County, Texas", "state": "48", "county": "453"}
+	for line := 3; line <= 49; line++ {
+		if line != 26 {
+			row[fmt.Sprintf("B01001_%03dE", line)] = "100"
+		}
+	}
+
+	chart, err := NewChart(ctx, []map[string]string{row}, ChartPyramid, "")
+	require.NoError(t, err)
+	assert.Equal(t, "Population pyramid: Travis County, Texas", chart.Title)
+	assert.Len(t, chart.Categories, 18)
+	assert.Equal(t, "85+", chart.Categories[17])
+	// 20-24 объединяет три строки таблицы B01001
+	assert.Equal(t, 300.0, chart.Series[0].Values[4])
+	assert.Equal(t, 300.0, chart.Series[1].Values[4])
+
+	svg := chart.SVG(ctx)
+	assertValidSVG(t, svg)
+	assert.Contains(t, svg, ">Male</text>")
+	assert.Contains(t, svg, ">Female</text>")
+
+	_, err = NewChart(ctx, []map[string]string{row, row}, ChartPyramid, "")
+	assert.Error(t, err)
+
+	delete(row, "B01001_049E")
+	_, err = NewChart(ctx, []map[string]string{row}, ChartPyramid, "")
+	assert.Error(t, err)
+}
+
+func TestParseChartKind(t *testing.T) {
+	kind, err := ParseChartKind("pyramid")
+	assert.NoError(t, err)
+	assert.Equal(t, ChartPyramid, kind)
+
+	_, err = ParseChartKind("pie")
+	assert.Error(t, err)
+}
+
+func TestNiceTicks(t *testing.T) {
+	assert.Equal(t, []float64{0, 20, 40, 60, 80}, niceTicks(0, 73))
+	assert.Equal(t, []float64{0, 10000000, 20000000, 30000000, 40000000}, niceTicks(0, 39538223))
+	assert.Equal(t, []float64{-50, 0, 50, 100, 150}, niceTicks(-20, 150))
+	assert.Equal(t, []float64{0, 0.5, 1}, niceTicks(0, 0))
+}
@@ -0,0 +1,146 @@
+package census
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// ColumnLayout задает порядок и подписи столбцов ответа пользовательского запроса.
+// Ответ Census API разбирается в строки map[string]string, в которых порядок столбцов
+// теряется, поэтому он передается форматтерам через контекст (WithColumnLayout).
+type ColumnLayout struct {
+	// Order - столбцы в порядке ответа Census API, то есть в порядке переменных запроса
+	// (например, "B19013_001E", "NAME"), и вычисляемые столбцы
+	Order []string
+	// Labels - подписи столбцов, выводимые вместо кодов переменных
+	// (например, "B01001_001E" - "Sex by Age - Total")
+	Labels map[string]string
+}
+
+// columnLayoutKey - ключ контекста для порядка и подписей столбцов
+type columnLayoutKey struct{}
+
+// WithColumnLayout возвращает контекст с порядком и подписями столбцов
+func WithColumnLayout(ctx context.Context, layout ColumnLayout) context.Context {
+	return context.WithValue(ctx, columnLayoutKey{}, layout)
+}
+
+// ColumnLayoutFromContext возвращает порядок и подписи столбцов из контекста
+func ColumnLayoutFromContext(ctx context.Context) ColumnLayout {
+	layout, _ := ctx.Value(columnLayoutKey{}).(ColumnLayout)
+	return layout
+}
+
+// Части подписи переменной, обозначающие вид значения, а не сам показатель
+var (
+	estimateLabelParts = map[string]bool{"Estimate": true, "Percent": true}
+	moeLabelParts      = map[string]bool{"Margin of Error": true, "Percent Margin of Error": true}
+)
+
+// VariableLabel возвращает читаемую подпись переменной по ее описанию в variables.json:
+// части подписи "Estimate!!Total:!!Male:" соединяются через " - " без служебных префиксов
+// ("Total - Male"), к подписи MOE добавляется "(MOE)", а подпись из одного "Total"
+// дополняется концепцией таблицы ("Sex by Age - Total"). Для пустой подписи возвращается "".
+func VariableLabel(info VariableInfo) string {
+	var parts []string
+	moe := false
+	for i, part := range strings.Split(info.Label, "!!") {
+		part = strings.TrimSpace(part)
+		part = strings.TrimSpace(strings.TrimSuffix(part, ":"))
+		part = strings.TrimSpace(strings.TrimSuffix(part, "--"))
+		if part == "" {
+			continue
+		}
+		if i == 0 && estimateLabelParts[part] {
+			continue
+		}
+		if i == 0 && moeLabelParts[part] {
+			moe = true
+			continue
+		}
+		parts = append(parts, part)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	if len(parts) == 1 && parts[0] == "Total" && info.Concept != "" {
+		parts = append([]string{info.Concept}, parts...)
+	}
+
+	label := strings.Join(parts, " - ")
+	if moe {
+		label += " (MOE)"
+	}
+	return label
+}
+
+// VariableLabels возвращает читаемые подписи перечисленных столбцов, описанных в definitions.
+// Столбцы без описания (NAME, вычисляемые и географические столбцы) пропускаются.
+func VariableLabels(definitions map[string]VariableInfo, columns []string) map[string]string {
+	labels := make(map[string]string, len(columns))
+	for _, column := range columns {
+		if IsGeographyColumn(column) {
+			continue
+		}
+		info, ok := definitions[column]
+		if !ok {
+			continue
+		}
+		if label := VariableLabel(info); label != "" {
+			labels[column] = label
+		}
+	}
+	return labels
+}
+
+// columnLabel возвращает подпись столбца из контекста или имя столбца
+func columnLabel(ctx context.Context, column string) string {
+	if label, ok := ColumnLayoutFromContext(ctx).Labels[column]; ok {
+		return label
+	}
+	return column
+}
+
+// responseColumns возвращает столбцы строк ответа Census API в порядке ответа: столбцы
+// из ColumnLayout контекста в заданном порядке, остальные столбцы (без порядка в контексте -
+// NAME и переменные в алфавитном порядке), затем географические столбцы по вложенности
+func responseColumns(ctx context.Context, data []map[string]string) []string {
+	seen := make(map[string]bool)
+	for _, row := range data {
+		for column := range row {
+			seen[column] = true
+		}
+	}
+
+	var columns []string
+	placed := make(map[string]bool)
+	for _, column := range ColumnLayoutFromContext(ctx).Order {
+		if seen[column] && !placed[column] && !IsGeographyColumn(column) {
+			columns = append(columns, column)
+			placed[column] = true
+		}
+	}
+
+	var rest []string
+	for column := range seen {
+		if !placed[column] && !IsGeographyColumn(column) {
+			rest = append(rest, column)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if (rest[i] == "NAME") != (rest[j] == "NAME") {
+			return rest[i] == "NAME"
+		}
+		return rest[i] < rest[j]
+	})
+	columns = append(columns, rest...)
+
+	for _, geo := range geoIDColumns {
+		if seen[geo] {
+			columns = append(columns, geo)
+		}
+	}
+	return columns
+}
@@ -0,0 +1,95 @@
+package census
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVariableLabel(t *testing.T) {
+	tests := []struct {
+		info     VariableInfo
+		expected string
+	}{
+		{VariableInfo{Label: "Estimate!!Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"}, "Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"},
+		{VariableInfo{Label: "Estimate!!Total:", Concept: "Sex by Age"}, "Sex by Age - Total"},
+		{VariableInfo{Label: "Estimate!!Total:!!Male:!!Under 5 years", Concept: "Sex by Age"}, "Total - Male - Under 5 years"},
+		{VariableInfo{Label: "Margin of Error!!Total:!!Male:"}, "Total - Male (MOE)"},
+		{VariableInfo{Label: "Estimate!!Median age --!!Total:"}, "Median age - Total"},
+		{VariableInfo{Label: "Percent!!EMPLOYMENT STATUS!!Unemployment Rate"}, "EMPLOYMENT STATUS - Unemployment Rate"},
+		{VariableInfo{Label: " !!Total:", Concept: "RACE"}, "RACE - Total"},
+		{VariableInfo{Label: "Geographic Area Name"}, "Geographic Area Name"},
+		{VariableInfo{}, ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, VariableLabel(tt.info), tt.info.Label)
+	}
+}
+
+func TestVariableLabels(t *testing.T) {
+	definitions := map[string]VariableInfo{
+		"B01001_001E": {Label: "Estimate!!Total:", Concept: "Sex by Age"},
+		"NAME":        {Label: "Geographic Area Name"},
+		"state":       {Label: "State"},
+	}
+
+	labels := VariableLabels(definitions, []string{"B01001_001E", "NAME", "pct_male", "state"})
+
+	assert.Equal(t, map[string]string{
+		"B01001_001E": "Sex by Age - Total",
+		"NAME":        "Geographic Area Name",
+	}, labels)
+}
+
+func TestResponseColumns(t *testing.T) {
+	data := []map[string]string{
+		{"NAME": "Travis County, Texas", "B19013_001E": "85043", "B01001_001E": "1290188", "pct_male": "50.1", "state": "48", "county": "453"},
+	}
+
+	t.Run("Без порядка в контексте", func(t *testing.T) {
+		assert.Equal(t, []string{"NAME", "B01001_001E", "B19013_001E", "pct_male", "state", "county"},
+			responseColumns(context.Background(), data))
+	})
+
+	t.Run("Порядок переменных запроса", func(t *testing.T) {
+		ctx := WithColumnLayout(context.Background(), ColumnLayout{
+			Order: []string{"B19013_001E", "NAME", "B01001_001E", "B01001_001M", "county"},
+		})
+		// Столбцы вне порядка идут после упорядоченных, географические столбцы - последними
+		assert.Equal(t, []string{"B19013_001E", "NAME", "B01001_001E", "pct_male", "state", "county"},
+			responseColumns(ctx, data))
+	})
+}
+
+func TestTextFormatter_ColumnLayout(t *testing.T) {
+	ctx := WithColumnLayout(context.Background(), ColumnLayout{
+		Order:  []string{"B19013_001E", "NAME"},
+		Labels: map[string]string{"B19013_001E": "Median household income"},
+	})
+
+	result := NewTextFormatter().Format(ctx, []map[string]string{
+		{"NAME": "Texas", "B19013_001E": "66963", "state": "48"},
+	})
+
+	assert.Contains(t, result, "| Median household income | NAME | state | \n")
+	assert.Contains(t, result, "| 66\u00a0963 | Texas | 48 | \n")
+}
+
+func TestJSONFormatter_ColumnLabels(t *testing.T) {
+	ctx := WithColumnLayout(context.Background(), ColumnLayout{
+		Order:  []string{"B19013_001E", "NAME"},
+		Labels: map[string]string{"B19013_001E": "Median household income"},
+	})
+
+	doc := NewJSONFormatter().customDataDocument(ctx, []map[string]string{
+		{"NAME": "Texas", "B19013_001E": "66963", "state": "48"},
+	})
+
+	assert.Equal(t, []JSONColumn{
+		{Name: "B19013_001E", Type: JSONColumnNumber, Label: "Median household income"},
+		{Name: "NAME", Type: JSONColumnString},
+		{Name: "state", Type: JSONColumnGeography},
+	}, doc.Columns)
+}
@@ -0,0 +1,397 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_years    = "years"
+	key_variable = "variable"
+)
+
+// maxComparisonYears - максимальное количество выпусков в одном сравнении
+const maxComparisonYears = 20
+
+// YearComparisonRequest представляет запрос сравнения переменных между выпусками данных
+type YearComparisonRequest struct {
+	Variables []string          // Список сравниваемых переменных
+	Dataset   string            // Набор данных (например, "acs/acs5")
+	Years     []string          // Список выпусков (упорядочивается по году)
+	GeoLevel  string            // Географический уровень
+	GeoFilter map[string]string // Фильтр географии
+	// InflationYear - год, в доллары которого пересчитываются долларовые переменные (пусто - без пересчета)
+	InflationYear string
+	// InflationSeries - индекс цен для пересчета (по умолчанию CPISeriesURS)
+	InflationSeries string
+}
+
+// ValueChange описывает изменение значения переменной между первым и последним доступными выпусками
+type ValueChange struct {
+	FromYear string  `json:"from_year"`
+	ToYear   string  `json:"to_year"`
+	Absolute float64 `json:"absolute"`
+	Percent  float64 `json:"percent"`
+	// HasPercent равен false, если исходное значение равно нулю
+	HasPercent bool `json:"has_percent"`
+}
+
+// YearComparisonRow содержит значения переменных одной географии по выпускам
+type YearComparisonRow struct {
+	GeoID string `json:"geoid"`
+	Name  string `json:"name"`
+	// Values - значения в виде переменная -> год -> значение
+	Values map[string]map[string]string `json:"values"`
+	// Changes - изменения в виде переменная -> изменение (только для числовых значений)
+	Changes map[string]ValueChange `json:"changes"`
+}
+
+// VariableDefinitionChange описывает изменение подписи или концепции переменной между выпусками
+type VariableDefinitionChange struct {
+	Variable string `json:"variable"`
+	FromYear string `json:"from_year"`
+	ToYear   string `json:"to_year"`
+	// From и To - определения переменной в выпусках; пустая строка означает, что переменной
+	// в выпуске нет
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// YearComparison содержит результат сравнения переменных между выпусками
+type YearComparison struct {
+	Dataset           string                     `json:"dataset"`
+	Years             []string                   `json:"years"`
+	Variables         []string                   `json:"variables"`
+	Rows              []YearComparisonRow        `json:"rows"`
+	DefinitionChanges []VariableDefinitionChange `json:"definition_changes,omitempty"`
+	// MissingYears - выпуски, данные за которые получить не удалось, с причиной
+	MissingYears map[string]error `json:"missing_years,omitempty"`
+	// Inflation - сведения о пересчете долларовых переменных в постоянные доллары
+	Inflation *InflationAdjustment `json:"inflation,omitempty"`
+}
+
+// YearRange возвращает список лет от start до end включительно
+func YearRange(start, end string) ([]string, error) {
+	from, err := strconv.Atoi(start)
+	if err != nil {
+		return nil, i18n.Errorf("некорректный начальный год: %q", start)
+	}
+
+	to, err := strconv.Atoi(end)
+	if err != nil {
+		return nil, i18n.Errorf("некорректный конечный год: %q", end)
+	}
+
+	if to < from {
+		return nil, i18n.Errorf("конечный год %d меньше начального %d", to, from)
+	}
+
+	if to-from+1 > maxComparisonYears {
+		return nil, i18n.Errorf("слишком большой диапазон лет: не более %d выпусков", maxComparisonYears)
+	}
+
+	years := make([]string, 0, to-from+1)
+	for y := from; y <= to; y++ {
+		years = append(years, strconv.Itoa(y))
+	}
+
+	return years, nil
+}
+
+// CompareYears запрашивает переменные за несколько выпусков, выравнивает строки по GEOID,
+// вычисляет абсолютное и относительное изменение и отмечает переменные, определение
+// которых менялось между выпусками
+func CompareYears(api CensusAPIClient, request YearComparisonRequest) (*YearComparison, error) {
+	slog.Info("Сравнение переменных между выпусками",
+		key_dataset, request.Dataset,
+		key_years, request.Years)
+
+	if len(request.Variables) == 0 {
+		return nil, i18n.Errorf("необходимо указать хотя бы одну переменную")
+	}
+
+	if len(request.Years) < 2 {
+		return nil, i18n.Errorf("для сравнения необходимо указать не менее двух лет")
+	}
+
+	if len(request.Years) > maxComparisonYears {
+		return nil, i18n.Errorf("слишком много выпусков: не более %d", maxComparisonYears)
+	}
+
+	years, err := sortYears(request.Years)
+	if err != nil {
+		return nil, err
+	}
+
+	// Строки выпусков сопоставляются по GEOID; для уровня, столбец которого не входит в GEOID,
+	// все географии получили бы один идентификатор и были бы объединены
+	if !IsGeographyColumn(request.GeoLevel) {
+		return nil, i18n.Errorf("географический уровень %q не поддерживается для сравнения выпусков", request.GeoLevel)
+	}
+
+	// NAME запрашиваем всегда, чтобы подписать строки
+	compared := withoutName(request.Variables)
+	if len(compared) == 0 {
+		return nil, i18n.Errorf("необходимо указать хотя бы одну переменную помимо NAME")
+	}
+	variables := append([]string{"NAME"}, compared...)
+
+	comparison := &YearComparison{
+		Dataset:      request.Dataset,
+		Variables:    compared,
+		MissingYears: make(map[string]error),
+	}
+
+	if request.InflationYear != "" {
+		adjustment, err := prepareInflationAdjustment(api, request, compared)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Inflation = adjustment
+	}
+
+	rowsByGeoID := make(map[string]*YearComparisonRow)
+	var order []string
+
+	for _, year := range years {
+		data, err := api.GetCustomData(CustomDataRequest{
+			Variables: variables,
+			Dataset:   request.Dataset,
+			Year:      year,
+			GeoLevel:  request.GeoLevel,
+			GeoFilter: request.GeoFilter,
+		})
+		if err != nil {
+			slog.Warn("Не удалось получить данные за год",
+				key_year, year,
+				key_err, err)
+			comparison.MissingYears[year] = err
+			continue
+		}
+
+		if comparison.Inflation != nil && len(comparison.Inflation.Variables) > 0 {
+			factor, err := AdjustRowsForInflation(data, comparison.Inflation.Variables,
+				comparison.Inflation.Series, year, comparison.Inflation.TargetYear)
+			if err != nil {
+				slog.Warn("Не удалось пересчитать данные за год в постоянные доллары",
+					key_year, year,
+					key_err, err)
+				comparison.MissingYears[year] = err
+				continue
+			}
+			comparison.Inflation.Factors[year] = factor
+		}
+
+		comparison.Years = append(comparison.Years, year)
+
+		for _, item := range data {
+			geoID := GeoID(item)
+			row, ok := rowsByGeoID[geoID]
+			if !ok {
+				row = &YearComparisonRow{
+					GeoID:   geoID,
+					Values:  make(map[string]map[string]string),
+					Changes: make(map[string]ValueChange),
+				}
+				rowsByGeoID[geoID] = row
+				order = append(order, geoID)
+			}
+
+			// Используем название из последнего выпуска
+			if name := item["NAME"]; name != "" {
+				row.Name = name
+			}
+
+			for _, variable := range comparison.Variables {
+				if _, ok := row.Values[variable]; !ok {
+					row.Values[variable] = make(map[string]string)
+				}
+				if value, ok := item[variable]; ok {
+					row.Values[variable][year] = value
+				}
+			}
+		}
+	}
+
+	if len(comparison.Years) == 0 {
+		return nil, i18n.Errorf("не удалось получить данные ни за один из запрошенных годов")
+	}
+
+	sort.Strings(order)
+	for _, geoID := range order {
+		row := rowsByGeoID[geoID]
+		for _, variable := range comparison.Variables {
+			if change, ok := computeChange(row.Values[variable], comparison.Years); ok {
+				row.Changes[variable] = change
+			}
+		}
+		comparison.Rows = append(comparison.Rows, *row)
+	}
+
+	comparison.DefinitionChanges = detectDefinitionChanges(api, request.Dataset, comparison.Years, comparison.Variables)
+
+	return comparison, nil
+}
+
+// sortYears возвращает копию списка выпусков, упорядоченную по возрастанию года
+func sortYears(years []string) ([]string, error) {
+	numbers := make(map[string]int, len(years))
+	for _, year := range years {
+		number, err := strconv.Atoi(year)
+		if err != nil {
+			return nil, i18n.Errorf("некорректный год: %q", year)
+		}
+		numbers[year] = number
+	}
+
+	sorted := append([]string(nil), years...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return numbers[sorted[i]] < numbers[sorted[j]]
+	})
+	return sorted, nil
+}
+
+// prepareInflationAdjustment проверяет параметры пересчета в постоянные доллары и определяет
+// долларовые переменные по описаниям первого выпуска, для которого они доступны
+func prepareInflationAdjustment(api CensusAPIClient, request YearComparisonRequest, variables []string) (*InflationAdjustment, error) {
+	series := request.InflationSeries
+	if series == "" {
+		series = CPISeriesURS
+	}
+
+	if _, err := CPIIndex(series, request.InflationYear); err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, year := range request.Years {
+		dollars, err := DollarVariables(api, request.Dataset, year, variables)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return &InflationAdjustment{
+			Series:     series,
+			TargetYear: request.InflationYear,
+			Variables:  dollars,
+			Factors:    make(map[string]float64),
+		}, nil
+	}
+
+	return nil, i18n.Errorf("не удалось определить долларовые переменные: %w", lastErr)
+}
+
+// computeChange вычисляет изменение между первым и последним выпусками с числовым значением
+func computeChange(values map[string]string, years []string) (ValueChange, bool) {
+	var first, last string
+	var firstValue, lastValue float64
+
+	for _, year := range years {
+		number, ok := ParseEstimate(values[year])
+		if !ok {
+			continue
+		}
+		if first == "" {
+			first, firstValue = year, number
+		}
+		last, lastValue = year, number
+	}
+
+	if first == "" || first == last {
+		return ValueChange{}, false
+	}
+
+	change := ValueChange{
+		FromYear: first,
+		ToYear:   last,
+		Absolute: lastValue - firstValue,
+	}
+
+	if firstValue != 0 {
+		change.Percent = (lastValue - firstValue) / firstValue * 100
+		change.HasPercent = true
+	}
+
+	return change, true
+}
+
+// detectDefinitionChanges сравнивает подписи и концепции переменных между соседними выпусками
+func detectDefinitionChanges(api CensusAPIClient, dataset string, years []string, variables []string) []VariableDefinitionChange {
+	var changes []VariableDefinitionChange
+
+	var previous map[string]VariableInfo
+	var previousYear string
+
+	for _, year := range years {
+		current, err := api.GetVariables(dataset, year)
+		if err != nil {
+			slog.Warn("Не удалось получить определения переменных за год",
+				key_year, year,
+				key_err, err)
+			continue
+		}
+
+		if previous != nil {
+			for _, variable := range variables {
+				before, hadBefore := previous[variable]
+				after, hasAfter := current[variable]
+
+				switch {
+				case hadBefore && !hasAfter:
+					changes = append(changes, VariableDefinitionChange{
+						Variable: variable, FromYear: previousYear, ToYear: year,
+						From: definitionText(before),
+					})
+				case !hadBefore && hasAfter:
+					changes = append(changes, VariableDefinitionChange{
+						Variable: variable, FromYear: previousYear, ToYear: year,
+						To: definitionText(after),
+					})
+				case hadBefore && hasAfter && normalizeDefinition(before) != normalizeDefinition(after):
+					slog.Debug("Определение переменной изменилось",
+						key_variable, variable,
+						key_year, year)
+					changes = append(changes, VariableDefinitionChange{
+						Variable: variable, FromYear: previousYear, ToYear: year,
+						From: definitionText(before), To: definitionText(after),
+					})
+				}
+			}
+		}
+
+		previous = current
+		previousYear = year
+	}
+
+	return changes
+}
+
+// definitionText возвращает подпись и концепцию переменной для сравнения между выпусками
+func definitionText(info VariableInfo) string {
+	if info.Concept == "" {
+		return info.Label
+	}
+	return info.Label + " (" + info.Concept + ")"
+}
+
+// normalizeDefinition приводит определение переменной к виду, не зависящему от оформления:
+// в разных выпусках ACS подписи отличаются регистром и двоеточиями ("Total:" и "Total")
+func normalizeDefinition(info VariableInfo) string {
+	return strings.ToLower(strings.ReplaceAll(definitionText(info), ":", ""))
+}
+
+// withoutName возвращает список переменных без NAME
+func withoutName(variables []string) []string {
+	result := make([]string, 0, len(variables))
+	for _, variable := range variables {
+		if variable != "NAME" {
+			result = append(result, variable)
+		}
+	}
+	return result
+}
@@ -0,0 +1,185 @@
+package census
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// yearStubAPI - тестовый клиент, возвращающий разные данные и определения по годам
+type yearStubAPI struct {
+	*MockCensusAPI
+	data      map[string][]map[string]string
+	variables map[string]map[string]VariableInfo
+}
+
+func (s *yearStubAPI) GetCustomData(request CustomDataRequest) ([]map[string]string, error) {
+	data, ok := s.data[request.Year]
+	if !ok {
+		return nil, errors.New("выпуск недоступен")
+	}
+	return data, nil
+}
+
+func (s *yearStubAPI) GetVariables(dataset, year string) (map[string]VariableInfo, error) {
+	return s.variables[year], nil
+}
+
+func TestYearRange(t *testing.T) {
+	years, err := YearRange("2019", "2021")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2019", "2020", "2021"}, years)
+
+	_, err = YearRange("2021", "2019")
+	assert.Error(t, err)
+
+	_, err = YearRange("2000", "2030")
+	assert.Error(t, err)
+
+	_, err = YearRange("год", "2021")
+	assert.Error(t, err)
+}
+
+func TestCompareYears(t *testing.T) {
+	api := &yearStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		data: map[string][]map[string]string{
+			"2019": {
+				{"NAME": "Texas", "B01001_001E": "28995881", "B19013_001E": "61874", "state": "48"},
+				{"NAME": "California", "B01001_001E": "39512223", "B19013_001E": "-666666666", "state": "06"},
+			},
+			"2021": {
+				{"NAME": "California", "B01001_001E": "39237836", "B19013_001E": "84907", "state": "06"},
+				{"NAME": "Texas", "B01001_001E": "29527941", "B19013_001E": "66963", "state": "48"},
+			},
+		},
+		variables: map[string]map[string]VariableInfo{
+			"2019": {
+				"B01001_001E": {Label: "Estimate!!Total", Concept: "SEX BY AGE"},
+				"B19013_001E": {Label: "Estimate!!Median household income", Concept: "MEDIAN HOUSEHOLD INCOME (IN 2019 DOLLARS)"},
+			},
+			"2021": {
+				"B01001_001E": {Label: "Estimate!!Total:", Concept: "SEX BY AGE"},
+				"B19013_001E": {Label: "Estimate!!Median household income", Concept: "MEDIAN HOUSEHOLD INCOME (IN 2021 DOLLARS)"},
+			},
+		},
+	}
+
+	comparison, err := CompareYears(api, YearComparisonRequest{
+		Variables: []string{"NAME", "B01001_001E", "B19013_001E"},
+		Dataset:   "acs/acs1",
+		Years:     []string{"2019", "2020", "2021"},
+		GeoLevel:  "state",
+		GeoFilter: map[string]string{"state": "*"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"2019", "2021"}, comparison.Years)
+	assert.Equal(t, []string{"B01001_001E", "B19013_001E"}, comparison.Variables)
+	assert.Contains(t, comparison.MissingYears, "2020")
+
+	// Строки выровнены по GEOID и отсортированы
+	assert.Len(t, comparison.Rows, 2)
+	assert.Equal(t, "06", comparison.Rows[0].GeoID)
+	assert.Equal(t, "California", comparison.Rows[0].Name)
+
+	change := comparison.Rows[1].Changes["B01001_001E"]
+	assert.Equal(t, float64(532060), change.Absolute)
+	assert.InDelta(t, 1.835, change.Percent, 0.001)
+	assert.True(t, change.HasPercent)
+
+	// Подавленное значение не участвует в расчете изменения
+	_, ok := comparison.Rows[0].Changes["B19013_001E"]
+	assert.False(t, ok)
+
+	// Изменение оформления подписи не считается изменением определения
+	assert.Len(t, comparison.DefinitionChanges, 1)
+	assert.Equal(t, "B19013_001E", comparison.DefinitionChanges[0].Variable)
+
+	result := NewTextFormatter().Format(context.Background(), comparison)
+	for _, str := range []string{
+		"# Сравнение выпусков acs/acs1: 2019, 2021",
+		"| 48 | Texas | 28\u00a0995\u00a0881 | 29\u00a0527\u00a0941 | 532\u00a0060 | 1,83\u00a0% |",
+		"Изменения определений переменных",
+		"Недоступные выпуски",
+	} {
+		assert.Contains(t, result, str)
+	}
+}
+
+func TestCompareYears_PUMAUnsortedYears(t *testing.T) {
+	api := &yearStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		data: map[string][]map[string]string{
+			"2019": {
+				{"NAME": "PUMA 03701", "B01001_001E": "100000", "state": "06", "public use microdata area": "03701"},
+				{"NAME": "PUMA 03702", "B01001_001E": "120000", "state": "06", "public use microdata area": "03702"},
+			},
+			"2021": {
+				{"NAME": "PUMA 03701", "B01001_001E": "110000", "state": "06", "public use microdata area": "03701"},
+				{"NAME": "PUMA 03702", "B01001_001E": "115000", "state": "06", "public use microdata area": "03702"},
+			},
+		},
+	}
+
+	comparison, err := CompareYears(api, YearComparisonRequest{
+		Variables: []string{"B01001_001E"},
+		Dataset:   "acs/acs1",
+		Years:     []string{"2021", "2019"},
+		GeoLevel:  "public use microdata area",
+		GeoFilter: map[string]string{"state": "06"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"2019", "2021"}, comparison.Years)
+	require.Len(t, comparison.Rows, 2)
+	assert.Equal(t, "0603701", comparison.Rows[0].GeoID)
+	assert.Equal(t, float64(10000), comparison.Rows[0].Changes["B01001_001E"].Absolute)
+	assert.Equal(t, "2019", comparison.Rows[0].Changes["B01001_001E"].FromYear)
+	assert.Equal(t, float64(-5000), comparison.Rows[1].Changes["B01001_001E"].Absolute)
+}
+
+func TestCompareYears_Errors(t *testing.T) {
+	api := NewMockCensusAPI()
+
+	_, err := CompareYears(api, YearComparisonRequest{Variables: []string{"B01001_001E"}, Years: []string{"2021"}})
+	assert.Error(t, err)
+
+	_, err = CompareYears(api, YearComparisonRequest{Variables: []string{"NAME"}, Years: []string{"2019", "2021"}})
+	assert.Error(t, err)
+
+	_, err = CompareYears(api, YearComparisonRequest{Variables: []string{"B01001_001E"}, Years: []string{"2019", "двадцать"}, GeoLevel: "state"})
+	assert.ErrorContains(t, err, "некорректный год")
+
+	// Уровень, не входящий в GEOID, объединил бы все географии в одну строку
+	_, err = CompareYears(api, YearComparisonRequest{Variables: []string{"B01001_001E"}, Years: []string{"2019", "2021"}, GeoLevel: "voting district"})
+	assert.ErrorContains(t, err, "не поддерживается")
+}
+
+func TestGeoID(t *testing.T) {
+	assert.Equal(t, "06037", GeoID(map[string]string{"NAME": "Los Angeles County", "state": "06", "county": "037"}))
+	assert.Equal(t, "06037101110", GeoID(map[string]string{"tract": "101110", "county": "037", "state": "06"}))
+	assert.Equal(t, "94110", GeoID(map[string]string{GeoLevelZCTA: "94110"}))
+	assert.Equal(t, "0603701", GeoID(map[string]string{"state": "06", "public use microdata area": "03701"}))
+	assert.Equal(t, "0622710", GeoID(map[string]string{"state": "06", "school district (unified)": "22710"}))
+	assert.Equal(t, "3562035614", GeoID(map[string]string{GeoLevelCBSA: "35620", "metropolitan division": "35614"}))
+	assert.Equal(t, "51445", GeoID(map[string]string{"urban area": "51445"}))
+}
+
+func TestParseEstimate(t *testing.T) {
+	value, ok := ParseEstimate("39538223")
+	assert.True(t, ok)
+	assert.Equal(t, float64(39538223), value)
+
+	value, ok = ParseEstimate(" 12.5 ")
+	assert.True(t, ok)
+	assert.Equal(t, 12.5, value)
+
+	for _, invalid := range []string{"", "-666666666", "-999999999", "N/A", "NaN"} {
+		_, ok = ParseEstimate(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
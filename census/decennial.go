@@ -0,0 +1,519 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_groups         = "groups"
+	key_base_year      = "base_year"
+	key_target_dataset = "target_dataset"
+)
+
+// Группы показателей переписи
+const (
+	DecennialGroupRace      = "race"
+	DecennialGroupEthnicity = "ethnicity"
+	DecennialGroupHousing   = "housing"
+)
+
+// Наборы данных переписи по умолчанию
+const (
+	DefaultDecennialDataset = "dec/pl"
+	DefaultDecennialYear    = "2020"
+)
+
+// DecennialGroups - поддерживаемые группы показателей в порядке вывода
+var DecennialGroups = []string{DecennialGroupRace, DecennialGroupEthnicity, DecennialGroupHousing}
+
+// DecennialMeasure описывает показатель переписи, не зависящий от структуры таблиц выпуска
+type DecennialMeasure struct {
+	Key    string   `json:"key"`
+	Label  string   `json:"label"`
+	Groups []string `json:"-"`
+	// Universe - показатель-знаменатель для расчета доли; пустой у самих знаменателей
+	Universe string `json:"-"`
+}
+
+// DecennialMeasures - показатели P.L. 94-171 и DHC в порядке вывода
+var DecennialMeasures = []DecennialMeasure{
+	{Key: "total_population", Label: "Все население", Groups: []string{DecennialGroupRace, DecennialGroupEthnicity}},
+	{Key: "white", Label: "Только белые", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "black", Label: "Только черные или афроамериканцы", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "aian", Label: "Только индейцы и коренные жители Аляски", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "asian", Label: "Только азиаты", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "nhpi", Label: "Только коренные гавайцы и жители островов Тихого океана", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "other_race", Label: "Только другая раса", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "two_or_more", Label: "Две расы и более", Groups: []string{DecennialGroupRace}, Universe: "total_population"},
+	{Key: "hispanic", Label: "Испаноязычные или латиноамериканцы", Groups: []string{DecennialGroupEthnicity}, Universe: "total_population"},
+	{Key: "not_hispanic", Label: "Не испаноязычные", Groups: []string{DecennialGroupEthnicity}, Universe: "total_population"},
+	{Key: "nh_white", Label: "Только белые, не испаноязычные", Groups: []string{DecennialGroupEthnicity}, Universe: "total_population"},
+	{Key: "housing_units", Label: "Всего жилых единиц", Groups: []string{DecennialGroupHousing}},
+	{Key: "occupied", Label: "Занятые жилые единицы", Groups: []string{DecennialGroupHousing}, Universe: "housing_units"},
+	{Key: "vacant", Label: "Пустующие жилые единицы", Groups: []string{DecennialGroupHousing}, Universe: "housing_units"},
+}
+
+// DecennialSchema связывает показатели с переменными конкретного выпуска переписи.
+// В 2010 году переменные имеют вид P001001, в 2020 - P1_001N; в DHC и SF1 раса,
+// испаноязычное происхождение и занятость жилья находятся в других таблицах, чем в P.L. 94-171.
+type DecennialSchema struct {
+	Dataset   string
+	Year      string
+	Title     string
+	Variables map[string]string
+}
+
+// decennialSchemas - поддерживаемые выпуски переписи
+var decennialSchemas = []DecennialSchema{
+	{
+		Dataset: "dec/pl",
+		Year:    "2020",
+		Title:   "Перепись 2020, данные для перераспределения округов (P.L. 94-171)",
+		Variables: map[string]string{
+			"total_population": "P1_001N",
+			"white":            "P1_003N",
+			"black":            "P1_004N",
+			"aian":             "P1_005N",
+			"asian":            "P1_006N",
+			"nhpi":             "P1_007N",
+			"other_race":       "P1_008N",
+			"two_or_more":      "P1_009N",
+			"hispanic":         "P2_002N",
+			"not_hispanic":     "P2_003N",
+			"nh_white":         "P2_005N",
+			"housing_units":    "H1_001N",
+			"occupied":         "H1_002N",
+			"vacant":           "H1_003N",
+		},
+	},
+	{
+		Dataset: "dec/dhc",
+		Year:    "2020",
+		Title:   "Перепись 2020, демографические и жилищные характеристики (DHC)",
+		Variables: map[string]string{
+			"total_population": "P1_001N",
+			"white":            "P3_002N",
+			"black":            "P3_003N",
+			"aian":             "P3_004N",
+			"asian":            "P3_005N",
+			"nhpi":             "P3_006N",
+			"other_race":       "P3_007N",
+			"two_or_more":      "P3_008N",
+			"hispanic":         "P4_003N",
+			"not_hispanic":     "P4_002N",
+			"nh_white":         "P5_003N",
+			"housing_units":    "H1_001N",
+			"occupied":         "H3_002N",
+			"vacant":           "H3_003N",
+		},
+	},
+	{
+		Dataset: "dec/pl",
+		Year:    "2010",
+		Title:   "Перепись 2010, данные для перераспределения округов (P.L. 94-171)",
+		Variables: map[string]string{
+			"total_population": "P001001",
+			"white":            "P001003",
+			"black":            "P001004",
+			"aian":             "P001005",
+			"asian":            "P001006",
+			"nhpi":             "P001007",
+			"other_race":       "P001008",
+			"two_or_more":      "P001009",
+			"hispanic":         "P002002",
+			"not_hispanic":     "P002003",
+			"nh_white":         "P002005",
+			"housing_units":    "H001001",
+			"occupied":         "H001002",
+			"vacant":           "H001003",
+		},
+	},
+	{
+		Dataset: "dec/sf1",
+		Year:    "2010",
+		Title:   "Перепись 2010, Summary File 1",
+		Variables: map[string]string{
+			"total_population": "P001001",
+			"white":            "P003002",
+			"black":            "P003003",
+			"aian":             "P003004",
+			"asian":            "P003005",
+			"nhpi":             "P003006",
+			"other_race":       "P003007",
+			"two_or_more":      "P003008",
+			"hispanic":         "P004003",
+			"not_hispanic":     "P004002",
+			"nh_white":         "P005003",
+			"housing_units":    "H001001",
+			"occupied":         "H003002",
+			"vacant":           "H003003",
+		},
+	},
+}
+
+// IsDecennialDataset сообщает, относится ли набор данных к десятилетней переписи.
+// Данные переписи являются полным счетом и не имеют MOE.
+func IsDecennialDataset(dataset string) bool {
+	return strings.HasPrefix(dataset, "dec/")
+}
+
+// LookupDecennialSchema возвращает схему переменных выпуска переписи
+func LookupDecennialSchema(dataset, year string) (DecennialSchema, error) {
+	supported := make([]string, 0, len(decennialSchemas))
+	for _, schema := range decennialSchemas {
+		if schema.Dataset == dataset && schema.Year == year {
+			return schema, nil
+		}
+		supported = append(supported, schema.Dataset+" "+schema.Year)
+	}
+
+	return DecennialSchema{}, i18n.Errorf("выпуск переписи %s %s не поддерживается; доступны: %s", dataset, year, strings.Join(supported, ", "))
+}
+
+// SelectDecennialMeasures возвращает показатели выбранных групп в порядке вывода.
+// Пустой список групп означает все группы.
+func SelectDecennialMeasures(groups []string) ([]DecennialMeasure, error) {
+	selected := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		group = strings.ToLower(strings.TrimSpace(group))
+		if !containsString(DecennialGroups, group) {
+			return nil, i18n.Errorf("неизвестная группа показателей %q; доступны: %s", group, strings.Join(DecennialGroups, ", "))
+		}
+		selected[group] = true
+	}
+
+	var measures []DecennialMeasure
+	for _, measure := range DecennialMeasures {
+		for _, group := range measure.Groups {
+			if len(selected) == 0 || selected[group] {
+				measures = append(measures, measure)
+				break
+			}
+		}
+	}
+	return measures, nil
+}
+
+// decennialGeoFilter дополняет фильтр географии символом "*" для запрашиваемого уровня и проверяет,
+// что для кварталов указаны штат и округ, без которых Census API не возвращает данные
+func decennialGeoFilter(geoLevel string, geoFilter map[string]string) (map[string]string, error) {
+	filter := WildcardGeoFilter(geoLevel, geoFilter)
+
+	if geoLevel == "block" {
+		for _, parent := range []string{"state", "county"} {
+			if code := filter[parent]; code == "" || code == "*" {
+				return nil, i18n.Errorf("для уровня block в фильтре географии необходимо указать конкретный код %s", parent)
+			}
+		}
+	}
+
+	return filter, nil
+}
+
+// DecennialRequest представляет запрос показателей переписи
+type DecennialRequest struct {
+	Dataset   string            // Набор данных ("dec/pl", "dec/dhc" или "dec/sf1")
+	Year      string            // Год переписи
+	GeoLevel  string            // Географический уровень, вплоть до "block"
+	GeoFilter map[string]string // Фильтр географии
+	Groups    []string          // Группы показателей (race, ethnicity, housing); пусто - все
+}
+
+// DecennialValue содержит значение показателя для одной географии
+type DecennialValue struct {
+	Key       string  `json:"key"`
+	Label     string  `json:"label"`
+	Variable  string  `json:"variable"`
+	Value     float64 `json:"value"`
+	Available bool    `json:"available"`
+	// Share - доля от знаменателя показателя в процентах
+	Share    float64 `json:"share"`
+	HasShare bool    `json:"has_share"`
+}
+
+// DecennialRow содержит показатели переписи для одной географии
+type DecennialRow struct {
+	GeoID  string           `json:"geoid"`
+	Name   string           `json:"name"`
+	Values []DecennialValue `json:"values"`
+}
+
+// DecennialResult содержит показатели переписи по географиям
+type DecennialResult struct {
+	Dataset  string         `json:"dataset"`
+	Year     string         `json:"year"`
+	Title    string         `json:"title"`
+	GeoLevel string         `json:"geo_level"`
+	Rows     []DecennialRow `json:"rows"`
+}
+
+// GetDecennialCounts запрашивает показатели расы, испаноязычного происхождения и занятости жилья
+// из выпуска переписи, сопоставляя их с переменными выпуска, и рассчитывает доли
+func GetDecennialCounts(api CensusAPIClient, request DecennialRequest) (*DecennialResult, error) {
+	slog.Info("Получение показателей переписи",
+		key_dataset, request.Dataset,
+		key_year, request.Year,
+		key_geo_level, request.GeoLevel,
+		key_groups, request.Groups)
+
+	schema, err := LookupDecennialSchema(request.Dataset, request.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	measures, err := SelectDecennialMeasures(request.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := fetchDecennialRows(api, schema, measures, request.GeoLevel, request.GeoFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DecennialResult{
+		Dataset:  schema.Dataset,
+		Year:     schema.Year,
+		Title:    schema.Title,
+		GeoLevel: request.GeoLevel,
+	}
+
+	for _, row := range data {
+		result.Rows = append(result.Rows, DecennialRow{
+			GeoID:  GeoID(row),
+			Name:   row["NAME"],
+			Values: decennialValues(schema, measures, row),
+		})
+	}
+
+	sort.Slice(result.Rows, func(i, j int) bool {
+		return result.Rows[i].GeoID < result.Rows[j].GeoID
+	})
+
+	return result, nil
+}
+
+// fetchDecennialRows запрашивает переменные выпуска для показателей
+func fetchDecennialRows(api CensusAPIClient, schema DecennialSchema, measures []DecennialMeasure, geoLevel string, geoFilter map[string]string) ([]map[string]string, error) {
+	filter, err := decennialGeoFilter(geoLevel, geoFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := []string{"NAME"}
+	for _, measure := range measures {
+		variables = append(variables, schema.Variables[measure.Key])
+	}
+
+	return api.GetCustomData(CustomDataRequest{
+		Variables: variables,
+		Dataset:   schema.Dataset,
+		Year:      schema.Year,
+		GeoLevel:  geoLevel,
+		GeoFilter: filter,
+	})
+}
+
+// decennialValues извлекает значения показателей из строки ответа и рассчитывает доли
+func decennialValues(schema DecennialSchema, measures []DecennialMeasure, row map[string]string) []DecennialValue {
+	values := make([]DecennialValue, 0, len(measures))
+	for _, measure := range measures {
+		variable := schema.Variables[measure.Key]
+		value, ok := ParseEstimate(row[variable])
+		item := DecennialValue{
+			Key:       measure.Key,
+			Label:     measure.Label,
+			Variable:  variable,
+			Value:     value,
+			Available: ok,
+		}
+
+		if ok && measure.Universe != "" {
+			universe, hasUniverse := ParseEstimate(row[schema.Variables[measure.Universe]])
+			if hasUniverse && universe > 0 {
+				item.Share = value / universe * 100
+				item.HasShare = true
+			}
+		}
+
+		values = append(values, item)
+	}
+	return values
+}
+
+// DecennialChangeRequest представляет запрос изменения показателей между двумя переписями
+type DecennialChangeRequest struct {
+	GeoLevel      string            // Географический уровень
+	GeoFilter     map[string]string // Фильтр географии
+	Groups        []string          // Группы показателей; пусто - все
+	BaseDataset   string            // Набор данных базовой переписи (по умолчанию "dec/pl")
+	BaseYear      string            // Год базовой переписи (по умолчанию "2010")
+	TargetDataset string            // Набор данных сравниваемой переписи (по умолчанию "dec/pl")
+	TargetYear    string            // Год сравниваемой переписи (по умолчанию "2020")
+}
+
+// DecennialChangeValue содержит изменение показателя между переписями
+type DecennialChangeValue struct {
+	Key            string  `json:"key"`
+	Label          string  `json:"label"`
+	BaseVariable   string  `json:"base_variable"`
+	TargetVariable string  `json:"target_variable"`
+	Base           float64 `json:"base"`
+	Target         float64 `json:"target"`
+	Change         float64 `json:"change"`
+	PercentChange  float64 `json:"percent_change"`
+	// HasChange - оба значения доступны
+	HasChange        bool `json:"has_change"`
+	HasPercentChange bool `json:"has_percent_change"`
+}
+
+// DecennialChangeRow содержит изменения показателей для одной географии
+type DecennialChangeRow struct {
+	GeoID  string                 `json:"geoid"`
+	Name   string                 `json:"name"`
+	Values []DecennialChangeValue `json:"values"`
+}
+
+// DecennialChangeResult содержит изменения показателей между двумя переписями
+type DecennialChangeResult struct {
+	BaseDataset   string               `json:"base_dataset"`
+	BaseYear      string               `json:"base_year"`
+	TargetDataset string               `json:"target_dataset"`
+	TargetYear    string               `json:"target_year"`
+	GeoLevel      string               `json:"geo_level"`
+	Rows          []DecennialChangeRow `json:"rows"`
+	// OnlyInBase и OnlyInTarget - географии, GEOID которых есть только в одной из переписей
+	OnlyInBase   []string `json:"only_in_base,omitempty"`
+	OnlyInTarget []string `json:"only_in_target,omitempty"`
+	Warnings     []error  `json:"warnings,omitempty"`
+}
+
+// decennialUnstableLevels - уровни, границы которых пересматриваются к каждой переписи
+var decennialUnstableLevels = map[string]bool{
+	"tract":       true,
+	"block group": true,
+	"block":       true,
+}
+
+// CompareDecennial сравнивает показатели двух переписей (по умолчанию 2010 и 2020) для одних и тех
+// же GEOID. Различия в структуре таблиц выпусков скрываются схемами переменных.
+func CompareDecennial(api CensusAPIClient, request DecennialChangeRequest) (*DecennialChangeResult, error) {
+	baseDataset, baseYear := request.BaseDataset, request.BaseYear
+	if baseDataset == "" {
+		baseDataset = DefaultDecennialDataset
+	}
+	if baseYear == "" {
+		baseYear = "2010"
+	}
+	targetDataset, targetYear := request.TargetDataset, request.TargetYear
+	if targetDataset == "" {
+		targetDataset = DefaultDecennialDataset
+	}
+	if targetYear == "" {
+		targetYear = DefaultDecennialYear
+	}
+
+	slog.Info("Сравнение показателей переписей",
+		key_dataset, baseDataset,
+		key_base_year, baseYear,
+		key_target_dataset, targetDataset,
+		key_target_year, targetYear,
+		key_geo_level, request.GeoLevel)
+
+	baseSchema, err := LookupDecennialSchema(baseDataset, baseYear)
+	if err != nil {
+		return nil, err
+	}
+	targetSchema, err := LookupDecennialSchema(targetDataset, targetYear)
+	if err != nil {
+		return nil, err
+	}
+
+	measures, err := SelectDecennialMeasures(request.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	baseRows, err := fetchDecennialRows(api, baseSchema, measures, request.GeoLevel, request.GeoFilter)
+	if err != nil {
+		return nil, i18n.Errorf("перепись %s: %w", baseYear, err)
+	}
+	targetRows, err := fetchDecennialRows(api, targetSchema, measures, request.GeoLevel, request.GeoFilter)
+	if err != nil {
+		return nil, i18n.Errorf("перепись %s: %w", targetYear, err)
+	}
+
+	result := &DecennialChangeResult{
+		BaseDataset:   baseDataset,
+		BaseYear:      baseYear,
+		TargetDataset: targetDataset,
+		TargetYear:    targetYear,
+		GeoLevel:      request.GeoLevel,
+	}
+
+	if decennialUnstableLevels[request.GeoLevel] {
+		result.Warnings = append(result.Warnings, i18n.Errorf(
+			"Границы уровня %s пересматриваются к каждой переписи: географии сопоставлены по GEOID, "+
+				"и одинаковый GEOID не гарантирует одинаковую территорию. Для точного сравнения используйте файлы соответствия (relationship files) Census Bureau.",
+			request.GeoLevel))
+	}
+
+	base := make(map[string]map[string]string, len(baseRows))
+	for _, row := range baseRows {
+		base[GeoID(row)] = row
+	}
+
+	matched := make(map[string]bool, len(targetRows))
+	for _, targetRow := range targetRows {
+		geoID := GeoID(targetRow)
+		baseRow, ok := base[geoID]
+		if !ok {
+			result.OnlyInTarget = append(result.OnlyInTarget, targetRow["NAME"])
+			continue
+		}
+		matched[geoID] = true
+
+		baseValues := decennialValues(baseSchema, measures, baseRow)
+		targetValues := decennialValues(targetSchema, measures, targetRow)
+
+		row := DecennialChangeRow{GeoID: geoID, Name: targetRow["NAME"]}
+		for i, measure := range measures {
+			value := DecennialChangeValue{
+				Key:            measure.Key,
+				Label:          measure.Label,
+				BaseVariable:   baseValues[i].Variable,
+				TargetVariable: targetValues[i].Variable,
+				Base:           baseValues[i].Value,
+				Target:         targetValues[i].Value,
+				HasChange:      baseValues[i].Available && targetValues[i].Available,
+			}
+			if value.HasChange {
+				value.Change = value.Target - value.Base
+				if value.Base != 0 {
+					value.PercentChange = value.Change / value.Base * 100
+					value.HasPercentChange = true
+				}
+			}
+			row.Values = append(row.Values, value)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+
+	for geoID, row := range base {
+		if !matched[geoID] {
+			result.OnlyInBase = append(result.OnlyInBase, row["NAME"])
+		}
+	}
+	sort.Strings(result.OnlyInBase)
+	sort.Strings(result.OnlyInTarget)
+
+	sort.Slice(result.Rows, func(i, j int) bool {
+		return result.Rows[i].GeoID < result.Rows[j].GeoID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectDecennialMeasures(t *testing.T) {
+	measures, err := SelectDecennialMeasures([]string{"housing"})
+	assert.NoError(t, err)
+	assert.Len(t, measures, 3)
+	assert.Equal(t, "housing_units", measures[0].Key)
+
+	// Общая численность населения входит в обе группы, но выводится один раз
+	measures, err = SelectDecennialMeasures([]string{"race", "ethnicity"})
+	assert.NoError(t, err)
+	assert.Len(t, measures, 11)
+
+	measures, err = SelectDecennialMeasures(nil)
+	assert.NoError(t, err)
+	assert.Len(t, measures, len(DecennialMeasures))
+
+	_, err = SelectDecennialMeasures([]string{"income"})
+	assert.Error(t, err)
+}
+
+func TestLookupDecennialSchema(t *testing.T) {
+	schema, err := LookupDecennialSchema("dec/pl", "2010")
+	assert.NoError(t, err)
+	assert.Equal(t, "P001001", schema.Variables["total_population"])
+
+	// Все схемы содержат переменные для всех показателей
+	for _, schema := range decennialSchemas {
+		for _, measure := range DecennialMeasures {
+			assert.NotEmpty(t, schema.Variables[measure.Key], "%s %s: %s", schema.Dataset, schema.Year, measure.Key)
+		}
+	}
+
+	_, err = LookupDecennialSchema("dec/dhc", "2010")
+	assert.Error(t, err)
+	assert.True(t, IsDecennialDataset("dec/pl"))
+	assert.False(t, IsDecennialDataset("acs/acs5"))
+}
+
+func TestGetDecennialCounts(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{"NAME": "Block 1001", "state": "06", "county": "037", "tract": "101110", "block": "1001", "H1_001N": "40", "H1_002N": "38", "H1_003N": "2"},
+		},
+	}
+
+	result, err := GetDecennialCounts(api, DecennialRequest{
+		Dataset:   "dec/pl",
+		Year:      "2020",
+		GeoLevel:  "block",
+		GeoFilter: map[string]string{"state": "06", "county": "037"},
+		Groups:    []string{"housing"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NAME", "H1_001N", "H1_002N", "H1_003N"}, api.requests[0].Variables)
+	assert.Equal(t, map[string]string{"state": "06", "county": "037", "block": "*"}, api.requests[0].GeoFilter)
+	assert.Equal(t, "060371011101001", result.Rows[0].GeoID)
+	assert.InDelta(t, 95, result.Rows[0].Values[1].Share, 1e-9)
+	assert.False(t, result.Rows[0].Values[0].HasShare)
+
+	// Для кварталов необходимы штат и округ
+	_, err = GetDecennialCounts(api, DecennialRequest{
+		Dataset:   "dec/pl",
+		Year:      "2020",
+		GeoLevel:  "block",
+		GeoFilter: map[string]string{"state": "06"},
+	})
+	assert.Error(t, err)
+}
+
+func TestCompareDecennial(t *testing.T) {
+	api := &yearStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		data: map[string][]map[string]string{
+			"2010": {
+				{"NAME": "Alpha County", "state": "06", "county": "001", "H001001": "100", "H001002": "90", "H001003": "10"},
+				{"NAME": "Old County", "state": "06", "county": "999", "H001001": "5", "H001002": "5", "H001003": "0"},
+			},
+			"2020": {
+				{"NAME": "Alpha County", "state": "06", "county": "001", "H1_001N": "120", "H1_002N": "108", "H1_003N": "12"},
+			},
+		},
+	}
+
+	result, err := CompareDecennial(api, DecennialChangeRequest{
+		GeoLevel:  "county",
+		GeoFilter: map[string]string{"state": "06"},
+		Groups:    []string{"housing"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, result.Rows, 1)
+	assert.Equal(t, []string{"Old County"}, result.OnlyInBase)
+	assert.Empty(t, result.Warnings)
+
+	units := result.Rows[0].Values[0]
+	assert.Equal(t, "H001001", units.BaseVariable)
+	assert.Equal(t, "H1_001N", units.TargetVariable)
+	assert.Equal(t, float64(20), units.Change)
+	assert.InDelta(t, 20, units.PercentChange, 1e-9)
+
+	// Для уровней с пересматриваемыми границами выдается предупреждение
+	result, err = CompareDecennial(api, DecennialChangeRequest{GeoLevel: "tract", Groups: []string{"housing"}})
+	assert.NoError(t, err)
+	assert.Len(t, result.Warnings, 1)
+}
@@ -0,0 +1,475 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DelimitedFormatter форматирует данные Census API в CSV или TSV для вставки в электронные
+// таблицы. Поля, содержащие разделитель, кавычки или перевод строки (например,
+// "Los Angeles County, California"), заключаются в кавычки по RFC 4180. Служебные значения
+// Census API (например, -666666666) выводятся пустыми ячейками.
+type DelimitedFormatter struct {
+	delimiter rune
+	// labelRow - выводить ли после заголовков строку с подписями столбцов
+	labelRow bool
+	// labels - подписи столбцов (например, подписи переменных Census API), дополняющие
+	// и переопределяющие встроенные подписи
+	labels map[string]string
+}
+
+// NewCSVFormatter создает форматтер значений, разделенных запятыми
+func NewCSVFormatter() *DelimitedFormatter {
+	slog.DebugContext(context.Background(), "Создание нового CSV-форматтера")
+	return &DelimitedFormatter{delimiter: ','}
+}
+
+// NewTSVFormatter создает форматтер значений, разделенных табуляцией
+func NewTSVFormatter() *DelimitedFormatter {
+	slog.DebugContext(context.Background(), "Создание нового TSV-форматтера")
+	return &DelimitedFormatter{delimiter: '\t'}
+}
+
+// WithLabelRow возвращает копию форматтера, выводящую после заголовков строку подписей.
+// labels задает подписи столбцов по имени (например, "B01001_001E" -> "Total population");
+// для столбцов без подписи ячейка остается пустой.
+func (f *DelimitedFormatter) WithLabelRow(labels map[string]string) *DelimitedFormatter {
+	copied := make(map[string]string, len(labels))
+	for column, label := range labels {
+		copied[column] = label
+	}
+	return &DelimitedFormatter{
+		delimiter: f.delimiter,
+		labelRow:  true,
+		labels:    copied,
+	}
+}
+
+// delimitedTable - промежуточное табличное представление данных
+type delimitedTable struct {
+	columns []string
+	labels  map[string]string
+	rows    [][]string
+}
+
+// Format форматирует данные Census API в CSV или TSV
+func (f *DelimitedFormatter) Format(ctx context.Context, data interface{}) string {
+	slog.DebugContext(ctx, "Форматирование данных в табличный текст",
+		key_type, reflect.TypeOf(data))
+
+	if data == nil {
+		slog.WarnContext(ctx, "Попытка форматирования nil данных")
+		return i18n.T(ctx, "Нет данных")
+	}
+
+	// Таблицы справочных данных совпадают со столбцами документов JSONFormatter
+	documents := &JSONFormatter{}
+
+	var table *delimitedTable
+	switch v := data.(type) {
+	case []PopulationData:
+		table = tableFromJSONDocument(documents.populationDocument(ctx, v))
+	case []DatasetInfo:
+		table = tableFromJSONDocument(documents.datasetsDocument(ctx, v))
+	case map[string]VariableInfo:
+		table = tableFromJSONDocument(documents.variablesDocument(ctx, v))
+	case []GeographyLevel:
+		table = tableFromJSONDocument(documents.geographyLevelsDocument(ctx, v))
+	case []CBSAInfo:
+		table = tableFromJSONDocument(documents.cbsaDocument(ctx, v))
+	case []NAICSIndustry:
+		table = tableFromJSONDocument(documents.naicsDocument(ctx, v))
+	case []map[string]string:
+		table = customDataTable(ctx, v)
+	case *YearComparison:
+		table = yearComparisonTable(ctx, v)
+	case *AggregationResult:
+		table = aggregationTable(ctx, v)
+	case *QuantileResult:
+		table = quantileTable(ctx, v)
+	case *RankResult:
+		table = rankTable(ctx, v)
+	case *EstimateComparison:
+		table = estimateComparisonTable(ctx, v)
+	case *ProfileResult:
+		table = profileTable(ctx, v)
+	case *DecennialResult:
+		table = decennialTable(ctx, v)
+	case *DecennialChangeResult:
+		table = decennialChangeTable(ctx, v)
+	case *PEPResult:
+		table = pepTable(ctx, v)
+	case *BusinessResult:
+		table = businessTable(ctx, v)
+	case *MigrationFlowResult:
+		table = migrationFlowTable(ctx, v)
+	default:
+		slog.WarnContext(ctx, "Неизвестный тип данных для форматирования",
+			key_type, reflect.TypeOf(data))
+		return fmt.Sprintf("%v", data)
+	}
+
+	output, err := f.write(table)
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при записи табличных данных",
+			key_err, err)
+		return i18n.T(ctx, "Ошибка при форматировании данных: ") + err.Error()
+	}
+
+	slog.DebugContext(ctx, "Форматирование данных в табличный текст завершено",
+		key_item_count, len(table.rows))
+	return output
+}
+
+// write записывает таблицу с заголовками и, при необходимости, строкой подписей
+func (f *DelimitedFormatter) write(table *delimitedTable) (string, error) {
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+	writer.Comma = f.delimiter
+
+	if err := writer.Write(table.columns); err != nil {
+		return "", err
+	}
+
+	if f.labelRow {
+		labels := make([]string, len(table.columns))
+		for i, column := range table.columns {
+			if label, ok := f.labels[column]; ok {
+				labels[i] = label
+			} else {
+				labels[i] = table.labels[column]
+			}
+		}
+		if err := writer.Write(labels); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writer.WriteAll(table.rows); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// tableFromJSONDocument преобразует табличный документ JSON в таблицу, сохраняя порядок
+// и подписи столбцов
+func tableFromJSONDocument(doc *JSONDocument) *delimitedTable {
+	table := &delimitedTable{labels: make(map[string]string, len(doc.Columns))}
+	for _, column := range doc.Columns {
+		table.columns = append(table.columns, column.Name)
+		table.labels[column.Name] = column.Label
+	}
+
+	for _, row := range doc.Rows {
+		cells := make([]string, len(table.columns))
+		for i, column := range table.columns {
+			cells[i] = delimitedCell(row[column])
+		}
+		table.rows = append(table.rows, cells)
+	}
+	return table
+}
+
+// delimitedCell преобразует значение ячейки документа JSON в текст
+func delimitedCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		return strings.Join(v, "; ")
+	case []CBSACounty:
+		names := make([]string, 0, len(v))
+		for _, county := range v {
+			names = append(names, fmt.Sprintf("%s (%s%s)", county.Name, county.StateFIPS, county.CountyFIPS))
+		}
+		return strings.Join(names, "; ")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// delimitedNumber форматирует число или возвращает пустую ячейку, если значение недоступно
+func delimitedNumber(value float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return FormatNumber(value)
+}
+
+// customDataTable формирует таблицу ответа пользовательского запроса в порядке столбцов
+// ответа Census API: переменные и NAME, затем географические столбцы. Подписи переменных
+// из контекста выводятся в строке подписей.
+func customDataTable(ctx context.Context, data []map[string]string) *delimitedTable {
+	table := &delimitedTable{columns: responseColumns(ctx, data), labels: ColumnLayoutFromContext(ctx).Labels}
+	for _, item := range data {
+		cells := make([]string, len(table.columns))
+		for i, column := range table.columns {
+			value := item[column]
+			if annotationValues[strings.TrimSpace(value)] {
+				value = ""
+			}
+			cells[i] = value
+		}
+		table.rows = append(table.rows, cells)
+	}
+	return table
+}
+
+// yearComparisonTable формирует таблицу сравнения выпусков в длинном формате:
+// одна строка на географию, переменную и год
+func yearComparisonTable(ctx context.Context, data *YearComparison) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "variable", "year", "value"},
+		labels: map[string]string{
+			"geoid": "GEOID", "name": i18n.T(ctx, "География"), "variable": i18n.T(ctx, "Переменная"), "year": i18n.T(ctx, "Год"), "value": i18n.T(ctx, "Значение"),
+		},
+	}
+	for _, row := range data.Rows {
+		for _, variable := range data.Variables {
+			for _, year := range data.Years {
+				value := row.Values[variable][year]
+				if annotationValues[value] {
+					value = ""
+				}
+				table.rows = append(table.rows, []string{row.GeoID, row.Name, variable, year, value})
+			}
+		}
+	}
+	return table
+}
+
+// aggregationTable формирует таблицу итогов по пользовательскому региону
+func aggregationTable(ctx context.Context, data *AggregationResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"variable", "label", "value", "moe", "components"},
+		labels: map[string]string{
+			"variable": i18n.T(ctx, "Переменная"), "label": i18n.T(ctx, "Подпись"), "value": i18n.T(ctx, "Сумма"), "moe": "MOE", "components": i18n.T(ctx, "Географий"),
+		},
+	}
+	for _, total := range data.Totals {
+		table.rows = append(table.rows, []string{
+			total.Variable, total.Label, FormatNumber(total.Value),
+			delimitedNumber(total.MOE, total.HasMOE), strconv.Itoa(total.Components),
+		})
+	}
+	for _, median := range data.Medians {
+		table.rows = append(table.rows, []string{
+			median.Variable, median.Label, FormatNumber(median.Estimate.Value),
+			delimitedNumber(median.Estimate.MOE, median.Estimate.HasMOE), strconv.Itoa(len(data.GeoIDs)),
+		})
+	}
+	return table
+}
+
+// quantileTable формирует таблицу квантилей: одна строка на географию и квантиль
+func quantileTable(ctx context.Context, data *QuantileResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "total", "quantile", "value", "moe", "method", "top_coded"},
+		labels: map[string]string{
+			"geoid": "GEOID", "name": i18n.T(ctx, "География"), "total": i18n.T(ctx, "Всего"), "quantile": i18n.T(ctx, "Квантиль"),
+			"value": i18n.T(ctx, "Оценка"), "moe": "MOE", "method": i18n.T(ctx, "Метод"), "top_coded": i18n.T(ctx, "Открытый интервал"),
+		},
+	}
+	for _, row := range data.Rows {
+		for _, q := range row.Quantiles {
+			table.rows = append(table.rows, []string{
+				row.GeoID, row.Name, FormatNumber(row.Total), FormatNumber(q.Quantile),
+				FormatNumber(q.Value), delimitedNumber(q.MOE, q.HasMOE), q.Method, strconv.FormatBool(q.TopCoded),
+			})
+		}
+	}
+	return table
+}
+
+// rankTable формирует таблицу рейтинга географий
+func rankTable(ctx context.Context, data *RankResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"rank", "geoid", "name", "value", "moe", "percentile"},
+		labels: map[string]string{
+			"rank": i18n.T(ctx, "Место"), "geoid": "GEOID", "name": i18n.T(ctx, "География"), "value": data.Variable,
+			"moe": "MOE", "percentile": i18n.T(ctx, "Процентиль"),
+		},
+	}
+	for _, row := range data.Rows {
+		table.rows = append(table.rows, []string{
+			strconv.Itoa(row.Rank), row.GeoID, row.Name, FormatNumber(row.Value),
+			delimitedNumber(row.MOE, row.HasMOE), FormatNumber(row.Percentile),
+		})
+	}
+	return table
+}
+
+// estimateComparisonTable формирует таблицу из двух сравниваемых оценок с результатом z-теста
+func estimateComparisonTable(ctx context.Context, data *EstimateComparison) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"year", "geoid", "name", "value", "moe", "difference", "difference_moe", "z", "significant"},
+		labels: map[string]string{
+			"year": i18n.T(ctx, "Год"), "geoid": "GEOID", "name": i18n.T(ctx, "География"), "value": data.Variable, "moe": "MOE",
+			"difference": i18n.T(ctx, "Разность"), "difference_moe": i18n.T(ctx, "MOE разности"), "z": "Z", "significant": i18n.T(ctx, "Значимо"),
+		},
+	}
+	for _, estimate := range []ComparedEstimate{data.First, data.Second} {
+		table.rows = append(table.rows, []string{
+			estimate.Year, estimate.GeoID, estimate.Name, FormatNumber(estimate.Value), FormatNumber(estimate.MOE),
+			FormatNumber(data.Difference), FormatNumber(data.DifferenceMOE), FormatNumber(data.Z),
+			strconv.FormatBool(data.Significant),
+		})
+	}
+	return table
+}
+
+// profileTable формирует таблицу профиля: одна строка на географию и строку профиля
+func profileTable(ctx context.Context, data *ProfileResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "code", "label", "estimate", "moe", "percent", "percent_moe"},
+		labels: map[string]string{
+			"geoid": "GEOID", "name": i18n.T(ctx, "География"), "code": i18n.T(ctx, "Код"), "label": i18n.T(ctx, "Показатель"),
+			"estimate": i18n.T(ctx, "Оценка"), "moe": "MOE", "percent": i18n.T(ctx, "Процент"), "percent_moe": i18n.T(ctx, "MOE процента"),
+		},
+	}
+	for _, geography := range data.Geographies {
+		for _, line := range geography.Lines {
+			table.rows = append(table.rows, []string{
+				geography.GeoID, geography.Name, line.Code, i18n.T(ctx, line.Label),
+				line.Estimate, line.MOE, line.Percent, line.PercentMOE,
+			})
+		}
+	}
+	return table
+}
+
+// decennialTable формирует таблицу показателей переписи: одна строка на географию и показатель
+func decennialTable(ctx context.Context, data *DecennialResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "key", "label", "variable", "value", "share"},
+		labels: map[string]string{
+			"geoid": "GEOID", "name": i18n.T(ctx, "География"), "key": i18n.T(ctx, "Показатель"), "label": i18n.T(ctx, "Подпись"),
+			"variable": i18n.T(ctx, "Переменная"), "value": i18n.T(ctx, "Значение"), "share": i18n.T(ctx, "Доля, %"),
+		},
+	}
+	for _, row := range data.Rows {
+		for _, value := range row.Values {
+			table.rows = append(table.rows, []string{
+				row.GeoID, row.Name, value.Key, i18n.T(ctx, value.Label), value.Variable,
+				delimitedNumber(value.Value, value.Available), delimitedNumber(value.Share, value.HasShare),
+			})
+		}
+	}
+	return table
+}
+
+// decennialChangeTable формирует таблицу изменений между переписями
+func decennialChangeTable(ctx context.Context, data *DecennialChangeResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "key", "label", "base", "target", "change", "percent_change"},
+		labels: map[string]string{
+			"geoid": "GEOID", "name": i18n.T(ctx, "География"), "key": i18n.T(ctx, "Показатель"), "label": i18n.T(ctx, "Подпись"),
+			"base": data.BaseYear, "target": data.TargetYear, "change": i18n.T(ctx, "Изменение"), "percent_change": i18n.T(ctx, "Изменение, %"),
+		},
+	}
+	for _, row := range data.Rows {
+		for _, value := range row.Values {
+			table.rows = append(table.rows, []string{
+				row.GeoID, row.Name, value.Key, i18n.T(ctx, value.Label),
+				delimitedNumber(value.Base, value.HasChange),
+				delimitedNumber(value.Target, value.HasChange),
+				delimitedNumber(value.Change, value.HasChange),
+				delimitedNumber(value.PercentChange, value.HasPercentChange),
+			})
+		}
+	}
+	return table
+}
+
+// pepTable формирует таблицу оценок PEP со столбцами результата и их подписями
+func pepTable(ctx context.Context, data *PEPResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "period"},
+		labels:  map[string]string{"geoid": "GEOID", "name": i18n.T(ctx, "География"), "period": i18n.T(ctx, "Период")},
+	}
+	for _, column := range data.Columns {
+		table.columns = append(table.columns, column.Key)
+		table.labels[column.Key] = i18n.T(ctx, column.Label)
+	}
+
+	for _, row := range data.Rows {
+		cells := []string{row.GeoID, row.Name, row.Period}
+		for _, column := range data.Columns {
+			value := row.Values[column.Key]
+			// Значения характеристик - расшифрованные подписи кодов, их переводим
+			if _, ok := pepCharacteristicLabels[column.Key]; ok {
+				value = i18n.T(ctx, value)
+			}
+			cells = append(cells, value)
+		}
+		table.rows = append(table.rows, cells)
+	}
+	return table
+}
+
+// businessTable формирует таблицу статистики предприятий
+func businessTable(ctx context.Context, data *BusinessResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"geoid", "name", "naics", "industry", "establishments", "employment", "payroll"},
+		labels: map[string]string{
+			"geoid": "GEOID", "name": i18n.T(ctx, "География"), "naics": "NAICS", "industry": i18n.T(ctx, "Отрасль"),
+			"establishments": i18n.T(ctx, "Предприятия"), "employment": i18n.T(ctx, "Занятые"), "payroll": i18n.T(ctx, "Фонд оплаты труда, тыс. $"),
+			"receipts": i18n.T(ctx, "Выручка, тыс. $"),
+		},
+	}
+	receipts := data.Dataset == DatasetEconomicCensus
+	if receipts {
+		table.columns = append(table.columns, "receipts")
+	}
+
+	for _, row := range data.Rows {
+		cells := []string{row.GeoID, row.Name, row.NAICS, row.Industry, row.Establishments, row.Employment, row.Payroll}
+		if receipts {
+			cells = append(cells, row.Receipts)
+		}
+		table.rows = append(table.rows, cells)
+	}
+	return table
+}
+
+// migrationFlowTable формирует таблицу крупнейших потоков притока и оттока
+func migrationFlowTable(ctx context.Context, data *MigrationFlowResult) *delimitedTable {
+	table := &delimitedTable{
+		columns: []string{"direction", "geoid", "name", "moved_in", "moved_in_moe", "moved_out", "moved_out_moe", "net", "net_moe"},
+		labels: map[string]string{
+			"direction": i18n.T(ctx, "Направление"), "geoid": i18n.T(ctx, "GEOID контрагента"), "name": i18n.T(ctx, "Контрагент"),
+			"moved_in": i18n.T(ctx, "Приток"), "moved_in_moe": i18n.T(ctx, "MOE притока"), "moved_out": i18n.T(ctx, "Отток"), "moved_out_moe": i18n.T(ctx, "MOE оттока"),
+			"net": i18n.T(ctx, "Чистая миграция"), "net_moe": i18n.T(ctx, "MOE чистой миграции"),
+		},
+	}
+
+	add := func(direction string, flows []MigrationCounterpart) {
+		for _, c := range flows {
+			table.rows = append(table.rows, []string{
+				direction, c.GeoID, c.Name,
+				delimitedNumber(c.MovedIn, c.HasIn), delimitedNumber(c.MovedInMOE, c.HasIn),
+				delimitedNumber(c.MovedOut, c.HasOut), delimitedNumber(c.MovedOutMOE, c.HasOut),
+				delimitedNumber(c.Net, c.HasNet), delimitedNumber(c.NetMOE, c.HasNet),
+			})
+		}
+	}
+	add("inflow", data.Inflows)
+	add("outflow", data.Outflows)
+	return table
+}
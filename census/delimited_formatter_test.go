@@ -0,0 +1,104 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelimitedFormatter_CSV_CustomData(t *testing.T) {
+	formatter := NewCSVFormatter()
+
+	output := formatter.Format(context.Background(), []map[string]string{
+		{"state": "06", "county": "037", "NAME": "Los Angeles County, California", "B19013_001E": "76367", "B01001_001E": "10014009"},
+		{"state": "06", "county": "003", "NAME": "Alpine County, California", "B19013_001E": "-666666666", "B01001_001E": "1204"},
+	})
+
+	assert.Equal(t, "NAME,B01001_001E,B19013_001E,state,county\n"+
+		"\"Los Angeles County, California\",10014009,76367,06,037\n"+
+		"\"Alpine County, California\",1204,,06,003\n", output)
+
+	// Результат должен корректно читаться стандартным CSV-парсером
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "Los Angeles County, California", records[1][0])
+}
+
+func TestDelimitedFormatter_TSV_LabelRow(t *testing.T) {
+	formatter := NewTSVFormatter().WithLabelRow(map[string]string{
+		"B01001_001E": "Estimate!!Total:",
+	})
+
+	output := formatter.Format(context.Background(), []map[string]string{
+		{"NAME": "Texas", "B01001_001E": "29145505", "state": "48"},
+	})
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "NAME\tB01001_001E\tstate", lines[0])
+	assert.Equal(t, "\tEstimate!!Total:\t", lines[1])
+	assert.Equal(t, "Texas\t29145505\t48", lines[2])
+}
+
+func TestDelimitedFormatter_BuiltInLabels(t *testing.T) {
+	formatter := NewCSVFormatter().WithLabelRow(nil)
+
+	output := formatter.Format(context.Background(), &PEPResult{
+		Kind:    PEPKindPopulation,
+		Vintage: "2019",
+		Columns: []PEPColumn{{Key: "POP", Label: "Население"}},
+		Rows:    []PEPRow{{GeoID: "48", Name: "Texas", Period: "2019", Values: map[string]string{"POP": "28995881"}}},
+	})
+
+	assert.Equal(t, "geoid,name,period,POP\nGEOID,География,Период,Население\n48,Texas,2019,28995881\n", output)
+}
+
+func TestDelimitedFormatter_Results(t *testing.T) {
+	formatter := NewCSVFormatter()
+
+	rank := formatter.Format(context.Background(), &RankResult{
+		Variable: "B19013_001E",
+		Rows:     []RankedGeography{{Rank: 1, GeoID: "24", Name: "Maryland", Value: 90203, MOE: 500, HasMOE: true, Percentile: 100}},
+	})
+	assert.Equal(t, "rank,geoid,name,value,moe,percentile\n1,24,Maryland,90203,500,100\n", rank)
+
+	flows := formatter.Format(context.Background(), &MigrationFlowResult{
+		Inflows: []MigrationCounterpart{{GeoID: "06059", Name: "Orange County, California", MovedIn: 100, MovedInMOE: 20, HasIn: true}},
+	})
+	assert.Contains(t, flows, "inflow,06059,\"Orange County, California\",100,20,,,,\n")
+
+	levels := formatter.Format(context.Background(), []GeographyLevel{
+		{Name: "county", Description: "Округа", RequiredFor: []string{"state"}, Wildcards: true},
+	})
+	assert.Equal(t, "name,description,required_for,wildcards\ncounty,Округа,state,true\n", levels)
+}
+
+func TestDelimitedFormatter_TranslatesLabels(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+	formatter := NewCSVFormatter()
+
+	profile := formatter.Format(ctx, &ProfileResult{
+		Geographies: []ProfileGeography{{GeoID: "48", Name: "Texas", Lines: []ProfileValue{
+			{Code: "DP02_0001", Label: "Всего домохозяйств", Estimate: "10490553"},
+		}}},
+	})
+	assert.Contains(t, profile, "48,Texas,DP02_0001,Total households,10490553")
+
+	pep := formatter.Format(ctx, &PEPResult{
+		Kind:    PEPKindCharacteristics,
+		Columns: []PEPColumn{{Key: "SEX", Label: "Пол"}, {Key: "POP", Label: "Население"}},
+		Rows:    []PEPRow{{GeoID: "48", Name: "Texas", Period: "2019", Values: map[string]string{"SEX": "Женщины", "POP": "14602823"}}},
+	})
+	assert.Contains(t, pep, "48,Texas,2019,Female,14602823")
+}
+
+func TestDelimitedFormatter_UnsupportedType(t *testing.T) {
+	assert.Equal(t, "123", NewCSVFormatter().Format(context.Background(), 123))
+	assert.Equal(t, "Нет данных", NewTSVFormatter().Format(context.Background(), nil))
+}
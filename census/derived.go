@@ -0,0 +1,642 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Estimate представляет оценку ACS вместе с ее предельной ошибкой (MOE) на уровне 90%
+type Estimate struct {
+	Value float64 `json:"value"`
+	MOE   float64 `json:"moe"`
+	// HasMOE равен false, если предельная ошибка неизвестна (например, для данных переписи)
+	HasMOE bool `json:"has_moe"`
+}
+
+// controlledMOE - служебное значение MOE для оценок, контролируемых по независимым данным;
+// такая оценка не имеет ошибки выборки
+const controlledMOE = "-555555555"
+
+// moeZ90 - z-значение для уровня доверия 90%, на котором публикуются MOE ACS
+const moeZ90 = 1.645
+
+// SumEstimates вычисляет сумму оценок; MOE суммы - корень из суммы квадратов MOE слагаемых.
+// По методике Census Bureau из нескольких нулевых оценок учитывается только наибольшая MOE.
+func SumEstimates(estimates ...Estimate) Estimate {
+	result := Estimate{HasMOE: true}
+	var squares, zeroMOE float64
+
+	for _, e := range estimates {
+		result.Value += e.Value
+		if !e.HasMOE {
+			result.HasMOE = false
+		}
+		if e.Value == 0 {
+			zeroMOE = math.Max(zeroMOE, e.MOE)
+			continue
+		}
+		squares += e.MOE * e.MOE
+	}
+
+	result.MOE = math.Sqrt(squares + zeroMOE*zeroMOE)
+	return result
+}
+
+// DifferenceEstimates вычисляет разность оценок; MOE - корень из суммы квадратов MOE
+func DifferenceEstimates(a, b Estimate) Estimate {
+	return Estimate{
+		Value:  a.Value - b.Value,
+		MOE:    math.Sqrt(a.MOE*a.MOE + b.MOE*b.MOE),
+		HasMOE: a.HasMOE && b.HasMOE,
+	}
+}
+
+// RatioEstimates вычисляет отношение оценок, числитель которого не является частью знаменателя
+func RatioEstimates(numerator, denominator Estimate) (Estimate, error) {
+	if denominator.Value == 0 {
+		return Estimate{}, i18n.Errorf("деление на нулевую оценку")
+	}
+
+	ratio := numerator.Value / denominator.Value
+	moe := math.Sqrt(numerator.MOE*numerator.MOE+ratio*ratio*denominator.MOE*denominator.MOE) / math.Abs(denominator.Value)
+
+	return Estimate{
+		Value:  ratio,
+		MOE:    moe,
+		HasMOE: numerator.HasMOE && denominator.HasMOE,
+	}, nil
+}
+
+// ProportionEstimates вычисляет долю, числитель которой является частью знаменателя.
+// Если подкоренное выражение формулы доли отрицательно, по методике Census Bureau
+// используется формула отношения.
+func ProportionEstimates(numerator, denominator Estimate) (Estimate, error) {
+	if denominator.Value == 0 {
+		return Estimate{}, i18n.Errorf("деление на нулевую оценку")
+	}
+
+	proportion := numerator.Value / denominator.Value
+	radicand := numerator.MOE*numerator.MOE - proportion*proportion*denominator.MOE*denominator.MOE
+	if radicand < 0 {
+		return RatioEstimates(numerator, denominator)
+	}
+
+	return Estimate{
+		Value:  proportion,
+		MOE:    math.Sqrt(radicand) / math.Abs(denominator.Value),
+		HasMOE: numerator.HasMOE && denominator.HasMOE,
+	}, nil
+}
+
+// ProductEstimates вычисляет произведение двух оценок
+func ProductEstimates(a, b Estimate) Estimate {
+	return Estimate{
+		Value:  a.Value * b.Value,
+		MOE:    math.Sqrt(a.Value*a.Value*b.MOE*b.MOE + b.Value*b.Value*a.MOE*a.MOE),
+		HasMOE: a.HasMOE && b.HasMOE,
+	}
+}
+
+// PercentChangeEstimates вычисляет относительное изменение (в процентах) от from к to
+func PercentChangeEstimates(from, to Estimate) (Estimate, error) {
+	ratio, err := RatioEstimates(to, from)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	return Estimate{
+		Value:  (ratio.Value - 1) * 100,
+		MOE:    ratio.MOE * 100,
+		HasMOE: ratio.HasMOE,
+	}, nil
+}
+
+// scaleEstimate умножает оценку на константу
+func scaleEstimate(e Estimate, factor float64) Estimate {
+	return Estimate{
+		Value:  e.Value * factor,
+		MOE:    e.MOE * math.Abs(factor),
+		HasMOE: e.HasMOE,
+	}
+}
+
+// MOEVariable возвращает имя переменной предельной ошибки для переменной оценки
+// (B01001_001E -> B01001_001M, DP03_0009PE -> DP03_0009PM)
+func MOEVariable(variable string) (string, bool) {
+	// Переменные оценок таблиц ACS имеют вид <таблица>_<номер>E, в отличие от NAME
+	if !strings.HasSuffix(variable, "E") || !strings.Contains(variable, "_") {
+		return "", false
+	}
+	return strings.TrimSuffix(variable, "E") + "M", true
+}
+
+// EstimateFromRow извлекает оценку и ее MOE из строки ответа Census API
+func EstimateFromRow(row map[string]string, variable string) (Estimate, bool) {
+	value, ok := ParseEstimate(row[variable])
+	if !ok {
+		return Estimate{}, false
+	}
+
+	estimate := Estimate{Value: value}
+
+	if moeVariable, ok := MOEVariable(variable); ok {
+		if raw, present := row[moeVariable]; present {
+			if strings.TrimSpace(raw) == controlledMOE {
+				estimate.HasMOE = true
+			} else if moe, ok := ParseEstimate(raw); ok {
+				estimate.MOE = math.Abs(moe)
+				estimate.HasMOE = true
+			}
+		}
+	}
+
+	return estimate, true
+}
+
+// DerivedColumn описывает вычисляемый столбец вида "pct_poverty = pct(B17001_002E, B17001_001E)"
+type DerivedColumn struct {
+	Name       string
+	Expression string
+	root       derivedNode
+}
+
+// Variables возвращает переменные Census API, используемые в выражении столбца
+func (c DerivedColumn) Variables() []string {
+	seen := make(map[string]bool)
+	var result []string
+	c.root.collect(func(variable string) {
+		if !seen[variable] {
+			seen[variable] = true
+			result = append(result, variable)
+		}
+	})
+	return result
+}
+
+// Kind возвращает вид значения столбца по функции верхнего уровня выражения:
+// pct и pct_change дают проценты, sum - счетчик, остальные выражения - прочие величины
+func (c DerivedColumn) Kind() ValueKind {
+	if function, ok := c.root.(functionNode); ok {
+		switch function.name {
+		case "pct", "pct_change":
+			return ValueKindPercent
+		case "sum":
+			return ValueKindCount
+		}
+	}
+	return ValueKindNumber
+}
+
+// Evaluate вычисляет значение столбца для строки ответа Census API
+func (c DerivedColumn) Evaluate(row map[string]string) (Estimate, error) {
+	return c.root.eval(row)
+}
+
+// ParseDerivedColumn разбирает определение вычисляемого столбца.
+// Поддерживаются операторы +, -, *, /, унарный минус, скобки, числовые константы и функции:
+// sum(a, b, ...) - сумма, prop(a, b) - доля, pct(a, b) - доля в процентах,
+// ratio(a, b) - отношение, pct_change(from, to) - относительное изменение в процентах.
+// Оператор "/" делит оценку на константу или часть на итог ее таблицы (B17001_002E / B17001_001E,
+// MOE по формуле доли); для других пар оценок нужно явно указать prop или ratio.
+func ParseDerivedColumn(definition string) (DerivedColumn, error) {
+	name, expression, found := strings.Cut(definition, "=")
+	name = strings.TrimSpace(name)
+	expression = strings.TrimSpace(expression)
+
+	if !found || name == "" || expression == "" {
+		return DerivedColumn{}, i18n.Errorf("вычисляемый столбец должен иметь вид 'имя = выражение': %q", definition)
+	}
+
+	for _, ch := range name {
+		if !unicode.IsLetter(ch) && !unicode.IsDigit(ch) && ch != '_' {
+			return DerivedColumn{}, i18n.Errorf("некорректное имя вычисляемого столбца: %q", name)
+		}
+	}
+
+	p := &derivedParser{input: expression}
+	root, err := p.parseExpression()
+	if err != nil {
+		return DerivedColumn{}, i18n.Errorf("ошибка в выражении столбца %s: %w", name, err)
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return DerivedColumn{}, i18n.Errorf("ошибка в выражении столбца %s: неожиданный символ %q", name, p.input[p.pos])
+	}
+
+	return DerivedColumn{Name: name, Expression: expression, root: root}, nil
+}
+
+// ApplyDerivedColumns добавляет в строки вычисляемые столбцы и столбцы их MOE (с суффиксом "_moe")
+func ApplyDerivedColumns(rows []map[string]string, columns []DerivedColumn) {
+	for _, row := range rows {
+		for _, column := range columns {
+			estimate, err := column.Evaluate(row)
+			if err != nil {
+				row[column.Name] = "N/A"
+				continue
+			}
+
+			row[column.Name] = formatDerivedValue(estimate.Value)
+			if estimate.HasMOE {
+				row[column.Name+"_moe"] = formatDerivedValue(estimate.MOE)
+			}
+		}
+	}
+}
+
+// DerivedRequestVariables дополняет список запрашиваемых переменных переменными, необходимыми
+// для вычисляемых столбцов, и (для наборов ACS) их MOE. Возвращает полный список переменных
+// и список добавленных, которые не запрашивались явно.
+func DerivedRequestVariables(variables []string, dataset string, columns []DerivedColumn) ([]string, []string) {
+	requested := make(map[string]bool, len(variables))
+	for _, variable := range variables {
+		requested[variable] = true
+	}
+
+	all := append([]string{}, variables...)
+	var added []string
+	add := func(variable string) {
+		if !requested[variable] {
+			requested[variable] = true
+			all = append(all, variable)
+			added = append(added, variable)
+		}
+	}
+
+	withMOE := strings.HasPrefix(dataset, "acs/")
+	for _, column := range columns {
+		for _, variable := range column.Variables() {
+			add(variable)
+			if moeVariable, ok := MOEVariable(variable); ok && withMOE {
+				add(moeVariable)
+			}
+		}
+	}
+
+	return all, added
+}
+
+// formatDerivedValue форматирует вычисленное значение с точностью до четырех знаков после запятой
+func formatDerivedValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10000)/10000, 'f', -1, 64)
+}
+
+// derivedNode - узел дерева выражения вычисляемого столбца
+type derivedNode interface {
+	eval(row map[string]string) (Estimate, error)
+	collect(visit func(variable string))
+}
+
+// constantNode - числовая константа
+type constantNode struct {
+	value float64
+}
+
+func (n constantNode) eval(map[string]string) (Estimate, error) {
+	return Estimate{Value: n.value, HasMOE: true}, nil
+}
+
+func (n constantNode) collect(func(string)) {}
+
+// variableNode - ссылка на переменную Census API
+type variableNode struct {
+	name string
+}
+
+func (n variableNode) eval(row map[string]string) (Estimate, error) {
+	estimate, ok := EstimateFromRow(row, n.name)
+	if !ok {
+		return Estimate{}, i18n.Errorf("нет значения переменной %s", n.name)
+	}
+	return estimate, nil
+}
+
+func (n variableNode) collect(visit func(string)) {
+	visit(n.name)
+}
+
+// binaryNode - бинарная операция
+type binaryNode struct {
+	op          byte
+	left, right derivedNode
+}
+
+func (n binaryNode) eval(row map[string]string) (Estimate, error) {
+	left, err := n.left.eval(row)
+	if err != nil {
+		return Estimate{}, err
+	}
+	right, err := n.right.eval(row)
+	if err != nil {
+		return Estimate{}, err
+	}
+
+	_, leftConst := n.left.(constantNode)
+	_, rightConst := n.right.(constantNode)
+
+	switch n.op {
+	case '+':
+		return SumEstimates(left, right), nil
+	case '-':
+		return DifferenceEstimates(left, right), nil
+	case '*':
+		switch {
+		case rightConst:
+			return scaleEstimate(left, right.Value), nil
+		case leftConst:
+			return scaleEstimate(right, left.Value), nil
+		default:
+			return ProductEstimates(left, right), nil
+		}
+	case '/':
+		if rightConst {
+			if right.Value == 0 {
+				return Estimate{}, i18n.Errorf("деление на ноль")
+			}
+			return scaleEstimate(left, 1/right.Value), nil
+		}
+		return RatioEstimates(left, right)
+	default:
+		return Estimate{}, i18n.Errorf("неизвестная операция %q", n.op)
+	}
+}
+
+func (n binaryNode) collect(visit func(string)) {
+	n.left.collect(visit)
+	n.right.collect(visit)
+}
+
+// functionNode - вызов функции производного показателя
+type functionNode struct {
+	name string
+	args []derivedNode
+}
+
+func (n functionNode) eval(row map[string]string) (Estimate, error) {
+	args := make([]Estimate, 0, len(n.args))
+	for _, arg := range n.args {
+		value, err := arg.eval(row)
+		if err != nil {
+			return Estimate{}, err
+		}
+		args = append(args, value)
+	}
+
+	switch n.name {
+	case "sum":
+		return SumEstimates(args...), nil
+	case "prop":
+		return ProportionEstimates(args[0], args[1])
+	case "pct":
+		proportion, err := ProportionEstimates(args[0], args[1])
+		if err != nil {
+			return Estimate{}, err
+		}
+		return scaleEstimate(proportion, 100), nil
+	case "ratio":
+		return RatioEstimates(args[0], args[1])
+	case "pct_change":
+		return PercentChangeEstimates(args[0], args[1])
+	default:
+		return Estimate{}, i18n.Errorf("неизвестная функция %s", n.name)
+	}
+}
+
+func (n functionNode) collect(visit func(string)) {
+	for _, arg := range n.args {
+		arg.collect(visit)
+	}
+}
+
+// derivedFunctionArity - количество аргументов функций (-1 - произвольное, но не меньше одного)
+var derivedFunctionArity = map[string]int{
+	"sum":        -1,
+	"prop":       2,
+	"pct":        2,
+	"ratio":      2,
+	"pct_change": 2,
+}
+
+// derivedParser - рекурсивный нисходящий разборщик выражений вычисляемых столбцов
+type derivedParser struct {
+	input string
+	pos   int
+}
+
+func (p *derivedParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *derivedParser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// parseExpression разбирает сложение и вычитание
+func (p *derivedParser) parseExpression() (derivedNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+// parseTerm разбирает умножение и деление
+func (p *derivedParser) parseTerm() (derivedNode, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		if op == '/' {
+			left, err = divisionNode(left, right)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		left = binaryNode{op: op, left: left, right: right}
+	}
+}
+
+// divisionNode возвращает узел деления left / right. Деление на константу масштабирует оценку,
+// а деление части на итог ее таблицы (строку _001, например B17001_002E / B17001_001E) вычисляет
+// долю с MOE по формуле доли. Для других пар оценок оператор неоднозначен: формулы доли
+// и отношения дают разные MOE, поэтому требуется явный вызов prop(a, b) или ratio(a, b).
+func divisionNode(left, right derivedNode) (derivedNode, error) {
+	if _, ok := right.(constantNode); ok || !hasVariables(left) {
+		return binaryNode{op: '/', left: left, right: right}, nil
+	}
+
+	if total, ok := right.(variableNode); ok && isSubsetOfTotal(left, total.name) {
+		return functionNode{name: "prop", args: []derivedNode{left, right}}, nil
+	}
+
+	return nil, i18n.Errorf("оператор '/' между двумя оценками допустим только для части и итога ее таблицы (например, B17001_002E / B17001_001E); для других оценок укажите prop(a, b) для доли или ratio(a, b) для отношения")
+}
+
+// hasVariables сообщает, содержит ли выражение переменные Census API
+func hasVariables(node derivedNode) bool {
+	found := false
+	node.collect(func(string) { found = true })
+	return found
+}
+
+// isSubsetOfTotal сообщает, что все переменные выражения - строки той же таблицы, что и итог
+// total (строка _001 оценки или счетчика переписи, например B17001_001E или P1_001N)
+func isSubsetOfTotal(node derivedNode, total string) bool {
+	table, line, ok := variableTableLine(total)
+	if !ok || line != 1 {
+		return false
+	}
+
+	subset := true
+	node.collect(func(variable string) {
+		otherTable, otherLine, ok := variableTableLine(variable)
+		if !ok || otherTable != table || otherLine == line {
+			subset = false
+		}
+	})
+	return subset
+}
+
+// variableTableLine разбирает переменную вида <таблица>_<строка><суффикс> (B17001_002E, P1_001N)
+// на таблицу и номер строки
+func variableTableLine(variable string) (string, int, bool) {
+	cut := strings.LastIndex(variable, "_")
+	if cut <= 0 || !strings.HasSuffix(variable, "E") && !strings.HasSuffix(variable, "N") {
+		return "", 0, false
+	}
+	line, err := strconv.Atoi(variable[cut+1 : len(variable)-1])
+	if err != nil {
+		return "", 0, false
+	}
+	return variable[:cut], line, true
+}
+
+// parseFactor разбирает константы, переменные, вызовы функций, выражения в скобках
+// и унарный минус перед ними
+func (p *derivedParser) parseFactor() (derivedNode, error) {
+	ch := p.peek()
+
+	switch {
+	case ch == 0:
+		return nil, i18n.Errorf("неожиданный конец выражения")
+	case ch == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		// Смена знака оценки - умножение на -1: MOE не меняется
+		if constant, ok := operand.(constantNode); ok {
+			return constantNode{value: -constant.value}, nil
+		}
+		return binaryNode{op: '*', left: constantNode{value: -1}, right: operand}, nil
+	case ch == '(':
+		p.pos++
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, i18n.Errorf("ожидалась закрывающая скобка")
+		}
+		p.pos++
+		return node, nil
+	case ch >= '0' && ch <= '9' || ch == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, i18n.Errorf("некорректное число %q", p.input[start:p.pos])
+		}
+		return constantNode{value: value}, nil
+	case isIdentifierChar(ch):
+		start := p.pos
+		for p.pos < len(p.input) && isIdentifierChar(p.input[p.pos]) {
+			p.pos++
+		}
+		name := p.input[start:p.pos]
+
+		if p.peek() != '(' {
+			return variableNode{name: name}, nil
+		}
+		p.pos++
+		return p.parseFunction(strings.ToLower(name))
+	default:
+		return nil, i18n.Errorf("неожиданный символ %q", ch)
+	}
+}
+
+// parseFunction разбирает аргументы вызова функции после открывающей скобки
+func (p *derivedParser) parseFunction(name string) (derivedNode, error) {
+	arity, ok := derivedFunctionArity[name]
+	if !ok {
+		return nil, i18n.Errorf("неизвестная функция %s", name)
+	}
+
+	var args []derivedNode
+	for {
+		arg, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		switch p.peek() {
+		case ',':
+			p.pos++
+			continue
+		case ')':
+			p.pos++
+		default:
+			return nil, i18n.Errorf("ожидалась запятая или закрывающая скобка в вызове %s", name)
+		}
+		break
+	}
+
+	if arity > 0 && len(args) != arity {
+		return nil, i18n.Errorf("функция %s принимает %d аргумента", name, arity)
+	}
+
+	return functionNode{name: name, args: args}, nil
+}
+
+// isIdentifierChar проверяет, может ли символ входить в имя переменной или функции
+func isIdentifierChar(ch byte) bool {
+	return ch >= 'A' && ch <= 'Z' || ch >= 'a' && ch <= 'z' || ch >= '0' && ch <= '9' || ch == '_'
+}
@@ -0,0 +1,193 @@
+package census
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumEstimates(t *testing.T) {
+	sum := SumEstimates(Estimate{Value: 100, MOE: 30, HasMOE: true}, Estimate{Value: 200, MOE: 40, HasMOE: true})
+	assert.Equal(t, float64(300), sum.Value)
+	assert.InDelta(t, 50, sum.MOE, 1e-9)
+	assert.True(t, sum.HasMOE)
+
+	// Из нескольких нулевых оценок учитывается только наибольшая MOE
+	sum = SumEstimates(
+		Estimate{Value: 0, MOE: 10, HasMOE: true},
+		Estimate{Value: 0, MOE: 20, HasMOE: true},
+		Estimate{Value: 5, MOE: 3, HasMOE: true},
+	)
+	assert.Equal(t, float64(5), sum.Value)
+	assert.InDelta(t, math.Sqrt(409), sum.MOE, 1e-9)
+
+	// Без MOE у одного из слагаемых MOE суммы неизвестна
+	sum = SumEstimates(Estimate{Value: 1, HasMOE: true}, Estimate{Value: 2})
+	assert.False(t, sum.HasMOE)
+}
+
+func TestProportionAndRatioEstimates(t *testing.T) {
+	numerator := Estimate{Value: 200, MOE: 40, HasMOE: true}
+	denominator := Estimate{Value: 1000, MOE: 60, HasMOE: true}
+
+	proportion, err := ProportionEstimates(numerator, denominator)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.2, proportion.Value, 1e-9)
+	assert.InDelta(t, math.Sqrt(1456)/1000, proportion.MOE, 1e-9)
+
+	ratio, err := RatioEstimates(numerator, denominator)
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.2, ratio.Value, 1e-9)
+	assert.InDelta(t, math.Sqrt(1744)/1000, ratio.MOE, 1e-9)
+
+	// При отрицательном подкоренном выражении используется формула отношения
+	proportion, err = ProportionEstimates(Estimate{Value: 100, MOE: 5, HasMOE: true}, Estimate{Value: 200, MOE: 50, HasMOE: true})
+	assert.NoError(t, err)
+	assert.InDelta(t, math.Sqrt(650)/200, proportion.MOE, 1e-9)
+
+	_, err = ProportionEstimates(numerator, Estimate{})
+	assert.Error(t, err)
+}
+
+func TestPercentChangeEstimates(t *testing.T) {
+	change, err := PercentChangeEstimates(
+		Estimate{Value: 1000, MOE: 60, HasMOE: true},
+		Estimate{Value: 1100, MOE: 80, HasMOE: true},
+	)
+	assert.NoError(t, err)
+	assert.InDelta(t, 10, change.Value, 1e-9)
+	assert.InDelta(t, math.Sqrt(6400+1.21*3600)/1000*100, change.MOE, 1e-9)
+}
+
+func TestMOEVariable(t *testing.T) {
+	moe, ok := MOEVariable("B01001_001E")
+	assert.True(t, ok)
+	assert.Equal(t, "B01001_001M", moe)
+
+	moe, ok = MOEVariable("DP03_0009PE")
+	assert.True(t, ok)
+	assert.Equal(t, "DP03_0009PM", moe)
+
+	_, ok = MOEVariable("NAME")
+	assert.False(t, ok)
+}
+
+func TestParseDerivedColumn(t *testing.T) {
+	column, err := ParseDerivedColumn("pct_poverty = B17001_002E / B17001_001E")
+	assert.NoError(t, err)
+	assert.Equal(t, "pct_poverty", column.Name)
+	assert.Equal(t, []string{"B17001_002E", "B17001_001E"}, column.Variables())
+
+	column, err = ParseDerivedColumn("under18 = sum(B01001_003E, B01001_027E) * 100 / B01001_001E")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B01001_003E", "B01001_027E", "B01001_001E"}, column.Variables())
+
+	// Унарный минус перед переменной, константой и выражением в скобках
+	column, err = ParseDerivedColumn("female = -B01001_002E + B01001_001E")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"B01001_002E", "B01001_001E"}, column.Variables())
+	female, err := column.Evaluate(map[string]string{
+		"B01001_002E": "600", "B01001_002M": "30",
+		"B01001_001E": "1000", "B01001_001M": "40",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, float64(400), female.Value)
+	assert.InDelta(t, 50, female.MOE, 1e-9)
+
+	column, err = ParseDerivedColumn("deficit = -(B01001_002E - B01001_001E) * -2")
+	require.NoError(t, err)
+	deficit, err := column.Evaluate(map[string]string{"B01001_002E": "600", "B01001_001E": "1000"})
+	require.NoError(t, err)
+	assert.Equal(t, float64(-800), deficit.Value)
+
+	// Табуляции и переводы строк считаются пробелами
+	column, err = ParseDerivedColumn("share =\tB17001_002E\n/ B17001_001E")
+	assert.NoError(t, err)
+	assert.Equal(t, "share", column.Name)
+
+	for _, invalid := range []string{
+		"B17001_002E / B17001_001E",
+		// Деление оценок разных таблиц или не на итог неоднозначно: нужно указать prop или ratio
+		"income_per_person = B19025_001E / B01003_001E",
+		"share = B17001_002E / B17001_003E",
+		"pct = ",
+		"pct = prop(B17001_002E)",
+		"pct = unknown(B17001_002E, B17001_001E)",
+		"pct = (B17001_002E / B17001_001E",
+		"pct = B17001_002E $ B17001_001E",
+		"bad name = B17001_002E",
+	} {
+		_, err := ParseDerivedColumn(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestDerivedColumn_Kind(t *testing.T) {
+	tests := map[string]ValueKind{
+		"pct_poverty = pct(B17001_002E, B17001_001E)":   ValueKindPercent,
+		"growth = pct_change(B01001_001E, B01001_002E)": ValueKindPercent,
+		"total = sum(B01001_002E, B01001_026E)":         ValueKindCount,
+		"share = prop(B17001_002E, B17001_001E)":        ValueKindNumber,
+		"diff = B01001_002E - B01001_026E":              ValueKindNumber,
+	}
+	for definition, expected := range tests {
+		column, err := ParseDerivedColumn(definition)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, column.Kind(), definition)
+	}
+}
+
+func TestApplyDerivedColumns(t *testing.T) {
+	rows := []map[string]string{
+		{
+			"NAME":        "California",
+			"B17001_001E": "1000",
+			"B17001_001M": "60",
+			"B17001_002E": "200",
+			"B17001_002M": "40",
+		},
+		{
+			"NAME":        "Nowhere",
+			"B17001_001E": "0",
+			"B17001_002E": "0",
+		},
+	}
+
+	share, err := ParseDerivedColumn("pct_poverty = pct(B17001_002E, B17001_001E)")
+	assert.NoError(t, err)
+	// Часть, деленная на итог таблицы, - доля с MOE по формуле доли
+	proportion, err := ParseDerivedColumn("share_poverty = B17001_002E / B17001_001E")
+	assert.NoError(t, err)
+	ratio, err := ParseDerivedColumn("ratio_poverty = ratio(B17001_002E, B17001_001E)")
+	assert.NoError(t, err)
+
+	ApplyDerivedColumns(rows, []DerivedColumn{share, proportion, ratio})
+
+	assert.Equal(t, "20", rows[0]["pct_poverty"])
+	assert.Equal(t, "3.8158", rows[0]["pct_poverty_moe"])
+	assert.Equal(t, "0.2", rows[0]["share_poverty"])
+	assert.Equal(t, "0.0382", rows[0]["share_poverty_moe"])
+	assert.Equal(t, "0.2", rows[0]["ratio_poverty"])
+	assert.Equal(t, "0.0418", rows[0]["ratio_poverty_moe"])
+
+	// Деление на ноль дает N/A, а без MOE в ответе столбец MOE не добавляется
+	assert.Equal(t, "N/A", rows[1]["pct_poverty"])
+	_, ok := rows[1]["pct_poverty_moe"]
+	assert.False(t, ok)
+}
+
+func TestDerivedRequestVariables(t *testing.T) {
+	column, err := ParseDerivedColumn("pct_poverty = pct(B17001_002E, B17001_001E)")
+	assert.NoError(t, err)
+
+	all, added := DerivedRequestVariables([]string{"NAME", "B17001_001E"}, "acs/acs5", []DerivedColumn{column})
+	assert.Equal(t, []string{"NAME", "B17001_001E", "B17001_002E", "B17001_002M", "B17001_001M"}, all)
+	assert.Equal(t, []string{"B17001_002E", "B17001_002M", "B17001_001M"}, added)
+
+	// Для переписи MOE не запрашиваются
+	all, added = DerivedRequestVariables([]string{"NAME"}, "dec/pl", []DerivedColumn{column})
+	assert.Equal(t, []string{"NAME", "B17001_002E", "B17001_001E"}, all)
+	assert.Equal(t, []string{"B17001_002E", "B17001_001E"}, added)
+}
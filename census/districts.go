@@ -0,0 +1,251 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_chamber   = "chamber"
+	key_districts = "districts"
+	key_congress  = "congress"
+)
+
+// Географические уровни избирательных округов в Census API
+const (
+	GeoLevelCongressionalDistrict = "congressional district"
+	GeoLevelStateLegislativeUpper = "state legislative district (upper chamber)"
+	GeoLevelStateLegislativeLower = "state legislative district (lower chamber)"
+)
+
+// Типы избирательных округов
+const (
+	ChamberCongressional = "congressional"
+	ChamberUpper         = "upper"
+	ChamberLower         = "lower"
+)
+
+// congressYears сопоставляет созыв Конгресса с выпусками ACS, в которых используются его округа
+var congressYears = map[int][]string{
+	113: {"2012", "2013"},
+	114: {"2014", "2015"},
+	115: {"2016", "2017"},
+	116: {"2018", "2019", "2020", "2021"},
+	118: {"2022", "2023"},
+	119: {"2024"},
+}
+
+// DistrictRequest представляет запрос данных по избирательным округам
+type DistrictRequest struct {
+	Chamber   string   // Тип округа: congressional, upper или lower
+	Districts []string // Идентификаторы округов (например, "CA-12", "TX", "*")
+	Variables []string // Список переменных для запроса
+	Dataset   string   // Набор данных (например, "acs/acs5")
+	Year      string   // Год данных
+	Congress  int      // Созыв Конгресса (необязательно, используется для выбора года)
+}
+
+// DistrictGeoLevel возвращает географический уровень Census API для типа округа
+func DistrictGeoLevel(chamber string) (string, error) {
+	switch chamber {
+	case ChamberCongressional, "":
+		return GeoLevelCongressionalDistrict, nil
+	case ChamberUpper:
+		return GeoLevelStateLegislativeUpper, nil
+	case ChamberLower:
+		return GeoLevelStateLegislativeLower, nil
+	default:
+		return "", i18n.Errorf("неизвестный тип округа: %q (допустимо: congressional, upper, lower)", chamber)
+	}
+}
+
+// CongressForYear возвращает созыв Конгресса, округа которого используются в выпуске ACS
+func CongressForYear(year string) (int, bool) {
+	for congress, years := range congressYears {
+		for _, y := range years {
+			if y == year {
+				return congress, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// YearForCongress возвращает последний выпуск ACS с округами указанного созыва Конгресса
+func YearForCongress(congress int) (string, bool) {
+	years, ok := congressYears[congress]
+	if !ok || len(years) == 0 {
+		return "", false
+	}
+
+	return years[len(years)-1], true
+}
+
+// ResolveDistrictYear согласует год данных и созыв Конгресса
+func ResolveDistrictYear(year string, congress int) (string, error) {
+	if congress == 0 {
+		if year == "" {
+			return "", i18n.Errorf("необходимо указать год или созыв Конгресса")
+		}
+		return year, nil
+	}
+
+	if year == "" {
+		resolved, ok := YearForCongress(congress)
+		if !ok {
+			return "", i18n.Errorf("нет выпусков ACS для %d-го созыва Конгресса", congress)
+		}
+		return resolved, nil
+	}
+
+	if actual, ok := CongressForYear(year); ok && actual != congress {
+		return "", i18n.Errorf("в выпуске %s используются округа %d-го созыва Конгресса, а не %d-го", year, actual, congress)
+	}
+
+	return year, nil
+}
+
+// ParseDistrictID разбирает идентификатор округа вида "CA-12" в код штата FIPS и код округа.
+// Идентификатор без номера ("CA" или "CA-*") означает все округа штата, "*" - все округа страны,
+// "AL" в качестве номера - единственный округ штата (at-large).
+func ParseDistrictID(id string, chamber string) (string, string, error) {
+	id = strings.TrimSpace(id)
+	if id == "*" {
+		return "*", "*", nil
+	}
+
+	statePart, districtPart, found := strings.Cut(id, "-")
+	if !found {
+		districtPart = "*"
+	}
+
+	state, ok := LookupState(statePart)
+	if !ok {
+		return "", "", i18n.Errorf("не удалось определить штат в идентификаторе округа %q", id)
+	}
+
+	districtPart = strings.TrimSpace(districtPart)
+	if districtPart == "" || districtPart == "*" {
+		return state.FIPS, "*", nil
+	}
+
+	if chamber == ChamberCongressional || chamber == "" {
+		if strings.EqualFold(districtPart, "AL") {
+			return state.FIPS, "00", nil
+		}
+
+		number, err := strconv.Atoi(districtPart)
+		if err != nil || number < 0 || number > 99 {
+			return "", "", i18n.Errorf("некорректный номер округа Конгресса в %q", id)
+		}
+		return state.FIPS, fmt.Sprintf("%02d", number), nil
+	}
+
+	// Коды округов легислатур штатов трехзначные, в ряде штатов - буквенно-цифровые
+	if number, err := strconv.Atoi(districtPart); err == nil {
+		if number < 0 || number > 999 {
+			return "", "", i18n.Errorf("некорректный номер округа легислатуры в %q", id)
+		}
+		return state.FIPS, fmt.Sprintf("%03d", number), nil
+	}
+
+	return state.FIPS, strings.ToUpper(districtPart), nil
+}
+
+// BuildDistrictDataRequests преобразует запрос по округам в пользовательские запросы Census API,
+// по одному на каждый штат
+func BuildDistrictDataRequests(request DistrictRequest) ([]CustomDataRequest, error) {
+	geoLevel, err := DistrictGeoLevel(request.Chamber)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Districts) == 0 {
+		return nil, i18n.Errorf("необходимо указать хотя бы один округ")
+	}
+
+	year, err := ResolveDistrictYear(request.Year, request.Congress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Группируем округа по штатам, сохраняя порядок и исключая дубликаты
+	byState := make(map[string][]string)
+	for _, id := range request.Districts {
+		state, district, err := ParseDistrictID(id, request.Chamber)
+		if err != nil {
+			return nil, err
+		}
+
+		existing := byState[state]
+		if len(existing) == 1 && existing[0] == "*" {
+			continue
+		}
+		if district == "*" {
+			byState[state] = []string{"*"}
+			continue
+		}
+		duplicate := false
+		for _, d := range existing {
+			if d == district {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			byState[state] = append(existing, district)
+		}
+	}
+
+	states := make([]string, 0, len(byState))
+	for state := range byState {
+		states = append(states, state)
+	}
+	sort.Strings(states)
+
+	requests := make([]CustomDataRequest, 0, len(states))
+	for _, state := range states {
+		requests = append(requests, CustomDataRequest{
+			Variables: request.Variables,
+			Dataset:   request.Dataset,
+			Year:      year,
+			GeoLevel:  geoLevel,
+			GeoFilter: map[string]string{
+				geoLevel: strings.Join(byState[state], ","),
+				"state":  state,
+			},
+		})
+	}
+
+	return requests, nil
+}
+
+// GetDistrictData возвращает данные по округам Конгресса или легислатур штатов
+func (c *CensusAPI) GetDistrictData(request DistrictRequest) ([]map[string]string, error) {
+	slog.Info("Получение данных по избирательным округам",
+		key_chamber, request.Chamber,
+		key_districts, request.Districts,
+		key_congress, request.Congress)
+
+	requests, err := BuildDistrictDataRequests(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []map[string]string
+	for _, customRequest := range requests {
+		data, err := c.GetCustomData(customRequest)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, data...)
+	}
+
+	return result, nil
+}
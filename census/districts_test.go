@@ -0,0 +1,114 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDistrictID(t *testing.T) {
+	tests := []struct {
+		id               string
+		chamber          string
+		expectedState    string
+		expectedDistrict string
+		expectError      bool
+	}{
+		{"CA-12", ChamberCongressional, "06", "12", false},
+		{"tx-7", ChamberCongressional, "48", "07", false},
+		{"VT-AL", ChamberCongressional, "50", "00", false},
+		{"Texas", ChamberCongressional, "48", "*", false},
+		{"NY-*", ChamberCongressional, "36", "*", false},
+		{"*", ChamberCongressional, "*", "*", false},
+		{"CA-11", ChamberUpper, "06", "011", false},
+		{"MA-A1", ChamberLower, "25", "A1", false},
+		{"ZZ-01", ChamberCongressional, "", "", true},
+		{"CA-XX", ChamberCongressional, "", "", true},
+	}
+
+	for _, tt := range tests {
+		state, district, err := ParseDistrictID(tt.id, tt.chamber)
+		if tt.expectError {
+			assert.Error(t, err, tt.id)
+			continue
+		}
+		assert.NoError(t, err, tt.id)
+		assert.Equal(t, tt.expectedState, state, tt.id)
+		assert.Equal(t, tt.expectedDistrict, district, tt.id)
+	}
+}
+
+func TestResolveDistrictYear(t *testing.T) {
+	year, err := ResolveDistrictYear("", 116)
+	assert.NoError(t, err)
+	assert.Equal(t, "2021", year)
+
+	year, err = ResolveDistrictYear("2022", 118)
+	assert.NoError(t, err)
+	assert.Equal(t, "2022", year)
+
+	_, err = ResolveDistrictYear("2021", 118)
+	assert.Error(t, err)
+
+	_, err = ResolveDistrictYear("", 117)
+	assert.Error(t, err)
+
+	_, err = ResolveDistrictYear("", 0)
+	assert.Error(t, err)
+}
+
+func TestBuildDistrictDataRequests(t *testing.T) {
+	requests, err := BuildDistrictDataRequests(DistrictRequest{
+		Chamber:   ChamberCongressional,
+		Districts: []string{"TX-07", "CA-12", "CA-13", "CA-12"},
+		Variables: []string{"NAME"},
+		Dataset:   "acs/acs5",
+		Congress:  118,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+
+	assert.Equal(t, GeoLevelCongressionalDistrict, requests[0].GeoLevel)
+	assert.Equal(t, "2023", requests[0].Year)
+	assert.Equal(t, map[string]string{GeoLevelCongressionalDistrict: "12,13", "state": "06"}, requests[0].GeoFilter)
+	assert.Equal(t, map[string]string{GeoLevelCongressionalDistrict: "07", "state": "48"}, requests[1].GeoFilter)
+
+	// Все округа штата поглощают отдельные округа
+	requests, err = BuildDistrictDataRequests(DistrictRequest{
+		Chamber:   ChamberUpper,
+		Districts: []string{"CA-11", "CA"},
+		Dataset:   "acs/acs5",
+		Year:      "2022",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, requests, 1)
+	assert.Equal(t, map[string]string{GeoLevelStateLegislativeUpper: "*", "state": "06"}, requests[0].GeoFilter)
+
+	_, err = BuildDistrictDataRequests(DistrictRequest{Chamber: "senate", Districts: []string{"CA-11"}, Year: "2022"})
+	assert.Error(t, err)
+}
+
+func TestLookupState(t *testing.T) {
+	for _, value := range []string{"06", "CA", "ca", "California", " california "} {
+		state, ok := LookupState(value)
+		assert.True(t, ok, value)
+		assert.Equal(t, "06", state.FIPS, value)
+	}
+
+	_, ok := LookupState("Atlantis")
+	assert.False(t, ok)
+}
+
+func TestMockCensusAPI_GetDistrictData(t *testing.T) {
+	mockAPI := NewMockCensusAPI()
+
+	data, err := mockAPI.GetDistrictData(DistrictRequest{
+		Chamber:   ChamberCongressional,
+		Districts: []string{"CA-12"},
+		Dataset:   "acs/acs5",
+		Year:      "2022",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, data, 1)
+	assert.Equal(t, "12", data[0][GeoLevelCongressionalDistrict])
+}
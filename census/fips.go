@@ -0,0 +1,82 @@
+package census
+
+import (
+	"strings"
+)
+
+// StateInfo содержит коды и название штата или эквивалентной территории
+type StateInfo struct {
+	FIPS         string `json:"fips"`
+	Abbreviation string `json:"abbreviation"`
+	Name         string `json:"name"`
+}
+
+// States - справочник штатов, округа Колумбия и Пуэрто-Рико с кодами FIPS
+var States = []StateInfo{
+	{FIPS: "01", Abbreviation: "AL", Name: "Alabama"},
+	{FIPS: "02", Abbreviation: "AK", Name: "Alaska"},
+	{FIPS: "04", Abbreviation: "AZ", Name: "Arizona"},
+	{FIPS: "05", Abbreviation: "AR", Name: "Arkansas"},
+	{FIPS: "06", Abbreviation: "CA", Name: "California"},
+	{FIPS: "08", Abbreviation: "CO", Name: "Colorado"},
+	{FIPS: "09", Abbreviation: "CT", Name: "Connecticut"},
+	{FIPS: "10", Abbreviation: "DE", Name: "Delaware"},
+	{FIPS: "11", Abbreviation: "DC", Name: "District of Columbia"},
+	{FIPS: "12", Abbreviation: "FL", Name: "Florida"},
+	{FIPS: "13", Abbreviation: "GA", Name: "Georgia"},
+	{FIPS: "15", Abbreviation: "HI", Name: "Hawaii"},
+	{FIPS: "16", Abbreviation: "ID", Name: "Idaho"},
+	{FIPS: "17", Abbreviation: "IL", Name: "Illinois"},
+	{FIPS: "18", Abbreviation: "IN", Name: "Indiana"},
+	{FIPS: "19", Abbreviation: "IA", Name: "Iowa"},
+	{FIPS: "20", Abbreviation: "KS", Name: "Kansas"},
+	{FIPS: "21", Abbreviation: "KY", Name: "Kentucky"},
+	{FIPS: "22", Abbreviation: "LA", Name: "Louisiana"},
+	{FIPS: "23", Abbreviation: "ME", Name: "Maine"},
+	{FIPS: "24", Abbreviation: "MD", Name: "Maryland"},
+	{FIPS: "25", Abbreviation: "MA", Name: "Massachusetts"},
+	{FIPS: "26", Abbreviation: "MI", Name: "Michigan"},
+	{FIPS: "27", Abbreviation: "MN", Name: "Minnesota"},
+	{FIPS: "28", Abbreviation: "MS", Name: "Mississippi"},
+	{FIPS: "29", Abbreviation: "MO", Name: "Missouri"},
+	{FIPS: "30", Abbreviation: "MT", Name: "Montana"},
+	{FIPS: "31", Abbreviation: "NE", Name: "Nebraska"},
+	{FIPS: "32", Abbreviation: "NV", Name: "Nevada"},
+	{FIPS: "33", Abbreviation: "NH", Name: "New Hampshire"},
+	{FIPS: "34", Abbreviation: "NJ", Name: "New Jersey"},
+	{FIPS: "35", Abbreviation: "NM", Name: "New Mexico"},
+	{FIPS: "36", Abbreviation: "NY", Name: "New York"},
+	{FIPS: "37", Abbreviation: "NC", Name: "North Carolina"},
+	{FIPS: "38", Abbreviation: "ND", Name: "North Dakota"},
+	{FIPS: "39", Abbreviation: "OH", Name: "Ohio"},
+	{FIPS: "40", Abbreviation: "OK", Name: "Oklahoma"},
+	{FIPS: "41", Abbreviation: "OR", Name: "Oregon"},
+	{FIPS: "42", Abbreviation: "PA", Name: "Pennsylvania"},
+	{FIPS: "44", Abbreviation: "RI", Name: "Rhode Island"},
+	{FIPS: "45", Abbreviation: "SC", Name: "South Carolina"},
+	{FIPS: "46", Abbreviation: "SD", Name: "South Dakota"},
+	{FIPS: "47", Abbreviation: "TN", Name: "Tennessee"},
+	{FIPS: "48", Abbreviation: "TX", Name: "Texas"},
+	{FIPS: "49", Abbreviation: "UT", Name: "Utah"},
+	{FIPS: "50", Abbreviation: "VT", Name: "Vermont"},
+	{FIPS: "51", Abbreviation: "VA", Name: "Virginia"},
+	{FIPS: "53", Abbreviation: "WA", Name: "Washington"},
+	{FIPS: "54", Abbreviation: "WV", Name: "West Virginia"},
+	{FIPS: "55", Abbreviation: "WI", Name: "Wisconsin"},
+	{FIPS: "56", Abbreviation: "WY", Name: "Wyoming"},
+	{FIPS: "72", Abbreviation: "PR", Name: "Puerto Rico"},
+}
+
+// LookupState ищет штат по коду FIPS, почтовому сокращению или полному названию
+func LookupState(value string) (StateInfo, bool) {
+	value = strings.TrimSpace(value)
+	for _, state := range States {
+		if state.FIPS == value ||
+			strings.EqualFold(state.Abbreviation, value) ||
+			strings.EqualFold(state.Name, value) {
+			return state, true
+		}
+	}
+
+	return StateInfo{}, false
+}
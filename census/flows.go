@@ -0,0 +1,185 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+)
+
+// DatasetACSFlows - набор данных миграционных потоков ACS (по 5-летним оценкам)
+const DatasetACSFlows = "acs/flows"
+
+// defaultFlowLimit - количество географий-контрагентов в списках притока и оттока по умолчанию
+const defaultFlowLimit = 10
+
+// flowVariables - переменные Census API Flows. Переменные с суффиксом 1 относятся к выбранной
+// географии, с суффиксом 2 - к географии-контрагенту.
+var flowVariables = []string{
+	"FULL1_NAME", "FULL2_NAME", "GEOID1", "GEOID2",
+	"MOVEDIN", "MOVEDIN_M", "MOVEDOUT", "MOVEDOUT_M", "MOVEDNET", "MOVEDNET_M",
+}
+
+// MigrationFlowRequest представляет запрос миграционных потоков географии
+type MigrationFlowRequest struct {
+	Year      string            // Последний год 5-летнего периода ACS (например, "2019" для 2015-2019)
+	GeoLevel  string            // "county" или уровень CBSA
+	GeoFilter map[string]string // Фильтр, выбирающий ровно одну географию
+	Limit     int               // Количество контрагентов в списках (по умолчанию 10)
+}
+
+// MigrationCounterpart содержит потоки между выбранной географией и контрагентом.
+// Для контрагентов за рубежом отток не публикуется (HasOut равен false).
+type MigrationCounterpart struct {
+	GeoID       string  `json:"geoid"`
+	Name        string  `json:"name"`
+	MovedIn     float64 `json:"moved_in"`
+	MovedInMOE  float64 `json:"moved_in_moe"`
+	HasIn       bool    `json:"has_in"`
+	MovedOut    float64 `json:"moved_out"`
+	MovedOutMOE float64 `json:"moved_out_moe"`
+	HasOut      bool    `json:"has_out"`
+	Net         float64 `json:"net"`
+	NetMOE      float64 `json:"net_moe"`
+	HasNet      bool    `json:"has_net"`
+}
+
+// MigrationFlowResult содержит крупнейшие потоки притока и оттока географии
+type MigrationFlowResult struct {
+	Year     string `json:"year"`
+	GeoLevel string `json:"geo_level"`
+	GeoID    string `json:"geoid"`
+	Name     string `json:"name"`
+	// TotalIn, TotalOut и TotalNet - суммы опубликованных потоков по всем контрагентам
+	TotalIn      Estimate               `json:"total_in"`
+	TotalOut     Estimate               `json:"total_out"`
+	TotalNet     Estimate               `json:"total_net"`
+	Counterparts int                    `json:"counterparts"`
+	Inflows      []MigrationCounterpart `json:"inflows"`
+	Outflows     []MigrationCounterpart `json:"outflows"`
+}
+
+// GetMigrationFlows запрашивает миграционные потоки ACS между географией и всеми контрагентами
+// и возвращает крупнейшие потоки притока и оттока с чистой миграцией и MOE
+func GetMigrationFlows(api CensusAPIClient, request MigrationFlowRequest) (*MigrationFlowResult, error) {
+	slog.Info("Получение миграционных потоков",
+		key_year, request.Year,
+		key_geo_level, request.GeoLevel)
+
+	if request.GeoLevel != "county" && request.GeoLevel != GeoLevelCBSA {
+		return nil, i18n.Errorf("миграционные потоки поддерживаются для уровней county и %s", GeoLevelCBSA)
+	}
+
+	code := request.GeoFilter[request.GeoLevel]
+	if code == "" || code == "*" || strings.Contains(code, ",") {
+		return nil, i18n.Errorf("фильтр географии должен выбирать одну географию уровня %s", request.GeoLevel)
+	}
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultFlowLimit
+	}
+
+	data, err := api.GetCustomData(CustomDataRequest{
+		Variables: flowVariables,
+		Dataset:   DatasetACSFlows,
+		Year:      request.Year,
+		GeoLevel:  request.GeoLevel,
+		GeoFilter: request.GeoFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, i18n.Errorf("нет данных о миграционных потоках")
+	}
+
+	result := &MigrationFlowResult{
+		Year:     request.Year,
+		GeoLevel: request.GeoLevel,
+		GeoID:    data[0]["GEOID1"],
+		Name:     data[0]["FULL1_NAME"],
+	}
+
+	var ins, outs, nets []Estimate
+	counterparts := make([]MigrationCounterpart, 0, len(data))
+	for _, row := range data {
+		counterpart := MigrationCounterpart{
+			GeoID: row["GEOID2"],
+			Name:  row["FULL2_NAME"],
+		}
+
+		if in, ok := flowEstimate(row, "MOVEDIN"); ok {
+			counterpart.MovedIn, counterpart.MovedInMOE, counterpart.HasIn = in.Value, in.MOE, true
+			ins = append(ins, in)
+		}
+		if out, ok := flowEstimate(row, "MOVEDOUT"); ok {
+			counterpart.MovedOut, counterpart.MovedOutMOE, counterpart.HasOut = out.Value, out.MOE, true
+			outs = append(outs, out)
+		}
+		if net, ok := flowEstimate(row, "MOVEDNET"); ok {
+			counterpart.Net, counterpart.NetMOE, counterpart.HasNet = net.Value, net.MOE, true
+			nets = append(nets, net)
+		}
+
+		if counterpart.HasIn || counterpart.HasOut {
+			counterparts = append(counterparts, counterpart)
+		}
+	}
+
+	result.Counterparts = len(counterparts)
+	result.TotalIn = SumEstimates(ins...)
+	result.TotalOut = SumEstimates(outs...)
+	result.TotalNet = SumEstimates(nets...)
+
+	result.Inflows = topMigrationFlows(counterparts, limit, func(c MigrationCounterpart) (float64, bool) {
+		return c.MovedIn, c.HasIn
+	})
+	result.Outflows = topMigrationFlows(counterparts, limit, func(c MigrationCounterpart) (float64, bool) {
+		return c.MovedOut, c.HasOut
+	})
+
+	return result, nil
+}
+
+// flowEstimate извлекает поток и его MOE из строки ответа Flows API, где MOE переменной
+// имеет суффикс "_M" (например, MOVEDIN_M)
+func flowEstimate(row map[string]string, variable string) (Estimate, bool) {
+	value, ok := ParseEstimate(row[variable])
+	if !ok {
+		return Estimate{}, false
+	}
+
+	estimate := Estimate{Value: value}
+	if moe, ok := ParseEstimate(row[variable+"_M"]); ok {
+		estimate.MOE = math.Abs(moe)
+		estimate.HasMOE = true
+	}
+	return estimate, true
+}
+
+// topMigrationFlows возвращает limit контрагентов с наибольшим значением потока
+func topMigrationFlows(counterparts []MigrationCounterpart, limit int, value func(MigrationCounterpart) (float64, bool)) []MigrationCounterpart {
+	var flows []MigrationCounterpart
+	for _, counterpart := range counterparts {
+		if v, ok := value(counterpart); ok && v > 0 {
+			flows = append(flows, counterpart)
+		}
+	}
+
+	sort.SliceStable(flows, func(i, j int) bool {
+		vi, _ := value(flows[i])
+		vj, _ := value(flows[j])
+		if vi != vj {
+			return vi > vj
+		}
+		return flows[i].Name < flows[j].Name
+	})
+
+	if len(flows) > limit {
+		flows = flows[:limit]
+	}
+	return flows
+}
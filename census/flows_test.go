@@ -0,0 +1,59 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMigrationFlows(t *testing.T) {
+	flow := func(name, geoID, in, inMOE, out, outMOE, net, netMOE string) map[string]string {
+		return map[string]string{
+			"FULL1_NAME": "Travis County, Texas", "GEOID1": "48453",
+			"FULL2_NAME": name, "GEOID2": geoID,
+			"MOVEDIN": in, "MOVEDIN_M": inMOE,
+			"MOVEDOUT": out, "MOVEDOUT_M": outMOE,
+			"MOVEDNET": net, "MOVEDNET_M": netMOE,
+			"state": "48", "county": "453",
+		}
+	}
+
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			flow("Williamson County, Texas", "48491", "9000", "800", "15000", "1200", "-6000", "1400"),
+			flow("Harris County, Texas", "48201", "7000", "700", "4000", "500", "3000", "860"),
+			flow("Los Angeles County, California", "06037", "3000", "300", "1000", "200", "2000", "360"),
+			flow("Asia", "", "5000", "600", "", "", "", ""),
+		},
+	}
+
+	result, err := GetMigrationFlows(api, MigrationFlowRequest{
+		Year:      "2020",
+		GeoLevel:  "county",
+		GeoFilter: map[string]string{"state": "48", "county": "453"},
+		Limit:     2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, DatasetACSFlows, api.requests[0].Dataset)
+	assert.Equal(t, "Travis County, Texas", result.Name)
+	assert.Equal(t, 4, result.Counterparts)
+
+	// Приток из-за рубежа учитывается, отток за рубеж не публикуется
+	assert.Equal(t, float64(24000), result.TotalIn.Value)
+	assert.Equal(t, float64(20000), result.TotalOut.Value)
+	assert.Equal(t, float64(-1000), result.TotalNet.Value)
+
+	assert.Len(t, result.Inflows, 2)
+	assert.Equal(t, "Williamson County, Texas", result.Inflows[0].Name)
+	assert.Equal(t, "Harris County, Texas", result.Inflows[1].Name)
+	assert.Equal(t, "Williamson County, Texas", result.Outflows[0].Name)
+	assert.Equal(t, float64(1200), result.Outflows[0].MovedOutMOE)
+
+	// Необходима одна конкретная география
+	_, err = GetMigrationFlows(api, MigrationFlowRequest{Year: "2020", GeoLevel: "county", GeoFilter: map[string]string{"state": "48", "county": "*"}})
+	assert.Error(t, err)
+
+	_, err = GetMigrationFlows(api, MigrationFlowRequest{Year: "2020", GeoLevel: "tract", GeoFilter: map[string]string{"tract": "000100"}})
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package census
 
 import (
+	"census_mcp/i18n"
 	"context"
 	"fmt"
 	"log/slog"
@@ -36,7 +37,7 @@ func (f *TextFormatter) Format(ctx context.Context, data interface{}) string {
 
 	if data == nil {
 		slog.WarnContext(ctx, "Попытка форматирования nil данных")
-		return "Нет данных"
+		return i18n.T(ctx, "Нет данных")
 	}
 
 	switch v := data.(type) {
@@ -50,6 +51,32 @@ func (f *TextFormatter) Format(ctx context.Context, data interface{}) string {
 		return f.formatGeographyLevel(ctx, v)
 	case []map[string]string:
 		return f.formatCustomData(ctx, v)
+	case []CBSAInfo:
+		return f.formatCBSAInfo(ctx, v)
+	case *YearComparison:
+		return f.formatYearComparison(ctx, v)
+	case *AggregationResult:
+		return f.formatAggregation(ctx, v)
+	case *QuantileResult:
+		return f.formatQuantiles(ctx, v)
+	case *RankResult:
+		return f.formatRanking(ctx, v)
+	case *EstimateComparison:
+		return f.formatEstimateComparison(ctx, v)
+	case *ProfileResult:
+		return f.formatProfile(ctx, v)
+	case *DecennialResult:
+		return f.formatDecennial(ctx, v)
+	case *DecennialChangeResult:
+		return f.formatDecennialChange(ctx, v)
+	case *PEPResult:
+		return f.formatPopulationEstimates(ctx, v)
+	case *BusinessResult:
+		return f.formatBusinessPatterns(ctx, v)
+	case []NAICSIndustry:
+		return f.formatNAICSIndustries(ctx, v)
+	case *MigrationFlowResult:
+		return f.formatMigrationFlows(ctx, v)
 	default:
 		slog.WarnContext(ctx, "Неизвестный тип данных для форматирования",
 			key_type, reflect.TypeOf(data))
@@ -63,23 +90,23 @@ func (f *TextFormatter) formatPopulationData(ctx context.Context, data []Populat
 		key_item_count, len(data))
 
 	if len(data) == 0 {
-		return "Нет данных о населении"
+		return i18n.T(ctx, "Нет данных о населении")
 	}
 
 	var sb strings.Builder
-	sb.WriteString("| Регион | Население |\n")
+	sb.WriteString(i18n.T(ctx, "| Регион | Население |\n"))
 	sb.WriteString("|--------|----------|\n")
 
 	for _, item := range data {
 		// Определяем, это штат или округ
 		var regionName string
 		if item.County != "" {
-			regionName = fmt.Sprintf("%s (округ %s, штат %s)", item.Name, item.County, item.State)
+			regionName = i18n.Sprintf(ctx, "%s (округ %s, штат %s)", item.Name, item.County, item.State)
 		} else {
-			regionName = fmt.Sprintf("%s (штат %s)", item.Name, item.State)
+			regionName = i18n.Sprintf(ctx, "%s (штат %s)", item.Name, item.State)
 		}
 
-		sb.WriteString(fmt.Sprintf("| %s | %s |\n", regionName, item.Population))
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", regionName, FormatRawValue(ctx, item.Population, ValueKindCount)))
 	}
 
 	slog.DebugContext(ctx, "Форматирование данных о населении завершено",
@@ -93,21 +120,21 @@ func (f *TextFormatter) formatDatasetInfo(ctx context.Context, data []DatasetInf
 		key_item_count, len(data))
 
 	if len(data) == 0 {
-		return "Нет данных о наборах данных"
+		return i18n.T(ctx, "Нет данных о наборах данных")
 	}
 
 	var sb strings.Builder
-	sb.WriteString("# Доступные наборы данных\n\n")
+	sb.WriteString(i18n.T(ctx, "# Доступные наборы данных\n\n"))
 
 	for _, item := range data {
 		sb.WriteString(fmt.Sprintf("## %s\n", item.Title))
-		sb.WriteString(fmt.Sprintf("- **ID набора**: %s\n", item.Dataset))
-		sb.WriteString(fmt.Sprintf("- **Описание**: %s\n", item.Description))
-		sb.WriteString("- **Доступные годы**: ")
+		sb.WriteString(i18n.Sprintf(ctx, "- **ID набора**: %s\n", item.Dataset))
+		sb.WriteString(i18n.Sprintf(ctx, "- **Описание**: %s\n", item.Description))
+		sb.WriteString(i18n.T(ctx, "- **Доступные годы**: "))
 		if len(item.YearsAvailable) > 0 {
 			sb.WriteString(strings.Join(item.YearsAvailable, ", "))
 		} else {
-			sb.WriteString("Нет информации")
+			sb.WriteString(i18n.T(ctx, "Нет информации"))
 		}
 		sb.WriteString("\n\n")
 	}
@@ -123,11 +150,11 @@ func (f *TextFormatter) formatVariableInfo(ctx context.Context, data map[string]
 		key_item_count, len(data))
 
 	if len(data) == 0 {
-		return "Нет данных о переменных"
+		return i18n.T(ctx, "Нет данных о переменных")
 	}
 
 	var sb strings.Builder
-	sb.WriteString("# Доступные переменные\n\n")
+	sb.WriteString(i18n.T(ctx, "# Доступные переменные\n\n"))
 
 	// Для стабильного вывода, сортируем ключи
 	var keys []string
@@ -140,13 +167,13 @@ func (f *TextFormatter) formatVariableInfo(ctx context.Context, data map[string]
 		item := data[key]
 		sb.WriteString(fmt.Sprintf("## %s: %s\n", key, item.Label))
 		if item.Description != "" {
-			sb.WriteString(fmt.Sprintf("- **Описание**: %s\n", item.Description))
+			sb.WriteString(i18n.Sprintf(ctx, "- **Описание**: %s\n", item.Description))
 		}
 		if item.Concept != "" {
-			sb.WriteString(fmt.Sprintf("- **Концепция**: %s\n", item.Concept))
+			sb.WriteString(i18n.Sprintf(ctx, "- **Концепция**: %s\n", item.Concept))
 		}
 		if item.Group != "" {
-			sb.WriteString(fmt.Sprintf("- **Группа**: %s\n", item.Group))
+			sb.WriteString(i18n.Sprintf(ctx, "- **Группа**: %s\n", item.Group))
 		}
 		sb.WriteString("\n")
 	}
@@ -162,21 +189,21 @@ func (f *TextFormatter) formatGeographyLevel(ctx context.Context, data []Geograp
 		key_item_count, len(data))
 
 	if len(data) == 0 {
-		return "Нет данных о географических уровнях"
+		return i18n.T(ctx, "Нет данных о географических уровнях")
 	}
 
 	var sb strings.Builder
-	sb.WriteString("# Доступные географические уровни\n\n")
+	sb.WriteString(i18n.T(ctx, "# Доступные географические уровни\n\n"))
 
 	for _, item := range data {
 		sb.WriteString(fmt.Sprintf("## %s\n", item.Name))
-		sb.WriteString(fmt.Sprintf("- **Описание**: %s\n", item.Description))
+		sb.WriteString(i18n.Sprintf(ctx, "- **Описание**: %s\n", item.Description))
 
 		if len(item.RequiredFor) > 0 {
-			sb.WriteString(fmt.Sprintf("- **Требуется для**: %s\n", strings.Join(item.RequiredFor, ", ")))
+			sb.WriteString(i18n.Sprintf(ctx, "- **Требуется для**: %s\n", strings.Join(item.RequiredFor, ", ")))
 		}
 
-		sb.WriteString(fmt.Sprintf("- **Поддержка подстановочных знаков**: %t\n", item.Wildcards))
+		sb.WriteString(i18n.Sprintf(ctx, "- **Поддержка подстановочных знаков**: %t\n", item.Wildcards))
 		sb.WriteString("\n")
 	}
 
@@ -191,31 +218,18 @@ func (f *TextFormatter) formatCustomData(ctx context.Context, data []map[string]
 		key_item_count, len(data))
 
 	if len(data) == 0 {
-		return "Нет данных"
+		return i18n.T(ctx, "Нет данных")
 	}
 
 	var sb strings.Builder
 
-	// Получаем все возможные заголовки из всех записей
-	var headers []string
-	headerMap := make(map[string]bool)
-
-	for _, item := range data {
-		for key := range item {
-			if !headerMap[key] {
-				headerMap[key] = true
-				headers = append(headers, key)
-			}
-		}
-	}
-
-	// Сортируем заголовки для стабильного вывода
-	sort.Strings(headers)
+	// Столбцы в порядке ответа Census API, географические столбцы - последними
+	headers := responseColumns(ctx, data)
 
 	// Создаем заголовок таблицы
 	sb.WriteString("| ")
 	for _, header := range headers {
-		sb.WriteString(header + " | ")
+		sb.WriteString(columnLabel(ctx, header) + " | ")
 	}
 	sb.WriteString("\n")
 
@@ -234,7 +248,7 @@ func (f *TextFormatter) formatCustomData(ctx context.Context, data []map[string]
 			if !ok {
 				value = "N/A"
 			}
-			sb.WriteString(value + " | ")
+			sb.WriteString(formatDataCell(ctx, header, value) + " | ")
 		}
 		sb.WriteString("\n")
 	}
@@ -243,3 +257,653 @@ func (f *TextFormatter) formatCustomData(ctx context.Context, data []map[string]
 		key_item_count, len(data))
 	return sb.String()
 }
+
+// formatDataCell оформляет значение столбца пользовательских данных. Географические столбцы
+// и столбцы неизвестного вида (NAME, коды) выводятся как есть.
+func formatDataCell(ctx context.Context, column, value string) string {
+	if kind, ok := dataColumnKind(ctx, column); ok {
+		return FormatRawValue(ctx, value, kind)
+	}
+	return value
+}
+
+// formatCBSAInfo форматирует информацию о статистических ареалах и их составе
+func (f *TextFormatter) formatCBSAInfo(ctx context.Context, data []CBSAInfo) string {
+	slog.DebugContext(ctx, "Форматирование информации о статистических ареалах",
+		key_item_count, len(data))
+
+	if len(data) == 0 {
+		return i18n.T(ctx, "Нет данных о статистических ареалах")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(ctx, "# Статистические ареалы (CBSA)\n\n"))
+
+	for _, item := range data {
+		sb.WriteString(fmt.Sprintf("## %s\n", item.Title))
+		sb.WriteString(i18n.Sprintf(ctx, "- **Код CBSA**: %s\n", item.Code))
+		sb.WriteString(i18n.Sprintf(ctx, "- **Тип**: %s\n", item.Type))
+
+		if len(item.Counties) > 0 {
+			sb.WriteString(i18n.Sprintf(ctx, "- **Округа** (%d):\n", len(item.Counties)))
+			for _, county := range item.Counties {
+				sb.WriteString(i18n.Sprintf(ctx, "  - %s, %s (штат %s, округ %s)\n",
+					county.Name, county.State, county.StateFIPS, county.CountyFIPS))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	slog.DebugContext(ctx, "Форматирование информации о статистических ареалах завершено",
+		key_item_count, len(data))
+	return sb.String()
+}
+
+// formatYearComparison форматирует результат сравнения переменных между выпусками
+func (f *TextFormatter) formatYearComparison(ctx context.Context, data *YearComparison) string {
+	if data == nil || len(data.Rows) == 0 {
+		return i18n.T(ctx, "Нет данных для сравнения")
+	}
+
+	slog.DebugContext(ctx, "Форматирование сравнения выпусков",
+		key_item_count, len(data.Rows))
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Сравнение выпусков %s: %s\n\n", data.Dataset, strings.Join(data.Years, ", ")))
+
+	for _, variable := range data.Variables {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", variable))
+		kind := variableKind(ctx, variable, VariableKind(variable, VariableInfo{}))
+
+		// Заголовок таблицы: GEOID, название, значения по годам и изменения
+		sb.WriteString(i18n.T(ctx, "| GEOID | Регион | "))
+		for _, year := range data.Years {
+			sb.WriteString(year + " | ")
+		}
+		sb.WriteString(i18n.T(ctx, "Изменение | Изменение, % |\n"))
+
+		sb.WriteString("| --- | --- | ")
+		for range data.Years {
+			sb.WriteString("--- | ")
+		}
+		sb.WriteString("--- | --- |\n")
+
+		for _, row := range data.Rows {
+			sb.WriteString(fmt.Sprintf("| %s | %s | ", row.GeoID, row.Name))
+			for _, year := range data.Years {
+				value, ok := row.Values[variable][year]
+				if !ok {
+					value = "N/A"
+				}
+				sb.WriteString(FormatRawValue(ctx, value, kind) + " | ")
+			}
+
+			change, ok := row.Changes[variable]
+			switch {
+			case !ok:
+				sb.WriteString("N/A | N/A |\n")
+			case change.HasPercent:
+				sb.WriteString(fmt.Sprintf("%s | %s |\n", FormatValue(ctx, change.Absolute, kind), FormatValue(ctx, change.Percent, ValueKindPercent)))
+			default:
+				sb.WriteString(fmt.Sprintf("%s | N/A |\n", FormatValue(ctx, change.Absolute, kind)))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(data.DefinitionChanges) > 0 {
+		sb.WriteString(i18n.T(ctx, "## ⚠ Изменения определений переменных\n\n"))
+		sb.WriteString(i18n.T(ctx, "Сравнение этих переменных между указанными выпусками может быть некорректным:\n\n"))
+		for _, change := range data.DefinitionChanges {
+			sb.WriteString(fmt.Sprintf("- **%s** (%s → %s): %s → %s\n",
+				change.Variable, change.FromYear, change.ToYear, definitionChangeText(ctx, change.From), definitionChangeText(ctx, change.To)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(data.MissingYears) > 0 {
+		years := make([]string, 0, len(data.MissingYears))
+		for year := range data.MissingYears {
+			years = append(years, year)
+		}
+		sort.Strings(years)
+
+		sb.WriteString(i18n.T(ctx, "## Недоступные выпуски\n\n"))
+		for _, year := range years {
+			sb.WriteString(fmt.Sprintf("- %s: %s\n", year, i18n.ErrorMessage(ctx, data.MissingYears[year])))
+		}
+		sb.WriteString("\n")
+	}
+
+	if note := InflationNote(ctx, data.Inflation); note != "" {
+		sb.WriteString(note + "\n")
+	}
+
+	slog.DebugContext(ctx, "Форматирование сравнения выпусков завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatAggregation форматирует итоги по пользовательскому региону
+func (f *TextFormatter) formatAggregation(ctx context.Context, data *AggregationResult) string {
+	if data == nil || (len(data.Totals) == 0 && len(data.Medians) == 0) {
+		return i18n.T(ctx, "Нет данных для агрегации")
+	}
+
+	slog.DebugContext(ctx, "Форматирование итогов агрегации",
+		key_item_count, len(data.Totals))
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Пользовательский регион (%s %s)\n\n", data.Dataset, data.Year))
+	sb.WriteString(i18n.Sprintf(ctx, "Объединено географий уровня %s: %d\n\n", data.GeoLevel, len(data.Components)))
+
+	if len(data.Totals) > 0 {
+		sb.WriteString(i18n.T(ctx, "## Итоги\n\n"))
+		sb.WriteString(i18n.T(ctx, "| Переменная | Описание | Сумма | MOE (90%) |\n"))
+		sb.WriteString("| --- | --- | --- | --- |\n")
+		for _, total := range data.Totals {
+			kind := variableKind(ctx, total.Variable, VariableKind(total.Variable, VariableInfo{Label: total.Label}))
+			moe := "N/A"
+			if total.HasMOE {
+				moe = "±" + FormatValue(ctx, total.MOE, kind)
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				total.Variable, total.Label, FormatValue(ctx, total.Value, kind), moe))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(data.Medians) > 0 {
+		sb.WriteString(i18n.T(ctx, "## Переоцененные медианы\n\n"))
+		sb.WriteString(i18n.T(ctx, "Медианы рассчитаны интерполяцией по суммированным интервальным таблицам:\n\n"))
+		sb.WriteString(i18n.T(ctx, "| Переменная | Описание | Таблица | Медиана | Метод |\n"))
+		sb.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, median := range data.Medians {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n",
+				median.Variable, median.Label, median.Table, formatQuantileValue(ctx, median.Estimate), median.Estimate.Method))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(i18n.T(ctx, "## Состав региона\n\n"))
+	sb.WriteString(i18n.T(ctx, "| GEOID | Регион |\n"))
+	sb.WriteString("| --- | --- |\n")
+	for _, row := range data.Components {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", GeoID(row), row["NAME"]))
+	}
+	sb.WriteString("\n")
+
+	if len(data.Skipped) > 0 {
+		sb.WriteString(i18n.T(ctx, "## ⚠ Исключенные переменные\n\n"))
+		sb.WriteString(i18n.T(ctx, "Медианы, проценты и коэффициенты нельзя получить суммированием:\n\n"))
+		for _, skipped := range data.Skipped {
+			sb.WriteString(fmt.Sprintf("- **%s** (%s): %s\n", skipped.Variable, i18n.T(ctx, skipped.Reason), skipped.Label))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(data.MissingGeoIDs) > 0 {
+		sb.WriteString(i18n.Sprintf(ctx, "## Не найденные GEOID\n\n%s\n\n", strings.Join(data.MissingGeoIDs, ", ")))
+	}
+
+	if len(data.Warnings) > 0 {
+		sb.WriteString(i18n.T(ctx, "## Предупреждения\n\n"))
+		for _, warning := range data.Warnings {
+			sb.WriteString("- " + i18n.ErrorMessage(ctx, warning) + "\n")
+		}
+	}
+
+	slog.DebugContext(ctx, "Форматирование итогов агрегации завершено",
+		key_item_count, len(data.Totals))
+	return sb.String()
+}
+
+// definitionChangeText возвращает определение переменной в кавычках или пометку
+// об отсутствии переменной в выпуске
+func definitionChangeText(ctx context.Context, definition string) string {
+	if definition == "" {
+		return i18n.T(ctx, "переменная отсутствует")
+	}
+	return "\"" + definition + "\""
+}
+
+// formatQuantiles форматирует оценки квантилей по интервальной таблице
+func (f *TextFormatter) formatQuantiles(ctx context.Context, data *QuantileResult) string {
+	if data == nil || len(data.Rows) == 0 {
+		return i18n.T(ctx, "Нет данных для оценки квантилей")
+	}
+
+	slog.DebugContext(ctx, "Форматирование оценок квантилей",
+		key_item_count, len(data.Rows))
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Квантили распределения %s: %s (%s %s)\n\n", data.Table, data.Title, data.Dataset, data.Year))
+
+	for _, row := range data.Rows {
+		sb.WriteString(fmt.Sprintf("## %s (%s)\n\n", row.Name, row.GeoID))
+		sb.WriteString(i18n.Sprintf(ctx, "- **Всего наблюдений**: %s\n", FormatValue(ctx, row.Total, ValueKindCount)))
+
+		if row.Error != nil {
+			sb.WriteString(i18n.Sprintf(ctx, "- **Ошибка**: %s\n\n", i18n.ErrorMessage(ctx, row.Error)))
+			continue
+		}
+
+		for _, estimate := range row.Quantiles {
+			value := formatQuantileValue(ctx, estimate)
+			if estimate.HasMOE {
+				value += " ±" + FormatValue(ctx, estimate.MOE, ValueKindMoney)
+			}
+			sb.WriteString(i18n.Sprintf(ctx, "- **Квантиль %s**: %s (метод: %s)\n",
+				FormatValue(ctx, estimate.Quantile, ValueKindNumber), value, estimate.Method))
+		}
+		sb.WriteString("\n")
+	}
+
+	slog.DebugContext(ctx, "Форматирование оценок квантилей завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatQuantileValue форматирует значение квантиля; для открытого интервала выводится его нижняя граница со знаком "+".
+// Все интервальные таблицы (доходы, стоимость жилья, арендная плата) измеряются в долларах.
+func formatQuantileValue(ctx context.Context, estimate QuantileEstimate) string {
+	if estimate.TopCoded {
+		return FormatValue(ctx, estimate.Value, ValueKindMoney) + "+"
+	}
+	return FormatValue(ctx, estimate.Value, ValueKindMoney)
+}
+
+// formatRanking форматирует рейтинг географий по значению переменной
+func (f *TextFormatter) formatRanking(ctx context.Context, data *RankResult) string {
+	if data == nil || len(data.Rows) == 0 {
+		return i18n.T(ctx, "Нет данных для рейтинга")
+	}
+
+	slog.DebugContext(ctx, "Форматирование рейтинга географий",
+		key_item_count, len(data.Rows))
+
+	order := i18n.T(ctx, "наибольшие")
+	if data.Ascending {
+		order = i18n.T(ctx, "наименьшие")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Рейтинг по %s (%s %s)\n\n", data.Variable, data.Dataset, data.Year))
+	sb.WriteString(i18n.Sprintf(ctx, "Показаны %s значения: %d из %d географий уровня %s\n\n",
+		order, len(data.Rows), data.Ranked, data.GeoLevel))
+
+	kind := variableKind(ctx, data.Variable, VariableKind(data.Variable, VariableInfo{}))
+	sb.WriteString(i18n.T(ctx, "| Место | GEOID | Регион | Значение | MOE (90%) | Процентиль |\n"))
+	sb.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, row := range data.Rows {
+		moe := "N/A"
+		if row.HasMOE {
+			moe = "±" + FormatValue(ctx, row.MOE, kind)
+		}
+		sb.WriteString(fmt.Sprintf("| %d | %s | %s | %s | %s | %s |\n",
+			row.Rank, row.GeoID, row.Name, FormatValue(ctx, row.Value, kind), moe, FormatValue(ctx, row.Percentile, ValueKindNumber)))
+	}
+
+	if len(data.Suppressed) > 0 {
+		sb.WriteString(i18n.Sprintf(ctx, "\nНе вошли в рейтинг из-за подавленных или отсутствующих значений (%d): %s\n",
+			len(data.Suppressed), strings.Join(data.Suppressed, ", ")))
+	}
+
+	slog.DebugContext(ctx, "Форматирование рейтинга географий завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatEstimateComparison форматирует результат проверки значимости различия двух оценок
+func (f *TextFormatter) formatEstimateComparison(ctx context.Context, data *EstimateComparison) string {
+	if data == nil {
+		return i18n.T(ctx, "Нет данных для сравнения оценок")
+	}
+
+	slog.DebugContext(ctx, "Форматирование проверки значимости")
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Сравнение оценок %s (%s)\n\n", data.Variable, data.Dataset))
+
+	kind := variableKind(ctx, data.Variable, VariableKind(data.Variable, VariableInfo{}))
+	sb.WriteString(i18n.T(ctx, "| Оценка | Регион | Год | Значение | MOE (90%) |\n"))
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for i, estimate := range []ComparedEstimate{data.First, data.Second} {
+		sb.WriteString(fmt.Sprintf("| %d | %s (%s) | %s | %s | ±%s |\n",
+			i+1, estimate.Name, estimate.GeoID, estimate.Year, FormatValue(ctx, estimate.Value, kind), FormatValue(ctx, estimate.MOE, kind)))
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(i18n.Sprintf(ctx, "- **Разность (1 − 2)**: %s ±%s\n", FormatValue(ctx, data.Difference, kind), FormatValue(ctx, data.DifferenceMOE, kind)))
+	sb.WriteString(i18n.Sprintf(ctx, "- **Z**: %s (критическое значение 1.645)\n", FormatValue(ctx, data.Z, ValueKindNumber)))
+	if data.Overlap > 0 {
+		sb.WriteString(i18n.Sprintf(ctx, "- **Учтено пересечение периодов**: %s общих лет\n", FormatValue(ctx, data.Overlap*100, ValueKindPercent)))
+	}
+
+	if data.Significant {
+		sb.WriteString(i18n.T(ctx, "\n**Вывод**: различие статистически значимо на уровне доверия 90%.\n"))
+	} else {
+		sb.WriteString(i18n.T(ctx, "\n**Вывод**: различие статистически не значимо на уровне доверия 90%.\n"))
+	}
+
+	return sb.String()
+}
+
+// formatProfile форматирует курируемую таблицу профиля ACS: для каждой географии выводятся
+// оценки и проценты строк с их MOE
+func (f *TextFormatter) formatProfile(ctx context.Context, data *ProfileResult) string {
+	if data == nil || len(data.Geographies) == 0 {
+		return i18n.T(ctx, "Нет данных профиля")
+	}
+
+	slog.DebugContext(ctx, "Форматирование профиля ACS",
+		key_item_count, len(data.Geographies))
+
+	withMOE := func(value, moe string, kind ValueKind) string {
+		if value == "" {
+			return "—"
+		}
+		value = FormatRawValue(ctx, value, kind)
+		if moe == "" {
+			return value
+		}
+		return fmt.Sprintf("%s ±%s", value, FormatRawValue(ctx, moe, kind))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s: %s (%s %s)\n", data.Table, i18n.T(ctx, data.Title), data.Dataset, data.Year))
+
+	for _, geography := range data.Geographies {
+		sb.WriteString(fmt.Sprintf("\n## %s (GEOID %s)\n\n", geography.Name, geography.GeoID))
+		sb.WriteString(i18n.T(ctx, "| Переменная | Показатель | Оценка | Процент |\n"))
+		sb.WriteString("| --- | --- | --- | --- |\n")
+		for _, line := range geography.Lines {
+			estimate := line.Code + "E"
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+				line.Code, i18n.T(ctx, line.Label),
+				withMOE(line.Estimate, line.MOE, variableKind(ctx, estimate, VariableKind(estimate, VariableInfo{}))),
+				withMOE(line.Percent, line.PercentMOE, ValueKindPercent)))
+		}
+	}
+
+	sb.WriteString(i18n.T(ctx, "\nMOE указаны для уровня доверия 90%. Прочерк означает, что значение не публикуется для строки.\n"))
+
+	slog.DebugContext(ctx, "Форматирование профиля ACS завершено",
+		key_item_count, len(data.Geographies))
+	return sb.String()
+}
+
+// formatDecennial форматирует показатели переписи: по строке на географию, доли указываются
+// в скобках после значений
+func (f *TextFormatter) formatDecennial(ctx context.Context, data *DecennialResult) string {
+	if data == nil || len(data.Rows) == 0 {
+		return i18n.T(ctx, "Нет данных переписи")
+	}
+
+	slog.DebugContext(ctx, "Форматирование показателей переписи",
+		key_item_count, len(data.Rows))
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# %s\n\n", i18n.T(ctx, data.Title)))
+	sb.WriteString(i18n.Sprintf(ctx, "Набор данных: %s %s, уровень: %s, географий: %d\n\n", data.Dataset, data.Year, data.GeoLevel, len(data.Rows)))
+
+	sb.WriteString(i18n.T(ctx, "| GEOID | Регион |"))
+	separator := "| --- | --- |"
+	for _, value := range data.Rows[0].Values {
+		sb.WriteString(fmt.Sprintf(" %s (%s) |", i18n.T(ctx, value.Label), value.Variable))
+		separator += " --- |"
+	}
+	sb.WriteString("\n" + separator + "\n")
+
+	for _, row := range data.Rows {
+		sb.WriteString(fmt.Sprintf("| %s | %s |", row.GeoID, row.Name))
+		for _, value := range row.Values {
+			cell := "N/A"
+			if value.Available {
+				cell = FormatValue(ctx, value.Value, ValueKindCount)
+				if value.HasShare {
+					cell += fmt.Sprintf(" (%s)", FormatValue(ctx, value.Share, ValueKindPercent))
+				}
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(i18n.T(ctx, "\nДанные переписи являются полным счетом и не имеют MOE. Доли рассчитаны от всего населения или всех жилых единиц.\n"))
+
+	slog.DebugContext(ctx, "Форматирование показателей переписи завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatDecennialChange форматирует изменения показателей между двумя переписями
+func (f *TextFormatter) formatDecennialChange(ctx context.Context, data *DecennialChangeResult) string {
+	if data == nil || (len(data.Rows) == 0 && len(data.OnlyInBase) == 0 && len(data.OnlyInTarget) == 0) {
+		return i18n.T(ctx, "Нет данных для сравнения переписей")
+	}
+
+	slog.DebugContext(ctx, "Форматирование сравнения переписей",
+		key_item_count, len(data.Rows))
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Изменение показателей переписи: %s %s → %s %s\n\n",
+		data.BaseDataset, data.BaseYear, data.TargetDataset, data.TargetYear))
+
+	for _, row := range data.Rows {
+		sb.WriteString(fmt.Sprintf("## %s (GEOID %s)\n\n", row.Name, row.GeoID))
+		sb.WriteString(i18n.Sprintf(ctx, "| Показатель | %s | %s | Изменение | Изменение, %% |\n", data.BaseYear, data.TargetYear))
+		sb.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, value := range row.Values {
+			base, target, change, percent := "N/A", "N/A", "N/A", "N/A"
+			if value.HasChange {
+				base = FormatValue(ctx, value.Base, ValueKindCount)
+				target = FormatValue(ctx, value.Target, ValueKindCount)
+				change = FormatValue(ctx, value.Change, ValueKindCount)
+				if value.HasPercentChange {
+					percent = FormatValue(ctx, value.PercentChange, ValueKindPercent)
+				}
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", i18n.T(ctx, value.Label), base, target, change, percent))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(data.OnlyInBase) > 0 {
+		sb.WriteString(i18n.Sprintf(ctx, "Только в переписи %s (%d): %s\n\n", data.BaseYear, len(data.OnlyInBase), strings.Join(data.OnlyInBase, ", ")))
+	}
+	if len(data.OnlyInTarget) > 0 {
+		sb.WriteString(i18n.Sprintf(ctx, "Только в переписи %s (%d): %s\n\n", data.TargetYear, len(data.OnlyInTarget), strings.Join(data.OnlyInTarget, ", ")))
+	}
+
+	for _, warning := range data.Warnings {
+		sb.WriteString(i18n.T(ctx, "Предупреждение: ") + i18n.ErrorMessage(ctx, warning) + "\n")
+	}
+
+	slog.DebugContext(ctx, "Форматирование сравнения переписей завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatPopulationEstimates форматирует оценки численности населения PEP
+func (f *TextFormatter) formatPopulationEstimates(ctx context.Context, data *PEPResult) string {
+	if data == nil || len(data.Rows) == 0 {
+		return i18n.T(ctx, "Нет оценок численности населения")
+	}
+
+	slog.DebugContext(ctx, "Форматирование оценок численности населения",
+		key_item_count, len(data.Rows))
+
+	titles := map[string]string{
+		PEPKindPopulation:      i18n.T(ctx, "Оценки численности населения"),
+		PEPKindComponents:      i18n.T(ctx, "Компоненты изменения численности населения"),
+		PEPKindCharacteristics: i18n.T(ctx, "Численность населения по характеристикам"),
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# %s (%s, выпуск %s)\n\n", titles[data.Kind], data.Dataset, data.Vintage))
+
+	sb.WriteString(i18n.T(ctx, "| GEOID | Регион | Период |"))
+	separator := "| --- | --- | --- |"
+	for _, column := range data.Columns {
+		sb.WriteString(" " + i18n.T(ctx, column.Label) + " |")
+		separator += " --- |"
+	}
+	sb.WriteString("\n" + separator + "\n")
+
+	for _, row := range data.Rows {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s |", row.GeoID, row.Name, row.Period))
+		for _, column := range data.Columns {
+			value := row.Values[column.Key]
+			// Значения характеристик - расшифрованные подписи кодов, их переводим;
+			// остальные столбцы - численность населения и ее компоненты
+			if _, ok := pepCharacteristicLabels[column.Key]; ok {
+				value = i18n.T(ctx, value)
+			} else {
+				value = FormatRawValue(ctx, value, ValueKindCount)
+			}
+			sb.WriteString(" " + value + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	if data.Kind == PEPKindPopulation {
+		sb.WriteString(i18n.T(ctx, "\nОценки приведены на 1 июля каждого года. Каждый выпуск пересматривает ряд целиком: не смешивайте значения разных выпусков.\n"))
+	}
+
+	slog.DebugContext(ctx, "Форматирование оценок численности населения завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatBusinessPatterns форматирует статистику предприятий по отраслям
+func (f *TextFormatter) formatBusinessPatterns(ctx context.Context, data *BusinessResult) string {
+	if data == nil || len(data.Rows) == 0 {
+		return i18n.T(ctx, "Нет данных о предприятиях")
+	}
+
+	slog.DebugContext(ctx, "Форматирование статистики предприятий",
+		key_item_count, len(data.Rows))
+
+	title := "County Business Patterns"
+	if data.Dataset == DatasetEconomicCensus {
+		title = i18n.T(ctx, "Экономическая перепись")
+	}
+
+	valueOrDash := func(value string) string {
+		if value == "" {
+			return "—"
+		}
+		return FormatRawValue(ctx, value, ValueKindCount)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# %s %s: предприятия по отраслям (%s)\n\n", title, data.Year, data.NAICSVariable))
+
+	header := i18n.T(ctx, "| GEOID | Регион | NAICS | Отрасль | Предприятий | Занятых | Фонд оплаты труда, тыс. $ |")
+	separator := "| --- | --- | --- | --- | --- | --- | --- |"
+	if data.Dataset == DatasetEconomicCensus {
+		header += i18n.T(ctx, " Выручка, тыс. $ |")
+		separator += " --- |"
+	}
+	sb.WriteString(header + "\n" + separator + "\n")
+
+	for _, row := range data.Rows {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |",
+			row.GeoID, row.Name, row.NAICS, row.Industry,
+			valueOrDash(row.Establishments), valueOrDash(row.Employment), valueOrDash(row.Payroll)))
+		if data.Dataset == DatasetEconomicCensus {
+			sb.WriteString(" " + valueOrDash(row.Receipts) + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(i18n.T(ctx, "\nПрочерк означает, что значение скрыто для защиты конфиденциальности или не публикуется.\n"))
+
+	slog.DebugContext(ctx, "Форматирование статистики предприятий завершено",
+		key_item_count, len(data.Rows))
+	return sb.String()
+}
+
+// formatNAICSIndustries форматирует список отраслей NAICS
+func (f *TextFormatter) formatNAICSIndustries(ctx context.Context, data []NAICSIndustry) string {
+	if len(data) == 0 {
+		return i18n.T(ctx, "Отрасли не найдены")
+	}
+
+	slog.DebugContext(ctx, "Форматирование списка отраслей NAICS",
+		key_item_count, len(data))
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(ctx, "# Отрасли NAICS\n\n"))
+	sb.WriteString(i18n.T(ctx, "| Код | Название | Уровень |\n"))
+	sb.WriteString("| --- | --- | --- |\n")
+	for _, industry := range data {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %d |\n", industry.Code, industry.Title, industry.Level()))
+	}
+
+	slog.DebugContext(ctx, "Форматирование списка отраслей NAICS завершено",
+		key_item_count, len(data))
+	return sb.String()
+}
+
+// formatMigrationFlows форматирует крупнейшие миграционные потоки географии
+func (f *TextFormatter) formatMigrationFlows(ctx context.Context, data *MigrationFlowResult) string {
+	if data == nil {
+		return i18n.T(ctx, "Нет данных о миграционных потоках")
+	}
+
+	slog.DebugContext(ctx, "Форматирование миграционных потоков",
+		key_item_count, data.Counterparts)
+
+	withMOE := func(value, moe float64, hasMOE bool) string {
+		if !hasMOE {
+			return FormatValue(ctx, value, ValueKindCount)
+		}
+		return fmt.Sprintf("%s ±%s", FormatValue(ctx, value, ValueKindCount), FormatValue(ctx, moe, ValueKindCount))
+	}
+
+	writeFlows := func(sb *strings.Builder, title string, flows []MigrationCounterpart) {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", title))
+		if len(flows) == 0 {
+			sb.WriteString(i18n.T(ctx, "Нет опубликованных потоков\n\n"))
+			return
+		}
+		sb.WriteString(i18n.T(ctx, "| Контрагент | GEOID | Приток | Отток | Чистая миграция |\n"))
+		sb.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, flow := range flows {
+			in, out, net := "N/A", "N/A", "N/A"
+			if flow.HasIn {
+				in = withMOE(flow.MovedIn, flow.MovedInMOE, true)
+			}
+			if flow.HasOut {
+				out = withMOE(flow.MovedOut, flow.MovedOutMOE, true)
+			}
+			if flow.HasNet {
+				net = withMOE(flow.Net, flow.NetMOE, true)
+			}
+			geoID := flow.GeoID
+			if geoID == "" {
+				geoID = "—"
+			}
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s |\n", flow.Name, geoID, in, out, net))
+		}
+		sb.WriteString("\n")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "# Миграционные потоки: %s (ACS %s, 5-летние оценки)\n\n", data.Name, data.Year))
+	sb.WriteString(i18n.Sprintf(ctx, "- Всего приток: %s\n", withMOE(data.TotalIn.Value, data.TotalIn.MOE, data.TotalIn.HasMOE)))
+	sb.WriteString(i18n.Sprintf(ctx, "- Всего отток: %s\n", withMOE(data.TotalOut.Value, data.TotalOut.MOE, data.TotalOut.HasMOE)))
+	sb.WriteString(i18n.Sprintf(ctx, "- Чистая миграция: %s\n", withMOE(data.TotalNet.Value, data.TotalNet.MOE, data.TotalNet.HasMOE)))
+	sb.WriteString(i18n.Sprintf(ctx, "- Географий-контрагентов: %d\n\n", data.Counterparts))
+
+	writeFlows(&sb, i18n.T(ctx, "Крупнейшие источники притока"), data.Inflows)
+	writeFlows(&sb, i18n.T(ctx, "Крупнейшие направления оттока"), data.Outflows)
+
+	sb.WriteString(i18n.T(ctx, "MOE указаны для уровня доверия 90%; MOE итогов приближенно рассчитаны как корень из суммы квадратов. Отток за рубеж не публикуется.\n"))
+
+	slog.DebugContext(ctx, "Форматирование миграционных потоков завершено",
+		key_item_count, data.Counterparts)
+	return sb.String()
+}
@@ -1,11 +1,14 @@
 package census
 
 import (
+	"census_mcp/i18n"
 	"context"
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestTextFormatter_Format_PopulationData(t *testing.T) {
@@ -34,9 +37,9 @@ func TestTextFormatter_Format_PopulationData(t *testing.T) {
 		"Регион",
 		"Население",
 		"California (штат 06)",
-		"39538223",
+		"39\u00a0538\u00a0223",
 		"Los Angeles County (округ 037, штат 06)",
-		"10014009",
+		"10\u00a0014\u00a0009",
 	}
 
 	for _, str := range expectedStrings {
@@ -186,10 +189,10 @@ func TestTextFormatter_Format_CustomData(t *testing.T) {
 		"B01001_001E",
 		"state",
 		"California",
-		"39538223",
+		"39\u00a0538\u00a0223",
 		"06",
 		"Texas",
-		"29145505",
+		"29\u00a0145\u00a0505",
 		"48",
 	}
 
@@ -216,3 +219,126 @@ func TestNewTextFormatter(t *testing.T) {
 	assert.NotNil(t, formatter)
 	assert.Equal(t, "*census.TextFormatter", fmt.Sprintf("%T", formatter))
 }
+
+func TestTextFormatter_Format_English(t *testing.T) {
+	formatter := NewTextFormatter()
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	popData := []PopulationData{{Name: "Los Angeles County", Population: "10014009", State: "06", County: "037"}}
+	result := formatter.Format(ctx, popData)
+	assert.Contains(t, result, "| Region | Population |")
+	assert.Contains(t, result, "Los Angeles County (county 037, state 06)")
+
+	assert.Equal(t, "No data", formatter.Format(ctx, nil))
+}
+
+func TestEnglishCatalog_CoversDomainLabels(t *testing.T) {
+	labels := []string{ZCTANotice}
+	for _, measure := range DecennialMeasures {
+		labels = append(labels, measure.Label)
+	}
+	for _, schema := range decennialSchemas {
+		labels = append(labels, schema.Title)
+	}
+	for _, table := range ProfileTables {
+		labels = append(labels, table.Title)
+		for _, line := range table.Lines {
+			labels = append(labels, line.Label)
+		}
+	}
+	for _, column := range append(append([]PEPColumn{{Label: "Население"}}, pepComponentColumns...), pepCharacteristics...) {
+		labels = append(labels, column.Label)
+	}
+	for _, values := range pepCharacteristicLabels {
+		for _, label := range values {
+			labels = append(labels, label)
+		}
+	}
+	for _, reason := range nonCountWords {
+		labels = append(labels, reason)
+	}
+	labels = append(labels, "процент")
+
+	for _, label := range labels {
+		assert.NotEqual(t, label, i18n.Translate(i18n.English, label), "нет английского перевода")
+	}
+}
+
+// unavailableYearAPI - тестовый клиент, для выпуска 2020 возвращающий ошибку Census API
+type unavailableYearAPI struct {
+	*aggregateStubAPI
+}
+
+func (s *unavailableYearAPI) GetCustomData(request CustomDataRequest) ([]map[string]string, error) {
+	if request.Year == "2020" {
+		return nil, i18n.Errorf("API вернул статус %d", 503)
+	}
+	return s.aggregateStubAPI.GetCustomData(request)
+}
+
+func TestFormatters_EnglishResultsWithoutCyrillic(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	table := BinnedTables["B19001"]
+	suppressed := map[string]string{"NAME": "Alpine County", "state": "06", "county": "003",
+		"B01001_001E": "-666666666", "B19001_001E": "-666666666", "B19013_001E": "-666666666"}
+	complete := map[string]string{"NAME": "Kern County", "state": "06", "county": "029",
+		"B01001_001E": "900000", "B19001_001E": "100", "B19013_001E": "52000"}
+	for _, bin := range table.Bins {
+		suppressed[bin.Variable] = "-666666666"
+		complete[bin.Variable] = "0"
+	}
+	complete["B19001_010E"] = "100"
+
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows:          []map[string]string{suppressed, complete},
+		variables: map[string]VariableInfo{
+			"B01001_001E": {Label: "Estimate!!Total:", Concept: "SEX BY AGE"},
+			"B19013_001E": {Label: "Estimate!!Median household income in the past 12 months", Concept: "MEDIAN HOUSEHOLD INCOME"},
+			"B19301_001E": {Label: "Estimate!!Per capita income in the past 12 months", Concept: "PER CAPITA INCOME"},
+		},
+	}
+
+	// Неполная сумма, медиана с подавленными интервалами и исключенный коэффициент
+	aggregation, err := AggregateGeographies(api, AggregationRequest{
+		GeoIDs:          []string{"06003", "06029"},
+		GeoLevel:        "county",
+		Variables:       []string{"B01001_001E", "B19013_001E", "B19301_001E"},
+		Dataset:         "acs/acs5",
+		Year:            "2021",
+		SkipNonAdditive: true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, aggregation.Warnings)
+	require.NotEmpty(t, aggregation.Skipped)
+
+	// Интервалы первой географии подавлены
+	quantiles, err := EstimateQuantiles(api, QuantileRequest{
+		Table: "B19001", Dataset: "acs/acs5", Year: "2021", GeoLevel: "county", Quantiles: []float64{0.5},
+	})
+	require.NoError(t, err)
+	require.Error(t, quantiles.Rows[0].Error)
+
+	// Выпуск 2020 недоступен, переменная B19013_001E отсутствует в описаниях 2019 года
+	comparison, err := CompareYears(&unavailableYearAPI{api}, YearComparisonRequest{
+		Variables: []string{"NAME", "B01001_001E"},
+		Dataset:   "acs/acs5",
+		Years:     []string{"2019", "2020", "2021"},
+		GeoLevel:  "county",
+		GeoFilter: map[string]string{"state": "06", "county": "*"},
+	})
+	require.NoError(t, err)
+	require.Contains(t, comparison.MissingYears, "2020")
+	comparison.DefinitionChanges = append(comparison.DefinitionChanges, VariableDefinitionChange{
+		Variable: "B19013_001E", FromYear: "2019", ToYear: "2021", To: "Estimate!!Median household income",
+	})
+
+	cyrillic := regexp.MustCompile(`\p{Cyrillic}`)
+	for _, formatter := range []Formatter{NewTextFormatter(), NewJSONFormatter()} {
+		for _, result := range []interface{}{aggregation, quantiles, comparison} {
+			output := formatter.Format(ctx, result)
+			assert.False(t, cyrillic.MatchString(output), "кириллица в результате %T:\n%s", result, output)
+		}
+	}
+}
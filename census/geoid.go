@@ -0,0 +1,72 @@
+package census
+
+import (
+	"strings"
+)
+
+// geoIDColumns - географические столбцы ответа Census API в порядке их вложенности.
+// GEOID строится конкатенацией кодов из присутствующих в строке столбцов.
+var geoIDColumns = []string{
+	"us",
+	"region",
+	"division",
+	"state",
+	"county",
+	"county subdivision",
+	"subminor civil division",
+	"place",
+	"consolidated city",
+	"tract",
+	"block group",
+	"block",
+	"public use microdata area",
+	"school district (elementary)",
+	"school district (secondary)",
+	"school district (unified)",
+	"american indian area/alaska native area/hawaiian home land",
+	GeoLevelZCTA,
+	"zip code",
+	GeoLevelCongressionalDistrict,
+	GeoLevelStateLegislativeUpper,
+	GeoLevelStateLegislativeLower,
+	"combined statistical area",
+	GeoLevelCBSA,
+	"metropolitan division",
+	"new england city and town area",
+	"urban area",
+}
+
+// IsGeographyColumn сообщает, является ли столбец ответа географическим идентификатором
+func IsGeographyColumn(column string) bool {
+	for _, geo := range geoIDColumns {
+		if geo == column {
+			return true
+		}
+	}
+	return false
+}
+
+// GeoID возвращает идентификатор географии строки ответа Census API
+// (например, "06037" для округа Лос-Анджелес)
+func GeoID(row map[string]string) string {
+	var sb strings.Builder
+	for _, column := range geoIDColumns {
+		if value, ok := row[column]; ok {
+			sb.WriteString(value)
+		}
+	}
+	return sb.String()
+}
+
+// WildcardGeoFilter возвращает копию фильтра географии, в которой для запрашиваемого уровня
+// указан символ "*", если фильтр задает только родительские географии (например, {"state": "48"})
+func WildcardGeoFilter(geoLevel string, geoFilter map[string]string) map[string]string {
+	filter := make(map[string]string, len(geoFilter)+1)
+	for level, code := range geoFilter {
+		filter[level] = code
+	}
+	if filter[geoLevel] == "" {
+		filter[geoLevel] = "*"
+	}
+	return filter
+}
@@ -0,0 +1,106 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+)
+
+// GeoJSONMIMEType - MIME-тип документов GeoJSON (RFC 7946)
+const GeoJSONMIMEType = "application/geo+json"
+
+// GeoJSONFeature - объект GeoJSON: география результата с ее границей. Свойства совпадают
+// со значениями строки документа JSONFormatter и дополнены GEOID; geometry равна null,
+// если граница географии не найдена.
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   json.RawMessage        `json:"geometry"`
+}
+
+// GeoJSONFeatureCollection - корневой объект документов GeoJSONFormatter
+type GeoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFormatter форматирует строки результатов с географическими кодами в FeatureCollection
+// GeoJSON, присоединяя к ним границы из BoundaryStore по GEOID
+type GeoJSONFormatter struct {
+	boundaries *BoundaryStore
+	json       *JSONFormatter
+}
+
+// NewGeoJSONFormatter создает новый экземпляр GeoJSON-форматтера. Если boundaries равен nil,
+// объекты выводятся без геометрии.
+func NewGeoJSONFormatter(boundaries *BoundaryStore) *GeoJSONFormatter {
+	slog.DebugContext(context.Background(), "Создание нового GeoJSON-форматтера")
+	return &GeoJSONFormatter{
+		boundaries: boundaries,
+		json:       NewJSONFormatter(),
+	}
+}
+
+// Format форматирует данные Census API в FeatureCollection GeoJSON. Поддерживаются строки
+// ответа Census API и данные о населении; для прочих данных возвращается документ ошибки JSON.
+func (f *GeoJSONFormatter) Format(ctx context.Context, data interface{}) string {
+	slog.DebugContext(ctx, "Форматирование данных в GeoJSON",
+		key_type, reflect.TypeOf(data))
+
+	var doc *JSONDocument
+	var keys []map[string]string
+	switch v := data.(type) {
+	case []map[string]string:
+		doc = f.json.customDataDocument(ctx, v)
+		keys = v
+	case []PopulationData:
+		doc = f.json.populationDocument(ctx, v)
+		keys = make([]map[string]string, len(v))
+		for i, item := range v {
+			keys[i] = map[string]string{"state": item.State}
+			if item.County != "" {
+				keys[i]["county"] = item.County
+			}
+		}
+	default:
+		slog.WarnContext(ctx, "Данные не поддерживаются форматом GeoJSON",
+			key_type, reflect.TypeOf(data))
+		return f.json.encodeError(ctx, i18n.T(ctx, "формат geojson поддерживает только строки данных с географическими кодами"))
+	}
+
+	collection := GeoJSONFeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]GeoJSONFeature, 0, len(doc.Rows)),
+	}
+	missing := 0
+	for i, row := range doc.Rows {
+		feature := GeoJSONFeature{Type: "Feature", Properties: row}
+		if layer, geoid, ok := BoundaryKey(keys[i]); ok {
+			feature.ID = geoid
+			feature.Properties["GEOID"] = geoid
+			if f.boundaries != nil {
+				feature.Geometry, _ = f.boundaries.Lookup(ctx, layer, geoid)
+			}
+		}
+		if feature.Geometry == nil && f.boundaries != nil {
+			missing++
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+	if missing > 0 {
+		slog.WarnContext(ctx, "Не найдены границы части географий",
+			key_missing, missing,
+			key_count, len(collection.Features))
+	}
+
+	encoded, err := json.Marshal(collection)
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка сериализации GeoJSON",
+			key_err, err)
+		return f.json.encodeError(ctx, err.Error())
+	}
+	return string(encoded)
+}
@@ -0,0 +1,68 @@
+package census
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoJSONFormatter_Rows(t *testing.T) {
+	dir := t.TempDir()
+	shp, dbf := testShapefile([]string{"06037"}, [][][][2]float64{{clockwiseSquare}})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cb_2021_us_county_500k.shp"), shp, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cb_2021_us_county_500k.dbf"), dbf, 0o644))
+
+	store, err := NewBoundaryStore(dir)
+	require.NoError(t, err)
+
+	result := NewGeoJSONFormatter(store).Format(context.Background(), []map[string]string{
+		{"NAME": "Los Angeles County, California", "B01001_001E": "10014009", "state": "06", "county": "037"},
+		{"NAME": "Harris County, Texas", "B01001_001E": "-666666666", "state": "48", "county": "201"},
+	})
+
+	var collection GeoJSONFeatureCollection
+	require.NoError(t, json.Unmarshal([]byte(result), &collection))
+	assert.Equal(t, "FeatureCollection", collection.Type)
+	require.Len(t, collection.Features, 2)
+
+	losAngeles := collection.Features[0]
+	assert.Equal(t, "Feature", losAngeles.Type)
+	assert.Equal(t, "06037", losAngeles.ID)
+	assert.Equal(t, "06037", losAngeles.Properties["GEOID"])
+	assert.Equal(t, "037", losAngeles.Properties["county"])
+	assert.Equal(t, 10014009.0, losAngeles.Properties["B01001_001E"])
+	assert.Contains(t, string(losAngeles.Geometry), `"type":"Polygon"`)
+
+	// Граница не найдена, служебное значение Census API выводится как null
+	harris := collection.Features[1]
+	assert.Equal(t, "48201", harris.ID)
+	assert.Nil(t, harris.Properties["B01001_001E"])
+	assert.Equal(t, "null", string(harris.Geometry))
+}
+
+func TestGeoJSONFormatter_WithoutBoundaries(t *testing.T) {
+	result := NewGeoJSONFormatter(nil).Format(context.Background(), []PopulationData{
+		{Name: "California", Population: "39538223", State: "06"},
+	})
+
+	assert.JSONEq(t, `{"type": "FeatureCollection", "features": [{
+		"type": "Feature",
+		"id": "06",
+		"properties": {"name": "California", "geoid": "06", "GEOID": "06", "state": "06", "county": "", "population": 39538223},
+		"geometry": null
+	}]}`, result)
+}
+
+func TestGeoJSONFormatter_UnsupportedData(t *testing.T) {
+	result := NewGeoJSONFormatter(nil).Format(context.Background(), []NAICSIndustry{{Code: "72", Title: "Accommodation and Food Services"}})
+
+	var doc JSONDocument
+	require.NoError(t, json.Unmarshal([]byte(result), &doc))
+	assert.Equal(t, JSONTypeError, doc.Type)
+	assert.Contains(t, doc.Error, "geojson")
+}
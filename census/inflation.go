@@ -0,0 +1,234 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Константы для ключей логирования
+const (
+	key_series      = "series"
+	key_target_year = "target_year"
+)
+
+// Индексы потребительских цен для пересчета в постоянные доллары
+const (
+	// CPISeriesURS - CPI-U-RS (Dec 1977 = 100), рекомендуемый Census Bureau для сравнения доходов между годами
+	CPISeriesURS = "cpi-u-rs"
+	// CPISeriesU - CPI-U (1982-84 = 100), используемый ACS для пересчета внутри периода оценки
+	CPISeriesU = "cpi-u"
+)
+
+// cpiCSV - среднегодовые значения индексов BLS CPI-U-RS и CPI-U. Пустое значение
+// означает, что индекс за год еще не опубликован.
+//
+//go:embed data/cpi.csv
+var cpiCSV string
+
+var (
+	cpiOnce   sync.Once
+	cpiTable  map[string]map[string]float64
+	cpiError  error
+	cpiSeries = []string{CPISeriesURS, CPISeriesU}
+)
+
+// loadCPI разбирает встроенную таблицу индексов потребительских цен
+func loadCPI() (map[string]map[string]float64, error) {
+	cpiOnce.Do(func() {
+		records, err := csv.NewReader(strings.NewReader(cpiCSV)).ReadAll()
+		if err != nil {
+			cpiError = i18n.Errorf("ошибка при чтении таблицы индексов цен: %w", err)
+			return
+		}
+
+		cpiTable = make(map[string]map[string]float64, len(cpiSeries))
+		for _, series := range cpiSeries {
+			cpiTable[series] = make(map[string]float64)
+		}
+
+		for i, record := range records {
+			// Первая строка - заголовки
+			if i == 0 {
+				continue
+			}
+			if len(record) != len(cpiSeries)+1 {
+				cpiError = i18n.Errorf("некорректная строка %d в таблице индексов цен", i+1)
+				return
+			}
+
+			for j, series := range cpiSeries {
+				if record[j+1] == "" {
+					continue
+				}
+				value, err := strconv.ParseFloat(record[j+1], 64)
+				if err != nil {
+					cpiError = i18n.Errorf("некорректное значение индекса в строке %d: %w", i+1, err)
+					return
+				}
+				cpiTable[series][record[0]] = value
+			}
+		}
+	})
+
+	return cpiTable, cpiError
+}
+
+// CPIIndex возвращает среднегодовое значение индекса цен за год
+func CPIIndex(series, year string) (float64, error) {
+	table, err := loadCPI()
+	if err != nil {
+		return 0, err
+	}
+
+	values, ok := table[series]
+	if !ok {
+		return 0, i18n.Errorf("неизвестный индекс цен %q; доступны: %s", series, strings.Join(cpiSeries, ", "))
+	}
+
+	value, ok := values[year]
+	if !ok {
+		years := make([]string, 0, len(values))
+		for y := range values {
+			years = append(years, y)
+		}
+		sort.Strings(years)
+		return 0, i18n.Errorf("нет значения индекса %s за %s год (доступны %s-%s)", series, year, years[0], years[len(years)-1])
+	}
+
+	return value, nil
+}
+
+// InflationFactor возвращает множитель для пересчета долларов fromYear в доллары toYear
+func InflationFactor(series, fromYear, toYear string) (float64, error) {
+	from, err := CPIIndex(series, fromYear)
+	if err != nil {
+		return 0, err
+	}
+
+	to, err := CPIIndex(series, toYear)
+	if err != nil {
+		return 0, err
+	}
+
+	return to / from, nil
+}
+
+// IsDollarVariable определяет по подписи и концепции, измеряется ли переменная в долларах
+// (например, "Median household income ... (in 2021 inflation-adjusted dollars)")
+func IsDollarVariable(info VariableInfo) bool {
+	words := strings.FieldsFunc(strings.ToLower(info.Label+" "+info.Concept), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		if word == "dollars" {
+			return true
+		}
+	}
+	return false
+}
+
+// DollarVariables возвращает переменные из списка, измеряемые в долларах, по их описаниям в выпуске.
+// MOE переменных списка не включаются: они пересчитываются вместе с оценками.
+func DollarVariables(api CensusAPIClient, dataset, year string, variables []string) ([]string, error) {
+	definitions, err := api.GetVariables(dataset, year)
+	if err != nil {
+		return nil, i18n.Errorf("не удалось получить описания переменных: %w", err)
+	}
+
+	moes := make(map[string]bool)
+	for _, variable := range variables {
+		if moe, ok := MOEVariable(variable); ok {
+			moes[moe] = true
+		}
+	}
+
+	var result []string
+	for _, variable := range variables {
+		if moes[variable] {
+			continue
+		}
+		if info, ok := definitions[variable]; ok && IsDollarVariable(info) {
+			result = append(result, variable)
+		}
+	}
+
+	return result, nil
+}
+
+// InflationAdjustment описывает пересчет долларовых переменных в постоянные доллары
+type InflationAdjustment struct {
+	Series     string   `json:"series"`
+	TargetYear string   `json:"target_year"`
+	Variables  []string `json:"variables"`
+	// Factors - множители пересчета по исходным годам данных
+	Factors map[string]float64 `json:"factors"`
+}
+
+// AdjustRowsForInflation пересчитывает значения долларовых переменных и их MOE из долларов
+// fromYear в доллары targetYear. Подавленные и нечисловые значения не изменяются.
+func AdjustRowsForInflation(rows []map[string]string, variables []string, series, fromYear, targetYear string) (float64, error) {
+	factor, err := InflationFactor(series, fromYear, targetYear)
+	if err != nil {
+		return 0, err
+	}
+
+	slog.Debug("Пересчет в постоянные доллары",
+		key_series, series,
+		key_year, fromYear,
+		key_target_year, targetYear)
+
+	columns := make([]string, 0, len(variables)*2)
+	for _, variable := range variables {
+		columns = append(columns, variable)
+		if moe, ok := MOEVariable(variable); ok {
+			columns = append(columns, moe)
+		}
+	}
+
+	for _, row := range rows {
+		for _, column := range columns {
+			value, ok := ParseEstimate(row[column])
+			if !ok {
+				continue
+			}
+			row[column] = FormatNumber(value * factor)
+		}
+	}
+
+	return factor, nil
+}
+
+// InflationNote возвращает текстовое примечание о пересчете в постоянные доллары на языке из контекста
+func InflationNote(ctx context.Context, adjustment *InflationAdjustment) string {
+	if adjustment == nil {
+		return ""
+	}
+
+	if len(adjustment.Variables) == 0 {
+		return i18n.T(ctx, "Примечание: среди запрошенных переменных не найдено долларовых, пересчет в постоянные доллары не выполнялся.")
+	}
+
+	years := make([]string, 0, len(adjustment.Factors))
+	for year := range adjustment.Factors {
+		years = append(years, year)
+	}
+	sort.Strings(years)
+
+	factors := make([]string, 0, len(years))
+	for _, year := range years {
+		factors = append(factors, fmt.Sprintf("%s: ×%s", year, strconv.FormatFloat(adjustment.Factors[year], 'f', 4, 64)))
+	}
+
+	return i18n.Sprintf(ctx, "Примечание: переменные %s пересчитаны в доллары %s года по индексу %s (%s). "+
+		"Для 5-летних оценок ACS исходными считаются доллары последнего года периода.",
+		strings.Join(adjustment.Variables, ", "), adjustment.TargetYear, strings.ToUpper(adjustment.Series), strings.Join(factors, ", "))
+}
@@ -0,0 +1,86 @@
+package census
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInflationFactor(t *testing.T) {
+	index, err := CPIIndex(CPISeriesU, "2021")
+	assert.NoError(t, err)
+	assert.Equal(t, 270.970, index)
+
+	factor, err := InflationFactor(CPISeriesURS, "2019", "2022")
+	assert.NoError(t, err)
+	assert.InDelta(t, 431.5/376.5, factor, 1e-9)
+
+	// CPI-U-RS за 2024 год во встроенной таблице отсутствует
+	_, err = InflationFactor(CPISeriesURS, "2019", "2024")
+	assert.Error(t, err)
+
+	_, err = CPIIndex("pce", "2021")
+	assert.Error(t, err)
+}
+
+func TestIsDollarVariable(t *testing.T) {
+	assert.True(t, IsDollarVariable(VariableInfo{
+		Label:   "Estimate!!Median household income in the past 12 months (in 2021 inflation-adjusted dollars)",
+		Concept: "MEDIAN HOUSEHOLD INCOME IN THE PAST 12 MONTHS (IN 2021 INFLATION-ADJUSTED DOLLARS)",
+	}))
+	assert.True(t, IsDollarVariable(VariableInfo{Label: "Estimate!!Median value (dollars)"}))
+	assert.False(t, IsDollarVariable(VariableInfo{Label: "Estimate!!Total:", Concept: "SEX BY AGE"}))
+}
+
+func TestAdjustRowsForInflation(t *testing.T) {
+	rows := []map[string]string{
+		{"B19013_001E": "50000", "B19013_001M": "1000", "B01001_001E": "100"},
+		{"B19013_001E": "-666666666", "B19013_001M": "-222222222", "B01001_001E": "200"},
+	}
+
+	factor, err := AdjustRowsForInflation(rows, []string{"B19013_001E"}, CPISeriesU, "2020", "2021")
+	assert.NoError(t, err)
+	assert.InDelta(t, 270.970/258.811, factor, 1e-9)
+	assert.Equal(t, FormatNumber(50000*factor), rows[0]["B19013_001E"])
+	assert.Equal(t, FormatNumber(1000*factor), rows[0]["B19013_001M"])
+	assert.Equal(t, "100", rows[0]["B01001_001E"])
+	assert.Equal(t, "-666666666", rows[1]["B19013_001E"])
+}
+
+func TestCompareYears_Inflation(t *testing.T) {
+	definition := VariableInfo{Label: "Estimate!!Median household income in the past 12 months (in inflation-adjusted dollars)"}
+	api := &yearStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		data: map[string][]map[string]string{
+			"2019": {{"NAME": "Texas", "B19013_001E": "61874", "B01001_001E": "28995881", "state": "48"}},
+			"2022": {{"NAME": "Texas", "B19013_001E": "72284", "B01001_001E": "29243342", "state": "48"}},
+		},
+		variables: map[string]map[string]VariableInfo{
+			"2019": {"B19013_001E": definition, "B01001_001E": {Label: "Estimate!!Total"}},
+			"2022": {"B19013_001E": definition, "B01001_001E": {Label: "Estimate!!Total"}},
+		},
+	}
+
+	comparison, err := CompareYears(api, YearComparisonRequest{
+		Variables:     []string{"B19013_001E", "B01001_001E"},
+		Years:         []string{"2019", "2022"},
+		GeoLevel:      "state",
+		InflationYear: "2022",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B19013_001E"}, comparison.Inflation.Variables)
+	assert.Equal(t, CPISeriesURS, comparison.Inflation.Series)
+	assert.Equal(t, FormatNumber(61874*431.5/376.5), comparison.Rows[0].Values["B19013_001E"]["2019"])
+	assert.Equal(t, "72284", comparison.Rows[0].Values["B19013_001E"]["2022"])
+	assert.Equal(t, "28995881", comparison.Rows[0].Values["B01001_001E"]["2019"])
+	assert.Contains(t, InflationNote(context.Background(), comparison.Inflation), "CPI-U-RS")
+
+	_, err = CompareYears(api, YearComparisonRequest{
+		Variables:     []string{"B19013_001E"},
+		Years:         []string{"2019", "2022"},
+		GeoLevel:      "state",
+		InflationYear: "1990",
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,525 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// JSONSchemaVersion - версия схемы документов JSONFormatter. Версия увеличивается при
+// несовместимых изменениях: удалении или переименовании полей и смене типов столбцов.
+const JSONSchemaVersion = "1"
+
+// Типы столбцов табличных документов JSON
+const (
+	JSONColumnString    = "string"    // строка
+	JSONColumnNumber    = "number"    // число или null для пустых и служебных значений Census API
+	JSONColumnInteger   = "integer"   // целое число или null
+	JSONColumnBoolean   = "boolean"   // логическое значение
+	JSONColumnGeography = "geography" // код географии (строка, ведущие нули сохраняются)
+	JSONColumnArray     = "array"     // массив
+)
+
+// Поставщик данных и адрес Census API для метаданных источника
+const (
+	jsonSourceProvider = "U.S. Census Bureau"
+	jsonSourceAPI      = "https://api.census.gov/data"
+)
+
+// JSONColumn описывает столбец табличного документа
+type JSONColumn struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Label string `json:"label,omitempty"`
+}
+
+// JSONSource содержит метаданные источника данных. Dataset и Year заполняются,
+// если они известны из результата.
+type JSONSource struct {
+	Provider string `json:"provider"`
+	API      string `json:"api"`
+	Dataset  string `json:"dataset,omitempty"`
+	Year     string `json:"year,omitempty"`
+}
+
+// JSONDocument - корневой объект всех документов JSONFormatter.
+//
+// Табличные данные (население, наборы данных, переменные, географические уровни,
+// пользовательские запросы, CBSA, отрасли NAICS) передаются в полях columns и rows:
+// каждая строка - объект, ключи которого совпадают с именами столбцов, а значения имеют
+// тип столбца. Результаты аналитических инструментов передаются в поле result в виде
+// объекта с полями соответствующей структуры пакета census; columns и rows для них пусты.
+// При ошибке документ имеет тип "error" и поле error.
+type JSONDocument struct {
+	SchemaVersion string                   `json:"schema_version"`
+	Type          string                   `json:"type"`
+	Source        JSONSource               `json:"source"`
+	Columns       []JSONColumn             `json:"columns"`
+	Rows          []map[string]interface{} `json:"rows"`
+	RowCount      int                      `json:"row_count"`
+	Result        interface{}              `json:"result,omitempty"`
+	Error         string                   `json:"error,omitempty"`
+}
+
+// Типы документов JSON
+const (
+	JSONTypePopulation          = "population"
+	JSONTypeDatasets            = "datasets"
+	JSONTypeVariables           = "variables"
+	JSONTypeGeographyLevels     = "geography_levels"
+	JSONTypeCustomData          = "custom_data"
+	JSONTypeCBSA                = "cbsa"
+	JSONTypeNAICSIndustries     = "naics_industries"
+	JSONTypeYearComparison      = "year_comparison"
+	JSONTypeAggregation         = "aggregation"
+	JSONTypeQuantiles           = "quantiles"
+	JSONTypeRanking             = "ranking"
+	JSONTypeEstimateComparison  = "estimate_comparison"
+	JSONTypeProfile             = "profile"
+	JSONTypeDecennial           = "decennial_counts"
+	JSONTypeDecennialChange     = "decennial_change"
+	JSONTypePopulationEstimates = "population_estimates"
+	JSONTypeBusinessPatterns    = "business_patterns"
+	JSONTypeMigrationFlows      = "migration_flows"
+	JSONTypeError               = "error"
+)
+
+// JSONFormatter форматирует данные Census API в JSON со стабильной схемой JSONDocument
+type JSONFormatter struct{}
+
+// NewJSONFormatter создает новый экземпляр JSON-форматтера
+func NewJSONFormatter() *JSONFormatter {
+	slog.DebugContext(context.Background(), "Создание нового JSON-форматтера")
+	return &JSONFormatter{}
+}
+
+// Format форматирует данные Census API в документ JSON
+func (f *JSONFormatter) Format(ctx context.Context, data interface{}) string {
+	slog.DebugContext(ctx, "Форматирование данных в JSON",
+		key_type, reflect.TypeOf(data))
+
+	if data == nil {
+		slog.WarnContext(ctx, "Попытка форматирования nil данных")
+		return f.encodeError(ctx, i18n.T(ctx, "нет данных"))
+	}
+
+	var doc *JSONDocument
+	switch v := data.(type) {
+	case []PopulationData:
+		doc = f.populationDocument(ctx, v)
+	case []DatasetInfo:
+		doc = f.datasetsDocument(ctx, v)
+	case map[string]VariableInfo:
+		doc = f.variablesDocument(ctx, v)
+	case []GeographyLevel:
+		doc = f.geographyLevelsDocument(ctx, v)
+	case []map[string]string:
+		doc = f.customDataDocument(ctx, v)
+	case []CBSAInfo:
+		doc = f.cbsaDocument(ctx, v)
+	case []NAICSIndustry:
+		doc = f.naicsDocument(ctx, v)
+	case *YearComparison:
+		doc = resultDocument(JSONTypeYearComparison, v.Dataset, "", localizedComparison(ctx, v))
+	case *AggregationResult:
+		doc = resultDocument(JSONTypeAggregation, v.Dataset, v.Year, localizedAggregation(ctx, v))
+	case *QuantileResult:
+		doc = resultDocument(JSONTypeQuantiles, v.Dataset, v.Year, localizedQuantiles(ctx, v))
+	case *RankResult:
+		doc = resultDocument(JSONTypeRanking, v.Dataset, v.Year, v)
+	case *EstimateComparison:
+		doc = resultDocument(JSONTypeEstimateComparison, v.Dataset, "", v)
+	case *ProfileResult:
+		doc = resultDocument(JSONTypeProfile, v.Dataset, v.Year, v)
+	case *DecennialResult:
+		doc = resultDocument(JSONTypeDecennial, v.Dataset, v.Year, v)
+	case *DecennialChangeResult:
+		doc = resultDocument(JSONTypeDecennialChange, v.TargetDataset, v.TargetYear, localizedDecennialChange(ctx, v))
+	case *PEPResult:
+		doc = resultDocument(JSONTypePopulationEstimates, v.Dataset, v.Vintage, v)
+	case *BusinessResult:
+		doc = resultDocument(JSONTypeBusinessPatterns, v.Dataset, v.Year, v)
+	case *MigrationFlowResult:
+		doc = resultDocument(JSONTypeMigrationFlows, DatasetACSFlows, v.Year, v)
+	default:
+		slog.WarnContext(ctx, "Неизвестный тип данных для форматирования",
+			key_type, reflect.TypeOf(data))
+		return f.encodeError(ctx, i18n.T(ctx, "неподдерживаемый тип данных: ")+reflect.TypeOf(data).String())
+	}
+
+	return f.encode(ctx, doc)
+}
+
+// encode сериализует документ; при ошибке (например, для значений NaN) возвращает документ ошибки
+func (f *JSONFormatter) encode(ctx context.Context, doc *JSONDocument) string {
+	encoded, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при сериализации данных в JSON",
+			key_type, doc.Type,
+			key_err, err)
+		return f.encodeError(ctx, i18n.T(ctx, "ошибка сериализации данных: ")+err.Error())
+	}
+
+	slog.DebugContext(ctx, "Форматирование данных в JSON завершено",
+		key_type, doc.Type,
+		key_item_count, doc.RowCount)
+	return string(encoded)
+}
+
+// encodeError возвращает документ ошибки
+func (f *JSONFormatter) encodeError(ctx context.Context, message string) string {
+	doc := newJSONDocument(JSONTypeError)
+	doc.Error = message
+	// Документ ошибки содержит только строки, поэтому сериализация не может завершиться ошибкой
+	encoded, _ := json.MarshalIndent(doc, "", "  ")
+	return string(encoded)
+}
+
+// newJSONDocument создает документ заданного типа с метаданными источника
+func newJSONDocument(docType string) *JSONDocument {
+	return &JSONDocument{
+		SchemaVersion: JSONSchemaVersion,
+		Type:          docType,
+		Source: JSONSource{
+			Provider: jsonSourceProvider,
+			API:      jsonSourceAPI,
+		},
+		Columns: []JSONColumn{},
+		Rows:    []map[string]interface{}{},
+	}
+}
+
+// resultDocument создает документ для результата аналитического инструмента
+func resultDocument(docType, dataset, year string, result interface{}) *JSONDocument {
+	doc := newJSONDocument(docType)
+	doc.Source.Dataset = dataset
+	doc.Source.Year = year
+	doc.Result = result
+	return doc
+}
+
+// localizedErrors возвращает копию списка ошибок с текстом на языке из контекста
+func localizedErrors(ctx context.Context, errs []error) []error {
+	if errs == nil {
+		return nil
+	}
+	localized := make([]error, len(errs))
+	for i, err := range errs {
+		localized[i] = i18n.Localize(ctx, err)
+	}
+	return localized
+}
+
+// localizedComparison возвращает копию сравнения выпусков с причинами пропуска выпусков
+// на языке из контекста
+func localizedComparison(ctx context.Context, data *YearComparison) *YearComparison {
+	localized := *data
+	if data.MissingYears != nil {
+		localized.MissingYears = make(map[string]error, len(data.MissingYears))
+		for year, err := range data.MissingYears {
+			localized.MissingYears[year] = i18n.Localize(ctx, err)
+		}
+	}
+	return &localized
+}
+
+// localizedAggregation возвращает копию итогов агрегации с причинами исключения переменных
+// и предупреждениями на языке из контекста
+func localizedAggregation(ctx context.Context, data *AggregationResult) *AggregationResult {
+	localized := *data
+	if data.Skipped != nil {
+		localized.Skipped = make([]NonAdditiveVariable, len(data.Skipped))
+		for i, skipped := range data.Skipped {
+			skipped.Reason = i18n.T(ctx, skipped.Reason)
+			localized.Skipped[i] = skipped
+		}
+	}
+	localized.Warnings = localizedErrors(ctx, data.Warnings)
+	return &localized
+}
+
+// localizedQuantiles возвращает копию оценок квантилей с ошибками строк на языке из контекста
+func localizedQuantiles(ctx context.Context, data *QuantileResult) *QuantileResult {
+	localized := *data
+	localized.Rows = make([]QuantileRow, len(data.Rows))
+	for i, row := range data.Rows {
+		row.Error = i18n.Localize(ctx, row.Error)
+		localized.Rows[i] = row
+	}
+	return &localized
+}
+
+// localizedDecennialChange возвращает копию сравнения переписей с предупреждениями на языке из контекста
+func localizedDecennialChange(ctx context.Context, data *DecennialChangeResult) *DecennialChangeResult {
+	localized := *data
+	localized.Warnings = localizedErrors(ctx, data.Warnings)
+	return &localized
+}
+
+// jsonNumber преобразует значение Census API в число; пустые и служебные значения
+// (например, -666666666) заменяются на null
+func jsonNumber(value string) interface{} {
+	number, ok := ParseEstimate(value)
+	if !ok {
+		return nil
+	}
+	return number
+}
+
+// jsonInteger преобразует значение Census API в целое число или null
+func jsonInteger(value string) interface{} {
+	number, ok := ParseEstimate(value)
+	if !ok || number != float64(int64(number)) {
+		return nil
+	}
+	return int64(number)
+}
+
+// jsonStrings возвращает непустой срез строк для сериализации в массив вместо null
+func jsonStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// populationDocument формирует документ данных о населении штатов и округов
+func (f *JSONFormatter) populationDocument(ctx context.Context, data []PopulationData) *JSONDocument {
+	doc := newJSONDocument(JSONTypePopulation)
+	doc.Source.Dataset = "acs/acs1"
+	doc.Source.Year = "2021"
+	doc.Columns = []JSONColumn{
+		{Name: "name", Type: JSONColumnString, Label: i18n.T(ctx, "Название региона")},
+		{Name: "geoid", Type: JSONColumnGeography, Label: i18n.T(ctx, "GEOID региона")},
+		{Name: "state", Type: JSONColumnGeography, Label: i18n.T(ctx, "Код FIPS штата")},
+		{Name: "county", Type: JSONColumnGeography, Label: i18n.T(ctx, "Код FIPS округа (пусто для штатов)")},
+		{Name: "population", Type: JSONColumnInteger, Label: i18n.T(ctx, "Население (B01001_001E)")},
+	}
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		doc.Rows = append(doc.Rows, map[string]interface{}{
+			"name":       item.Name,
+			"geoid":      item.State + item.County,
+			"state":      item.State,
+			"county":     item.County,
+			"population": jsonInteger(item.Population),
+		})
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
+
+// datasetsDocument формирует документ списка наборов данных
+func (f *JSONFormatter) datasetsDocument(ctx context.Context, data []DatasetInfo) *JSONDocument {
+	doc := newJSONDocument(JSONTypeDatasets)
+	doc.Columns = []JSONColumn{
+		{Name: "dataset", Type: JSONColumnString, Label: i18n.T(ctx, "Идентификатор набора данных")},
+		{Name: "title", Type: JSONColumnString, Label: i18n.T(ctx, "Название")},
+		{Name: "description", Type: JSONColumnString, Label: i18n.T(ctx, "Описание")},
+		{Name: "years_available", Type: JSONColumnArray, Label: i18n.T(ctx, "Доступные годы")},
+	}
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		doc.Rows = append(doc.Rows, map[string]interface{}{
+			"dataset":         item.Dataset,
+			"title":           item.Title,
+			"description":     item.Description,
+			"years_available": jsonStrings(item.YearsAvailable),
+		})
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
+
+// variablesDocument формирует документ списка переменных, упорядоченных по имени
+func (f *JSONFormatter) variablesDocument(ctx context.Context, data map[string]VariableInfo) *JSONDocument {
+	doc := newJSONDocument(JSONTypeVariables)
+	doc.Columns = []JSONColumn{
+		{Name: "name", Type: JSONColumnString, Label: i18n.T(ctx, "Имя переменной")},
+		{Name: "label", Type: JSONColumnString, Label: i18n.T(ctx, "Подпись")},
+		{Name: "concept", Type: JSONColumnString, Label: i18n.T(ctx, "Концепция")},
+		{Name: "group", Type: JSONColumnString, Label: i18n.T(ctx, "Таблица")},
+		{Name: "description", Type: JSONColumnString, Label: i18n.T(ctx, "Описание")},
+	}
+
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, name := range names {
+		item := data[name]
+		if item.Name == "" {
+			item.Name = name
+		}
+		doc.Rows = append(doc.Rows, map[string]interface{}{
+			"name":        item.Name,
+			"label":       item.Label,
+			"concept":     item.Concept,
+			"group":       item.Group,
+			"description": item.Description,
+		})
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
+
+// geographyLevelsDocument формирует документ списка географических уровней
+func (f *JSONFormatter) geographyLevelsDocument(ctx context.Context, data []GeographyLevel) *JSONDocument {
+	doc := newJSONDocument(JSONTypeGeographyLevels)
+	doc.Columns = []JSONColumn{
+		{Name: "name", Type: JSONColumnString, Label: i18n.T(ctx, "Уровень")},
+		{Name: "description", Type: JSONColumnString, Label: i18n.T(ctx, "Описание")},
+		{Name: "required_for", Type: JSONColumnArray, Label: i18n.T(ctx, "Обязательные родительские уровни")},
+		{Name: "wildcards", Type: JSONColumnBoolean, Label: i18n.T(ctx, "Поддержка символа \"*\"")},
+	}
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		doc.Rows = append(doc.Rows, map[string]interface{}{
+			"name":         item.Name,
+			"description":  item.Description,
+			"required_for": jsonStrings(item.RequiredFor),
+			"wildcards":    item.Wildcards,
+		})
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
+
+// customDataDocument формирует документ ответа пользовательского запроса. Типы столбцов
+// определяются по их именам и видам значений (см. customColumnType), а не по значениям,
+// поэтому схема столбца не зависит от состава строк.
+func (f *JSONFormatter) customDataDocument(ctx context.Context, data []map[string]string) *JSONDocument {
+	doc := newJSONDocument(JSONTypeCustomData)
+
+	headers := responseColumns(ctx, data)
+	labels := ColumnLayoutFromContext(ctx).Labels
+
+	doc.Columns = make([]JSONColumn, 0, len(headers))
+	for _, header := range headers {
+		doc.Columns = append(doc.Columns, JSONColumn{Name: header, Type: customColumnType(ctx, header), Label: labels[header]})
+	}
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		row := make(map[string]interface{}, len(doc.Columns))
+		for _, column := range doc.Columns {
+			value, ok := item[column.Name]
+			switch {
+			case !ok:
+				row[column.Name] = nil
+			case column.Type == JSONColumnNumber:
+				row[column.Name] = jsonNumber(value)
+			default:
+				row[column.Name] = value
+			}
+		}
+		doc.Rows = append(doc.Rows, row)
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
+
+// measureColumns - числовые показатели PEP и статистики предприятий, имена которых
+// не имеют суффикса переменной таблицы
+var measureColumns = map[string]bool{
+	"POP":     true,
+	"DENSITY": true,
+	"ESTAB":   true,
+	"EMP":     true,
+	"PAYANN":  true,
+	"RCPTOT":  true,
+}
+
+// customColumnType определяет тип столбца ответа Census API по его имени: географические
+// столбцы имеют тип geography; столбцы с известным видом значений из контекста (вычисляемые
+// столбцы), переменные таблиц (B01001_001E, DP05_0001PE, P1_001N) и показатели PEP
+// и статистики предприятий - тип number; аннотации переменных (B01001_001EA) и прочие
+// столбцы - тип string. Значения строк не учитываются: тип столбца не меняется от того,
+// что на странице все значения пустые или служебные.
+func customColumnType(ctx context.Context, column string) string {
+	switch {
+	case IsGeographyColumn(column):
+		return JSONColumnGeography
+	case isAnnotationColumn(column):
+		return JSONColumnString
+	}
+	if _, ok := NumberFormatFromContext(ctx).Kinds[column]; ok {
+		return JSONColumnNumber
+	}
+	if isVariableColumn(column) || measureColumns[column] {
+		return JSONColumnNumber
+	}
+	return JSONColumnString
+}
+
+// isAnnotationColumn сообщает, является ли столбец аннотацией переменной таблицы: суффиксы
+// EA, MA, PEA, PMA и NA содержат коды примечаний к оценке, MOE или численности переписи
+func isAnnotationColumn(column string) bool {
+	if !isVariableColumn(column) {
+		return false
+	}
+	for _, suffix := range []string{"EA", "MA", "NA"} {
+		if strings.HasSuffix(column, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cbsaDocument формирует документ списка статистических ареалов
+func (f *JSONFormatter) cbsaDocument(ctx context.Context, data []CBSAInfo) *JSONDocument {
+	doc := newJSONDocument(JSONTypeCBSA)
+	doc.Columns = []JSONColumn{
+		{Name: "code", Type: JSONColumnGeography, Label: i18n.T(ctx, "Код CBSA")},
+		{Name: "title", Type: JSONColumnString, Label: i18n.T(ctx, "Название")},
+		{Name: "type", Type: JSONColumnString, Label: i18n.T(ctx, "Тип ареала")},
+		{Name: "counties", Type: JSONColumnArray, Label: i18n.T(ctx, "Округа ареала (объекты state_fips, county_fips, name, state)")},
+	}
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		counties := item.Counties
+		if counties == nil {
+			counties = []CBSACounty{}
+		}
+		doc.Rows = append(doc.Rows, map[string]interface{}{
+			"code":     item.Code,
+			"title":    item.Title,
+			"type":     item.Type,
+			"counties": counties,
+		})
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
+
+// naicsDocument формирует документ списка отраслей NAICS
+func (f *JSONFormatter) naicsDocument(ctx context.Context, data []NAICSIndustry) *JSONDocument {
+	doc := newJSONDocument(JSONTypeNAICSIndustries)
+	doc.Columns = []JSONColumn{
+		{Name: "code", Type: JSONColumnString, Label: i18n.T(ctx, "Код NAICS")},
+		{Name: "title", Type: JSONColumnString, Label: i18n.T(ctx, "Название отрасли")},
+		{Name: "level", Type: JSONColumnInteger, Label: i18n.T(ctx, "Уровень классификатора (2 - сектор)")},
+	}
+
+	doc.Rows = make([]map[string]interface{}, 0, len(data))
+	for _, item := range data {
+		doc.Rows = append(doc.Rows, map[string]interface{}{
+			"code":  item.Code,
+			"title": item.Title,
+			"level": item.Level(),
+		})
+	}
+	doc.RowCount = len(doc.Rows)
+	return doc
+}
@@ -0,0 +1,167 @@
+package census
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// decodeJSONDocument разбирает документ JSONFormatter в обобщенную структуру
+func decodeJSONDocument(t *testing.T, output string) map[string]interface{} {
+	t.Helper()
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(output), &doc))
+	return doc
+}
+
+func TestJSONFormatter_Format_PopulationData(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output := formatter.Format(context.Background(), []PopulationData{
+		{Name: "California", Population: "39538223", State: "06"},
+		{Name: "Los Angeles County", Population: "-666666666", State: "06", County: "037"},
+	})
+
+	doc := decodeJSONDocument(t, output)
+	assert.Equal(t, JSONSchemaVersion, doc["schema_version"])
+	assert.Equal(t, JSONTypePopulation, doc["type"])
+	assert.Equal(t, float64(2), doc["row_count"])
+
+	source := doc["source"].(map[string]interface{})
+	assert.Equal(t, "U.S. Census Bureau", source["provider"])
+	assert.Equal(t, "acs/acs1", source["dataset"])
+
+	columns := doc["columns"].([]interface{})
+	require.Len(t, columns, 5)
+	assert.Equal(t, map[string]interface{}{"name": "population", "type": "integer", "label": "Население (B01001_001E)"}, columns[4])
+
+	rows := doc["rows"].([]interface{})
+	first := rows[0].(map[string]interface{})
+	assert.Equal(t, float64(39538223), first["population"])
+	assert.Equal(t, "06", first["geoid"])
+
+	second := rows[1].(map[string]interface{})
+	assert.Equal(t, "06037", second["geoid"])
+	assert.Nil(t, second["population"])
+}
+
+func TestJSONFormatter_Format_CustomData(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	output := formatter.Format(context.Background(), []map[string]string{
+		{"NAME": "Harris County, Texas", "B19013_001E": "65788", "state": "48", "county": "201"},
+		{"NAME": "Loving County, Texas", "B19013_001E": "-666666666", "state": "48", "county": "301"},
+	})
+
+	doc := decodeJSONDocument(t, output)
+	assert.Equal(t, JSONTypeCustomData, doc["type"])
+
+	types := make(map[string]string)
+	for _, column := range doc["columns"].([]interface{}) {
+		c := column.(map[string]interface{})
+		types[c["name"].(string)] = c["type"].(string)
+	}
+	assert.Equal(t, map[string]string{
+		"NAME":        JSONColumnString,
+		"B19013_001E": JSONColumnNumber,
+		"state":       JSONColumnGeography,
+		"county":      JSONColumnGeography,
+	}, types)
+
+	rows := doc["rows"].([]interface{})
+	assert.Equal(t, float64(65788), rows[0].(map[string]interface{})["B19013_001E"])
+	assert.Equal(t, "201", rows[0].(map[string]interface{})["county"])
+	assert.Nil(t, rows[1].(map[string]interface{})["B19013_001E"])
+}
+
+func TestJSONFormatter_Format_CustomDataStableColumnTypes(t *testing.T) {
+	formatter := NewJSONFormatter()
+	columnTypes := func(ctx context.Context, data []map[string]string) map[string]string {
+		types := make(map[string]string)
+		for _, column := range decodeJSONDocument(t, formatter.Format(ctx, data))["columns"].([]interface{}) {
+			c := column.(map[string]interface{})
+			types[c["name"].(string)] = c["type"].(string)
+		}
+		return types
+	}
+
+	// Тип столбца определяется по имени, а не по значениям страницы: пустые и служебные
+	// значения не превращают переменную в строку, коды аннотаций остаются строками
+	expected := map[string]string{
+		"NAME":         JSONColumnString,
+		"B19013_001E":  JSONColumnNumber,
+		"B19013_001EA": JSONColumnString,
+		"POP":          JSONColumnNumber,
+		"state":        JSONColumnGeography,
+	}
+	assert.Equal(t, expected, columnTypes(context.Background(), []map[string]string{
+		{"NAME": "Texas", "B19013_001E": "65788", "B19013_001EA": "", "POP": "29145505", "state": "48"},
+	}))
+	assert.Equal(t, expected, columnTypes(context.Background(), []map[string]string{
+		{"NAME": "Loving County, Texas", "B19013_001E": "-666666666", "B19013_001EA": "-", "POP": "", "state": "48"},
+	}))
+
+	// Вычисляемые столбцы получают тип по виду значений из контекста
+	ctx := WithNumberFormat(context.Background(), NumberFormat{Kinds: map[string]ValueKind{"pct_poverty": ValueKindPercent}})
+	types := columnTypes(ctx, []map[string]string{{"NAME": "Texas", "pct_poverty": "", "state": "48"}})
+	assert.Equal(t, JSONColumnNumber, types["pct_poverty"])
+}
+
+func TestJSONFormatter_Format_VariablesAndLevels(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	variables := decodeJSONDocument(t, formatter.Format(context.Background(), map[string]VariableInfo{
+		"B19013_001E": {Name: "B19013_001E", Label: "Median household income"},
+		"B01001_001E": {Name: "B01001_001E", Label: "Total population"},
+	}))
+	assert.Equal(t, JSONTypeVariables, variables["type"])
+	rows := variables["rows"].([]interface{})
+	require.Len(t, rows, 2)
+	assert.Equal(t, "B01001_001E", rows[0].(map[string]interface{})["name"])
+
+	levels := decodeJSONDocument(t, formatter.Format(context.Background(), []GeographyLevel{
+		{Name: "state", Description: "Штаты", Wildcards: true},
+	}))
+	assert.Equal(t, JSONTypeGeographyLevels, levels["type"])
+	level := levels["rows"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, true, level["wildcards"])
+	assert.Equal(t, []interface{}{}, level["required_for"])
+}
+
+func TestJSONFormatter_Format_Result(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	doc := decodeJSONDocument(t, formatter.Format(context.Background(), &RankResult{
+		Variable: "B19013_001E",
+		Dataset:  "acs/acs5",
+		Year:     "2021",
+		GeoLevel: "state",
+		Ranked:   1,
+		Rows:     []RankedGeography{{GeoID: "24", Name: "Maryland", Rank: 1}},
+	}))
+
+	assert.Equal(t, JSONTypeRanking, doc["type"])
+	assert.Equal(t, "2021", doc["source"].(map[string]interface{})["year"])
+	assert.Equal(t, []interface{}{}, doc["rows"])
+	result := doc["result"].(map[string]interface{})
+	assert.Equal(t, "B19013_001E", result["variable"])
+}
+
+func TestJSONFormatter_Format_Errors(t *testing.T) {
+	formatter := NewJSONFormatter()
+
+	unsupported := decodeJSONDocument(t, formatter.Format(context.Background(), 123))
+	assert.Equal(t, JSONTypeError, unsupported["type"])
+	assert.Contains(t, unsupported["error"], "int")
+
+	// NaN не представим в JSON и должен приводить к документу ошибки, а не к некорректному JSON
+	invalid := decodeJSONDocument(t, formatter.Format(context.Background(), &EstimateComparison{Z: math.NaN()}))
+	assert.Equal(t, JSONTypeError, invalid["type"])
+
+	empty := decodeJSONDocument(t, formatter.Format(context.Background(), nil))
+	assert.Equal(t, JSONTypeError, empty["type"])
+}
@@ -1,5 +1,9 @@
 package census
 
+import (
+	"strings"
+)
+
 // MockCensusAPI - это реализация API Census для тестов, которая возвращает тестовые данные
 // Реализует интерфейс CensusAPIClient
 type MockCensusAPI struct{}
@@ -138,12 +142,48 @@ func (m *MockCensusAPI) GetAvailableDatasets() ([]DatasetInfo, error) {
 			Dataset:        "acs/acs1",
 			YearsAvailable: []string{"2019", "2020", "2021"},
 		},
+		{
+			Title:          "American Community Survey 5-Year Data Profiles",
+			Description:    "Data profiles DP02-DP05 with estimates and percentages for key social, economic, housing and demographic characteristics",
+			Dataset:        "acs/acs5/profile",
+			YearsAvailable: []string{"2019", "2020", "2021"},
+		},
+		{
+			Title:          "American Community Survey 5-Year Subject Tables",
+			Description:    "Subject tables S0101-S2919 with an overview of a topic in a single table",
+			Dataset:        "acs/acs5/subject",
+			YearsAvailable: []string{"2019", "2020", "2021"},
+		},
 		{
 			Title:          "Decennial Census",
 			Description:    "Complete count of the US population conducted every 10 years",
 			Dataset:        "dec/sf1",
 			YearsAvailable: []string{"2000", "2010", "2020"},
 		},
+		{
+			Title:          "Decennial Census: Redistricting Data (P.L. 94-171)",
+			Description:    "Race, Hispanic origin and housing occupancy counts down to the block level",
+			Dataset:        "dec/pl",
+			YearsAvailable: []string{"2010", "2020"},
+		},
+		{
+			Title:          "Decennial Census: Demographic and Housing Characteristics File",
+			Description:    "Detailed demographic and housing characteristics from the 2020 Census",
+			Dataset:        "dec/dhc",
+			YearsAvailable: []string{"2020"},
+		},
+		{
+			Title:          "County Business Patterns",
+			Description:    "Annual establishments, employment and payroll by NAICS industry",
+			Dataset:        "cbp",
+			YearsAvailable: []string{"2019", "2020", "2021"},
+		},
+		{
+			Title:          "Economic Census: Basic Statistics",
+			Description:    "Establishments, employment, payroll and receipts by NAICS industry",
+			Dataset:        "ecnbasic",
+			YearsAvailable: []string{"2012", "2017"},
+		},
 		{
 			Title:          "Population Estimates Program",
 			Description:    "Annual population estimates between decennial censuses",
@@ -239,18 +279,27 @@ func (m *MockCensusAPI) GetCustomData(request CustomDataRequest) ([]map[string]s
 			"NAME":        "California",
 			"B01001_001E": "39538223",
 			"B19013_001E": "78672",
+			"DP03_0128E":  "-888888888",
+			"DP03_0128PE": "14.9",
+			"DP03_0128PM": "0.1",
 			"state":       "06",
 		},
 		{
 			"NAME":        "New York",
 			"B01001_001E": "20201249",
 			"B19013_001E": "71117",
+			"DP03_0128E":  "-888888888",
+			"DP03_0128PE": "13.2",
+			"DP03_0128PM": "0.1",
 			"state":       "36",
 		},
 		{
 			"NAME":        "Texas",
 			"B01001_001E": "29145505",
 			"B19013_001E": "63826",
+			"DP03_0128E":  "-888888888",
+			"DP03_0128PE": "14.2",
+			"DP03_0128PM": "0.1",
 			"state":       "48",
 		},
 	}
@@ -283,6 +332,124 @@ func (m *MockCensusAPI) GetCustomData(request CustomDataRequest) ([]map[string]s
 	return data, nil
 }
 
+// GetZCTAData возвращает тестовые данные по ZCTA
+func (m *MockCensusAPI) GetZCTAData(request ZCTARequest) ([]map[string]string, error) {
+	if _, err := BuildZCTADataRequest(request); err != nil {
+		return nil, err
+	}
+
+	// Тестовые данные по ZCTA
+	zctas := []map[string]string{
+		{
+			"NAME":        "ZCTA5 94110",
+			"B01001_001E": "69333",
+			GeoLevelZCTA:  "94110",
+		},
+		{
+			"NAME":        "ZCTA5 10001",
+			"B01001_001E": "27004",
+			GeoLevelZCTA:  "10001",
+		},
+		{
+			"NAME":        "ZCTA5 77002",
+			"B01001_001E": "17436",
+			GeoLevelZCTA:  "77002",
+		},
+	}
+
+	var result []map[string]string
+	for _, zcta := range zctas {
+		for _, code := range request.ZCTAs {
+			if code == "*" || code == zcta[GeoLevelZCTA] {
+				result = append(result, zcta)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetDistrictData возвращает тестовые данные по избирательным округам
+func (m *MockCensusAPI) GetDistrictData(request DistrictRequest) ([]map[string]string, error) {
+	requests, err := BuildDistrictDataRequests(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Тестовые данные по округам Калифорнии и Техаса
+	districts := map[string][]map[string]string{
+		GeoLevelCongressionalDistrict: {
+			{"NAME": "Congressional District 12 (118th Congress), California", "B01001_001E": "742248", "state": "06"},
+			{"NAME": "Congressional District 7 (118th Congress), Texas", "B01001_001E": "769451", "state": "48"},
+		},
+		GeoLevelStateLegislativeUpper: {
+			{"NAME": "State Senate District 11 (2022), California", "B01001_001E": "988372", "state": "06"},
+		},
+		GeoLevelStateLegislativeLower: {
+			{"NAME": "Assembly District 17 (2022), California", "B01001_001E": "496120", "state": "06"},
+		},
+	}
+	codes := map[string][]string{
+		GeoLevelCongressionalDistrict: {"12", "07"},
+		GeoLevelStateLegislativeUpper: {"011"},
+		GeoLevelStateLegislativeLower: {"017"},
+	}
+
+	var result []map[string]string
+	for _, customRequest := range requests {
+		wanted := strings.Split(customRequest.GeoFilter[customRequest.GeoLevel], ",")
+		for i, district := range districts[customRequest.GeoLevel] {
+			code := codes[customRequest.GeoLevel][i]
+			if customRequest.GeoFilter["state"] != "*" && customRequest.GeoFilter["state"] != district["state"] {
+				continue
+			}
+			for _, w := range wanted {
+				if w == "*" || w == code {
+					row := make(map[string]string, len(district)+1)
+					for k, v := range district {
+						row[k] = v
+					}
+					row[customRequest.GeoLevel] = code
+					result = append(result, row)
+					break
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// GetCBSAData возвращает тестовые данные по статистическим ареалам
+func (m *MockCensusAPI) GetCBSAData(request CBSARequest) ([]map[string]string, error) {
+	customRequest, err := BuildCBSADataRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Тестовые данные по статистическим ареалам
+	cbsas := []map[string]string{
+		{"NAME": "Los Angeles-Long Beach-Anaheim, CA Metro Area", "B01001_001E": "12997353", GeoLevelCBSA: "31080"},
+		{"NAME": "Seattle-Tacoma-Bellevue, WA Metro Area", "B01001_001E": "4011553", GeoLevelCBSA: "42660"},
+		{"NAME": "Bozeman, MT Micro Area", "B01001_001E": "122713", GeoLevelCBSA: "14580"},
+	}
+
+	wanted := strings.Split(customRequest.GeoFilter[GeoLevelCBSA], ",")
+
+	var result []map[string]string
+	for _, cbsa := range cbsas {
+		for _, code := range wanted {
+			if code == "*" || code == cbsa[GeoLevelCBSA] {
+				result = append(result, cbsa)
+				break
+			}
+		}
+	}
+
+	return result, nil
+}
+
 // contains проверяет, содержит ли строка подстроку без учета регистра
 func contains(s, substr string) bool {
 	s, substr = toLower(s), toLower(substr)
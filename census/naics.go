@@ -0,0 +1,237 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	_ "embed"
+	"encoding/csv"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// naicsCSV - коды и названия отраслей NAICS 2017: секторы, подсекторы и часто используемые
+// группы отраслей. Названия совпадают с подписями NAICS2017_LABEL в Census API. Полный
+// классификатор загружает LoadNAICSFile.
+//
+//go:embed data/naics.csv
+var naicsCSV string
+
+var (
+	naicsMu         sync.Mutex
+	naicsIndustries []NAICSIndustry
+	naicsByCode     map[string]NAICSIndustry
+	naicsError      error
+)
+
+// NAICSIndustry описывает отрасль классификатора NAICS
+type NAICSIndustry struct {
+	Code  string `json:"code"`
+	Title string `json:"title"`
+}
+
+// Level возвращает уровень отрасли: 2 - сектор, 3 - подсектор, 4 - группа отраслей и т.д.
+// Составные коды секторов ("31-33") относятся к уровню 2.
+func (i NAICSIndustry) Level() int {
+	if strings.Contains(i.Code, "-") {
+		return 2
+	}
+	return len(i.Code)
+}
+
+// loadNAICS возвращает таблицу отраслей NAICS: загруженную LoadNAICSFile или встроенную
+func loadNAICS() ([]NAICSIndustry, map[string]NAICSIndustry, error) {
+	naicsMu.Lock()
+	defer naicsMu.Unlock()
+
+	if naicsIndustries == nil && naicsError == nil {
+		naicsIndustries, naicsError = ReadNAICS(strings.NewReader(naicsCSV))
+		naicsByCode = naicsIndex(naicsIndustries)
+	}
+	return naicsIndustries, naicsByCode, naicsError
+}
+
+// LoadNAICSFile заменяет встроенную таблицу отраслей полным классификатором NAICS 2017
+// из файла CSV и возвращает число отраслей. Подходит файл кодов 2-6 знаков Census Bureau
+// ("2-6 digit_2017_Codes"), сохраненный в CSV.
+func LoadNAICSFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, i18n.Errorf("ошибка при чтении таблицы NAICS: %w", err)
+	}
+	defer file.Close()
+
+	industries, err := ReadNAICS(file)
+	if err != nil {
+		return 0, err
+	}
+
+	naicsMu.Lock()
+	defer naicsMu.Unlock()
+	naicsIndustries, naicsByCode, naicsError = industries, naicsIndex(industries), nil
+
+	slog.Info("Загружена таблица отраслей NAICS",
+		key_count, len(industries))
+	return len(industries), nil
+}
+
+// ReadNAICS разбирает таблицу отраслей NAICS в формате CSV. Столбцы кода и названия
+// определяются по заголовкам, содержащим "code" и "title"; строки до заголовка и без кода
+// пропускаются. Признак "T" (отрасль, общая для США, Канады и Мексики) в конце названий
+// файла Census Bureau отбрасывается.
+func ReadNAICS(r io.Reader) ([]NAICSIndustry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, i18n.Errorf("ошибка при чтении таблицы NAICS: %w", err)
+	}
+
+	header, codeColumn, titleColumn := -1, -1, -1
+	for i, record := range records {
+		codeColumn, titleColumn = -1, -1
+		for j, value := range record {
+			value = strings.ToLower(value)
+			if codeColumn < 0 && strings.Contains(value, "code") {
+				codeColumn = j
+			}
+			if titleColumn < 0 && strings.Contains(value, "title") {
+				titleColumn = j
+			}
+		}
+		if codeColumn >= 0 && titleColumn >= 0 {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		return nil, i18n.Errorf("в таблице NAICS нет строки заголовков")
+	}
+
+	var industries []NAICSIndustry
+	for i, record := range records[header+1:] {
+		if codeColumn >= len(record) || strings.TrimSpace(record[codeColumn]) == "" {
+			continue
+		}
+		if titleColumn >= len(record) {
+			return nil, i18n.Errorf("некорректная строка %d в таблице NAICS", header+i+2)
+		}
+
+		industries = append(industries, NAICSIndustry{
+			Code:  strings.TrimSpace(record[codeColumn]),
+			Title: naicsTitle(record[titleColumn]),
+		})
+	}
+	return industries, nil
+}
+
+// naicsTitle убирает из названия отрасли пробелы по краям и признак "T" после строчной
+// буквы или скобки (например, "Soybean FarmingT")
+func naicsTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if n := len(title); n > 1 && title[n-1] == 'T' {
+		if prev := rune(title[n-2]); unicode.IsLower(prev) || prev == ')' {
+			title = title[:n-1]
+		}
+	}
+	return title
+}
+
+// naicsIndex индексирует отрасли по коду
+func naicsIndex(industries []NAICSIndustry) map[string]NAICSIndustry {
+	byCode := make(map[string]NAICSIndustry, len(industries))
+	for _, industry := range industries {
+		byCode[industry.Code] = industry
+	}
+	return byCode
+}
+
+// LookupNAICS возвращает отрасль по коду NAICS
+func LookupNAICS(code string) (NAICSIndustry, bool) {
+	_, byCode, err := loadNAICS()
+	if err != nil {
+		return NAICSIndustry{}, false
+	}
+
+	industry, ok := byCode[strings.TrimSpace(code)]
+	return industry, ok
+}
+
+// ValidateNAICSCode проверяет формат кода NAICS: от 2 до 6 цифр или составной код сектора
+// из таблицы отраслей (например, "44-45")
+func ValidateNAICSCode(code string) error {
+	if _, ok := LookupNAICS(code); ok {
+		return nil
+	}
+
+	if len(code) < 2 || len(code) > 6 {
+		return i18n.Errorf("код NAICS должен состоять из 2-6 цифр: %q", code)
+	}
+	for _, ch := range code {
+		if ch < '0' || ch > '9' {
+			return i18n.Errorf("код NAICS должен состоять из 2-6 цифр: %q", code)
+		}
+	}
+	return nil
+}
+
+// naicsWords разбивает текст на слова в нижнем регистре
+func naicsWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchNAICS ищет отрасли по свободному тексту: каждое слово запроса должно быть началом
+// одного из слов названия (например, "restaurant" находит "Full-Service Restaurants").
+// Запрос, совпадающий с кодом, возвращает эту отрасль. Более общие отрасли идут первыми.
+func SearchNAICS(query string, limit int) ([]NAICSIndustry, error) {
+	industries, byCode, err := loadNAICS()
+	if err != nil {
+		return nil, err
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, i18n.Errorf("необходимо указать текст запроса")
+	}
+
+	if industry, ok := byCode[query]; ok {
+		return []NAICSIndustry{industry}, nil
+	}
+
+	queryWords := naicsWords(query)
+	var matches []NAICSIndustry
+	for _, industry := range industries {
+		titleWords := naicsWords(industry.Title)
+		matched := true
+		for _, queryWord := range queryWords {
+			found := false
+			for _, titleWord := range titleWords {
+				if strings.HasPrefix(titleWord, queryWord) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, industry)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Level() < matches[j].Level()
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
@@ -0,0 +1,108 @@
+package census
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchNAICS(t *testing.T) {
+	industries, err := SearchNAICS("restaurant", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "7225", industries[0].Code)
+	assert.Len(t, industries, 3)
+
+	// Более общие отрасли идут первыми
+	industries, err = SearchNAICS("retail", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, NAICSIndustry{Code: "44-45", Title: "Retail Trade"}, industries[0])
+	assert.Equal(t, 2, industries[0].Level())
+
+	// Поиск по коду
+	industries, err = SearchNAICS("5415", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Computer Systems Design and Related Services", industries[0].Title)
+
+	industries, err = SearchNAICS("quantum teleportation", 0)
+	assert.NoError(t, err)
+	assert.Empty(t, industries)
+
+	_, err = SearchNAICS(" ", 0)
+	assert.Error(t, err)
+}
+
+func TestValidateNAICSCode(t *testing.T) {
+	assert.NoError(t, ValidateNAICSCode("31-33"))
+	assert.NoError(t, ValidateNAICSCode("722511"))
+	assert.NoError(t, ValidateNAICSCode("5191"))
+	assert.Error(t, ValidateNAICSCode("7"))
+	assert.Error(t, ValidateNAICSCode("72-XX"))
+}
+
+// naicsCodesCSV - фрагмент файла кодов NAICS 2017 Census Bureau ("2-6 digit_2017_Codes"),
+// сохраненного из электронной таблицы в CSV
+const naicsCodesCSV = `Seq. No.,2017 NAICS US   Code,2017 NAICS US Title,,
+,,,,
+1,11,"Agriculture, Forestry, Fishing and HuntingT",,
+2,111,Crop ProductionT,,
+3,1111,Oilseed and Grain FarmingT,,
+4,11111,Soybean FarmingT,,
+5,111110,Soybean Farming,,
+6,31-33,ManufacturingT,,
+7,311811,Retail Bakeries,,
+`
+
+func TestReadNAICS_CensusFile(t *testing.T) {
+	industries, err := ReadNAICS(strings.NewReader(naicsCodesCSV))
+	require.NoError(t, err)
+	require.Len(t, industries, 7)
+
+	// Признак "T" в конце названий отбрасывается
+	assert.Equal(t, NAICSIndustry{Code: "11", Title: "Agriculture, Forestry, Fishing and Hunting"}, industries[0])
+	assert.Equal(t, NAICSIndustry{Code: "11111", Title: "Soybean Farming"}, industries[3])
+	assert.Equal(t, NAICSIndustry{Code: "31-33", Title: "Manufacturing"}, industries[5])
+
+	_, err = ReadNAICS(strings.NewReader("naics,name\n11,Agriculture\n"))
+	assert.Error(t, err)
+}
+
+func TestLoadNAICSFile(t *testing.T) {
+	embedded, byCode, err := loadNAICS()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		naicsMu.Lock()
+		naicsIndustries, naicsByCode, naicsError = embedded, byCode, nil
+		naicsMu.Unlock()
+	})
+
+	// Встроенная таблица содержит шестизначные отрасли
+	industry, ok := LookupNAICS("722511")
+	assert.True(t, ok)
+	assert.Equal(t, "Full-Service Restaurants", industry.Title)
+
+	path := filepath.Join(t.TempDir(), "naics_2017.csv")
+	require.NoError(t, os.WriteFile(path, []byte(naicsCodesCSV), 0o644))
+
+	count, err := LoadNAICSFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 7, count)
+
+	// Загруженный классификатор заменяет встроенную таблицу
+	industry, ok = LookupNAICS("311811")
+	assert.True(t, ok)
+	assert.Equal(t, "Retail Bakeries", industry.Title)
+	assert.Equal(t, 6, industry.Level())
+	_, ok = LookupNAICS("722511")
+	assert.False(t, ok)
+
+	industries, err := SearchNAICS("soybean", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"11111", "111110"}, []string{industries[0].Code, industries[1].Code})
+
+	_, err = LoadNAICSFile(filepath.Join(t.TempDir(), "missing.csv"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,265 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ValueKind - вид числового значения, определяющий его оформление при выводе
+type ValueKind string
+
+// Виды числовых значений
+const (
+	ValueKindCount   ValueKind = "count"   // счетчики: население, домохозяйства, предприятия
+	ValueKindMoney   ValueKind = "money"   // суммы в долларах: доходы, стоимость жилья
+	ValueKindPercent ValueKind = "percent" // проценты и доли в процентах
+	ValueKindNumber  ValueKind = "number"  // прочие величины: медианный возраст, отношения, индексы
+)
+
+// predicateTypeString - тип текстовых переменных в описаниях Census API
+const predicateTypeString = "string"
+
+// DefaultPrecision - число знаков после запятой по умолчанию
+const DefaultPrecision = 2
+
+// MaxPrecision - наибольшее допустимое число знаков после запятой
+const MaxPrecision = 6
+
+// NumberFormat задает оформление чисел в текстовых форматах. Язык берется из контекста
+// (i18n.FromContext), машиночитаемые форматы (JSON, CSV, TSV) выводят числа без оформления.
+type NumberFormat struct {
+	// Precision - наибольшее число знаков после запятой; незначащие нули отбрасываются
+	Precision int
+	// Kinds - виды значений переменных по их именам (например, "B19013_001E" - ValueKindMoney)
+	Kinds map[string]ValueKind
+}
+
+// numberSymbols - разделители и знаки единиц для языка
+type numberSymbols struct {
+	group          string
+	decimal        string
+	currencyPrefix string
+	currencySuffix string
+	percentSuffix  string
+}
+
+// localeSymbols - оформление чисел по языкам. В русском языке разряды разделяются
+// неразрывным пробелом, а знаки "$" и "%" отделяются от числа.
+var localeSymbols = map[i18n.Locale]numberSymbols{
+	i18n.Russian: {group: "\u00a0", decimal: ",", currencySuffix: "\u00a0$", percentSuffix: "\u00a0%"},
+	i18n.English: {group: ",", decimal: ".", currencyPrefix: "$", percentSuffix: "%"},
+}
+
+// nonCountWords - слова в подписи переменной, указывающие на величину, которая не является
+// счетчиком (медианы, средние, отношения, коэффициенты), и описание величины для сообщений.
+// Список общий для оформления чисел и проверки аддитивности при агрегации.
+var nonCountWords = map[string]string{
+	"median":  "медиана",
+	"mean":    "среднее значение",
+	"average": "среднее значение",
+	"capita":  "показатель на душу населения",
+	"rate":    "коэффициент",
+	"rates":   "коэффициент",
+	"ratio":   "отношение",
+	"gini":    "индекс",
+	"index":   "индекс",
+}
+
+// numberFormatKey - ключ контекста для параметров оформления чисел
+type numberFormatKey struct{}
+
+// WithNumberFormat возвращает контекст с параметрами оформления чисел
+func WithNumberFormat(ctx context.Context, format NumberFormat) context.Context {
+	return context.WithValue(ctx, numberFormatKey{}, format)
+}
+
+// NumberFormatFromContext возвращает параметры оформления чисел из контекста
+// или параметры по умолчанию
+func NumberFormatFromContext(ctx context.Context) NumberFormat {
+	if format, ok := ctx.Value(numberFormatKey{}).(NumberFormat); ok {
+		return format
+	}
+	return NumberFormat{Precision: DefaultPrecision}
+}
+
+// VariableKind определяет вид значения переменной по ее имени и подписи (см. labelMeasure).
+// MOE переменной имеет тот же вид, что и оценка, так как подпись MOE повторяет подпись оценки.
+func VariableKind(variable string, info VariableInfo) ValueKind {
+	if strings.HasPrefix(variable, "DP") && (strings.HasSuffix(variable, "PE") || strings.HasSuffix(variable, "PM")) {
+		return ValueKindPercent
+	}
+	if IsDollarVariable(info) {
+		return ValueKindMoney
+	}
+
+	kind, _ := labelMeasure(info.Label)
+	return kind
+}
+
+// labelMeasure определяет по подписи оценки вид ее значения и описание величины, если значение
+// не является счетчиком. Концепция таблицы не учитывается: в таблицах C17002 ("RATIO OF INCOME
+// TO POVERTY LEVEL") и B25070 ("GROSS RENT AS A PERCENTAGE OF HOUSEHOLD INCOME") значения -
+// численности по интервалам. Слово "percent" после числа ("Less than 10.0 percent") - граница
+// интервала, а не процент. Сравнение выполняется по целым словам, чтобы "Separated" не совпадало
+// с "rate".
+func labelMeasure(label string) (ValueKind, string) {
+	words := strings.FieldsFunc(strings.ToLower(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	kind, reason := ValueKindCount, ""
+	for i, word := range words {
+		switch {
+		case word == "percent" || word == "percentage":
+			if i > 0 && unicode.IsDigit(rune(words[i-1][0])) {
+				continue
+			}
+			return ValueKindPercent, "процент"
+		case kind == ValueKindCount && nonCountWords[word] != "":
+			kind, reason = ValueKindNumber, nonCountWords[word]
+		}
+	}
+	return kind, reason
+}
+
+// VariableKinds определяет виды значений числовых переменных выпуска по их описаниям.
+// Текстовые переменные (predicateType "string", например NAME) вида значений не имеют.
+func VariableKinds(definitions map[string]VariableInfo) map[string]ValueKind {
+	kinds := make(map[string]ValueKind, len(definitions))
+	for name, info := range definitions {
+		if info.PredicateType == predicateTypeString {
+			continue
+		}
+		kinds[name] = VariableKind(name, info)
+	}
+	return kinds
+}
+
+// FormatValue оформляет число для чтения человеком: разделяет разряды, использует десятичный
+// разделитель языка из контекста, добавляет знак доллара к суммам и знак процента к процентам
+func FormatValue(ctx context.Context, value float64, kind ValueKind) string {
+	symbols, ok := localeSymbols[i18n.FromContext(ctx)]
+	if !ok {
+		symbols = localeSymbols[i18n.DefaultLocale]
+	}
+
+	precision := NumberFormatFromContext(ctx).Precision
+	if precision < 0 || precision > MaxPrecision {
+		precision = DefaultPrecision
+	}
+
+	scale := math.Pow(10, float64(precision))
+	rounded := math.Round(value*scale) / scale
+	if rounded == 0 {
+		rounded = 0 // избавляемся от "-0"
+	}
+
+	digits := strconv.FormatFloat(math.Abs(rounded), 'f', -1, 64)
+	integer, fraction, _ := strings.Cut(digits, ".")
+	// Суммы в долларах с дробной частью выводятся с полным числом знаков ("$12.50")
+	if kind == ValueKindMoney && fraction != "" {
+		fraction += strings.Repeat("0", precision-len(fraction))
+	}
+
+	var sb strings.Builder
+	if rounded < 0 {
+		sb.WriteString("-")
+	}
+	if kind == ValueKindMoney {
+		sb.WriteString(symbols.currencyPrefix)
+	}
+	sb.WriteString(groupDigits(integer, symbols.group))
+	if fraction != "" {
+		sb.WriteString(symbols.decimal + fraction)
+	}
+	switch kind {
+	case ValueKindMoney:
+		sb.WriteString(symbols.currencySuffix)
+	case ValueKindPercent:
+		sb.WriteString(symbols.percentSuffix)
+	}
+	return sb.String()
+}
+
+// FormatRawValue оформляет значение из ответа Census API. Нечисловые и служебные значения
+// (например, "-666666666") возвращаются без изменений.
+func FormatRawValue(ctx context.Context, raw string, kind ValueKind) string {
+	value, ok := ParseEstimate(raw)
+	if !ok {
+		return raw
+	}
+	return FormatValue(ctx, value, kind)
+}
+
+// formatVariableValue оформляет значение переменной по ее виду из контекста; если вид
+// неизвестен, используется fallback
+func formatVariableValue(ctx context.Context, variable string, value float64, fallback ValueKind) string {
+	return FormatValue(ctx, value, variableKind(ctx, variable, fallback))
+}
+
+// variableKind возвращает вид значения переменной из контекста или fallback
+func variableKind(ctx context.Context, variable string, fallback ValueKind) ValueKind {
+	if kind, ok := NumberFormatFromContext(ctx).Kinds[variable]; ok {
+		return kind
+	}
+	return fallback
+}
+
+// dataColumnKind возвращает вид значений столбца пользовательских данных: из контекста или,
+// если описания переменных недоступны, по имени переменной. Для географических столбцов
+// и столбцов, не похожих на переменные, возвращается false.
+func dataColumnKind(ctx context.Context, column string) (ValueKind, bool) {
+	if IsGeographyColumn(column) {
+		return "", false
+	}
+	if kind, ok := NumberFormatFromContext(ctx).Kinds[column]; ok {
+		return kind, true
+	}
+	if isVariableColumn(column) {
+		return VariableKind(column, VariableInfo{}), true
+	}
+	return "", false
+}
+
+// isVariableColumn сообщает, похоже ли имя столбца на переменную таблицы Census API
+// (B01001_001E, S1701_C03_001E, DP05_0001PE, P1_001N). Такие столбцы оформляются как числа
+// даже без описаний переменных, в отличие от кодов и годов.
+func isVariableColumn(column string) bool {
+	i := strings.LastIndex(column, "_")
+	if i <= 0 || i == len(column)-1 {
+		return false
+	}
+	suffix := strings.TrimRight(column[i+1:], "ABCDEFGHIJKLMNOPQRSTUVWXYZ")
+	if suffix == "" || len(suffix) == len(column)-i-1 {
+		return false
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// groupDigits разделяет разряды целой части числа
+func groupDigits(integer, separator string) string {
+	if len(integer) <= 3 {
+		return integer
+	}
+
+	var sb strings.Builder
+	head := len(integer) % 3
+	if head > 0 {
+		sb.WriteString(integer[:head])
+	}
+	for i := head; i < len(integer); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteString(separator)
+		}
+		sb.WriteString(integer[i : i+3])
+	}
+	return sb.String()
+}
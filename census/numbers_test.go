@@ -0,0 +1,115 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatValue(t *testing.T) {
+	russian := context.Background()
+	english := i18n.WithLocale(context.Background(), i18n.English)
+
+	tests := []struct {
+		name    string
+		value   float64
+		kind    ValueKind
+		russian string
+		english string
+	}{
+		{"Счетчик", 39538223, ValueKindCount, "39\u00a0538\u00a0223", "39,538,223"},
+		{"Малое число", 785, ValueKindCount, "785", "785"},
+		{"Отрицательное число", -274387, ValueKindCount, "-274\u00a0387", "-274,387"},
+		{"Доллары", 76367, ValueKindMoney, "76\u00a0367\u00a0$", "$76,367"},
+		{"Доллары с центами", 1234.5, ValueKindMoney, "1\u00a0234,50\u00a0$", "$1,234.50"},
+		{"Отрицательная сумма", -1500, ValueKindMoney, "-1\u00a0500\u00a0$", "-$1,500"},
+		{"Процент", 12.345, ValueKindPercent, "12,35\u00a0%", "12.35%"},
+		{"Прочая величина", 2.5, ValueKindNumber, "2,5", "2.5"},
+		{"Отрицательный ноль", -0.001, ValueKindCount, "0", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.russian, FormatValue(russian, tt.value, tt.kind))
+			assert.Equal(t, tt.english, FormatValue(english, tt.value, tt.kind))
+		})
+	}
+}
+
+func TestFormatValue_Precision(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	assert.Equal(t, "3.14", FormatValue(ctx, 3.14159, ValueKindNumber))
+	assert.Equal(t, "3", FormatValue(WithNumberFormat(ctx, NumberFormat{Precision: 0}), 3.14159, ValueKindNumber))
+	assert.Equal(t, "3.1416", FormatValue(WithNumberFormat(ctx, NumberFormat{Precision: 4}), 3.14159, ValueKindNumber))
+	assert.Equal(t, "$12.5000", FormatValue(WithNumberFormat(ctx, NumberFormat{Precision: 4}), 12.5, ValueKindMoney))
+	// Недопустимая точность заменяется точностью по умолчанию
+	assert.Equal(t, "3.14", FormatValue(WithNumberFormat(ctx, NumberFormat{Precision: 10}), 3.14159, ValueKindNumber))
+}
+
+func TestFormatRawValue(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+
+	assert.Equal(t, "10,014,009", FormatRawValue(ctx, "10014009", ValueKindCount))
+	assert.Equal(t, "-666666666", FormatRawValue(ctx, "-666666666", ValueKindMoney))
+	assert.Equal(t, "N/A", FormatRawValue(ctx, "N/A", ValueKindCount))
+}
+
+func TestVariableKinds_SkipsStringVariables(t *testing.T) {
+	kinds := VariableKinds(map[string]VariableInfo{
+		"NAME":        {Label: "Geographic Area Name", PredicateType: "string"},
+		"B01001_001E": {Label: "Estimate!!Total:", PredicateType: "int"},
+	})
+	assert.Equal(t, map[string]ValueKind{"B01001_001E": ValueKindCount}, kinds)
+}
+
+func TestVariableKind(t *testing.T) {
+	tests := []struct {
+		variable string
+		info     VariableInfo
+		expected ValueKind
+	}{
+		{"B01001_001E", VariableInfo{Label: "Estimate!!Total:", Concept: "SEX BY AGE"}, ValueKindCount},
+		{"B19013_001E", VariableInfo{Label: "Estimate!!Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"}, ValueKindMoney},
+		{"B19013_001M", VariableInfo{Label: "Margin of Error!!Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"}, ValueKindMoney},
+		{"B01002_001E", VariableInfo{Label: "Estimate!!Median age --!!Total:", Concept: "MEDIAN AGE BY SEX"}, ValueKindNumber},
+		{"S1701_C03_001E", VariableInfo{Label: "Estimate!!Percent below poverty level!!Population for whom poverty status is determined"}, ValueKindPercent},
+		{"DP03_0009PE", VariableInfo{Label: "Percent!!EMPLOYMENT STATUS!!Unemployment Rate"}, ValueKindPercent},
+		{"DP05_0001PE", VariableInfo{}, ValueKindPercent},
+		{"P1_001N", VariableInfo{Label: " !!Total:"}, ValueKindCount},
+		{"B25070_002E", VariableInfo{Label: "Estimate!!Total:!!Less than 10.0 percent", Concept: "GROSS RENT AS A PERCENTAGE OF HOUSEHOLD INCOME IN THE PAST 12 MONTHS"}, ValueKindCount},
+		{"C17002_002E", VariableInfo{Label: "Estimate!!Total:!!Under .50", Concept: "RATIO OF INCOME TO POVERTY LEVEL IN THE PAST 12 MONTHS"}, ValueKindCount},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, VariableKind(tt.variable, tt.info), tt.variable)
+	}
+}
+
+func TestIsVariableColumn(t *testing.T) {
+	for _, column := range []string{"B01001_001E", "B19013_001M", "S1701_C03_001E", "DP05_0001PE", "P1_001N"} {
+		assert.True(t, isVariableColumn(column), column)
+	}
+	for _, column := range []string{"NAME", "GEO_ID", "state", "pct_poverty", "POP_2019", "time"} {
+		assert.False(t, isVariableColumn(column), column)
+	}
+}
+
+func TestTextFormatter_NumberKinds(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+	ctx = WithNumberFormat(ctx, NumberFormat{
+		Precision: 1,
+		Kinds: map[string]ValueKind{
+			"B19013_001E": ValueKindMoney,
+			"pct_poverty": ValueKindPercent,
+		},
+	})
+
+	result := NewTextFormatter().Format(ctx, []map[string]string{
+		{"NAME": "Texas", "B01001_001E": "29145505", "B19013_001E": "66963", "pct_poverty": "13.97", "state": "48"},
+	})
+
+	assert.Contains(t, result, "| Texas | 29,145,505 | $66,963 | 14% | 48 |")
+}
@@ -0,0 +1,398 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_kind    = "kind"
+	key_vintage = "vintage"
+)
+
+// Виды данных программы оценок численности населения (PEP)
+const (
+	PEPKindPopulation      = "population"
+	PEPKindComponents      = "components"
+	PEPKindCharacteristics = "characteristics"
+)
+
+// Выпуски PEP
+const (
+	// DefaultPEPVintage - последний выпуск PEP, опубликованный в Census API в формате с DATE_CODE
+	DefaultPEPVintage = "2019"
+	// PEPTimeseries - выпуск межпереписных оценок 2000-2010, публикуемых как timeseries
+	PEPTimeseries = "timeseries"
+	// pepFirstYearColumnsVintage - первый выпуск, в котором оценки по годам публикуются
+	// отдельными переменными POP_<год> вместо строк с DATE_CODE
+	pepFirstYearColumnsVintage = 2020
+	// pepFirstYear - первый год оценок в переменных POP_<год> (оценка на 1 июля 2020 года)
+	pepFirstYear = 2020
+)
+
+// PEPKinds - поддерживаемые виды данных PEP
+var PEPKinds = []string{PEPKindPopulation, PEPKindComponents, PEPKindCharacteristics}
+
+// pepComponentColumns - компоненты изменения численности населения
+var pepComponentColumns = []PEPColumn{
+	{Key: "BIRTHS", Label: "Рождения"},
+	{Key: "DEATHS", Label: "Смерти"},
+	{Key: "NATURALINC", Label: "Естественный прирост"},
+	{Key: "INTERNATIONALMIG", Label: "Международная миграция"},
+	{Key: "DOMESTICMIG", Label: "Внутренняя миграция"},
+	{Key: "NETMIG", Label: "Чистая миграция"},
+}
+
+// pepCharacteristics - переменные характеристик населения в порядке вывода
+var pepCharacteristics = []PEPColumn{
+	{Key: "AGEGROUP", Label: "Возрастная группа"},
+	{Key: "SEX", Label: "Пол"},
+	{Key: "RACE", Label: "Раса"},
+	{Key: "HISP", Label: "Испаноязычное происхождение"},
+}
+
+// pepCharacteristicLabels - расшифровка кодов характеристик населения
+var pepCharacteristicLabels = map[string]map[string]string{
+	"SEX": {
+		"0": "Оба пола",
+		"1": "Мужчины",
+		"2": "Женщины",
+	},
+	"HISP": {
+		"0": "Все",
+		"1": "Не испаноязычные",
+		"2": "Испаноязычные",
+	},
+	"RACE": {
+		"0": "Все расы",
+		"1": "Только белые",
+		"2": "Только черные или афроамериканцы",
+		"3": "Только индейцы и коренные жители Аляски",
+		"4": "Только азиаты",
+		"5": "Только коренные гавайцы и жители островов Тихого океана",
+		"6": "Две расы и более",
+	},
+	"AGEGROUP": pepAgeGroupLabels(),
+}
+
+// pepAgeGroupLabels возвращает подписи пятилетних возрастных групп PEP (коды 1-18)
+func pepAgeGroupLabels() map[string]string {
+	labels := map[string]string{
+		"0":  "Все возрасты",
+		"18": "85 лет и старше",
+	}
+	for code := 1; code < 18; code++ {
+		labels[strconv.Itoa(code)] = fmt.Sprintf("%d-%d лет", (code-1)*5, code*5-1)
+	}
+	return labels
+}
+
+// PEPRequest представляет запрос оценок численности населения
+type PEPRequest struct {
+	Kind      string            // Вид данных: population, components или characteristics
+	Vintage   string            // Выпуск оценок (например, "2019") или "timeseries" для оценок 2000-2010
+	GeoLevel  string            // Географический уровень (us, state, county и т.д.)
+	GeoFilter map[string]string // Фильтр географии
+	// Time - год для выпуска timeseries (по умолчанию все годы)
+	Time string
+	// Characteristics - фильтр характеристик (AGEGROUP, SEX, RACE, HISP). Не указанные
+	// характеристики принимают значение "0" (итог), значение "all" возвращает все коды.
+	Characteristics map[string]string
+}
+
+// PEPColumn описывает столбец результата PEP
+type PEPColumn struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// PEPRow содержит значения одной строки результата PEP
+type PEPRow struct {
+	GeoID  string            `json:"geoid"`
+	Name   string            `json:"name"`
+	Period string            `json:"period"`
+	Values map[string]string `json:"values"`
+}
+
+// PEPResult содержит оценки численности населения
+type PEPResult struct {
+	Kind     string      `json:"kind"`
+	Dataset  string      `json:"dataset"`
+	Vintage  string      `json:"vintage"`
+	GeoLevel string      `json:"geo_level"`
+	Columns  []PEPColumn `json:"columns"`
+	Rows     []PEPRow    `json:"rows"`
+}
+
+// PEPDataset возвращает набор данных и год запроса Census API для вида данных и выпуска PEP
+func PEPDataset(kind, vintage string) (string, string, error) {
+	if vintage == PEPTimeseries {
+		switch kind {
+		case PEPKindPopulation:
+			return "pep/int_population", PEPTimeseries, nil
+		case PEPKindCharacteristics:
+			return "pep/int_charagegroups", PEPTimeseries, nil
+		default:
+			return "", "", i18n.Errorf("вид данных %q недоступен в выпуске timeseries", kind)
+		}
+	}
+
+	year, err := strconv.Atoi(vintage)
+	if err != nil {
+		return "", "", i18n.Errorf("некорректный выпуск PEP %q: ожидается год или %q", vintage, PEPTimeseries)
+	}
+
+	switch kind {
+	case PEPKindPopulation:
+		return "pep/population", vintage, nil
+	case PEPKindComponents:
+		if year >= pepFirstYearColumnsVintage {
+			return "", "", i18n.Errorf("компоненты изменения численности доступны в Census API для выпусков до %d", pepFirstYearColumnsVintage-1)
+		}
+		return "pep/components", vintage, nil
+	case PEPKindCharacteristics:
+		if year >= pepFirstYearColumnsVintage {
+			return "", "", i18n.Errorf("характеристики населения доступны в Census API для выпусков до %d", pepFirstYearColumnsVintage-1)
+		}
+		return "pep/charagegroups", vintage, nil
+	default:
+		return "", "", i18n.Errorf("неизвестный вид данных PEP %q; доступны: %s", kind, strings.Join(PEPKinds, ", "))
+	}
+}
+
+// PEPEstimateYear извлекает год оценки на 1 июля из описания даты PEP
+// (например, "7/1/2015 population estimate" -> "2015"). Для оценочной базы
+// и результатов переписи на 1 апреля возвращает false.
+func PEPEstimateYear(dateDescription string) (string, bool) {
+	description := strings.TrimSpace(dateDescription)
+	if !strings.HasPrefix(description, "7/1/") || len(description) < len("7/1/")+4 {
+		return "", false
+	}
+
+	year := description[len("7/1/") : len("7/1/")+4]
+	if _, err := strconv.Atoi(year); err != nil {
+		return "", false
+	}
+	return year, true
+}
+
+// GetPopulationEstimates запрашивает оценки численности населения PEP: годовые итоги,
+// компоненты изменения (рождения, смерти, миграция) или характеристики по возрасту, полу и расе
+func GetPopulationEstimates(api CensusAPIClient, request PEPRequest) (*PEPResult, error) {
+	vintage := request.Vintage
+	if vintage == "" {
+		vintage = DefaultPEPVintage
+	}
+	kind := request.Kind
+	if kind == "" {
+		kind = PEPKindPopulation
+	}
+
+	slog.Info("Получение оценок численности населения",
+		key_kind, kind,
+		key_vintage, vintage,
+		key_geo_level, request.GeoLevel)
+
+	dataset, year, err := PEPDataset(kind, vintage)
+	if err != nil {
+		return nil, err
+	}
+
+	dataRequest := CustomDataRequest{
+		Dataset:   dataset,
+		Year:      year,
+		GeoLevel:  request.GeoLevel,
+		GeoFilter: WildcardGeoFilter(request.GeoLevel, request.GeoFilter),
+	}
+	if vintage == PEPTimeseries && request.Time != "" {
+		dataRequest.Predicates = map[string]string{"time": request.Time}
+	}
+
+	result := &PEPResult{
+		Kind:     kind,
+		Dataset:  dataset,
+		Vintage:  vintage,
+		GeoLevel: request.GeoLevel,
+	}
+
+	switch kind {
+	case PEPKindPopulation:
+		err = fetchPEPPopulation(api, dataRequest, vintage, result)
+	case PEPKindComponents:
+		err = fetchPEPComponents(api, dataRequest, result)
+	case PEPKindCharacteristics:
+		err = fetchPEPCharacteristics(api, dataRequest, vintage, request.Characteristics, result)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result.Rows, func(i, j int) bool {
+		if result.Rows[i].GeoID != result.Rows[j].GeoID {
+			return result.Rows[i].GeoID < result.Rows[j].GeoID
+		}
+		return result.Rows[i].Period < result.Rows[j].Period
+	})
+
+	return result, nil
+}
+
+// fetchPEPPopulation запрашивает годовые оценки численности населения. До выпуска 2020 оценки
+// по годам возвращаются строками с DATE_CODE, начиная с него - переменными POP_<год>.
+func fetchPEPPopulation(api CensusAPIClient, request CustomDataRequest, vintage string, result *PEPResult) error {
+	result.Columns = []PEPColumn{{Key: "POP", Label: "Население"}}
+
+	vintageYear, _ := strconv.Atoi(vintage)
+	if vintage != PEPTimeseries && vintageYear >= pepFirstYearColumnsVintage {
+		request.Variables = []string{"NAME"}
+		for year := pepFirstYear; year <= vintageYear; year++ {
+			request.Variables = append(request.Variables, fmt.Sprintf("POP_%d", year))
+		}
+
+		data, err := api.GetCustomData(request)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range data {
+			for year := pepFirstYear; year <= vintageYear; year++ {
+				value, ok := row[fmt.Sprintf("POP_%d", year)]
+				if !ok {
+					continue
+				}
+				result.Rows = append(result.Rows, PEPRow{
+					GeoID:  GeoID(row),
+					Name:   row["NAME"],
+					Period: strconv.Itoa(year),
+					Values: map[string]string{"POP": pepNumber(value)},
+				})
+			}
+		}
+		return nil
+	}
+
+	request.Variables = []string{"NAME", "POP", "DATE_DESC"}
+	data, err := api.GetCustomData(request)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range data {
+		year, ok := PEPEstimateYear(row["DATE_DESC"])
+		if !ok {
+			continue
+		}
+		result.Rows = append(result.Rows, PEPRow{
+			GeoID:  GeoID(row),
+			Name:   row["NAME"],
+			Period: year,
+			Values: map[string]string{"POP": pepNumber(row["POP"])},
+		})
+	}
+	return nil
+}
+
+// fetchPEPComponents запрашивает компоненты изменения численности населения за период выпуска
+func fetchPEPComponents(api CensusAPIClient, request CustomDataRequest, result *PEPResult) error {
+	result.Columns = pepComponentColumns
+
+	request.Variables = []string{"NAME", "PERIOD_DESC"}
+	for _, column := range pepComponentColumns {
+		request.Variables = append(request.Variables, column.Key)
+	}
+
+	data, err := api.GetCustomData(request)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range data {
+		values := make(map[string]string, len(pepComponentColumns))
+		for _, column := range pepComponentColumns {
+			values[column.Key] = pepNumber(row[column.Key])
+		}
+		result.Rows = append(result.Rows, PEPRow{
+			GeoID:  GeoID(row),
+			Name:   row["NAME"],
+			Period: row["PERIOD_DESC"],
+			Values: values,
+		})
+	}
+	return nil
+}
+
+// fetchPEPCharacteristics запрашивает численность населения по возрасту, полу, расе и
+// испаноязычному происхождению. Для годовых выпусков возвращается оценка на 1 июля года выпуска.
+func fetchPEPCharacteristics(api CensusAPIClient, request CustomDataRequest, vintage string, filter map[string]string, result *PEPResult) error {
+	result.Columns = append(append([]PEPColumn(nil), pepCharacteristics...), PEPColumn{Key: "POP", Label: "Население"})
+
+	for key := range filter {
+		if _, ok := pepCharacteristicLabels[key]; !ok {
+			return i18n.Errorf("неизвестная характеристика %q; доступны: AGEGROUP, SEX, RACE, HISP", key)
+		}
+	}
+
+	if request.Predicates == nil {
+		request.Predicates = make(map[string]string)
+	}
+	for _, characteristic := range pepCharacteristics {
+		value, ok := filter[characteristic.Key]
+		switch {
+		case !ok || value == "":
+			request.Predicates[characteristic.Key] = "0"
+		case strings.EqualFold(value, "all"):
+			// Без предиката Census API возвращает все коды характеристики
+		default:
+			request.Predicates[characteristic.Key] = value
+		}
+	}
+
+	request.Variables = []string{"NAME", "POP", "DATE_DESC"}
+	for _, characteristic := range pepCharacteristics {
+		request.Variables = append(request.Variables, characteristic.Key)
+	}
+
+	data, err := api.GetCustomData(request)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range data {
+		year, ok := PEPEstimateYear(row["DATE_DESC"])
+		if !ok || (vintage != PEPTimeseries && year != vintage) {
+			continue
+		}
+
+		values := map[string]string{"POP": pepNumber(row["POP"])}
+		for _, characteristic := range pepCharacteristics {
+			code := row[characteristic.Key]
+			if label, ok := pepCharacteristicLabels[characteristic.Key][code]; ok {
+				values[characteristic.Key] = label
+			} else {
+				values[characteristic.Key] = code
+			}
+		}
+
+		result.Rows = append(result.Rows, PEPRow{
+			GeoID:  GeoID(row),
+			Name:   row["NAME"],
+			Period: year,
+			Values: values,
+		})
+	}
+	return nil
+}
+
+// pepNumber нормализует числовое значение PEP; нечисловые значения возвращаются как есть
+func pepNumber(value string) string {
+	number, ok := ParseEstimate(value)
+	if !ok {
+		return value
+	}
+	return FormatNumber(number)
+}
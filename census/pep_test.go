@@ -0,0 +1,104 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPEPDataset(t *testing.T) {
+	dataset, year, err := PEPDataset(PEPKindPopulation, "2019")
+	assert.NoError(t, err)
+	assert.Equal(t, "pep/population", dataset)
+	assert.Equal(t, "2019", year)
+
+	dataset, year, err = PEPDataset(PEPKindCharacteristics, PEPTimeseries)
+	assert.NoError(t, err)
+	assert.Equal(t, "pep/int_charagegroups", dataset)
+	assert.Equal(t, "timeseries", year)
+
+	_, _, err = PEPDataset(PEPKindComponents, "2021")
+	assert.Error(t, err)
+
+	_, _, err = PEPDataset(PEPKindComponents, PEPTimeseries)
+	assert.Error(t, err)
+
+	_, _, err = PEPDataset("housing", "2019")
+	assert.Error(t, err)
+}
+
+func TestPEPEstimateYear(t *testing.T) {
+	year, ok := PEPEstimateYear("7/1/2015 population estimate")
+	assert.True(t, ok)
+	assert.Equal(t, "2015", year)
+
+	_, ok = PEPEstimateYear("4/1/2010 Census population")
+	assert.False(t, ok)
+}
+
+func TestGetPopulationEstimates(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{"NAME": "Alpine County", "state": "06", "county": "003", "POP": "1101", "DATE_DESC": "7/1/2019 population estimate"},
+			{"NAME": "Alpine County", "state": "06", "county": "003", "POP": "1175", "DATE_DESC": "4/1/2010 Census population"},
+			{"NAME": "Alpine County", "state": "06", "county": "003", "POP": "1118", "DATE_DESC": "7/1/2018 population estimate"},
+		},
+	}
+
+	result, err := GetPopulationEstimates(api, PEPRequest{GeoLevel: "county", GeoFilter: map[string]string{"state": "06"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "2019", api.requests[0].Year)
+	assert.Equal(t, map[string]string{"state": "06", "county": "*"}, api.requests[0].GeoFilter)
+
+	// Оценки на 1 апреля пропускаются, годы упорядочены
+	assert.Len(t, result.Rows, 2)
+	assert.Equal(t, "2018", result.Rows[0].Period)
+	assert.Equal(t, "1101", result.Rows[1].Values["POP"])
+
+	// Выпуски с 2020 года публикуют годы отдельными переменными
+	api.rows = []map[string]string{{"NAME": "Alpine County", "state": "06", "county": "003", "POP_2020": "1200", "POP_2021": "1235"}}
+	result, err = GetPopulationEstimates(api, PEPRequest{Vintage: "2021", GeoLevel: "county"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"NAME", "POP_2020", "POP_2021"}, api.requests[1].Variables)
+	assert.Len(t, result.Rows, 2)
+	assert.Equal(t, "2021", result.Rows[1].Period)
+
+	// Выпуск timeseries передает год предикатом time
+	api.rows = nil
+	_, err = GetPopulationEstimates(api, PEPRequest{Vintage: PEPTimeseries, Time: "2005", GeoLevel: "state"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pep/int_population", api.requests[2].Dataset)
+	assert.Equal(t, map[string]string{"time": "2005"}, api.requests[2].Predicates)
+}
+
+func TestGetPopulationEstimates_Characteristics(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{"NAME": "Texas", "state": "48", "POP": "14400000", "DATE_DESC": "7/1/2019 population estimate", "AGEGROUP": "0", "SEX": "1", "RACE": "0", "HISP": "0"},
+			{"NAME": "Texas", "state": "48", "POP": "14300000", "DATE_DESC": "7/1/2018 population estimate", "AGEGROUP": "0", "SEX": "1", "RACE": "0", "HISP": "0"},
+			{"NAME": "Texas", "state": "48", "POP": "2000000", "DATE_DESC": "7/1/2019 population estimate", "AGEGROUP": "2", "SEX": "2", "RACE": "0", "HISP": "0"},
+		},
+	}
+
+	result, err := GetPopulationEstimates(api, PEPRequest{
+		Kind:            PEPKindCharacteristics,
+		GeoLevel:        "state",
+		Characteristics: map[string]string{"SEX": "all", "AGEGROUP": "all"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"RACE": "0", "HISP": "0"}, api.requests[0].Predicates)
+
+	// Возвращается только оценка года выпуска
+	assert.Len(t, result.Rows, 2)
+	assert.Equal(t, "Мужчины", result.Rows[0].Values["SEX"])
+	assert.Equal(t, "5-9 лет", result.Rows[1].Values["AGEGROUP"])
+
+	_, err = GetPopulationEstimates(api, PEPRequest{
+		Kind:            PEPKindCharacteristics,
+		GeoLevel:        "state",
+		Characteristics: map[string]string{"INCOME": "1"},
+	})
+	assert.Error(t, err)
+}
@@ -0,0 +1,126 @@
+package census
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// PlainFormatter форматирует данные Census API в виде простого текста без разметки Markdown:
+// заголовки выводятся без символов "#", таблицы - колонками, выровненными пробелами
+type PlainFormatter struct {
+	text *TextFormatter
+}
+
+// NewPlainFormatter создает новый экземпляр форматтера простого текста
+func NewPlainFormatter() *PlainFormatter {
+	slog.DebugContext(context.Background(), "Создание нового форматтера простого текста")
+	return &PlainFormatter{text: &TextFormatter{}}
+}
+
+// Format форматирует данные Census API в простой текст
+func (f *PlainFormatter) Format(ctx context.Context, data interface{}) string {
+	slog.DebugContext(ctx, "Форматирование данных в простой текст",
+		key_type, reflect.TypeOf(data))
+
+	return markdownToPlain(f.text.Format(ctx, data))
+}
+
+// markdownToPlain удаляет разметку Markdown, которую использует TextFormatter
+func markdownToPlain(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	result := make([]string, 0, len(lines))
+
+	var table [][]string
+	flushTable := func() {
+		if len(table) > 0 {
+			result = append(result, alignColumns(table)...)
+			table = nil
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "|") {
+			cells := markdownTableCells(trimmed)
+			if !isMarkdownSeparator(cells) {
+				table = append(table, cells)
+			}
+			continue
+		}
+		flushTable()
+
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			line = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+		case strings.HasPrefix(trimmed, "> "):
+			line = strings.TrimPrefix(trimmed, "> ")
+		}
+		result = append(result, stripInlineMarkdown(line))
+	}
+	flushTable()
+
+	return strings.Join(result, "\n")
+}
+
+// markdownTableCells разбивает строку таблицы Markdown на ячейки
+func markdownTableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	parts := strings.Split(line, "|")
+	cells := make([]string, 0, len(parts))
+	for _, part := range parts {
+		cells = append(cells, stripInlineMarkdown(strings.TrimSpace(part)))
+	}
+	return cells
+}
+
+// isMarkdownSeparator сообщает, является ли строка таблицы разделителем заголовка ("| --- | :-: |")
+func isMarkdownSeparator(cells []string) bool {
+	for _, cell := range cells {
+		if strings.Trim(cell, "-: ") != "" || !strings.Contains(cell, "-") {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+// alignColumns выравнивает ячейки таблицы по ширине столбцов
+func alignColumns(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := utf8.RuneCountInString(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	lines := make([]string, 0, len(rows))
+	for _, row := range rows {
+		var sb strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			sb.WriteString(cell)
+			if i < len(row)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+			}
+		}
+		lines = append(lines, strings.TrimRight(sb.String(), " "))
+	}
+	return lines
+}
+
+// stripInlineMarkdown удаляет выделение жирным шрифтом и моноширинный шрифт
+func stripInlineMarkdown(text string) string {
+	return strings.NewReplacer("**", "", "`", "").Replace(text)
+}
@@ -0,0 +1,24 @@
+package census
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownToPlain(t *testing.T) {
+	markdown := "# Рейтинг\n\n**Всего**: 2\n\n| Место | География |\n|---|:---:|\n| 1 | Maryland |\n| 10 | District of Columbia |\n\n> Примечание"
+
+	assert.Equal(t, "Рейтинг\n\nВсего: 2\n\nМесто  География\n1      Maryland\n10     District of Columbia\n\nПримечание",
+		markdownToPlain(markdown))
+}
+
+func TestPlainFormatter_Format(t *testing.T) {
+	result := NewPlainFormatter().Format(context.Background(), []PopulationData{
+		{Name: "California", Population: "39538223", State: "06"},
+	})
+
+	assert.NotContains(t, result, "|")
+	assert.Contains(t, result, "California (штат 06)  39\u00a0538\u00a0223")
+}
@@ -0,0 +1,340 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Суффиксы наборов данных ACS с профилями и тематическими таблицами
+const (
+	profileDatasetSuffix = "/profile"
+	subjectDatasetSuffix = "/subject"
+)
+
+// DefaultProfileDataset - набор данных профилей ACS по умолчанию
+const DefaultProfileDataset = "acs/acs5/profile"
+
+// IsProfileDataset сообщает, является ли набор данных профилем ACS (DP02-DP05)
+func IsProfileDataset(dataset string) bool {
+	return strings.HasPrefix(dataset, "acs/") && strings.HasSuffix(dataset, profileDatasetSuffix)
+}
+
+// IsSubjectDataset сообщает, является ли набор данных тематическими таблицами ACS (S0101-S2919)
+func IsSubjectDataset(dataset string) bool {
+	return strings.HasPrefix(dataset, "acs/") && strings.HasSuffix(dataset, subjectDatasetSuffix)
+}
+
+// PercentVariable возвращает процентный столбец профиля для переменной-оценки
+// (например, DP03_0062E -> DP03_0062PE). Для процентных столбцов и MOE возвращает false.
+func PercentVariable(variable string) (string, bool) {
+	if !strings.HasPrefix(variable, "DP") || !strings.HasSuffix(variable, "E") || strings.HasSuffix(variable, "PE") {
+		return "", false
+	}
+	return strings.TrimSuffix(variable, "E") + "PE", true
+}
+
+// subjectVariablePattern разбирает переменные тематических таблиц ACS: таблица, столбец,
+// строка и суффикс (например, S1701_C03_001E - таблица S1701, столбец C03, строка 001, оценка)
+var subjectVariablePattern = regexp.MustCompile(`^(S[0-9A-Z]+)_C([0-9]+)_([0-9]+)([EM])$`)
+
+// PairPercentVariables дополняет список переменных профиля или тематической таблицы ACS
+// процентными столбцами, вставляя их сразу после запрошенной оценки, чтобы количество
+// и доля выводились рядом; после запрошенного MOE вставляется MOE процента.
+//
+// В профилях процент оценки DPxx_xxxxE - столбец DPxx_xxxxPE, а его MOE - DPxx_xxxxPM.
+// В тематических таблицах процент публикуется в другом столбце той же строки
+// (S1701_C01_001E - S1701_C03_001E), поэтому процентные столбцы определяются по описаниям
+// переменных выпуска definitions; без описаний список тематической таблицы не меняется.
+// Для других наборов данных список возвращается без изменений.
+func PairPercentVariables(dataset string, variables []string, definitions map[string]VariableInfo) []string {
+	var partners func(variable string) []string
+	switch {
+	case IsProfileDataset(dataset):
+		partners = profilePercentVariables
+	case IsSubjectDataset(dataset) && len(definitions) > 0:
+		partners = func(variable string) []string {
+			return subjectPercentVariables(variable, definitions)
+		}
+	default:
+		return variables
+	}
+
+	result := make([]string, 0, 2*len(variables))
+	added := make(map[string]bool, 2*len(variables))
+	for _, variable := range variables {
+		if added[variable] {
+			continue
+		}
+		result = append(result, variable)
+		added[variable] = true
+
+		for _, partner := range partners(variable) {
+			if !added[partner] {
+				result = append(result, partner)
+				added[partner] = true
+			}
+		}
+	}
+	return result
+}
+
+// profilePercentVariables возвращает процентный столбец профиля для оценки (DP03_0062E ->
+// DP03_0062PE) или MOE процента для MOE оценки (DP03_0062M -> DP03_0062PM)
+func profilePercentVariables(variable string) []string {
+	if percent, ok := PercentVariable(variable); ok {
+		return []string{percent}
+	}
+	if strings.HasPrefix(variable, "DP") && strings.HasSuffix(variable, "M") && !strings.HasSuffix(variable, "PM") {
+		return []string{strings.TrimSuffix(variable, "M") + "PM"}
+	}
+	return nil
+}
+
+// subjectPercentVariables возвращает процентные столбцы той же строки тематической таблицы
+// для оценки (S1701_C01_001E -> S1701_C03_001E) или их MOE для MOE оценки. Процентные
+// столбцы определяются по описаниям переменных; для самих процентных столбцов пар нет.
+func subjectPercentVariables(variable string, definitions map[string]VariableInfo) []string {
+	match := subjectVariablePattern.FindStringSubmatch(variable)
+	if match == nil {
+		return nil
+	}
+	table, column, line, suffix := match[1], match[2], match[3], match[4]
+
+	estimate := fmt.Sprintf("%s_C%s_%sE", table, column, line)
+	if VariableKind(estimate, definitions[estimate]) == ValueKindPercent {
+		return nil
+	}
+
+	var partners []string
+	for name, info := range definitions {
+		other := subjectVariablePattern.FindStringSubmatch(name)
+		if other == nil || other[1] != table || other[3] != line || other[2] == column || other[4] != "E" {
+			continue
+		}
+		if VariableKind(name, info) != ValueKindPercent {
+			continue
+		}
+		if suffix == "M" {
+			name = strings.TrimSuffix(name, "E") + "M"
+		}
+		partners = append(partners, name)
+	}
+	sort.Strings(partners)
+	return partners
+}
+
+// ProfileLine описывает строку курируемого профиля: базовый код переменной без суффикса
+// (например, "DP03_0062") и ее название
+type ProfileLine struct {
+	Code  string
+	Label string
+}
+
+// ProfileTable описывает курируемую таблицу профиля ACS
+type ProfileTable struct {
+	Table string
+	Title string
+	Lines []ProfileLine
+}
+
+// ProfileTables - курируемые таблицы профилей ACS. Коды строк соответствуют выпускам
+// профилей ACS 2019-2022; в других выпусках нумерация строк может отличаться.
+var ProfileTables = map[string]ProfileTable{
+	"DP02": {
+		Table: "DP02",
+		Title: "Социальные характеристики",
+		Lines: []ProfileLine{
+			{"DP02_0001", "Всего домохозяйств"},
+			{"DP02_0016", "Средний размер домохозяйства"},
+			{"DP02_0017", "Средний размер семьи"},
+			{"DP02_0067", "Среднее образование или выше (25 лет и старше)"},
+			{"DP02_0068", "Степень бакалавра или выше (25 лет и старше)"},
+			{"DP02_0153", "Домохозяйства с компьютером"},
+			{"DP02_0154", "Домохозяйства с широкополосным интернетом"},
+		},
+	},
+	"DP03": {
+		Table: "DP03",
+		Title: "Экономические характеристики",
+		Lines: []ProfileLine{
+			{"DP03_0001", "Население 16 лет и старше"},
+			{"DP03_0002", "В составе рабочей силы"},
+			{"DP03_0009", "Уровень безработицы"},
+			{"DP03_0025", "Среднее время в пути на работу (минут)"},
+			{"DP03_0062", "Медианный доход домохозяйства (долларов)"},
+			{"DP03_0063", "Средний доход домохозяйства (долларов)"},
+			{"DP03_0088", "Доход на душу населения (долларов)"},
+			{"DP03_0096", "С медицинской страховкой"},
+			{"DP03_0099", "Без медицинской страховки"},
+			{"DP03_0119", "Семьи с доходом ниже черты бедности"},
+			{"DP03_0128", "Все жители с доходом ниже черты бедности"},
+		},
+	},
+	"DP04": {
+		Table: "DP04",
+		Title: "Жилищные характеристики",
+		Lines: []ProfileLine{
+			{"DP04_0001", "Всего жилых единиц"},
+			{"DP04_0002", "Занятые жилые единицы"},
+			{"DP04_0003", "Пустующие жилые единицы"},
+			{"DP04_0004", "Доля пустующего жилья владельцев"},
+			{"DP04_0005", "Доля пустующего арендного жилья"},
+			{"DP04_0046", "Занято владельцами"},
+			{"DP04_0047", "Занято арендаторами"},
+			{"DP04_0089", "Медианная стоимость жилья (долларов)"},
+			{"DP04_0134", "Медианная валовая арендная плата (долларов)"},
+		},
+	},
+	"DP05": {
+		Table: "DP05",
+		Title: "Демографические характеристики",
+		Lines: []ProfileLine{
+			{"DP05_0001", "Всего населения"},
+			{"DP05_0002", "Мужчины"},
+			{"DP05_0003", "Женщины"},
+			{"DP05_0018", "Медианный возраст (лет)"},
+			{"DP05_0021", "18 лет и старше"},
+			{"DP05_0024", "65 лет и старше"},
+			{"DP05_0071", "Испаноязычные или латиноамериканцы (любой расы)"},
+			{"DP05_0077", "Белые, не испаноязычные"},
+			{"DP05_0078", "Черные или афроамериканцы, не испаноязычные"},
+			{"DP05_0080", "Азиаты, не испаноязычные"},
+		},
+	},
+}
+
+// LookupProfileTable возвращает курируемую таблицу профиля по ее идентификатору
+func LookupProfileTable(table string) (ProfileTable, error) {
+	if profile, ok := ProfileTables[strings.ToUpper(strings.TrimSpace(table))]; ok {
+		return profile, nil
+	}
+
+	supported := make([]string, 0, len(ProfileTables))
+	for id := range ProfileTables {
+		supported = append(supported, id)
+	}
+	sort.Strings(supported)
+
+	return ProfileTable{}, i18n.Errorf("таблица профиля %q не поддерживается; доступны: %s", table, strings.Join(supported, ", "))
+}
+
+// Variables возвращает переменные Census API для строк профиля: оценку, ее MOE,
+// процент и MOE процента
+func (t ProfileTable) Variables() []string {
+	variables := make([]string, 0, len(t.Lines)*4)
+	for _, line := range t.Lines {
+		variables = append(variables, line.Code+"E", line.Code+"M", line.Code+"PE", line.Code+"PM")
+	}
+	return variables
+}
+
+// ProfileRequest представляет запрос курируемой таблицы профиля
+type ProfileRequest struct {
+	Table     string            // Таблица профиля (DP02-DP05)
+	Dataset   string            // Набор данных (по умолчанию DefaultProfileDataset)
+	Year      string            // Год данных
+	GeoLevel  string            // Географический уровень
+	GeoFilter map[string]string // Фильтр географии
+}
+
+// ProfileValue содержит значения одной строки профиля. Пустая строка означает,
+// что значение не публикуется (например, процент для медианы).
+type ProfileValue struct {
+	Code       string `json:"code"`
+	Label      string `json:"label"`
+	Estimate   string `json:"estimate"`
+	MOE        string `json:"moe"`
+	Percent    string `json:"percent"`
+	PercentMOE string `json:"percent_moe"`
+}
+
+// ProfileGeography содержит строки профиля для одной географии
+type ProfileGeography struct {
+	GeoID string         `json:"geoid"`
+	Name  string         `json:"name"`
+	Lines []ProfileValue `json:"lines"`
+}
+
+// ProfileResult содержит курируемую таблицу профиля по географиям
+type ProfileResult struct {
+	Table       string             `json:"table"`
+	Title       string             `json:"title"`
+	Dataset     string             `json:"dataset"`
+	Year        string             `json:"year"`
+	Geographies []ProfileGeography `json:"geographies"`
+}
+
+// GetProfile запрашивает курируемую таблицу профиля ACS, объединяя для каждой строки
+// оценку, процент и их MOE
+func GetProfile(api CensusAPIClient, request ProfileRequest) (*ProfileResult, error) {
+	dataset := request.Dataset
+	if dataset == "" {
+		dataset = DefaultProfileDataset
+	}
+
+	slog.Info("Получение профиля ACS",
+		key_table, request.Table,
+		key_dataset, dataset)
+
+	if !IsProfileDataset(dataset) {
+		return nil, i18n.Errorf("набор данных %q не является профилем ACS (например, %q)", dataset, DefaultProfileDataset)
+	}
+
+	table, err := LookupProfileTable(request.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := api.GetCustomData(CustomDataRequest{
+		Variables: append([]string{"NAME"}, table.Variables()...),
+		Dataset:   dataset,
+		Year:      request.Year,
+		GeoLevel:  request.GeoLevel,
+		GeoFilter: request.GeoFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProfileResult{
+		Table:   table.Table,
+		Title:   table.Title,
+		Dataset: dataset,
+		Year:    request.Year,
+	}
+
+	for _, row := range data {
+		geography := ProfileGeography{GeoID: GeoID(row), Name: row["NAME"]}
+		for _, line := range table.Lines {
+			geography.Lines = append(geography.Lines, ProfileValue{
+				Code:       line.Code,
+				Label:      line.Label,
+				Estimate:   profileNumber(row[line.Code+"E"]),
+				MOE:        profileNumber(row[line.Code+"M"]),
+				Percent:    profileNumber(row[line.Code+"PE"]),
+				PercentMOE: profileNumber(row[line.Code+"PM"]),
+			})
+		}
+		result.Geographies = append(result.Geographies, geography)
+	}
+
+	sort.Slice(result.Geographies, func(i, j int) bool {
+		return result.Geographies[i].GeoID < result.Geographies[j].GeoID
+	})
+
+	return result, nil
+}
+
+// profileNumber нормализует значение профиля: служебные и нечисловые значения
+// (например, "(X)" для процента медианы) заменяются пустой строкой
+func profileNumber(value string) string {
+	number, ok := ParseEstimate(value)
+	if !ok {
+		return ""
+	}
+	return FormatNumber(number)
+}
@@ -0,0 +1,90 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfileDatasets(t *testing.T) {
+	assert.True(t, IsProfileDataset("acs/acs5/profile"))
+	assert.True(t, IsProfileDataset("acs/acs1/profile"))
+	assert.False(t, IsProfileDataset("acs/acs5"))
+	assert.True(t, IsSubjectDataset("acs/acs5/subject"))
+	assert.False(t, IsSubjectDataset("acs/acs5/profile"))
+}
+
+func TestPairPercentVariables(t *testing.T) {
+	// Процент и MOE процента профиля следуют сразу за оценкой и ее MOE
+	assert.Equal(t,
+		[]string{"NAME", "DP05_0001E", "DP05_0001PE", "DP03_0062E", "DP03_0062PE", "DP03_0062M", "DP03_0062PM", "DP03_0009PE"},
+		PairPercentVariables("acs/acs5/profile", []string{"NAME", "DP05_0001E", "DP03_0062E", "DP03_0062M", "DP03_0009PE", "DP05_0001PE"}, nil))
+
+	// Для других наборов данных список не меняется
+	assert.Equal(t, []string{"B19013_001E"}, PairPercentVariables("acs/acs5", []string{"B19013_001E"}, nil))
+
+	_, ok := PercentVariable("DP03_0062M")
+	assert.False(t, ok)
+}
+
+func TestPairPercentVariables_Subject(t *testing.T) {
+	definitions := map[string]VariableInfo{
+		"S1701_C01_001E": {Label: "Estimate!!Total!!Population for whom poverty status is determined", Concept: "POVERTY STATUS IN THE PAST 12 MONTHS"},
+		"S1701_C02_001E": {Label: "Estimate!!Below poverty level!!Population for whom poverty status is determined", Concept: "POVERTY STATUS IN THE PAST 12 MONTHS"},
+		"S1701_C03_001E": {Label: "Estimate!!Percent below poverty level!!Population for whom poverty status is determined", Concept: "POVERTY STATUS IN THE PAST 12 MONTHS"},
+		"S1701_C03_002E": {Label: "Estimate!!Percent below poverty level!!Population for whom poverty status is determined!!AGE!!Under 18 years", Concept: "POVERTY STATUS IN THE PAST 12 MONTHS"},
+	}
+
+	assert.Equal(t,
+		[]string{"NAME", "S1701_C02_001E", "S1701_C03_001E", "S1701_C02_001M", "S1701_C03_001M"},
+		PairPercentVariables("acs/acs5/subject", []string{"NAME", "S1701_C02_001E", "S1701_C02_001M"}, definitions))
+
+	// Процентный столбец не получает пары, а без описаний список не меняется
+	assert.Equal(t, []string{"S1701_C03_001E"}, PairPercentVariables("acs/acs5/subject", []string{"S1701_C03_001E"}, definitions))
+	assert.Equal(t, []string{"S1701_C01_001E"}, PairPercentVariables("acs/acs5/subject", []string{"S1701_C01_001E"}, nil))
+}
+
+func TestGetProfile(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{
+				"NAME": "Texas", "state": "48",
+				"DP03_0062E": "67321", "DP03_0062M": "220", "DP03_0062PE": "-888888888", "DP03_0062PM": "-888888888",
+				"DP03_0009E": "-888888888", "DP03_0009M": "-888888888", "DP03_0009PE": "5.3", "DP03_0009PM": "0.1",
+			},
+			{"NAME": "California", "state": "06"},
+		},
+	}
+
+	result, err := GetProfile(api, ProfileRequest{Table: "dp03", Year: "2021", GeoLevel: "state"})
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultProfileDataset, api.requests[0].Dataset)
+	assert.Contains(t, api.requests[0].Variables, "DP03_0128PM")
+	assert.Equal(t, "Экономические характеристики", result.Title)
+
+	// Географии упорядочены по GEOID
+	assert.Equal(t, "06", result.Geographies[0].GeoID)
+	texas := result.Geographies[1]
+	assert.Len(t, texas.Lines, len(ProfileTables["DP03"].Lines))
+
+	var income, unemployment ProfileValue
+	for _, line := range texas.Lines {
+		switch line.Code {
+		case "DP03_0062":
+			income = line
+		case "DP03_0009":
+			unemployment = line
+		}
+	}
+	assert.Equal(t, ProfileValue{Code: "DP03_0062", Label: "Медианный доход домохозяйства (долларов)", Estimate: "67321", MOE: "220"}, income)
+	assert.Equal(t, "", unemployment.Estimate)
+	assert.Equal(t, "5.3", unemployment.Percent)
+	assert.Equal(t, "0.1", unemployment.PercentMOE)
+
+	_, err = GetProfile(api, ProfileRequest{Table: "DP01", GeoLevel: "state"})
+	assert.Error(t, err)
+
+	_, err = GetProfile(api, ProfileRequest{Table: "DP03", Dataset: "acs/acs5", GeoLevel: "state"})
+	assert.Error(t, err)
+}
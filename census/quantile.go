@@ -0,0 +1,371 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_table    = "table"
+	key_quantile = "quantile"
+)
+
+// Методы интерполяции квантилей по интервальному распределению
+const (
+	MethodLinear = "linear" // Линейная интерполяция внутри интервала
+	MethodPareto = "pareto" // Интерполяция по распределению Парето
+	// MethodAuto использует интерполяцию Парето для интервалов шире paretoMinWidth
+	// с положительной нижней границей и линейную интерполяцию для остальных
+	MethodAuto = "auto"
+)
+
+// paretoMinWidth - ширина интервала, начиная с которой Census Bureau применяет интерполяцию Парето
+const paretoMinWidth = 2500
+
+// Bin представляет один интервал распределения и количество наблюдений в нем
+type Bin struct {
+	Lower float64 // Нижняя граница интервала
+	Upper float64 // Верхняя граница интервала (не используется для открытого интервала)
+	// OpenEnded означает верхний открытый интервал (например, "$200,000 or more")
+	OpenEnded bool
+	Count     float64
+}
+
+// BinDefinition связывает переменную таблицы Census с границами интервала
+type BinDefinition struct {
+	Variable  string
+	Lower     float64
+	Upper     float64
+	OpenEnded bool
+}
+
+// BinnedTable описывает интервальную таблицу Census, по которой можно оценить медиану
+type BinnedTable struct {
+	Table string
+	Title string
+	// Total - переменная с общим числом наблюдений в интервалах
+	Total string
+	Bins  []BinDefinition
+}
+
+// dollarBins строит определения интервалов таблицы по границам в долларах.
+// Последняя граница задает нижнюю границу открытого интервала.
+func dollarBins(table string, first int, bounds ...float64) []BinDefinition {
+	bins := make([]BinDefinition, 0, len(bounds))
+	lower := 0.0
+	for i, bound := range bounds {
+		variable := fmt.Sprintf("%s_%03dE", table, first+i)
+		if i == len(bounds)-1 {
+			bins = append(bins, BinDefinition{Variable: variable, Lower: bound, OpenEnded: true})
+			break
+		}
+		bins = append(bins, BinDefinition{Variable: variable, Lower: lower, Upper: bound})
+		lower = bound
+	}
+	return bins
+}
+
+// BinnedTables - поддерживаемые интервальные таблицы ACS
+var BinnedTables = map[string]BinnedTable{
+	"B19001": {
+		Table: "B19001",
+		Title: "Household income in the past 12 months",
+		Total: "B19001_001E",
+		Bins: dollarBins("B19001", 2,
+			10000, 15000, 20000, 25000, 30000, 35000, 40000, 45000, 50000,
+			60000, 75000, 100000, 125000, 150000, 200000, 200000),
+	},
+	"B25075": {
+		Table: "B25075",
+		Title: "Value of owner-occupied housing units",
+		Total: "B25075_001E",
+		Bins: dollarBins("B25075", 2,
+			10000, 15000, 20000, 25000, 30000, 35000, 40000, 50000, 60000,
+			70000, 80000, 90000, 100000, 125000, 150000, 175000, 200000, 250000,
+			300000, 400000, 500000, 750000, 1000000, 1500000, 2000000, 2000000),
+	},
+	"B25063": {
+		Table: "B25063",
+		Title: "Gross rent (renter-occupied units paying cash rent)",
+		Total: "B25063_002E",
+		Bins: dollarBins("B25063", 3,
+			100, 150, 200, 250, 300, 350, 400, 450, 500, 550, 600, 650, 700, 750,
+			800, 900, 1000, 1250, 1500, 2000, 2500, 3000, 3500, 3500),
+	},
+}
+
+// MedianSources связывает опубликованные медианы с интервальными таблицами, по которым
+// медиану можно переоценить для объединения географий
+var MedianSources = map[string]string{
+	"B19013_001E": "B19001",
+	"B25077_001E": "B25075",
+	"B25064_001E": "B25063",
+}
+
+// QuantileEstimate содержит оценку квантиля распределения
+type QuantileEstimate struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+	MOE      float64 `json:"moe"`
+	HasMOE   bool    `json:"has_moe"`
+	Method   string  `json:"method"`
+	// TopCoded означает, что квантиль попал в открытый интервал и Value - его нижняя граница
+	TopCoded bool `json:"top_coded"`
+}
+
+// EstimateQuantile оценивает квантиль p (0 < p < 1) интервального распределения.
+// Интервалы должны быть упорядочены по возрастанию границ.
+func EstimateQuantile(bins []Bin, p float64, method string) (QuantileEstimate, error) {
+	if p <= 0 || p >= 1 {
+		return QuantileEstimate{}, i18n.Errorf("квантиль должен быть в интервале (0, 1): %v", p)
+	}
+
+	switch method {
+	case "":
+		method = MethodLinear
+	case MethodLinear, MethodPareto, MethodAuto:
+	default:
+		return QuantileEstimate{}, i18n.Errorf("неизвестный метод интерполяции: %q", method)
+	}
+
+	total := 0.0
+	for _, bin := range bins {
+		total += bin.Count
+	}
+	if total <= 0 {
+		return QuantileEstimate{}, i18n.Errorf("распределение не содержит наблюдений")
+	}
+
+	target := p * total
+	below := 0.0
+
+	for _, bin := range bins {
+		if bin.Count <= 0 || below+bin.Count < target {
+			below += bin.Count
+			continue
+		}
+
+		estimate := QuantileEstimate{Quantile: p}
+		if bin.OpenEnded {
+			estimate.Value = bin.Lower
+			estimate.Method = method
+			estimate.TopCoded = true
+			return estimate, nil
+		}
+
+		// Доли наблюдений не ниже нижней и верхней границ интервала
+		aboveLower := total - below
+		aboveUpper := total - below - bin.Count
+
+		usePareto := method == MethodPareto ||
+			(method == MethodAuto && bin.Upper-bin.Lower > paretoMinWidth)
+		if usePareto && bin.Lower > 0 && aboveUpper > 0 {
+			// S(x) = S(L) * (L/x)^theta, theta = ln(S(L)/S(U)) / ln(U/L)
+			theta := math.Log(aboveLower/aboveUpper) / math.Log(bin.Upper/bin.Lower)
+			estimate.Value = bin.Lower * math.Pow(aboveLower/(total-target), 1/theta)
+			estimate.Method = MethodPareto
+			return estimate, nil
+		}
+
+		estimate.Value = bin.Lower + (target-below)/bin.Count*(bin.Upper-bin.Lower)
+		estimate.Method = MethodLinear
+		return estimate, nil
+	}
+
+	return QuantileEstimate{}, i18n.Errorf("не удалось найти интервал квантиля %v", p)
+}
+
+// QuantileMOE вычисляет MOE (90%) квантиля по методу Census Bureau: стандартная ошибка
+// доли SE(P) = DF × √(99/B × P × (100 − P)) переводится в границы доверительного
+// интервала для P, которые интерполируются по тому же распределению.
+// designFactor - коэффициент дизайна выборки из таблиц ACS для данной характеристики.
+func QuantileMOE(bins []Bin, p float64, method string, designFactor float64) (float64, error) {
+	if designFactor <= 0 {
+		return 0, i18n.Errorf("коэффициент дизайна должен быть положительным")
+	}
+
+	total := 0.0
+	for _, bin := range bins {
+		total += bin.Count
+	}
+	if total <= 0 {
+		return 0, i18n.Errorf("распределение не содержит наблюдений")
+	}
+
+	percent := p * 100
+	se := designFactor * math.Sqrt(99/total*percent*(100-percent))
+
+	lowerP := math.Max((percent-moeZ90*se)/100, 1e-9)
+	upperP := math.Min((percent+moeZ90*se)/100, 1-1e-9)
+
+	lower, err := EstimateQuantile(bins, lowerP, method)
+	if err != nil {
+		return 0, err
+	}
+	upper, err := EstimateQuantile(bins, upperP, method)
+	if err != nil {
+		return 0, err
+	}
+
+	return (upper.Value - lower.Value) / 2, nil
+}
+
+// BinsFromRows суммирует интервалы таблицы по одной или нескольким строкам ответа Census API.
+// Возвращает false, если значение интервала подавлено или отсутствует хотя бы в одной строке.
+func BinsFromRows(table BinnedTable, rows ...map[string]string) ([]Bin, bool) {
+	bins := make([]Bin, len(table.Bins))
+	for i, definition := range table.Bins {
+		bins[i] = Bin{Lower: definition.Lower, Upper: definition.Upper, OpenEnded: definition.OpenEnded}
+		for _, row := range rows {
+			count, ok := ParseEstimate(row[definition.Variable])
+			if !ok {
+				return nil, false
+			}
+			bins[i].Count += count
+		}
+	}
+	return bins, true
+}
+
+// Variables возвращает переменные таблицы, необходимые для оценки квантилей
+func (t BinnedTable) Variables() []string {
+	variables := make([]string, 0, len(t.Bins)+1)
+	variables = append(variables, t.Total)
+	for _, bin := range t.Bins {
+		variables = append(variables, bin.Variable)
+	}
+	return variables
+}
+
+// LookupBinnedTable возвращает интервальную таблицу по ее идентификатору
+func LookupBinnedTable(table string) (BinnedTable, error) {
+	if binned, ok := BinnedTables[strings.ToUpper(strings.TrimSpace(table))]; ok {
+		return binned, nil
+	}
+
+	supported := make([]string, 0, len(BinnedTables))
+	for id := range BinnedTables {
+		supported = append(supported, id)
+	}
+	sort.Strings(supported)
+
+	return BinnedTable{}, i18n.Errorf("таблица %q не поддерживается; доступны: %s", table, strings.Join(supported, ", "))
+}
+
+// QuantileRequest представляет запрос оценки квантилей по интервальной таблице
+type QuantileRequest struct {
+	Table     string            // Интервальная таблица (например, "B19001")
+	Dataset   string            // Набор данных (например, "acs/acs5")
+	Year      string            // Год данных
+	GeoLevel  string            // Географический уровень
+	GeoFilter map[string]string // Фильтр географии
+	Quantiles []float64         // Оцениваемые квантили (по умолчанию медиана)
+	Method    string            // Метод интерполяции
+	// DesignFactor - коэффициент дизайна для расчета MOE; если не задан, MOE не вычисляется
+	DesignFactor float64
+}
+
+// QuantileRow содержит оценки квантилей для одной географии
+type QuantileRow struct {
+	GeoID     string             `json:"geoid"`
+	Name      string             `json:"name"`
+	Total     float64            `json:"total"`
+	Quantiles []QuantileEstimate `json:"quantiles"`
+	// Error - причина, по которой квантили не удалось оценить
+	Error error `json:"error,omitempty"`
+}
+
+// QuantileResult содержит оценки квантилей по географиям
+type QuantileResult struct {
+	Table   string        `json:"table"`
+	Title   string        `json:"title"`
+	Dataset string        `json:"dataset"`
+	Year    string        `json:"year"`
+	Rows    []QuantileRow `json:"rows"`
+}
+
+// EstimateQuantiles запрашивает интервальную таблицу и оценивает квантили распределения
+// для каждой географии
+func EstimateQuantiles(api CensusAPIClient, request QuantileRequest) (*QuantileResult, error) {
+	slog.Info("Оценка квантилей по интервальной таблице",
+		key_table, request.Table,
+		key_dataset, request.Dataset)
+
+	table, err := LookupBinnedTable(request.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	quantiles := request.Quantiles
+	if len(quantiles) == 0 {
+		quantiles = []float64{0.5}
+	}
+	for _, p := range quantiles {
+		if p <= 0 || p >= 1 {
+			return nil, i18n.Errorf("квантиль должен быть в интервале (0, 1): %v", p)
+		}
+	}
+
+	data, err := api.GetCustomData(CustomDataRequest{
+		Variables: append([]string{"NAME"}, table.Variables()...),
+		Dataset:   request.Dataset,
+		Year:      request.Year,
+		GeoLevel:  request.GeoLevel,
+		GeoFilter: request.GeoFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &QuantileResult{
+		Table:   table.Table,
+		Title:   table.Title,
+		Dataset: request.Dataset,
+		Year:    request.Year,
+	}
+
+	for _, item := range data {
+		row := QuantileRow{GeoID: GeoID(item), Name: item["NAME"]}
+		row.Total, _ = ParseEstimate(item[table.Total])
+
+		bins, ok := BinsFromRows(table, item)
+		if !ok {
+			row.Error = i18n.Errorf("значения интервалов подавлены или отсутствуют")
+			result.Rows = append(result.Rows, row)
+			continue
+		}
+
+		for _, p := range quantiles {
+			estimate, err := EstimateQuantile(bins, p, request.Method)
+			if err != nil {
+				slog.Debug("Не удалось оценить квантиль",
+					key_quantile, p,
+					key_err, err)
+				row.Error = err
+				break
+			}
+
+			if request.DesignFactor > 0 && !estimate.TopCoded {
+				if moe, err := QuantileMOE(bins, p, request.Method, request.DesignFactor); err == nil {
+					estimate.MOE = moe
+					estimate.HasMOE = true
+				}
+			}
+
+			row.Quantiles = append(row.Quantiles, estimate)
+		}
+
+		result.Rows = append(result.Rows, row)
+	}
+
+	sort.Slice(result.Rows, func(i, j int) bool {
+		return result.Rows[i].GeoID < result.Rows[j].GeoID
+	})
+
+	return result, nil
+}
@@ -0,0 +1,119 @@
+package census
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testBins - распределение из трех интервалов: 10 наблюдений до 10 000, 20 - от 10 000 до 20 000
+// и 10 - в открытом интервале от 20 000
+func testBins() []Bin {
+	return []Bin{
+		{Lower: 0, Upper: 10000, Count: 10},
+		{Lower: 10000, Upper: 20000, Count: 20},
+		{Lower: 20000, OpenEnded: true, Count: 10},
+	}
+}
+
+func TestEstimateQuantile(t *testing.T) {
+	estimate, err := EstimateQuantile(testBins(), 0.5, MethodLinear)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(15000), estimate.Value)
+	assert.Equal(t, MethodLinear, estimate.Method)
+
+	// Парето: S(L) = 30, S(U) = 10, theta = ln(3)/ln(2), медиана = L * (30/20)^(1/theta)
+	estimate, err = EstimateQuantile(testBins(), 0.5, MethodPareto)
+	assert.NoError(t, err)
+	assert.InDelta(t, 10000*math.Pow(1.5, math.Log(2)/math.Log(3)), estimate.Value, 1e-6)
+	assert.Equal(t, MethodPareto, estimate.Method)
+
+	// В первом интервале с нулевой нижней границей Парето неприменим
+	estimate, err = EstimateQuantile(testBins(), 0.1, MethodPareto)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(4000), estimate.Value)
+	assert.Equal(t, MethodLinear, estimate.Method)
+
+	// Квантиль в открытом интервале возвращает его нижнюю границу
+	estimate, err = EstimateQuantile(testBins(), 0.9, MethodLinear)
+	assert.NoError(t, err)
+	assert.True(t, estimate.TopCoded)
+	assert.Equal(t, float64(20000), estimate.Value)
+
+	_, err = EstimateQuantile(testBins(), 1.5, MethodLinear)
+	assert.Error(t, err)
+
+	_, err = EstimateQuantile([]Bin{{Lower: 0, Upper: 10}}, 0.5, MethodLinear)
+	assert.Error(t, err)
+
+	_, err = EstimateQuantile(testBins(), 0.5, "spline")
+	assert.Error(t, err)
+}
+
+func TestQuantileMOE(t *testing.T) {
+	bins := testBins()
+	for i := range bins {
+		bins[i].Count *= 1000
+	}
+
+	moe, err := QuantileMOE(bins, 0.5, MethodLinear, 1.5)
+	assert.NoError(t, err)
+	assert.Greater(t, moe, float64(0))
+
+	// Больший коэффициент дизайна дает большую ошибку
+	wider, err := QuantileMOE(bins, 0.5, MethodLinear, 2)
+	assert.NoError(t, err)
+	assert.Greater(t, wider, moe)
+
+	_, err = QuantileMOE(bins, 0.5, MethodLinear, 0)
+	assert.Error(t, err)
+}
+
+func TestBinnedTables(t *testing.T) {
+	table, err := LookupBinnedTable("b19001")
+	assert.NoError(t, err)
+	assert.Len(t, table.Bins, 16)
+	assert.Equal(t, "B19001_017E", table.Bins[15].Variable)
+	assert.True(t, table.Bins[15].OpenEnded)
+	assert.Equal(t, float64(200000), table.Bins[15].Lower)
+	assert.Equal(t, BinDefinition{Variable: "B19001_011E", Lower: 50000, Upper: 60000}, table.Bins[9])
+
+	_, err = LookupBinnedTable("B01001")
+	assert.Error(t, err)
+
+	// Интервалы нескольких географий суммируются
+	rows := []map[string]string{
+		{"B19001_002E": "10", "B19001_017E": "5"},
+		{"B19001_002E": "20", "B19001_017E": "-666666666"},
+	}
+	_, ok := BinsFromRows(table, rows...)
+	assert.False(t, ok)
+}
+
+func TestEstimateQuantiles(t *testing.T) {
+	table := BinnedTables["B19001"]
+	row := map[string]string{"NAME": "Test County", "state": "01", "county": "001", "B19001_001E": "100"}
+	for _, bin := range table.Bins {
+		row[bin.Variable] = "0"
+	}
+	row["B19001_010E"] = "50" // 45 000 - 50 000
+	row["B19001_011E"] = "50" // 50 000 - 60 000
+
+	api := &aggregateStubAPI{MockCensusAPI: NewMockCensusAPI(), rows: []map[string]string{row}}
+
+	result, err := EstimateQuantiles(api, QuantileRequest{
+		Table:        "B19001",
+		Dataset:      "acs/acs5",
+		Year:         "2021",
+		GeoLevel:     "county",
+		Quantiles:    []float64{0.25, 0.5},
+		DesignFactor: 1.5,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "01001", result.Rows[0].GeoID)
+	assert.Equal(t, float64(47500), result.Rows[0].Quantiles[0].Value)
+	assert.Equal(t, float64(50000), result.Rows[0].Quantiles[1].Value)
+	assert.True(t, result.Rows[0].Quantiles[1].HasMOE)
+	assert.Contains(t, api.requests[0].Variables, "B19001_017E")
+}
@@ -0,0 +1,165 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_limit = "limit"
+)
+
+// defaultRankLimit - количество географий в рейтинге по умолчанию
+const defaultRankLimit = 10
+
+// RankRequest представляет запрос рейтинга географий по значению переменной
+type RankRequest struct {
+	Variable  string            // Переменная, по которой строится рейтинг
+	Dataset   string            // Набор данных (например, "acs/acs5")
+	Year      string            // Год данных
+	GeoLevel  string            // Географический уровень ранжируемых географий
+	GeoFilter map[string]string // Фильтр родительской географии (например, {"state": "48"})
+	Limit     int               // Количество возвращаемых географий (по умолчанию 10)
+	// Ascending возвращает географии с наименьшими значениями вместо наибольших
+	Ascending bool
+}
+
+// RankedGeography содержит место географии в рейтинге
+type RankedGeography struct {
+	Rank   int     `json:"rank"`
+	GeoID  string  `json:"geoid"`
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"`
+	MOE    float64 `json:"moe"`
+	HasMOE bool    `json:"has_moe"`
+	// Percentile - доля остальных географий с меньшим значением, в процентах
+	Percentile float64 `json:"percentile"`
+}
+
+// RankResult содержит рейтинг географий по значению переменной
+type RankResult struct {
+	Variable  string            `json:"variable"`
+	Dataset   string            `json:"dataset"`
+	Year      string            `json:"year"`
+	GeoLevel  string            `json:"geo_level"`
+	Ascending bool              `json:"ascending"`
+	Ranked    int               `json:"ranked"`
+	Rows      []RankedGeography `json:"rows"`
+	// Suppressed - географии с подавленным или отсутствующим значением, не вошедшие в рейтинг
+	Suppressed []string `json:"suppressed,omitempty"`
+}
+
+// RankGeographies запрашивает переменную для всех географий уровня, сортирует их по числовому
+// значению и возвращает первые или последние Limit географий с местом и процентилем.
+// Одинаковые значения получают одинаковое место (1, 2, 2, 4).
+func RankGeographies(api CensusAPIClient, request RankRequest) (*RankResult, error) {
+	slog.Info("Построение рейтинга географий",
+		key_variable, request.Variable,
+		key_geo_level, request.GeoLevel,
+		key_limit, request.Limit)
+
+	if request.Variable == "" || request.Variable == "NAME" {
+		return nil, i18n.Errorf("необходимо указать числовую переменную для рейтинга")
+	}
+
+	limit := request.Limit
+	if limit <= 0 {
+		limit = defaultRankLimit
+	}
+
+	variables := []string{"NAME", request.Variable}
+	moeVariable, hasMOE := MOEVariable(request.Variable)
+	hasMOE = hasMOE && strings.HasPrefix(request.Dataset, "acs/")
+	if hasMOE {
+		variables = append(variables, moeVariable)
+	}
+
+	// Ранжируются все географии уровня внутри родительской географии
+	geoFilter := WildcardGeoFilter(request.GeoLevel, request.GeoFilter)
+
+	data, err := api.GetCustomData(CustomDataRequest{
+		Variables: variables,
+		Dataset:   request.Dataset,
+		Year:      request.Year,
+		GeoLevel:  request.GeoLevel,
+		GeoFilter: geoFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RankResult{
+		Variable:  request.Variable,
+		Dataset:   request.Dataset,
+		Year:      request.Year,
+		GeoLevel:  request.GeoLevel,
+		Ascending: request.Ascending,
+	}
+
+	var ranked []RankedGeography
+	for _, row := range data {
+		estimate, ok := EstimateFromRow(row, request.Variable)
+		if !ok {
+			result.Suppressed = append(result.Suppressed, row["NAME"])
+			continue
+		}
+		ranked = append(ranked, RankedGeography{
+			GeoID:  GeoID(row),
+			Name:   row["NAME"],
+			Value:  estimate.Value,
+			MOE:    estimate.MOE,
+			HasMOE: hasMOE && estimate.HasMOE,
+		})
+	}
+	sort.Strings(result.Suppressed)
+
+	if len(ranked) == 0 {
+		return nil, i18n.Errorf("нет географий с числовым значением переменной %s", request.Variable)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Value != ranked[j].Value {
+			if request.Ascending {
+				return ranked[i].Value < ranked[j].Value
+			}
+			return ranked[i].Value > ranked[j].Value
+		}
+		return ranked[i].GeoID < ranked[j].GeoID
+	})
+
+	// Количество меньших и больших значений для каждой географии находим бинарным поиском
+	// по отсортированному по возрастанию списку значений
+	values := make([]float64, len(ranked))
+	for i, item := range ranked {
+		values[i] = item.Value
+	}
+	sort.Float64s(values)
+
+	for i := range ranked {
+		below := sort.SearchFloat64s(values, ranked[i].Value)
+		above := len(values) - sort.Search(len(values), func(k int) bool { return values[k] > ranked[i].Value })
+
+		if request.Ascending {
+			ranked[i].Rank = below + 1
+		} else {
+			ranked[i].Rank = above + 1
+		}
+
+		if len(values) > 1 {
+			ranked[i].Percentile = float64(below) / float64(len(values)-1) * 100
+		} else {
+			ranked[i].Percentile = 100
+		}
+	}
+
+	result.Ranked = len(ranked)
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	result.Rows = ranked
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankGeographies(t *testing.T) {
+	api := &aggregateStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		rows: []map[string]string{
+			{"NAME": "Travis County", "B19013_001E": "85043", "B19013_001M": "900", "state": "48", "county": "453"},
+			{"NAME": "Loving County", "B19013_001E": "-666666666", "B19013_001M": "-222222222", "state": "48", "county": "301"},
+			{"NAME": "Collin County", "B19013_001E": "104327", "B19013_001M": "1100", "state": "48", "county": "085"},
+			{"NAME": "Starr County", "B19013_001E": "35979", "B19013_001M": "2500", "state": "48", "county": "427"},
+			{"NAME": "Fort Bend County", "B19013_001E": "104327", "B19013_001M": "1200", "state": "48", "county": "157"},
+		},
+	}
+
+	result, err := RankGeographies(api, RankRequest{
+		Variable:  "B19013_001E",
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+		GeoLevel:  "county",
+		GeoFilter: map[string]string{"state": "48"},
+		Limit:     3,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"state": "48", "county": "*"}, api.requests[0].GeoFilter)
+	assert.Equal(t, []string{"NAME", "B19013_001E", "B19013_001M"}, api.requests[0].Variables)
+	assert.Equal(t, 4, result.Ranked)
+	assert.Equal(t, []string{"Loving County"}, result.Suppressed)
+	assert.Len(t, result.Rows, 3)
+
+	// Одинаковые значения делят место, порядок внутри них - по GEOID
+	assert.Equal(t, "48085", result.Rows[0].GeoID)
+	assert.Equal(t, 1, result.Rows[0].Rank)
+	assert.Equal(t, 1, result.Rows[1].Rank)
+	assert.Equal(t, 3, result.Rows[2].Rank)
+	assert.Equal(t, float64(1100), result.Rows[0].MOE)
+	assert.InDelta(t, 66.67, result.Rows[0].Percentile, 0.01)
+
+	result, err = RankGeographies(api, RankRequest{
+		Variable:  "B19013_001E",
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+		GeoLevel:  "county",
+		Ascending: true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Starr County", result.Rows[0].Name)
+	assert.Equal(t, 1, result.Rows[0].Rank)
+	assert.Equal(t, float64(0), result.Rows[0].Percentile)
+
+	_, err = RankGeographies(api, RankRequest{Variable: "NAME", GeoLevel: "county"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Константы для ключей логирования
+const (
+	key_format = "format"
+)
+
+// Имена встроенных форматов результатов
+const (
+	FormatMarkdown = "markdown" // Markdown-таблицы (TextFormatter)
+	FormatPlain    = "plain"    // текст без разметки Markdown (PlainFormatter)
+	FormatJSON     = "json"     // JSON со схемой JSONDocument (JSONFormatter)
+	FormatCSV      = "csv"      // значения, разделенные запятыми (DelimitedFormatter)
+	FormatTSV      = "tsv"      // значения, разделенные табуляцией (DelimitedFormatter)
+	FormatGeoJSON  = "geojson"  // FeatureCollection GeoJSON с границами географий (GeoJSONFormatter)
+)
+
+// FormatterRegistry сопоставляет имена форматов с форматтерами. Обработчики инструментов
+// выбирают форматтер для каждого запроса; при пустом имени используется форматтер по умолчанию.
+type FormatterRegistry struct {
+	mu               sync.RWMutex
+	formatters       map[string]Formatter
+	defaultFormatter Formatter
+}
+
+// NewFormatterRegistry создает реестр со встроенными форматами. defaultFormatter используется
+// для запросов без формата; если он равен nil, используется Markdown.
+func NewFormatterRegistry(defaultFormatter Formatter) *FormatterRegistry {
+	slog.DebugContext(context.Background(), "Создание реестра форматтеров")
+
+	registry := &FormatterRegistry{
+		formatters: make(map[string]Formatter),
+	}
+	registry.Register(FormatMarkdown, NewTextFormatter())
+	registry.Register(FormatPlain, NewPlainFormatter())
+	registry.Register(FormatJSON, NewJSONFormatter())
+	registry.Register(FormatCSV, NewCSVFormatter())
+	registry.Register(FormatTSV, NewTSVFormatter())
+	// Без каталога границ объекты GeoJSON выводятся без геометрии
+	registry.Register(FormatGeoJSON, NewGeoJSONFormatter(nil))
+
+	if defaultFormatter == nil {
+		defaultFormatter = registry.formatters[FormatMarkdown]
+	}
+	registry.defaultFormatter = defaultFormatter
+	return registry
+}
+
+// Register добавляет форматтер или заменяет зарегистрированный под тем же именем
+func (r *FormatterRegistry) Register(name string, formatter Formatter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.formatters[normalizeFormatName(name)] = formatter
+}
+
+// Lookup возвращает форматтер по имени формата без учета регистра. Для пустого имени
+// возвращается форматтер по умолчанию.
+func (r *FormatterRegistry) Lookup(name string) (Formatter, error) {
+	name = normalizeFormatName(name)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name == "" {
+		return r.defaultFormatter, nil
+	}
+
+	formatter, ok := r.formatters[name]
+	if !ok {
+		slog.Warn("Запрошен неизвестный формат результатов",
+			key_format, name)
+		return nil, i18n.Errorf("неизвестный формат %q; доступны: %s", name, strings.Join(r.namesLocked(), ", "))
+	}
+	return formatter, nil
+}
+
+// Names возвращает отсортированный список зарегистрированных форматов
+func (r *FormatterRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.namesLocked()
+}
+
+// namesLocked возвращает имена форматов; вызывающий код должен удерживать блокировку
+func (r *FormatterRegistry) namesLocked() []string {
+	names := make([]string, 0, len(r.formatters))
+	for name := range r.formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsStructuredFormatter сообщает, выдает ли форматтер машиночитаемый результат (JSON, CSV, TSV,
+// GeoJSON), к которому нельзя дописывать текстовые пояснения
+func IsStructuredFormatter(formatter Formatter) bool {
+	switch formatter.(type) {
+	case *JSONFormatter, *DelimitedFormatter, *GeoJSONFormatter:
+		return true
+	}
+	return false
+}
+
+// normalizeFormatName приводит имя формата к нижнему регистру без пробелов по краям
+func normalizeFormatName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
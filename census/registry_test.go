@@ -0,0 +1,45 @@
+package census
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatterRegistry_Lookup(t *testing.T) {
+	registry := NewFormatterRegistry(nil)
+
+	assert.Equal(t, []string{FormatCSV, FormatGeoJSON, FormatJSON, FormatMarkdown, FormatPlain, FormatTSV}, registry.Names())
+
+	formatter, err := registry.Lookup("")
+	require.NoError(t, err)
+	assert.IsType(t, &TextFormatter{}, formatter)
+
+	formatter, err = registry.Lookup(" JSON ")
+	require.NoError(t, err)
+	assert.IsType(t, &JSONFormatter{}, formatter)
+	assert.True(t, IsStructuredFormatter(formatter))
+
+	formatter, err = registry.Lookup(FormatPlain)
+	require.NoError(t, err)
+	assert.False(t, IsStructuredFormatter(formatter))
+
+	_, err = registry.Lookup("xml")
+	assert.ErrorContains(t, err, "доступны: csv, geojson, json, markdown, plain, tsv")
+}
+
+func TestFormatterRegistry_DefaultAndRegister(t *testing.T) {
+	registry := NewFormatterRegistry(NewCSVFormatter())
+
+	formatter, err := registry.Lookup("")
+	require.NoError(t, err)
+	assert.IsType(t, &DelimitedFormatter{}, formatter)
+
+	registry.Register("tsv-labels", NewTSVFormatter().WithLabelRow(nil))
+	formatter, err = registry.Lookup("TSV-Labels")
+	require.NoError(t, err)
+	assert.Contains(t, formatter.Format(context.Background(), []NAICSIndustry{{Code: "72", Title: "Accommodation and Food Services"}}),
+		"code\ttitle\tlevel\nКод NAICS\t")
+}
@@ -0,0 +1,253 @@
+package census
+
+import (
+	"bytes"
+	"census_mcp/i18n"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Типы фигур shapefile (ESRI Shapefile Technical Description). Файлы границ TIGER/Line
+// и картографических границ содержат полигоны; координаты Z и M не используются.
+const (
+	shapeNull     = 0
+	shapePolygon  = 5
+	shapePolygonZ = 15
+	shapePolygonM = 25
+)
+
+// Заголовок файла .shp: код файла 9994 и 100 байт заголовка до первой записи
+const (
+	shpFileCode   = 9994
+	shpHeaderSize = 100
+)
+
+// Размер заголовка полигона: тип фигуры, ограничивающий прямоугольник, число частей и точек
+const shpPolygonHeaderSize = 44
+
+// readShapes читает фигуры файла .shp и возвращает их геометрии GeoJSON в порядке записей.
+// Для пустых фигур возвращается nil.
+func readShapes(data []byte) ([]json.RawMessage, error) {
+	if len(data) < shpHeaderSize || binary.BigEndian.Uint32(data[0:4]) != shpFileCode {
+		return nil, i18n.Errorf("файл не является shapefile")
+	}
+
+	var shapes []json.RawMessage
+	offset := shpHeaderSize
+	for offset+8 <= len(data) {
+		// Длина содержимого записи задается в 16-битных словах
+		length := int(binary.BigEndian.Uint32(data[offset+4:offset+8])) * 2
+		start := offset + 8
+		if length < 4 || length > len(data)-start {
+			return nil, i18n.Errorf("запись %d shapefile повреждена", len(shapes)+1)
+		}
+
+		geometry, err := shapeGeometry(data[start : start+length])
+		if err != nil {
+			return nil, i18n.Errorf("запись %d shapefile: %w", len(shapes)+1, err)
+		}
+		shapes = append(shapes, geometry)
+		offset = start + length
+	}
+	return shapes, nil
+}
+
+// shapeGeometry преобразует запись полигона shapefile в геометрию GeoJSON
+func shapeGeometry(record []byte) (json.RawMessage, error) {
+	switch shapeType := binary.LittleEndian.Uint32(record[0:4]); shapeType {
+	case shapeNull:
+		return nil, nil
+	case shapePolygon, shapePolygonZ, shapePolygonM:
+	default:
+		return nil, i18n.Errorf("неподдерживаемый тип фигуры %d: ожидаются полигоны", shapeType)
+	}
+
+	if len(record) < shpPolygonHeaderSize {
+		return nil, i18n.Errorf("запись полигона повреждена")
+	}
+	numParts := int(binary.LittleEndian.Uint32(record[36:40]))
+	numPoints := int(binary.LittleEndian.Uint32(record[40:44]))
+	pointsStart := shpPolygonHeaderSize + 4*numParts
+	if numParts < 0 || numPoints < 0 || numParts > len(record) || numPoints > len(record) ||
+		pointsStart+16*numPoints > len(record) {
+		return nil, i18n.Errorf("запись полигона повреждена")
+	}
+
+	rings := make([][][2]float64, 0, numParts)
+	for i := 0; i < numParts; i++ {
+		first := int(binary.LittleEndian.Uint32(record[shpPolygonHeaderSize+4*i:]))
+		last := numPoints
+		if i+1 < numParts {
+			last = int(binary.LittleEndian.Uint32(record[shpPolygonHeaderSize+4*(i+1):]))
+		}
+		if first < 0 || first > last || last > numPoints {
+			return nil, i18n.Errorf("запись полигона повреждена")
+		}
+
+		ring := make([][2]float64, last-first)
+		for j := range ring {
+			point := record[pointsStart+16*(first+j):]
+			ring[j] = [2]float64{
+				math.Float64frombits(binary.LittleEndian.Uint64(point[0:8])),
+				math.Float64frombits(binary.LittleEndian.Uint64(point[8:16])),
+			}
+		}
+		rings = append(rings, ring)
+	}
+	return polygonGeometry(rings), nil
+}
+
+// polygonGeometry собирает кольца shapefile в Polygon или MultiPolygon GeoJSON. Внешние кольца
+// shapefile обходятся по часовой стрелке, а дыры - против нее и следуют за своим внешним кольцом.
+// В GeoJSON (RFC 7946) направления обратные, поэтому кольца ориентируются заново.
+func polygonGeometry(rings [][][2]float64) json.RawMessage {
+	var polygons [][][][2]float64
+	for _, ring := range rings {
+		// Замкнутое кольцо содержит не менее четырех точек
+		if len(ring) < 4 {
+			continue
+		}
+		if ringArea(ring) <= 0 || len(polygons) == 0 {
+			polygons = append(polygons, [][][2]float64{orientRing(ring, true)})
+			continue
+		}
+		last := len(polygons) - 1
+		polygons[last] = append(polygons[last], orientRing(ring, false))
+	}
+	if len(polygons) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if len(polygons) == 1 {
+		buf.WriteString(`{"type":"Polygon","coordinates":`)
+		writePolygon(&buf, polygons[0])
+	} else {
+		buf.WriteString(`{"type":"MultiPolygon","coordinates":[`)
+		for i, polygon := range polygons {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writePolygon(&buf, polygon)
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// ringArea возвращает ориентированную площадь кольца: положительную при обходе против
+// часовой стрелки и отрицательную при обходе по часовой
+func ringArea(ring [][2]float64) float64 {
+	area := 0.0
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
+
+// orientRing возвращает кольцо с обходом против часовой стрелки (counterClockwise) или по ней
+func orientRing(ring [][2]float64, counterClockwise bool) [][2]float64 {
+	if (ringArea(ring) > 0) == counterClockwise {
+		return ring
+	}
+	reversed := make([][2]float64, len(ring))
+	for i, point := range ring {
+		reversed[len(ring)-1-i] = point
+	}
+	return reversed
+}
+
+// writePolygon записывает координаты колец полигона GeoJSON
+func writePolygon(buf *bytes.Buffer, polygon [][][2]float64) {
+	var number []byte
+	buf.WriteByte('[')
+	for i, ring := range polygon {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('[')
+		for j, point := range ring {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			buf.WriteByte('[')
+			number = strconv.AppendFloat(number[:0], point[0], 'f', -1, 64)
+			buf.Write(number)
+			buf.WriteByte(',')
+			number = strconv.AppendFloat(number[:0], point[1], 'f', -1, 64)
+			buf.Write(number)
+			buf.WriteByte(']')
+		}
+		buf.WriteByte(']')
+	}
+	buf.WriteByte(']')
+}
+
+// Заголовок файла .dbf и описания его полей
+const (
+	dbfHeaderSize     = 32
+	dbfFieldSize      = 32
+	dbfFieldsEnd      = 0x0D
+	dbfDeletedRecord  = '*'
+	dbfFieldNameSize  = 11
+	dbfFieldLengthPos = 16
+)
+
+// readDBF читает записи атрибутов файла .dbf. Удаленные записи возвращаются как nil,
+// чтобы номера записей совпадали с номерами фигур файла .shp.
+func readDBF(data []byte) ([]map[string]string, error) {
+	if len(data) < dbfHeaderSize {
+		return nil, i18n.Errorf("файл атрибутов .dbf поврежден")
+	}
+	count := int(binary.LittleEndian.Uint32(data[4:8]))
+	headerSize := int(binary.LittleEndian.Uint16(data[8:10]))
+	recordSize := int(binary.LittleEndian.Uint16(data[10:12]))
+	if headerSize > len(data) || recordSize == 0 {
+		return nil, i18n.Errorf("файл атрибутов .dbf поврежден")
+	}
+
+	type dbfField struct {
+		name   string
+		offset int
+		length int
+	}
+	var fields []dbfField
+	// Первый байт записи - признак удаления
+	offset := 1
+	for pos := dbfHeaderSize; pos+dbfFieldSize <= headerSize && data[pos] != dbfFieldsEnd; pos += dbfFieldSize {
+		length := int(data[pos+dbfFieldLengthPos])
+		fields = append(fields, dbfField{
+			name:   strings.TrimRight(string(data[pos:pos+dbfFieldNameSize]), "\x00 "),
+			offset: offset,
+			length: length,
+		})
+		offset += length
+	}
+	if offset > recordSize {
+		return nil, i18n.Errorf("файл атрибутов .dbf поврежден")
+	}
+
+	records := make([]map[string]string, 0, count)
+	for i := 0; i < count; i++ {
+		start := headerSize + i*recordSize
+		if start+recordSize > len(data) {
+			return nil, i18n.Errorf("запись %d файла атрибутов .dbf повреждена", i+1)
+		}
+		record := data[start : start+recordSize]
+		if record[0] == dbfDeletedRecord {
+			records = append(records, nil)
+			continue
+		}
+
+		values := make(map[string]string, len(fields))
+		for _, field := range fields {
+			values[field.name] = strings.TrimSpace(string(record[field.offset : field.offset+field.length]))
+		}
+		records = append(records, values)
+	}
+	return records, nil
+}
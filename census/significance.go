@@ -0,0 +1,180 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// EstimateTarget задает одну оценку для сравнения: год и географию
+type EstimateTarget struct {
+	Year      string            // Год данных
+	GeoLevel  string            // Географический уровень
+	GeoFilter map[string]string // Фильтр, выбирающий ровно одну географию
+}
+
+// EstimateComparisonRequest представляет запрос проверки значимости различия двух оценок
+type EstimateComparisonRequest struct {
+	Variable string         // Сравниваемая переменная (оценка ACS с суффиксом E)
+	Dataset  string         // Набор данных (например, "acs/acs5")
+	First    EstimateTarget // Первая оценка
+	Second   EstimateTarget // Вторая оценка
+}
+
+// ComparedEstimate содержит одну из сравниваемых оценок
+type ComparedEstimate struct {
+	Year  string  `json:"year"`
+	GeoID string  `json:"geoid"`
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	MOE   float64 `json:"moe"`
+}
+
+// EstimateComparison содержит результат проверки значимости различия двух оценок
+type EstimateComparison struct {
+	Variable      string           `json:"variable"`
+	Dataset       string           `json:"dataset"`
+	First         ComparedEstimate `json:"first"`
+	Second        ComparedEstimate `json:"second"`
+	Difference    float64          `json:"difference"`
+	DifferenceMOE float64          `json:"difference_moe"`
+	Z             float64          `json:"z"`
+	Significant   bool             `json:"significant"`
+	// Overlap - доля общих лет периодов многолетних оценок, учтенная в стандартной ошибке разности
+	Overlap float64 `json:"overlap"`
+}
+
+// DifferenceSignificance проверяет значимость разности двух оценок ACS на уровне доверия 90% по
+// методике Census Bureau: Z = (E1 − E2) / √(SE1² + SE2²), SE = MOE / 1.645. Для многолетних
+// оценок с пересекающимися периодами стандартная ошибка умножается на √(1 − C), где C - доля
+// общих лет. Возвращает разность с ее MOE, значение Z и признак значимости различия.
+func DifferenceSignificance(first, second Estimate, overlap float64) (Estimate, float64, bool, error) {
+	if !first.HasMOE || !second.HasMOE {
+		return Estimate{}, 0, false, i18n.Errorf("для проверки значимости необходимы MOE обеих оценок")
+	}
+
+	if overlap < 0 || overlap >= 1 {
+		return Estimate{}, 0, false, i18n.Errorf("некорректная доля пересечения периодов: %v", overlap)
+	}
+
+	difference := Estimate{
+		Value:  first.Value - second.Value,
+		MOE:    math.Sqrt(1-overlap) * math.Hypot(first.MOE, second.MOE),
+		HasMOE: true,
+	}
+
+	if difference.MOE == 0 {
+		// Обе оценки не имеют ошибки выборки: любое различие значимо, Z не определено
+		return difference, 0, difference.Value != 0, nil
+	}
+
+	z := difference.Value / (difference.MOE / moeZ90)
+	return difference, z, math.Abs(z) > moeZ90, nil
+}
+
+// PeriodOverlap возвращает долю общих лет периодов двух многолетних оценок набора данных
+// (например, ACS 5-year 2017 и 2021 имеют один общий год из пяти)
+func PeriodOverlap(dataset, firstYear, secondYear string) float64 {
+	length := 1
+	switch {
+	case strings.HasSuffix(dataset, "acs5"):
+		length = 5
+	case strings.HasSuffix(dataset, "acs3"):
+		length = 3
+	}
+
+	first, err1 := strconv.Atoi(firstYear)
+	second, err2 := strconv.Atoi(secondYear)
+	if err1 != nil || err2 != nil || first == second || length == 1 {
+		return 0
+	}
+
+	gap := first - second
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap >= length {
+		return 0
+	}
+
+	return float64(length-gap) / float64(length)
+}
+
+// CompareEstimates запрашивает две оценки переменной с их MOE и проверяет значимость их различия
+func CompareEstimates(api CensusAPIClient, request EstimateComparisonRequest) (*EstimateComparison, error) {
+	slog.Info("Проверка значимости различия оценок",
+		key_variable, request.Variable,
+		key_dataset, request.Dataset)
+
+	moeVariable, ok := MOEVariable(request.Variable)
+	if !ok {
+		return nil, i18n.Errorf("переменная %s не является оценкой ACS с MOE", request.Variable)
+	}
+
+	first, firstEstimate, err := fetchComparedEstimate(api, request.Dataset, request.Variable, moeVariable, request.First)
+	if err != nil {
+		return nil, i18n.Errorf("первая оценка: %w", err)
+	}
+
+	second, secondEstimate, err := fetchComparedEstimate(api, request.Dataset, request.Variable, moeVariable, request.Second)
+	if err != nil {
+		return nil, i18n.Errorf("вторая оценка: %w", err)
+	}
+
+	// Пересечение периодов учитывается только для одной и той же географии в разные годы
+	overlap := 0.0
+	if first.GeoID == second.GeoID {
+		overlap = PeriodOverlap(request.Dataset, first.Year, second.Year)
+	}
+
+	difference, z, significant, err := DifferenceSignificance(firstEstimate, secondEstimate, overlap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EstimateComparison{
+		Variable:      request.Variable,
+		Dataset:       request.Dataset,
+		First:         first,
+		Second:        second,
+		Difference:    difference.Value,
+		DifferenceMOE: difference.MOE,
+		Z:             z,
+		Significant:   significant,
+		Overlap:       overlap,
+	}, nil
+}
+
+// fetchComparedEstimate запрашивает оценку и MOE переменной для одной географии
+func fetchComparedEstimate(api CensusAPIClient, dataset, variable, moeVariable string, target EstimateTarget) (ComparedEstimate, Estimate, error) {
+	data, err := api.GetCustomData(CustomDataRequest{
+		Variables: []string{"NAME", variable, moeVariable},
+		Dataset:   dataset,
+		Year:      target.Year,
+		GeoLevel:  target.GeoLevel,
+		GeoFilter: target.GeoFilter,
+	})
+	if err != nil {
+		return ComparedEstimate{}, Estimate{}, err
+	}
+
+	if len(data) != 1 {
+		return ComparedEstimate{}, Estimate{}, i18n.Errorf("фильтр географии должен выбирать ровно одну географию, получено %d", len(data))
+	}
+
+	row := data[0]
+	estimate, ok := EstimateFromRow(row, variable)
+	if !ok {
+		return ComparedEstimate{}, Estimate{}, i18n.Errorf("оценка %s для %s подавлена или отсутствует", variable, row["NAME"])
+	}
+
+	return ComparedEstimate{
+		Year:  target.Year,
+		GeoID: GeoID(row),
+		Name:  row["NAME"],
+		Value: estimate.Value,
+		MOE:   estimate.MOE,
+	}, estimate, nil
+}
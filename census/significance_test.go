@@ -0,0 +1,90 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDifferenceSignificance(t *testing.T) {
+	// Пример из руководства ACS: 31 000 ± 2 000 и 28 000 ± 1 500
+	difference, z, significant, err := DifferenceSignificance(
+		Estimate{Value: 31000, MOE: 2000, HasMOE: true},
+		Estimate{Value: 28000, MOE: 1500, HasMOE: true},
+		0,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3000), difference.Value)
+	assert.InDelta(t, 2500, difference.MOE, 1e-9)
+	assert.InDelta(t, 3000/(2500/1.645), z, 1e-9)
+	assert.True(t, significant)
+
+	_, _, significant, err = DifferenceSignificance(
+		Estimate{Value: 100, MOE: 80, HasMOE: true},
+		Estimate{Value: 90, MOE: 60, HasMOE: true},
+		0,
+	)
+	assert.NoError(t, err)
+	assert.False(t, significant)
+
+	// Пересечение периодов уменьшает стандартную ошибку разности
+	difference, _, _, err = DifferenceSignificance(
+		Estimate{Value: 100, MOE: 30, HasMOE: true},
+		Estimate{Value: 90, MOE: 40, HasMOE: true},
+		0.75,
+	)
+	assert.NoError(t, err)
+	assert.InDelta(t, 25, difference.MOE, 1e-9)
+
+	// Контролируемые оценки без ошибки выборки
+	_, z, significant, err = DifferenceSignificance(
+		Estimate{Value: 100, HasMOE: true},
+		Estimate{Value: 90, HasMOE: true},
+		0,
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(0), z)
+	assert.True(t, significant)
+
+	_, _, _, err = DifferenceSignificance(Estimate{Value: 1}, Estimate{Value: 2, HasMOE: true}, 0)
+	assert.Error(t, err)
+}
+
+func TestPeriodOverlap(t *testing.T) {
+	assert.Equal(t, 0.2, PeriodOverlap("acs/acs5", "2017", "2021"))
+	assert.Equal(t, 0.6, PeriodOverlap("acs/acs5", "2021", "2019"))
+	assert.Equal(t, float64(0), PeriodOverlap("acs/acs5", "2011", "2021"))
+	assert.Equal(t, float64(0), PeriodOverlap("acs/acs1", "2019", "2021"))
+}
+
+func TestCompareEstimates(t *testing.T) {
+	api := &yearStubAPI{
+		MockCensusAPI: NewMockCensusAPI(),
+		data: map[string][]map[string]string{
+			"2019": {{"NAME": "Travis County, Texas", "B19013_001E": "80668", "B19013_001M": "1077", "state": "48", "county": "453"}},
+			"2021": {{"NAME": "Travis County, Texas", "B19013_001E": "92731", "B19013_001M": "1248", "state": "48", "county": "453"}},
+		},
+	}
+
+	target := EstimateTarget{Year: "2019", GeoLevel: "county", GeoFilter: map[string]string{"state": "48", "county": "453"}}
+	second := target
+	second.Year = "2021"
+
+	comparison, err := CompareEstimates(api, EstimateComparisonRequest{
+		Variable: "B19013_001E",
+		Dataset:  "acs/acs5",
+		First:    target,
+		Second:   second,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(80668-92731), comparison.Difference)
+	assert.Equal(t, 0.6, comparison.Overlap)
+	assert.True(t, comparison.Significant)
+
+	_, err = CompareEstimates(api, EstimateComparisonRequest{Variable: "NAME", First: target, Second: second})
+	assert.Error(t, err)
+
+	second.Year = "2015"
+	_, err = CompareEstimates(api, EstimateComparisonRequest{Variable: "B19013_001E", Dataset: "acs/acs5", First: target, Second: second})
+	assert.Error(t, err)
+}
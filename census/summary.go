@@ -0,0 +1,206 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ColumnSummary - сводка числового столбца ответа Census API: число значений, наименьшее,
+// наибольшее значение и медиана. Пустые и служебные значения Census API не учитываются.
+type ColumnSummary struct {
+	Column string  `json:"column"`
+	Count  int     `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Median float64 `json:"median"`
+}
+
+// SummarizeRows возвращает сводки числовых столбцов строк ответа Census API в порядке
+// столбцов ответа (см. ColumnLayout). Географические и текстовые столбцы пропускаются.
+func SummarizeRows(ctx context.Context, data []map[string]string) []ColumnSummary {
+	var summaries []ColumnSummary
+	for _, column := range responseColumns(ctx, data) {
+		if customColumnType(ctx, column) != JSONColumnNumber {
+			continue
+		}
+
+		values := make([]float64, 0, len(data))
+		for _, row := range data {
+			if value, ok := ParseEstimate(row[column]); ok {
+				values = append(values, value)
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		sort.Float64s(values)
+
+		median := values[len(values)/2]
+		if len(values)%2 == 0 {
+			median = (values[len(values)/2-1] + values[len(values)/2]) / 2
+		}
+
+		summaries = append(summaries, ColumnSummary{
+			Column: column,
+			Count:  len(values),
+			Min:    values[0],
+			Max:    values[len(values)-1],
+			Median: median,
+		})
+	}
+	return summaries
+}
+
+// FormatRowSummary оформляет сводки столбцов для пояснения к усеченному результату.
+// total - число строк, по которым рассчитаны сводки.
+func FormatRowSummary(ctx context.Context, total int, summaries []ColumnSummary) string {
+	if len(summaries) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.Sprintf(ctx, "**Сводка по всем строкам (%d)**:\n", total))
+	for _, summary := range summaries {
+		kind, ok := dataColumnKind(ctx, summary.Column)
+		if !ok {
+			kind = ValueKindNumber
+		}
+		sb.WriteString(i18n.Sprintf(ctx, "- %s: мин. %s, макс. %s, медиана %s (значений: %d)\n",
+			columnLabel(ctx, summary.Column),
+			FormatValue(ctx, summary.Min, kind),
+			FormatValue(ctx, summary.Max, kind),
+			FormatValue(ctx, summary.Median, kind),
+			summary.Count))
+	}
+	return sb.String()
+}
+
+// FormatResultSummary оформляет сводки числовых столбцов табличного результата: строк ответа
+// Census API, данных о населении, оценок PEP, статистики предприятий, рейтинга, сравнения
+// выпусков (по значениям последнего года), слагаемых агрегации и миграционных потоков.
+// Для прочих данных возвращается пустая строка.
+func FormatResultSummary(ctx context.Context, data interface{}) string {
+	var rows []map[string]string
+	var columns []PEPColumn
+	switch v := data.(type) {
+	case []map[string]string:
+		rows = v
+	case []PopulationData:
+		rows = make([]map[string]string, len(v))
+		for i, item := range v {
+			rows[i] = map[string]string{"NAME": item.Name, "B01001_001E": item.Population}
+		}
+	case *PEPResult:
+		if v == nil {
+			return ""
+		}
+		// Столбцы характеристик содержат подписи кодов и в сводку не попадают
+		for _, column := range v.Columns {
+			if _, ok := pepCharacteristicLabels[column.Key]; !ok {
+				columns = append(columns, column)
+			}
+		}
+		rows = make([]map[string]string, len(v.Rows))
+		for i, row := range v.Rows {
+			rows[i] = make(map[string]string, len(columns))
+			for _, column := range columns {
+				rows[i][column.Key] = row.Values[column.Key]
+			}
+		}
+	case *BusinessResult:
+		if v == nil {
+			return ""
+		}
+		columns = []PEPColumn{
+			{Key: "establishments", Label: "Предприятия"},
+			{Key: "employment", Label: "Занятые"},
+			{Key: "payroll", Label: "Фонд оплаты труда, тыс. $"},
+		}
+		if v.Dataset == DatasetEconomicCensus {
+			columns = append(columns, PEPColumn{Key: "receipts", Label: "Выручка, тыс. $"})
+		}
+		rows = make([]map[string]string, len(v.Rows))
+		for i, row := range v.Rows {
+			rows[i] = map[string]string{
+				"establishments": row.Establishments,
+				"employment":     row.Employment,
+				"payroll":        row.Payroll,
+				"receipts":       row.Receipts,
+			}
+		}
+	case *RankResult:
+		if v == nil {
+			return ""
+		}
+		rows = make([]map[string]string, len(v.Rows))
+		for i, row := range v.Rows {
+			rows[i] = map[string]string{v.Variable: strconv.FormatFloat(row.Value, 'f', -1, 64)}
+		}
+	case *YearComparison:
+		if v == nil || len(v.Years) == 0 {
+			return ""
+		}
+		last := v.Years[len(v.Years)-1]
+		rows = make([]map[string]string, len(v.Rows))
+		for i, row := range v.Rows {
+			rows[i] = make(map[string]string, len(v.Variables))
+			for _, variable := range v.Variables {
+				rows[i][variable] = row.Values[variable][last]
+			}
+		}
+	case *AggregationResult:
+		if v == nil {
+			return ""
+		}
+		rows = v.Components
+	case *MigrationFlowResult:
+		if v == nil {
+			return ""
+		}
+		columns = []PEPColumn{
+			{Key: "moved_in", Label: "Приток"},
+			{Key: "moved_out", Label: "Отток"},
+			{Key: "net", Label: "Чистая миграция"},
+		}
+		// Потоки без опубликованного значения в сводку не попадают
+		value := func(value float64, ok bool) string {
+			if !ok {
+				return ""
+			}
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		for _, flows := range [][]MigrationCounterpart{v.Inflows, v.Outflows} {
+			for _, flow := range flows {
+				rows = append(rows, map[string]string{
+					"moved_in":  value(flow.MovedIn, flow.HasIn),
+					"moved_out": value(flow.MovedOut, flow.HasOut),
+					"net":       value(flow.Net, flow.HasNet),
+				})
+			}
+		}
+	default:
+		return ""
+	}
+
+	// Показатели PEP, статистики предприятий и миграции - численности, их столбцы подписываются по-русски
+	if len(columns) > 0 {
+		layout := ColumnLayout{Labels: make(map[string]string, len(columns))}
+		numbers := NumberFormatFromContext(ctx)
+		kinds := make(map[string]ValueKind, len(numbers.Kinds)+len(columns))
+		for name, kind := range numbers.Kinds {
+			kinds[name] = kind
+		}
+		for _, column := range columns {
+			layout.Order = append(layout.Order, column.Key)
+			layout.Labels[column.Key] = i18n.T(ctx, column.Label)
+			kinds[column.Key] = ValueKindCount
+		}
+		numbers.Kinds = kinds
+		ctx = WithNumberFormat(WithColumnLayout(ctx, layout), numbers)
+	}
+
+	return FormatRowSummary(ctx, len(rows), SummarizeRows(ctx, rows))
+}
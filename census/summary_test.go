@@ -0,0 +1,44 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeRows(t *testing.T) {
+	data := []map[string]string{
+		{"NAME": "Autauga County, Alabama", "B01001_001E": "58239", "B19013_001E": "62660", "state": "01", "county": "001"},
+		{"NAME": "Baldwin County, Alabama", "B01001_001E": "227131", "B19013_001E": "-666666666", "state": "01", "county": "003"},
+		{"NAME": "Barbour County, Alabama", "B01001_001E": "25259", "B19013_001E": "34186", "state": "01", "county": "005"},
+		{"NAME": "Bibb County, Alabama", "B01001_001E": "22412", "B19013_001E": "51725", "state": "01", "county": "007"},
+	}
+
+	summaries := SummarizeRows(context.Background(), data)
+
+	assert.Equal(t, []ColumnSummary{
+		{Column: "B01001_001E", Count: 4, Min: 22412, Max: 227131, Median: 41749},
+		{Column: "B19013_001E", Count: 3, Min: 34186, Max: 62660, Median: 51725},
+	}, summaries)
+}
+
+func TestFormatRowSummary(t *testing.T) {
+	ctx := i18n.WithLocale(context.Background(), i18n.English)
+	ctx = WithNumberFormat(ctx, NumberFormat{
+		Precision: 0,
+		Kinds:     map[string]ValueKind{"B19013_001E": ValueKindMoney},
+	})
+	ctx = WithColumnLayout(ctx, ColumnLayout{Labels: map[string]string{"B19013_001E": "Median household income"}})
+
+	result := FormatRowSummary(ctx, 4, []ColumnSummary{
+		{Column: "B01001_001E", Count: 4, Min: 22412, Max: 227131, Median: 41749},
+		{Column: "B19013_001E", Count: 3, Min: 34186, Max: 62660, Median: 51725},
+	})
+
+	assert.Equal(t, "**Summary of all rows (4)**:\n"+
+		"- B01001_001E: min 22,412, max 227,131, median 41,749 (values: 4)\n"+
+		"- Median household income: min $34,186, max $62,660, median $51,725 (values: 3)\n", result)
+	assert.Empty(t, FormatRowSummary(ctx, 4, nil))
+}
@@ -0,0 +1,40 @@
+package census
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// annotationValues - служебные отрицательные значения Census API, обозначающие
+// отсутствие или подавление оценки (например, -666666666 - недостаточно наблюдений)
+var annotationValues = map[string]bool{
+	"-111111111": true,
+	"-222222222": true,
+	"-333333333": true,
+	"-555555555": true,
+	"-666666666": true,
+	"-888888888": true,
+	"-999999999": true,
+}
+
+// ParseEstimate преобразует значение из ответа Census API в число.
+// Возвращает false для пустых, нечисловых и подавленных значений.
+func ParseEstimate(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" || annotationValues[value] {
+		return 0, false
+	}
+
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// FormatNumber форматирует число без лишних нулей с точностью до двух знаков после запятой
+func FormatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,117 @@
+package census
+
+import (
+	"census_mcp/i18n"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_zctas = "zctas"
+	key_state = "state"
+)
+
+// GeoLevelZCTA - название географического уровня ZCTA в Census API
+const GeoLevelZCTA = "zip code tabulation area"
+
+// ZCTANotice - пояснение о том, что ZCTA не совпадают с почтовыми индексами USPS
+const ZCTANotice = "> **Примечание**: ZCTA (ZIP Code Tabulation Area) — это статистическое приближение " +
+	"почтовых индексов USPS, построенное Census Bureau из блоков переписи. Границы ZCTA не совпадают " +
+	"с зонами доставки USPS, а часть почтовых индексов (абонентские ящики, отдельные организации) " +
+	"не имеет соответствующей ZCTA."
+
+// ZCTARequest представляет запрос данных по ZCTA
+type ZCTARequest struct {
+	ZCTAs     []string // Список кодов ZCTA (пятизначные, "*" для всех)
+	State     string   // Код штата для выпусков, где ZCTA вложены в штаты (необязательно)
+	Variables []string // Список переменных для запроса
+	Dataset   string   // Набор данных (например, "acs/acs5")
+	Year      string   // Год данных
+}
+
+// ZCTANestedInState сообщает, поддерживает ли выпуск данных вложение ZCTA в штат.
+// Вложение доступно в 5-летних оценках ACS (acs/acs5) по выпуск 2018 года включительно
+// и в наборах переписи (dec/*) по перепись 2010 года включительно: в их иерархии географий
+// Census API есть уровень "zip code tabulation area" внутри "state" (части ZCTA, пересекающих
+// границу штата). С выпуска ACS 2019 года и переписи 2020 года ZCTA публикуются только
+// на уровне страны. В 1-летних оценках ACS данных по ZCTA нет.
+func ZCTANestedInState(dataset, year string) bool {
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return false
+	}
+
+	if strings.HasPrefix(dataset, "dec/") {
+		return y <= 2010
+	}
+
+	return dataset == "acs/acs5" && y <= 2018
+}
+
+// ValidateZCTA проверяет, что код ZCTA состоит из пяти цифр или является "*"
+func ValidateZCTA(zcta string) error {
+	if zcta == "*" {
+		return nil
+	}
+
+	if len(zcta) != 5 {
+		return i18n.Errorf("код ZCTA должен состоять из 5 цифр: %q", zcta)
+	}
+
+	for _, ch := range zcta {
+		if ch < '0' || ch > '9' {
+			return i18n.Errorf("код ZCTA должен состоять из 5 цифр: %q", zcta)
+		}
+	}
+
+	return nil
+}
+
+// BuildZCTADataRequest преобразует запрос по ZCTA в пользовательский запрос Census API
+func BuildZCTADataRequest(request ZCTARequest) (CustomDataRequest, error) {
+	if len(request.ZCTAs) == 0 {
+		return CustomDataRequest{}, i18n.Errorf("необходимо указать хотя бы один код ZCTA")
+	}
+
+	for _, zcta := range request.ZCTAs {
+		if err := ValidateZCTA(zcta); err != nil {
+			return CustomDataRequest{}, err
+		}
+	}
+
+	geoFilter := map[string]string{
+		GeoLevelZCTA: strings.Join(request.ZCTAs, ","),
+	}
+
+	if request.State != "" {
+		if !ZCTANestedInState(request.Dataset, request.Year) {
+			return CustomDataRequest{}, i18n.Errorf(
+				"в выпуске %s %s ZCTA не вложены в штаты, уберите фильтр по штату", request.Dataset, request.Year)
+		}
+		geoFilter["state"] = request.State
+	}
+
+	return CustomDataRequest{
+		Variables: request.Variables,
+		Dataset:   request.Dataset,
+		Year:      request.Year,
+		GeoLevel:  GeoLevelZCTA,
+		GeoFilter: geoFilter,
+	}, nil
+}
+
+// GetZCTAData возвращает данные по ZCTA (приближению почтовых индексов)
+func (c *CensusAPI) GetZCTAData(request ZCTARequest) ([]map[string]string, error) {
+	slog.Info("Получение данных по ZCTA",
+		key_zctas, request.ZCTAs,
+		key_state, request.State)
+
+	customRequest, err := BuildZCTADataRequest(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.GetCustomData(customRequest)
+}
@@ -0,0 +1,82 @@
+package census
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZCTANestedInState(t *testing.T) {
+	tests := []struct {
+		dataset  string
+		year     string
+		expected bool
+	}{
+		{"acs/acs5", "2015", true},
+		{"acs/acs5", "2018", true},
+		{"acs/acs5", "2019", false},
+		{"acs/acs5", "2021", false},
+		{"dec/sf1", "2010", true},
+		{"dec/dhc", "2020", false},
+		{"acs/acs1", "2015", false},
+		{"acs/acs5", "не год", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, ZCTANestedInState(tt.dataset, tt.year), "%s %s", tt.dataset, tt.year)
+	}
+}
+
+func TestValidateZCTA(t *testing.T) {
+	assert.NoError(t, ValidateZCTA("94110"))
+	assert.NoError(t, ValidateZCTA("*"))
+	assert.Error(t, ValidateZCTA("9411"))
+	assert.Error(t, ValidateZCTA("94110-1234"))
+	assert.Error(t, ValidateZCTA("9411a"))
+}
+
+func TestBuildZCTADataRequest(t *testing.T) {
+	// Современный выпуск: ZCTA запрашиваются на уровне страны
+	request, err := BuildZCTADataRequest(ZCTARequest{
+		ZCTAs:     []string{"94110", "94103"},
+		Variables: []string{"NAME", "B01001_001E"},
+		Dataset:   "acs/acs5",
+		Year:      "2021",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, GeoLevelZCTA, request.GeoLevel)
+	assert.Equal(t, map[string]string{GeoLevelZCTA: "94110,94103"}, request.GeoFilter)
+
+	// Старый выпуск: ZCTA вложены в штат
+	request, err = BuildZCTADataRequest(ZCTARequest{
+		ZCTAs:     []string{"*"},
+		State:     "06",
+		Variables: []string{"NAME"},
+		Dataset:   "acs/acs5",
+		Year:      "2015",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{GeoLevelZCTA: "*", "state": "06"}, request.GeoFilter)
+
+	// Фильтр по штату недоступен в новых выпусках
+	_, err = BuildZCTADataRequest(ZCTARequest{
+		ZCTAs:   []string{"94110"},
+		State:   "06",
+		Dataset: "acs/acs5",
+		Year:    "2021",
+	})
+	assert.Error(t, err)
+
+	// Без кодов ZCTA запрос невозможен
+	_, err = BuildZCTADataRequest(ZCTARequest{Dataset: "acs/acs5", Year: "2021"})
+	assert.Error(t, err)
+}
+
+func TestMockCensusAPI_GetZCTAData(t *testing.T) {
+	mockAPI := NewMockCensusAPI()
+
+	data, err := mockAPI.GetZCTAData(ZCTARequest{ZCTAs: []string{"94110"}, Dataset: "acs/acs5", Year: "2021"})
+	assert.NoError(t, err)
+	assert.Len(t, data, 1)
+	assert.Equal(t, "94110", data[0][GeoLevelZCTA])
+}
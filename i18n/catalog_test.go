@@ -0,0 +1,188 @@
+package i18n
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// translatedPackages - пакеты, сообщения которых должны быть переведены на английский
+var translatedPackages = []string{"../census", "../mcp"}
+
+// translatedFunctions - функции пакета i18n и номер их аргумента, являющегося ключом каталога
+var translatedFunctions = map[string]int{"T": 1, "Sprintf": 1, "Translate": 1, "Errorf": 0}
+
+// formatFunctions - функции пакета i18n, ключ каталога которых является строкой формата
+var formatFunctions = map[string]bool{"Sprintf": true, "Errorf": true}
+
+func TestEnglishCatalog_CoversSourceMessages(t *testing.T) {
+	messages := sourceMessages(t)
+	require.NotEmpty(t, messages)
+
+	var missing []string
+	for message := range messages {
+		if _, ok := english[message]; !ok {
+			missing = append(missing, message)
+		}
+	}
+	sort.Strings(missing)
+	assert.Empty(t, missing, "сообщения без английского перевода")
+}
+
+func TestEnglishCatalog_KeepsFormatVerbs(t *testing.T) {
+	for message, isFormat := range sourceMessages(t) {
+		translated, ok := english[message]
+		if !isFormat || !ok {
+			continue
+		}
+		assert.Equal(t, formatVerbs(message), formatVerbs(translated), "спецификаторы формата в переводе %q", message)
+	}
+}
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Locale
+	}{
+		{"", Russian},
+		{"ru", Russian},
+		{"EN", English},
+		{" en-US ", English},
+		{"ru_RU", Russian},
+	}
+	for _, tt := range tests {
+		locale, err := ParseLocale(tt.value)
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.expected, locale, tt.value)
+	}
+
+	_, err := ParseLocale("de")
+	assert.Error(t, err)
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "No data", Translate(English, "Нет данных"))
+	assert.Equal(t, "Нет данных", Translate(Russian, "Нет данных"))
+	// Сообщение без перевода возвращается без изменений
+	assert.Equal(t, "Census Bureau", Translate(English, "Census Bureau"))
+}
+
+func TestContextLocale(t *testing.T) {
+	ctx := t.Context()
+	assert.Equal(t, DefaultLocale, FromContext(ctx))
+
+	ctx = WithLocale(ctx, English)
+	assert.Equal(t, English, FromContext(ctx))
+	assert.Equal(t, "No data", T(ctx, "Нет данных"))
+	assert.Equal(t, "Austin (state 48)", Sprintf(ctx, "%s (штат %s)", "Austin", "48"))
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := Errorf("ошибка при отправке запроса: %w", Errorf("API вернул статус %d", 500))
+	assert.Equal(t, "ошибка при отправке запроса: API вернул статус 500", err.Error())
+	assert.Equal(t, "ошибка при отправке запроса: API вернул статус 500", ErrorMessage(t.Context(), err))
+
+	ctx := WithLocale(t.Context(), English)
+	assert.Equal(t, "error sending request: API returned status 500", ErrorMessage(ctx, err))
+
+	// Вложенная ошибка доступна errors.Is, текст прочих ошибок не переводится
+	assert.ErrorIs(t, Errorf("перепись %s: %w", "2020", context.Canceled), context.Canceled)
+	assert.Equal(t, "context canceled", ErrorMessage(ctx, context.Canceled))
+}
+
+// sourceMessages собирает строковые литералы, переданные функциям перевода в пакетах
+// translatedPackages; значение сообщает, используется ли литерал как строка формата
+func sourceMessages(t *testing.T) map[string]bool {
+	t.Helper()
+
+	messages := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, dir := range translatedPackages {
+		files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		require.NoError(t, err)
+
+		for _, path := range files {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			require.NoError(t, err)
+
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				selector, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				index, ok := translatedFunctions[selector.Sel.Name]
+				if !ok || len(call.Args) <= index {
+					return true
+				}
+				if pkg, ok := selector.X.(*ast.Ident); !ok || pkg.Name != "i18n" {
+					return true
+				}
+				if message, ok := stringLiteral(call.Args[index]); ok {
+					messages[message] = messages[message] || formatFunctions[selector.Sel.Name]
+				}
+				return true
+			})
+		}
+	}
+	return messages
+}
+
+// stringLiteral возвращает значение строкового литерала или конкатенации литералов
+func stringLiteral(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		left, ok := stringLiteral(e.X)
+		if !ok {
+			return "", false
+		}
+		right, ok := stringLiteral(e.Y)
+		return left + right, ok
+	case *ast.ParenExpr:
+		return stringLiteral(e.X)
+	}
+	return "", false
+}
+
+// formatVerbs возвращает спецификаторы формата строки в порядке следования
+func formatVerbs(format string) []string {
+	var verbs []string
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(format) && strings.IndexByte("+-# 0123456789.", format[j]) >= 0 {
+			j++
+		}
+		if j < len(format) {
+			verbs = append(verbs, format[i:j+1])
+		}
+		i = j
+	}
+	return verbs
+}
@@ -0,0 +1,628 @@
+package i18n
+
+// english - английский каталог сообщений. Ключи совпадают с исходными русскими текстами
+// в пакетах census и mcp; переводы строк формата сохраняют порядок спецификаторов.
+var english = map[string]string{
+	// Описания инструментов MCP
+	"Получает данные о населении штата США":                                                                                       "Gets population data for US states",
+	"Получает данные о населении округов в штате США":                                                                             "Gets population data for counties in a US state",
+	"Ищет штат по названию (полному или частичному)":                                                                              "Searches for a state by its full or partial name",
+	"Получает список доступных наборов данных Census API":                                                                         "Gets the list of available Census API datasets",
+	"Получает список доступных переменных для указанного набора данных":                                                           "Gets the list of available variables for the specified dataset",
+	"Получает список доступных географических уровней для указанного набора данных":                                               "Gets the available geography levels for the specified dataset",
+	"Позволяет делать пользовательские запросы к Census API с указанием набора данных, года, переменных и географического уровня": "Runs custom Census API queries with a dataset, year, variables and geography level",
+	"Получает данные по ZCTA (ZIP Code Tabulation Area) - статистическому приближению почтовых индексов USPS. ZCTA не равны почтовым индексам: границы различаются, а у части индексов нет ZCTA":                                                                                                                                                  "Gets data for ZCTAs (ZIP Code Tabulation Areas), the statistical approximation of USPS ZIP codes. ZCTAs are not ZIP codes: the boundaries differ and some ZIP codes have no ZCTA",
+	"Получает данные по округам Палаты представителей Конгресса США. Округа задаются идентификаторами вида 'CA-12', 'VT-AL' (единственный округ), 'TX' (все округа штата) или '*' (все округа страны)":                                                                                                                                            "Gets data for US House congressional districts. Districts are identified as 'CA-12', 'VT-AL' (at-large district), 'TX' (all districts of a state) or '*' (all districts in the country)",
+	"Получает данные по округам верхней (сенат) или нижней (палата представителей, ассамблея) палаты легислатуры штата. Округа задаются идентификаторами вида 'CA-11', 'CA' (все округа штата) или '*'":                                                                                                                                           "Gets data for upper (senate) or lower (house, assembly) chamber state legislative districts. Districts are identified as 'CA-11', 'CA' (all districts of a state) or '*'",
+	"Ищет метрополитенский или микрополитенский статистический ареал (CBSA) по названию, например 'Seattle metro'. Возвращает код CBSA и входящие в ареал округа":                                                                                                                                                                                 "Searches for a metropolitan or micropolitan statistical area (CBSA) by name, for example 'Seattle metro'. Returns the CBSA code and its component counties",
+	"Получает данные по метрополитенским и микрополитенским статистическим ареалам (CBSA). Ареалы не вложены в штаты и могут охватывать несколько штатов":                                                                                                                                                                                         "Gets data for metropolitan and micropolitan statistical areas (CBSAs). CBSAs do not nest within states and may span several states",
+	"Получает список округов, входящих в статистический ареал (CBSA), по делимитации OMB":                                                                                                                                                                                                                                                         "Gets the counties that make up a statistical area (CBSA) from the OMB delineation",
+	"Сравнивает значения переменных для набора географий за несколько лет: выравнивает строки по GEOID, вычисляет абсолютное и относительное изменение и предупреждает, если подпись или определение переменной менялись между выпусками":                                                                                                         "Compares variable values for a set of geographies across several years: aligns rows by GEOID, computes absolute and percent change and warns when a variable's label or definition changed between releases",
+	"Объединяет список географий (по GEOID) в пользовательский регион: суммирует переменные-счетчики и вычисляет MOE суммы как корень из суммы квадратов. Медианы, проценты и коэффициенты суммировать нельзя - по умолчанию такой запрос отклоняется":                                                                                            "Combines a list of geographies (by GEOID) into a custom region: sums count variables and computes the MOE of the sum as the root of the sum of squares. Medians, percentages and ratios cannot be summed, so by default such a request is rejected",
+	"Оценивает медиану и другие квантили по интервальной таблице ACS (например, B19001 - распределение доходов домохозяйств) линейной интерполяцией или интерполяцией Парето по методике Census Bureau":                                                                                                                                           "Estimates the median and other quantiles from an ACS binned table (for example, B19001 - household income distribution) using linear or Pareto interpolation following the Census Bureau method",
+	"Строит рейтинг географий по значению переменной: запрашивает все географии уровня (при необходимости внутри родительской географии), сортирует их по числовому значению и возвращает первые или последние N с местом, значением и процентилем. Географии с подавленными значениями в рейтинг не входят":                                      "Ranks geographies by a variable's value: fetches all geographies of a level (optionally within a parent geography), sorts them by numeric value and returns the top or bottom N with rank, value and percentile. Geographies with suppressed values are excluded from the ranking",
+	"Проверяет, различаются ли две оценки ACS статистически значимо: для двух географий или двух лет запрашивает оценки и MOE переменной и выполняет рекомендованный Census Bureau z-тест на уровне доверия 90%. Возвращает разность, ее MOE и вывод о значимости":                                                                                "Tests whether two ACS estimates differ significantly: fetches a variable's estimates and MOEs for two geographies or two years and runs the z-test recommended by the Census Bureau at the 90% confidence level. Returns the difference, its MOE and the significance conclusion",
+	"Возвращает курируемую таблицу профиля ACS (DP02 - социальные, DP03 - экономические, DP04 - жилищные, DP05 - демографические характеристики) для географии: ключевые строки с оценками, процентами и их MOE":                                                                                                                                  "Returns a curated ACS profile table (DP02 - social, DP03 - economic, DP04 - housing, DP05 - demographic characteristics) for a geography: key lines with estimates, percentages and their MOEs",
+	"Возвращает показатели десятилетней переписи (P.L. 94-171, DHC, SF1): численность населения по расе и испаноязычному происхождению и занятость жилья с долями, вплоть до уровня кварталов (block). Данные переписи не имеют MOE":                                                                                                              "Returns decennial census measures (P.L. 94-171, DHC, SF1): population by race and Hispanic origin and housing occupancy with shares, down to the block level. Census counts have no MOE",
+	"Сравнивает показатели расы, испаноязычного происхождения и занятости жилья двух переписей (по умолчанию 2010 и 2020) для одних и тех же GEOID, сопоставляя переменные выпусков с разной структурой таблиц. Возвращает значения, абсолютные и относительные изменения":                                                                        "Compares race, Hispanic origin and housing occupancy measures of two censuses (2010 and 2020 by default) for the same GEOIDs, matching variables across releases with different table layouts. Returns the values and the absolute and percent changes",
+	"Возвращает ежегодные оценки численности населения программы PEP (Population Estimates Program): годовые итоги на 1 июля, компоненты изменения (рождения, смерти, международная и внутренняя миграция) или численность по возрасту, полу, расе и испаноязычному происхождению. В отличие от ACS 1-year, оценки PEP доступны для всех округов": "Returns annual population estimates from the Population Estimates Program (PEP): July 1 totals, components of change (births, deaths, international and domestic migration) or population by age, sex, race and Hispanic origin. Unlike ACS 1-year, PEP estimates are available for all counties",
+	"Ищет коды отраслей NAICS 2017 по названию на английском языке (например, 'restaurants', 'software') или по коду. Более общие отрасли (секторы) выводятся первыми":                                                                                                                                                                            "Searches NAICS 2017 industry codes by English name (for example, 'restaurants', 'software') or by code. Broader industries (sectors) are listed first",
+	"Возвращает число предприятий, занятость и годовой фонд оплаты труда по отраслям NAICS из County Business Patterns (cbp) или экономической переписи (ecnbasic, также выручка). Отрасль задается кодами NAICS или текстом, который сопоставляется с таблицей NAICS":                                                                            "Returns establishment counts, employment and annual payroll by NAICS industry from County Business Patterns (cbp) or the Economic Census (ecnbasic, which also includes receipts). Industries are given as NAICS codes or as text matched against the NAICS table",
+	"Возвращает миграционные потоки ACS (acs/flows) для округа или статистического ареала: крупнейшие источники притока и направления оттока среди других географий с чистой миграцией и MOE, а также итоги по всем контрагентам":                                                                                                                 "Returns ACS migration flows (acs/flows) for a county or statistical area: the largest sources of inflow and destinations of outflow among other geographies with net migration and MOEs, plus totals across all counterparts",
+
+	// Описания параметров инструментов
+	"Формат результата: markdown (по умолчанию), plain - текст без разметки, json - JSON со стабильной схемой, csv или tsv - таблица для электронных таблиц, geojson - FeatureCollection с границами географий для карт": "Result format: markdown (default), plain - text without markup, json - JSON with a stable schema, csv or tsv - a table for spreadsheets, geojson - a FeatureCollection with geography boundaries for maps",
+	"Наибольшее число знаков после запятой в текстовых форматах (от 0 до 6, по умолчанию 2)":                                                                                                                             "Maximum number of decimal places in text formats (0 to 6, default 2)",
+	"формат geojson поддерживает только строки данных с географическими кодами":                                                                                                                                          "the geojson format supports only data rows with geography codes",
+	"%s (наибольшие %d из %d)":    "%s (top %d of %d)",
+	"%s (первые %d рядов из %d)":  "%s (first %d of %d series)",
+	"Половозрастная пирамида: %s": "Population pyramid: %s",
+	"Построить по результату диаграмму SVG: bar - столбцы по географиям, line - временной ряд, pyramid - половозрастная пирамида по таблице B01001": "Build an SVG chart from the result: bar - bars by geography, line - time series, pyramid - population pyramid from table B01001",
+	"Столбец или переменная значений диаграммы (по умолчанию первый числовой столбец)":                                                              "Column or variable with the chart values (default: the first numeric column)",
+	"Ошибка при построении диаграммы: ": "Error building the chart: ",
+	"Добавить к результату встроенный ресурс с полными данными для программной обработки: json, csv, tsv, geojson - FeatureCollection с границами географий или none - без ресурса (по умолчанию задается сервером)": "Attach an embedded resource with the full data for programmatic use: json, csv, tsv, geojson - a FeatureCollection with geography boundaries or none - no resource (the server sets the default)",
+	"Неизвестный формат ресурса %q; доступны: %s": "Unknown resource format %q; available: %s",
+	"Курсор следующей страницы из пояснения к усеченному результату; остальные параметры должны совпадать с предыдущим вызовом":                                      "Next page cursor from the note of a truncated result; the other parameters must match the previous call",
+	"Примерный бюджет токенов результата; более длинные результаты усекаются со сводкой, табличные - с курсором следующей страницы (по умолчанию задается сервером)": "Approximate token budget of the result; longer results are truncated with a summary, tabular ones with a next page cursor (the server sets the default)",
+	"Некорректный параметр 'cursor'": "Invalid 'cursor' parameter",
+	"Параметр 'cursor' относится к другому запросу: повторите вызов с теми же параметрами, что и у предыдущей страницы":                                                       "The 'cursor' parameter belongs to a different request: repeat the call with the same parameters as for the previous page",
+	"Параметр 'cursor' указывает за пределы результата из %d строк":                                                                                                           "The 'cursor' parameter points past the end of the %d-row result",
+	"Параметр 'maxTokens' должен быть положительным числом":                                                                                                                   "The 'maxTokens' parameter must be a positive number",
+	"**Показаны строки %d-%d из %d**: результат превышает бюджет около %d токенов. Чтобы получить следующие строки, повторите вызов с теми же параметрами и cursor=\"%s\".\n": "**Showing rows %d-%d of %d**: the result exceeds the budget of about %d tokens. To get the next rows, repeat the call with the same parameters and cursor=\"%s\".\n",
+	"**Результат усечен**: он превышает бюджет около %d токенов, показано начало. Сузьте запрос или увеличьте параметр maxTokens.\n":                                          "**Result truncated**: it exceeds the budget of about %d tokens, showing the beginning. Narrow the query or increase the maxTokens parameter.\n",
+	"**Показаны строки %d-%d из %d** (последняя страница).\n":                                                                                                                 "**Showing rows %d-%d of %d** (last page).\n",
+	"**Сводка по всем строкам (%d)**:\n":                   "**Summary of all rows (%d)**:\n",
+	"- %s: мин. %s, макс. %s, медиана %s (значений: %d)\n": "- %s: min %s, max %s, median %s (values: %d)\n",
+	"Подписывать столбцы переменных их описаниями из Census API (например, 'Median household income' вместо 'B19013_001E')":                            "Label variable columns with their Census API descriptions (e.g. 'Median household income' instead of 'B19013_001E')",
+	"Язык результата и сообщений: ru - русский, en - английский (по умолчанию - язык сервера)":                                                         "Language of the result and messages: ru - Russian, en - English (defaults to the server language)",
+	"ID штата (например, '06' для Калифорнии). Если не указан, возвращает данные для всех округов":                                                     "State ID (for example, '06' for California). If omitted, returns data for all counties",
+	"ID штата (например, '06' для Калифорнии). Если не указан, возвращает данные для всех штатов":                                                      "State ID (for example, '06' for California). If omitted, returns data for all states",
+	"ID штата для выпусков, где ZCTA вложены в штаты (ACS 5-year до 2018 года, перепись 2010 года)":                                                    "State ID for releases where ZCTAs nest within states (ACS 5-year before 2018, 2010 census)",
+	"'top' - наибольшие значения (по умолчанию), 'bottom' - наименьшие":                                                                                "'top' - largest values (default), 'bottom' - smallest",
+	"Вид данных: 'population' - годовые итоги (по умолчанию), 'components' - компоненты изменения, 'characteristics' - численность по характеристикам": "Kind of data: 'population' - annual totals (default), 'components' - components of change, 'characteristics' - population by characteristics",
+	"Выпуск оценок (по умолчанию '2019'); 'timeseries' - межпереписные оценки 2000-2010. Выпуски с 2020 года содержат только годовые итоги":            "Estimates vintage (default '2019'); 'timeseries' - 2000-2010 intercensal estimates. Vintages from 2020 on contain annual totals only",
+	"Вычисляемые столбцы вида 'имя = выражение' с расчетом MOE по методике Census Bureau, например ['pct_poverty = B17001_002E / B17001_001E']. Доступны операторы + - * /, унарный минус, скобки и функции sum(a, ...), prop(a, b), pct(a, b), ratio(a, b), pct_change(from, to). Оператор / делит часть на итог ее таблицы (строку _001) по формуле доли; для других пар оценок укажите prop или ratio. Для каждого столбца добавляется столбец '<имя>_moe'": "Derived columns of the form 'name = expression' with MOEs computed following the Census Bureau method, for example ['pct_poverty = B17001_002E / B17001_001E']. Operators + - * /, unary minus, parentheses and the functions sum(a, ...), prop(a, b), pct(a, b), ratio(a, b), pct_change(from, to) are available. The / operator divides a part by its table total (line _001) using the proportion formula; for other pairs of estimates use prop or ratio. A '<name>_moe' column is added for each column",
+	"Географический уровень (например, 'state' или 'county')":                                                                                           "Geography level (for example, 'state' or 'county')",
+	"Географический уровень (например, 'state', 'county' или 'zip code')":                                                                               "Geography level (for example, 'state', 'county' or 'zip code')",
+	"Географический уровень (например, 'state', 'county', 'tract', 'block group' или 'block'). Для 'block' в фильтре необходимо указать state и county": "Geography level (for example, 'state', 'county', 'tract', 'block group' or 'block'). For 'block' the filter must include state and county",
+	"Географический уровень (например, 'us', 'state' или 'county')":                                                                                     "Geography level (for example, 'us', 'state' or 'county')",
+	"Географический уровень второй оценки (по умолчанию совпадает с 'geoLevel')":                                                                        "Geography level of the second estimate (defaults to 'geoLevel')",
+	"Географический уровень первой оценки (например, 'county')":                                                                                         "Geography level of the first estimate (for example, 'county')",
+	"Географический уровень ранжируемых географий (например, 'county')":                                                                                 "Geography level of the ranked geographies (for example, 'county')",
+	"Географический уровень: 'county' (по умолчанию) или уровень CBSA":                                                                                  "Geography level: 'county' (default) or the CBSA level",
+	"Год базовой переписи (по умолчанию '2010')":                                                                                                        "Base census year (default '2010')",
+	"Год второй оценки (по умолчанию совпадает с 'year')":                                                                                               "Year of the second estimate (defaults to 'year')",
+	"Год данных (например, '2021')":                                                                                                                     "Data year (for example, '2021')",
+	"Год данных (по умолчанию '2021' для cbp и '2017' для ecnbasic)":                                                                                    "Data year (default '2021' for cbp and '2017' for ecnbasic)",
+	"Год данных (по умолчанию '2021')":                                                                                                                  "Data year (default '2021')",
+	"Год данных (по умолчанию '2021'). Коды строк курируемых таблиц соответствуют выпускам 2019-2022":                                                   "Data year (default '2021'). Line codes of the curated tables match the 2019-2022 releases",
+	"Год данных (по умолчанию '2022')":                                                                                                                  "Data year (default '2022')",
+	"Год оценки для выпуска 'timeseries' (например, '2005'). Если не указан, возвращаются все годы":                                                     "Estimate year for the 'timeseries' vintage (for example, '2005'). If omitted, all years are returned",
+	"Год первой оценки (например, '2021')":                                                                                                              "Year of the first estimate (for example, '2021')",
+	"Год переписи: '2020' для dec/pl и dec/dhc, '2010' для dec/pl и dec/sf1 (по умолчанию '2020')":                                                      "Census year: '2020' for dec/pl and dec/dhc, '2010' for dec/pl and dec/sf1 (default '2020')",
+	"Год сравниваемой переписи (по умолчанию '2020')":                                                                                                   "Year of the compared census (default '2020')",
+	"Год, в доллары которого пересчитываются долларовые переменные (например, '2022'). Долларовые переменные определяются по подписи переменной": "Year whose dollars dollar-denominated variables are adjusted to (for example, '2022'). Dollar variables are detected from the variable label",
+	"Группы показателей: 'race' (раса), 'ethnicity' (испаноязычное происхождение), 'housing' (занятость жилья). По умолчанию все":                "Measure groups: 'race', 'ethnicity' (Hispanic origin), 'housing' (housing occupancy). All by default",
+	"Группы показателей: 'race', 'ethnicity', 'housing'. По умолчанию все":                                                                       "Measure groups: 'race', 'ethnicity', 'housing'. All by default",
+	"Идентификатор набора данных":                                     "Dataset identifier",
+	"Индекс цен для пересчета: 'cpi-u-rs' (по умолчанию) или 'cpi-u'": "Price index for the adjustment: 'cpi-u-rs' (default) or 'cpi-u'",
+	"Интервальная таблица: B19001 (доход домохозяйств), B25075 (стоимость жилья), B25063 (валовая арендная плата)":                            "Binned table: B19001 (household income), B25075 (home value), B25063 (gross rent)",
+	"Исключить медианы, проценты и коэффициенты с предупреждением вместо отказа (по умолчанию false)":                                         "Skip medians, percentages and ratios with a warning instead of rejecting the request (default false)",
+	"Код CBSA (например, '42660') или название ареала (например, 'Seattle metro')":                                                            "CBSA code (for example, '42660') or area name (for example, 'Seattle metro')",
+	"Коды отраслей NAICS (например, ['72', '7225', '44-45']). Если не указаны вместе с 'industry', возвращается итог по всем секторам ('00')": "NAICS industry codes (for example, ['72', '7225', '44-45']). If neither these nor 'industry' are given, the all-sectors total ('00') is returned",
+	"Количество географий в результате (по умолчанию 10)":                                                                                     "Number of geographies in the result (default 10)",
+	"Количество контрагентов в списках притока и оттока (по умолчанию 10)":                                                                    "Number of counterparts in the inflow and outflow lists (default 10)",
+	"Конечный год диапазона (используется, если не указан 'years')":                                                                           "Last year of the range (used when 'years' is not given)",
+	"Коэффициент дизайна выборки ACS для расчета MOE квантилей (например, 1.5). Если не указан, MOE не вычисляется":                           "ACS design factor for quantile MOEs (for example, 1.5). If omitted, no MOE is computed",
+	"Коэффициент дизайна выборки ACS для расчета MOE переоцененных медиан (например, 1.5). Если не указан, медианы возвращаются без MOE":      "ACS design factor for the MOEs of re-estimated medians (for example, 1.5). If omitted, medians are returned without a MOE",
+	"Максимальное количество результатов (по умолчанию 20)":                                                                                   "Maximum number of results (default 20)",
+	"Метод интерполяции: 'linear' (по умолчанию), 'pareto' или 'auto' (Парето для интервалов шире $2,500)":                                    "Interpolation method: 'linear' (default), 'pareto' or 'auto' (Pareto for bins wider than $2,500)",
+	"Набор данных (например, 'acs/acs1')": "Dataset (for example, 'acs/acs1')",
+	"Набор данных (например, 'acs/acs1', 'acs/acs5/profile' для профилей DP02-DP05 или 'acs/acs5/subject' для тематических таблиц S). Сразу после каждой оценки профиля (DPxx_xxxxE) или тематической таблицы (например, S1701_C01_001E) добавляется ее процентный столбец (DPxx_xxxxPE, S1701_C03_001E), а после MOE - MOE процента": "Dataset (for example, 'acs/acs1', 'acs/acs5/profile' for DP02-DP05 profiles or 'acs/acs5/subject' for S subject tables). Each profile estimate (DPxx_xxxxE) or subject table estimate (for example, S1701_C01_001E) is immediately followed by its percent column (DPxx_xxxxPE, S1701_C03_001E), and each MOE by the percent MOE",
+	"Набор данных (например, 'acs/acs5')":                        "Dataset (for example, 'acs/acs5')",
+	"Набор данных (по умолчанию 'acs/acs1')":                     "Dataset (default 'acs/acs1')",
+	"Набор данных (по умолчанию 'acs/acs5')":                     "Dataset (default 'acs/acs5')",
+	"Набор данных базовой переписи (по умолчанию 'dec/pl')":      "Base census dataset (default 'dec/pl')",
+	"Набор данных переписи (по умолчанию 'dec/pl')":              "Census dataset (default 'dec/pl')",
+	"Набор данных профиля (по умолчанию 'acs/acs5/profile')":     "Profile dataset (default 'acs/acs5/profile')",
+	"Набор данных сравниваемой переписи (по умолчанию 'dec/pl')": "Compared census dataset (default 'dec/pl')",
+	"Набор данных: 'cbp' (по умолчанию) или 'ecnbasic'":          "Dataset: 'cbp' (default) or 'ecnbasic'",
+	"Название ареала или его главного города":                    "Name of the area or its principal city",
+	"Название отрасли на английском языке (например, 'restaurants'); используется наиболее общая найденная отрасль": "Industry name in English (for example, 'restaurants'); the broadest matching industry is used",
+	"Название штата для поиска":                                                                                                                             "State name to search for",
+	"Начальный год диапазона (используется, если не указан 'years')":                                                                                        "First year of the range (used when 'years' is not given)",
+	"Палата легислатуры: 'upper' (верхняя) или 'lower' (нижняя)":                                                                                            "Legislative chamber: 'upper' or 'lower'",
+	"Переменная для рейтинга (например, 'B19013_001E' - медианный доход домохозяйства)":                                                                     "Variable to rank by (for example, 'B19013_001E' - median household income)",
+	"Переменная-оценка ACS (например, 'B19013_001E')":                                                                                                       "ACS estimate variable (for example, 'B19013_001E')",
+	"Последний год 5-летнего периода ACS (по умолчанию '2020' - период 2016-2020)":                                                                          "Last year of the ACS 5-year period (default '2020' - the 2016-2020 period)",
+	"Созыв Конгресса (например, 118). Если год не указан, выбирается последний выпуск ACS с округами этого созыва":                                          "Congress number (for example, 118). If no year is given, the latest ACS release with this Congress's districts is used",
+	"Список GEOID объединяемых географий (например, ['06037', '06059'] для округов)":                                                                        "GEOIDs of the geographies to combine (for example, ['06037', '06059'] for counties)",
+	"Список идентификаторов округов (например, ['CA-11', 'CA-17'])":                                                                                         "District identifiers (for example, ['CA-11', 'CA-17'])",
+	"Список идентификаторов округов (например, ['CA-12', 'TX-07'])":                                                                                         "District identifiers (for example, ['CA-12', 'TX-07'])",
+	"Список квантилей от 0 до 1 (по умолчанию [0.5] - медиана)":                                                                                             "Quantiles between 0 and 1 (default [0.5] - the median)",
+	"Список кодов CBSA (например, ['42660']), названий (например, ['Seattle metro']) или ['*'] для всех ареалов":                                            "CBSA codes (for example, ['42660']), names (for example, ['Seattle metro']) or ['*'] for all areas",
+	"Список лет для сравнения (например, ['2015', '2019', '2022'])":                                                                                         "Years to compare (for example, ['2015', '2019', '2022'])",
+	"Список переменных для запроса (например, ['NAME', 'B01001_001E'])":                                                                                     "Variables to request (for example, ['NAME', 'B01001_001E'])",
+	"Список переменных для запроса (по умолчанию ['NAME', 'B01001_001E'])":                                                                                  "Variables to request (default ['NAME', 'B01001_001E'])",
+	"Список пятизначных кодов ZCTA (например, ['94110', '10001']) или ['*'] для всех":                                                                       "Five-digit ZCTA codes (for example, ['94110', '10001']) or ['*'] for all",
+	"Список сравниваемых переменных (например, ['B01001_001E', 'B19013_001E'])":                                                                             "Variables to compare (for example, ['B01001_001E', 'B19013_001E'])",
+	"Список суммируемых переменных (например, ['B01001_001E', 'B25001_001E'])":                                                                              "Variables to sum (for example, ['B01001_001E', 'B25001_001E'])",
+	"Текст запроса или код NAICS":                                                                                                                           "Query text or NAICS code",
+	"Фильтр географии (например, {\"state\": \"06\", \"county\": \"*\"}). Если не указан, будет использован wildcard для указанного географического уровня": "Geography filter (for example, {\"state\": \"06\", \"county\": \"*\"}). If omitted, a wildcard for the specified geography level is used",
+	"Фильтр географии (например, {\"state\": \"06\", \"county\": \"037\"}). Если не указан, возвращаются все географии уровня":                              "Geography filter (for example, {\"state\": \"06\", \"county\": \"037\"}). If omitted, all geographies of the level are returned",
+	"Фильтр географии (например, {\"state\": \"06\"}). Если не указан, возвращаются все географии уровня":                                                   "Geography filter (for example, {\"state\": \"06\"}). If omitted, all geographies of the level are returned",
+	"Фильтр географии (например, {\"state\": \"06\"}). Если не указан, сравниваются все географии уровня":                                                   "Geography filter (for example, {\"state\": \"06\"}). If omitted, all geographies of the level are compared",
+	"Фильтр родительской географии (например, {\"state\": \"48\"} для округов Техаса). Если не указан, ранжируются все географии уровня":                    "Parent geography filter (for example, {\"state\": \"48\"} for Texas counties). If omitted, all geographies of the level are ranked",
+	"Фильтр характеристик для kind='characteristics': AGEGROUP (0 - все, 1-18 - пятилетние группы), SEX (0, 1, 2), RACE (0-6), HISP (0, 1, 2). Не указанные характеристики принимают значение 0 (итог), значение 'all' возвращает все коды (например, {\"SEX\": \"all\"})": "Characteristics filter for kind='characteristics': AGEGROUP (0 - all, 1-18 - five-year groups), SEX (0, 1, 2), RACE (0-6), HISP (0, 1, 2). Omitted characteristics default to 0 (total); 'all' returns every code (for example, {\"SEX\": \"all\"})",
+	"Фильтр, выбирающий одну географию (например, {\"state\": \"06\", \"county\": \"037\"} или {\"metropolitan statistical area/micropolitan statistical area\": \"31080\"})":                                                                                              "Filter selecting a single geography (for example, {\"state\": \"06\", \"county\": \"037\"} or {\"metropolitan statistical area/micropolitan statistical area\": \"31080\"})",
+	"Фильтр, выбирающий одну географию для второй оценки (по умолчанию совпадает с 'geoFilter')":                                                                                                                                                                           "Filter selecting a single geography for the second estimate (defaults to 'geoFilter')",
+	"Фильтр, выбирающий одну географию для первой оценки (например, {\"state\": \"06\", \"county\": \"037\"})":                                                                                                                                                             "Filter selecting a single geography for the first estimate (for example, {\"state\": \"06\", \"county\": \"037\"})",
+
+	// Сообщения об ошибках инструментов
+	"Неподдерживаемый язык; доступны: ru, en":                                              "Unsupported language; available: ru, en",
+	"Неизвестный формат %q; доступны: %s":                                                  "Unknown format %q; available: %s",
+	"Некорректный диапазон лет: ":                                                          "Invalid year range: ",
+	"Необходимо указать 'secondYear' или 'secondGeoFilter', отличающиеся от первой оценки": "'secondYear' or 'secondGeoFilter' must differ from the first estimate",
+	"Необходимо указать параметр 'cbsa'":                                                   "The 'cbsa' parameter is required",
+	"Необходимо указать параметр 'cbsas'":                                                  "The 'cbsas' parameter is required",
+	"Необходимо указать параметр 'chamber' со значением 'upper' или 'lower'":               "The 'chamber' parameter must be 'upper' or 'lower'",
+	"Необходимо указать параметр 'districts'":                                              "The 'districts' parameter is required",
+	"Необходимо указать параметр 'geoFilter'":                                              "The 'geoFilter' parameter is required",
+	"Необходимо указать параметр 'geoLevel'":                                               "The 'geoLevel' parameter is required",
+	"Необходимо указать параметр 'geoids'":                                                 "The 'geoids' parameter is required",
+	"Необходимо указать параметр 'name'":                                                   "The 'name' parameter is required",
+	"Необходимо указать параметр 'query'":                                                  "The 'query' parameter is required",
+	"Необходимо указать параметр 'table'":                                                  "The 'table' parameter is required",
+	"Необходимо указать параметр 'variable'":                                               "The 'variable' parameter is required",
+	"Необходимо указать параметр 'variables'":                                              "The 'variables' parameter is required",
+	"Необходимо указать параметр 'years' или параметры 'startYear' и 'endYear'":            "The 'years' parameter or the 'startYear' and 'endYear' parameters are required",
+	"Необходимо указать параметр 'zctas'":                                                  "The 'zctas' parameter is required",
+	"Необходимо указать параметры 'dataset' и 'year'":                                      "The 'dataset' and 'year' parameters are required",
+	"Необходимо указать параметры 'dataset', 'geoLevel' и 'variables'":                     "The 'dataset', 'geoLevel' and 'variables' parameters are required",
+	"Необходимо указать параметры 'dataset', 'year', 'geoLevel' и 'variables'":             "The 'dataset', 'year', 'geoLevel' and 'variables' parameters are required",
+	"Необходимо указать параметры 'variable', 'geoLevel', 'geoFilter' и 'year'":            "The 'variable', 'geoLevel', 'geoFilter' and 'year' parameters are required",
+	"Параметр 'precision' должен быть от 0 до %d":                                          "The 'precision' parameter must be between 0 and %d",
+	"Параметр 'order' должен быть 'top' или 'bottom'":                                      "The 'order' parameter must be 'top' or 'bottom'",
+	"Отрасль '%s' не найдена в таблице NAICS; используйте search_naics для поиска кода":    "Industry '%s' was not found in the NAICS table; use search_naics to look up the code",
+	"Состав статистического ареала не найден в делимитации CBSA: ":                         "The statistical area composition was not found in the CBSA delineation: ",
+	"Статистические ареалы не найдены по запросу: ":                                        "No statistical areas found for the query: ",
+	"Штаты не найдены по запросу: ":                                                        "No states found for the query: ",
+	"Отрасли не найдены":                                                                   "No industries found",
+	"Ошибка в вычисляемом столбце: ":                                                       "Error in a derived column: ",
+	"Ошибка при агрегации географий: ":                                                     "Error aggregating geographies: ",
+	"Ошибка при определении статистического ареала: ":                                      "Error resolving the statistical area: ",
+	"Ошибка при оценке квантилей: ":                                                        "Error estimating quantiles: ",
+	"Ошибка при пересчете в постоянные доллары: ":                                          "Error adjusting to constant dollars: ",
+	"Ошибка при поиске отраслей: ":                                                         "Error searching industries: ",
+	"Ошибка при поиске отрасли: ":                                                          "Error looking up the industry: ",
+	"Ошибка при поиске статистического ареала: ":                                           "Error searching statistical areas: ",
+	"Ошибка при поиске штата: ":                                                            "Error searching states: ",
+	"Ошибка при получении данных о доступных географических уровнях: ":                     "Error getting available geography levels: ",
+	"Ошибка при получении данных о доступных наборах данных: ":                             "Error getting available datasets: ",
+	"Ошибка при получении данных о доступных переменных: ":                                 "Error getting available variables: ",
+	"Ошибка при получении данных о населении округов: ":                                    "Error getting county population data: ",
+	"Ошибка при получении данных о населении: ":                                            "Error getting population data: ",
+	"Ошибка при получении данных по ZCTA: ":                                                "Error getting ZCTA data: ",
+	"Ошибка при получении данных по избирательным округам: ":                               "Error getting district data: ",
+	"Ошибка при получении данных по статистическим ареалам: ":                              "Error getting statistical area data: ",
+	"Ошибка при получении миграционных потоков: ":                                          "Error getting migration flows: ",
+	"Ошибка при получении оценок численности населения: ":                                  "Error getting population estimates: ",
+	"Ошибка при получении показателей переписи: ":                                          "Error getting census measures: ",
+	"Ошибка при получении пользовательских данных: ":                                       "Error getting custom data: ",
+	"Ошибка при получении профиля: ":                                                       "Error getting the profile: ",
+	"Ошибка при получении статистики предприятий: ":                                        "Error getting business statistics: ",
+	"Ошибка при построении рейтинга: ":                                                     "Error building the ranking: ",
+	"Ошибка при сравнении выпусков: ":                                                      "Error comparing releases: ",
+	"Ошибка при сравнении оценок: ":                                                        "Error comparing estimates: ",
+	"Ошибка при сравнении переписей: ":                                                     "Error comparing censuses: ",
+	"Ошибка при форматировании данных: ":                                                   "Error formatting data: ",
+	"неподдерживаемый тип данных: ":                                                        "unsupported data type: ",
+	"ошибка сериализации данных: ":                                                         "data serialization error: ",
+
+	// Пояснения к результатам
+	"> **Примечание**: ZCTA (ZIP Code Tabulation Area) — это статистическое приближение почтовых индексов USPS, построенное Census Bureau из блоков переписи. Границы ZCTA не совпадают с зонами доставки USPS, а часть почтовых индексов (абонентские ящики, отдельные организации) не имеет соответствующей ZCTA.": "> **Note**: a ZCTA (ZIP Code Tabulation Area) is a statistical approximation of USPS ZIP codes built by the Census Bureau from census blocks. ZCTA boundaries do not match USPS delivery areas, and some ZIP codes (PO boxes, single organizations) have no corresponding ZCTA.",
+	"Примечание: переменные %s пересчитаны в доллары %s года по индексу %s (%s). Для 5-летних оценок ACS исходными считаются доллары последнего года периода.":                                                                                                                                                       "Note: variables %s were adjusted to %s dollars using the %s index (%s). For ACS 5-year estimates the source dollars are those of the last year of the period.",
+	"Примечание: среди запрошенных переменных не найдено долларовых, пересчет в постоянные доллары не выполнялся.":                                                                                                                                                                                                   "Note: none of the requested variables are in dollars, so no constant-dollar adjustment was made.",
+	"Предупреждение: ": "Warning: ",
+
+	// Заголовки и текст результатов
+	"# Доступные географические уровни\n\n":                   "# Available geography levels\n\n",
+	"# Доступные наборы данных\n\n":                           "# Available datasets\n\n",
+	"# Доступные переменные\n\n":                              "# Available variables\n\n",
+	"# Отрасли NAICS\n\n":                                     "# NAICS industries\n\n",
+	"# Статистические ареалы (CBSA)\n\n":                      "# Statistical areas (CBSA)\n\n",
+	"# %s %s: предприятия по отраслям (%s)\n\n":               "# %s %s: establishments by industry (%s)\n\n",
+	"# %s (%s, выпуск %s)\n\n":                                "# %s (%s, vintage %s)\n\n",
+	"# Изменение показателей переписи: %s %s → %s %s\n\n":     "# Change in census measures: %s %s → %s %s\n\n",
+	"# Квантили распределения %s: %s (%s %s)\n\n":             "# Distribution quantiles %s: %s (%s %s)\n\n",
+	"# Миграционные потоки: %s (ACS %s, 5-летние оценки)\n\n": "# Migration flows: %s (ACS %s, 5-year estimates)\n\n",
+	"# Пользовательский регион (%s %s)\n\n":                   "# Custom region (%s %s)\n\n",
+	"# Рейтинг по %s (%s %s)\n\n":                             "# Ranking by %s (%s %s)\n\n",
+	"# Сравнение выпусков %s: %s\n\n":                         "# Release comparison %s: %s\n\n",
+	"# Сравнение оценок %s (%s)\n\n":                          "# Estimate comparison %s (%s)\n\n",
+	"## Итоги\n\n":                              "## Totals\n\n",
+	"## Не найденные GEOID\n\n%s\n\n":           "## GEOIDs not found\n\n%s\n\n",
+	"## Недоступные выпуски\n\n":                "## Unavailable releases\n\n",
+	"## Переоцененные медианы\n\n":              "## Re-estimated medians\n\n",
+	"## Предупреждения\n\n":                     "## Warnings\n\n",
+	"## Состав региона\n\n":                     "## Region composition\n\n",
+	"## ⚠ Изменения определений переменных\n\n": "## ⚠ Variable definition changes\n\n",
+	"## ⚠ Исключенные переменные\n\n":           "## ⚠ Skipped variables\n\n",
+	"\n**Вывод**: различие статистически значимо на уровне доверия 90%.\n":                                                                  "\n**Conclusion**: the difference is statistically significant at the 90% confidence level.\n",
+	"\n**Вывод**: различие статистически не значимо на уровне доверия 90%.\n":                                                               "\n**Conclusion**: the difference is not statistically significant at the 90% confidence level.\n",
+	"\nMOE указаны для уровня доверия 90%. Прочерк означает, что значение не публикуется для строки.\n":                                     "\nMOEs are at the 90% confidence level. A dash means the value is not published for the line.\n",
+	"\nДанные переписи являются полным счетом и не имеют MOE. Доли рассчитаны от всего населения или всех жилых единиц.\n":                  "\nCensus data are complete counts and have no MOE. Shares are computed from the total population or all housing units.\n",
+	"\nНе вошли в рейтинг из-за подавленных или отсутствующих значений (%d): %s\n":                                                          "\nExcluded from the ranking due to suppressed or missing values (%d): %s\n",
+	"\nОценки приведены на 1 июля каждого года. Каждый выпуск пересматривает ряд целиком: не смешивайте значения разных выпусков.\n":        "\nEstimates are as of July 1 of each year. Each vintage revises the whole series: do not mix values from different vintages.\n",
+	"\nПрочерк означает, что значение скрыто для защиты конфиденциальности или не публикуется.\n":                                           "\nA dash means the value is withheld to protect confidentiality or is not published.\n",
+	"MOE указаны для уровня доверия 90%; MOE итогов приближенно рассчитаны как корень из суммы квадратов. Отток за рубеж не публикуется.\n": "MOEs are at the 90% confidence level; MOEs of the totals are approximated as the root of the sum of squares. Outflows abroad are not published.\n",
+	"Медианы рассчитаны интерполяцией по суммированным интервальным таблицам:\n\n":                                                          "Medians were computed by interpolation over the summed binned tables:\n\n",
+	"Медианы, проценты и коэффициенты нельзя получить суммированием:\n\n":                                                                   "Medians, percentages and ratios cannot be obtained by summing:\n\n",
+	"Сравнение этих переменных между указанными выпусками может быть некорректным:\n\n":                                                     "Comparing these variables across the specified releases may be invalid:\n\n",
+	"Нет опубликованных потоков\n\n":                         "No published flows\n\n",
+	"Набор данных: %s %s, уровень: %s, географий: %d\n\n":    "Dataset: %s %s, level: %s, geographies: %d\n\n",
+	"Объединено географий уровня %s: %d\n\n":                 "Combined geographies at the %s level: %d\n\n",
+	"Показаны %s значения: %d из %d географий уровня %s\n\n": "Showing %s values: %d of %d geographies at the %s level\n\n",
+	"Только в переписи %s (%d): %s\n\n":                      "Only in the %s census (%d): %s\n\n",
+	"  - %s, %s (штат %s, округ %s)\n":                       "  - %s, %s (state %s, county %s)\n",
+	"%s (округ %s, штат %s)":                                 "%s (county %s, state %s)",
+	"%s (штат %s)":                                           "%s (state %s)",
+	"- **ID набора**: %s\n":                                  "- **Dataset ID**: %s\n",
+	"- **Z**: %s (критическое значение 1.645)\n":             "- **Z**: %s (critical value 1.645)\n",
+	"- **Всего наблюдений**: %s\n":                           "- **Total observations**: %s\n",
+	"- **Группа**: %s\n":                                     "- **Group**: %s\n",
+	"- **Доступные годы**: ":                                 "- **Available years**: ",
+	"- **Квантиль %s**: %s (метод: %s)\n":                    "- **Quantile %s**: %s (method: %s)\n",
+	"- **Код CBSA**: %s\n":                                   "- **CBSA code**: %s\n",
+	"- **Концепция**: %s\n":                                  "- **Concept**: %s\n",
+	"- **Округа** (%d):\n":                                   "- **Counties** (%d):\n",
+	"- **Описание**: %s\n":                                   "- **Description**: %s\n",
+	"- **Ошибка**: %s\n\n":                                   "- **Error**: %s\n\n",
+	"- **Поддержка подстановочных знаков**: %t\n":            "- **Wildcard support**: %t\n",
+	"- **Разность (1 − 2)**: %s ±%s\n":                       "- **Difference (1 − 2)**: %s ±%s\n",
+	"- **Тип**: %s\n":                                        "- **Type**: %s\n",
+	"- **Требуется для**: %s\n":                              "- **Required for**: %s\n",
+	"- **Учтено пересечение периодов**: %s общих лет\n":      "- **Period overlap accounted for**: %s of shared years\n",
+	"- Всего отток: %s\n":                                    "- Total outflow: %s\n",
+	"- Всего приток: %s\n":                                   "- Total inflow: %s\n",
+	"- Географий-контрагентов: %d\n\n":                       "- Counterpart geographies: %d\n\n",
+	"- Чистая миграция: %s\n":                                "- Net migration: %s\n",
+	"наибольшие":                                             "largest",
+	"наименьшие":                                             "smallest",
+
+	// Заголовки таблиц
+	" Выручка, тыс. $ |":   " Receipts, $1,000 |",
+	"| GEOID | Регион |":   "| GEOID | Region |",
+	"| GEOID | Регион |\n": "| GEOID | Region |\n",
+	"| GEOID | Регион | ":  "| GEOID | Region | ",
+	"| GEOID | Регион | NAICS | Отрасль | Предприятий | Занятых | Фонд оплаты труда, тыс. $ |": "| GEOID | Region | NAICS | Industry | Establishments | Employees | Payroll, $1,000 |",
+	"| GEOID | Регион | Период |":                                      "| GEOID | Region | Period |",
+	"| Код | Название | Уровень |\n":                                   "| Code | Title | Level |\n",
+	"| Контрагент | GEOID | Приток | Отток | Чистая миграция |\n":      "| Counterpart | GEOID | Inflow | Outflow | Net migration |\n",
+	"| Место | GEOID | Регион | Значение | MOE (90%) | Процентиль |\n": "| Rank | GEOID | Region | Value | MOE (90%) | Percentile |\n",
+	"| Оценка | Регион | Год | Значение | MOE (90%) |\n":               "| Estimate | Region | Year | Value | MOE (90%) |\n",
+	"| Переменная | Описание | Сумма | MOE (90%) |\n":                  "| Variable | Description | Sum | MOE (90%) |\n",
+	"| Переменная | Описание | Таблица | Медиана | Метод |\n":          "| Variable | Description | Table | Median | Method |\n",
+	"| Переменная | Показатель | Оценка | Процент |\n":                 "| Variable | Measure | Estimate | Percent |\n",
+	"| Показатель | %s | %s | Изменение | Изменение, %% |\n":           "| Measure | %s | %s | Change | Change, %% |\n",
+	"| Регион | Население |\n":                                         "| Region | Population |\n",
+	"Изменение | Изменение, % |\n":                                     "Change | Change, % |\n",
+
+	// Подписи столбцов и значений
+	"GEOID контрагента":            "Counterpart GEOID",
+	"GEOID региона":                "Region GEOID",
+	"MOE оттока":                   "Outflow MOE",
+	"MOE притока":                  "Inflow MOE",
+	"MOE процента":                 "Percent MOE",
+	"MOE разности":                 "Difference MOE",
+	"MOE чистой миграции":          "Net migration MOE",
+	"Всего":                        "Total",
+	"Выручка, тыс. $":              "Receipts, $1,000",
+	"Географий":                    "Geographies",
+	"Географический уровень GEOID": "GEOID geography level",
+	"География":                    "Geography",
+	"Год":                          "Year",
+	"Доля, %":                      "Share, %",
+	"Доступные годы":               "Available years",
+	"Занятые":                      "Employees",
+	"Значение":                     "Value",
+	"Значимо":                      "Significant",
+	"Изменение":                    "Change",
+	"Изменение, %":                 "Change, %",
+	"Имя переменной":               "Variable name",
+	"Квантиль":                     "Quantile",
+	"Код":                          "Code",
+	"Код CBSA":                     "CBSA code",
+	"Код FIPS округа (пусто для штатов)": "County FIPS code (empty for states)",
+	"Код FIPS штата":                     "State FIPS code",
+	"Код NAICS":                          "NAICS code",
+	"Компоненты изменения численности населения": "Components of population change",
+	"Контрагент": "Counterpart",
+	"Концепция":  "Concept",
+	"Крупнейшие источники притока":  "Largest sources of inflow",
+	"Крупнейшие направления оттока": "Largest destinations of outflow",
+	"Место":                                                        "Rank",
+	"Метод":                                                        "Method",
+	"Название":                                                     "Title",
+	"Название отрасли":                                             "Industry title",
+	"Название региона":                                             "Region name",
+	"Направление":                                                  "Direction",
+	"Население (B01001_001E)":                                      "Population (B01001_001E)",
+	"Нет данных":                                                   "No data",
+	"Нет данных для агрегации":                                     "No data to aggregate",
+	"Нет данных для оценки квантилей":                              "No data to estimate quantiles",
+	"Нет данных для рейтинга":                                      "No data to rank",
+	"Нет данных для сравнения":                                     "No data to compare",
+	"Нет данных для сравнения оценок":                              "No data to compare estimates",
+	"Нет данных для сравнения переписей":                           "No data to compare censuses",
+	"Нет данных о географических уровнях":                          "No geography level data",
+	"Нет данных о миграционных потоках":                            "No migration flow data",
+	"Нет данных о наборах данных":                                  "No dataset data",
+	"Нет данных о населении":                                       "No population data",
+	"Нет данных о переменных":                                      "No variable data",
+	"Нет данных о предприятиях":                                    "No business data",
+	"Нет данных о статистических ареалах":                          "No statistical area data",
+	"Нет данных переписи":                                          "No census data",
+	"Нет данных профиля":                                           "No profile data",
+	"Нет информации":                                               "No information",
+	"Нет оценок численности населения":                             "No population estimates",
+	"нет данных":                                                   "no data",
+	"Обязательные родительские уровни":                             "Required parent levels",
+	"Округа ареала (объекты state_fips, county_fips, name, state)": "Counties of the area (objects with state_fips, county_fips, name, state)",
+	"Описание":                                                     "Description",
+	"Открытый интервал":                                            "Open-ended bin",
+	"Отрасль":                                                      "Industry",
+	"Отток":                                                        "Outflow",
+	"Оценка":                                                       "Estimate",
+	"Оценки численности населения":                                 "Population estimates",
+	"Переменная":                                                   "Variable",
+	"Период":                                                       "Period",
+	"Поддержка символа \"*\"":                                      "Supports \"*\"",
+	"Подпись":                                                      "Label",
+	"Показатель":                                                   "Measure",
+	"Предприятия":                                                  "Establishments",
+	"Приток":                                                       "Inflow",
+	"Процент":                                                      "Percent",
+	"Процентиль":                                                   "Percentile",
+	"Разность":                                                     "Difference",
+	"Сумма":                                                        "Sum",
+	"Таблица":                                                      "Table",
+	"Таблица профиля":                                              "Profile table",
+	"Тип ареала":                                                   "Area type",
+	"Уровень":                                                      "Level",
+	"Уровень классификатора (2 - сектор)":                          "Classification level (2 - sector)",
+	"Фонд оплаты труда, тыс. $":                                    "Payroll, $1,000",
+	"Численность населения по характеристикам": "Population by characteristics",
+	"Чистая миграция":        "Net migration",
+	"Экономическая перепись": "Economic Census",
+
+	// Показатели десятилетней переписи
+	"Все население":                           "Total population",
+	"Только белые":                            "White alone",
+	"Только черные или афроамериканцы":        "Black or African American alone",
+	"Только индейцы и коренные жители Аляски": "American Indian and Alaska Native alone",
+	"Только азиаты":                           "Asian alone",
+	"Только коренные гавайцы и жители островов Тихого океана": "Native Hawaiian and Other Pacific Islander alone",
+	"Только другая раса":                                                "Some Other Race alone",
+	"Две расы и более":                                                  "Two or more races",
+	"Испаноязычные или латиноамериканцы":                                "Hispanic or Latino",
+	"Не испаноязычные":                                                  "Not Hispanic or Latino",
+	"Только белые, не испаноязычные":                                    "White alone, not Hispanic or Latino",
+	"Всего жилых единиц":                                                "Total housing units",
+	"Занятые жилые единицы":                                             "Occupied housing units",
+	"Пустующие жилые единицы":                                           "Vacant housing units",
+	"Перепись 2020, данные для перераспределения округов (P.L. 94-171)": "2020 Census, Redistricting Data (P.L. 94-171)",
+	"Перепись 2020, демографические и жилищные характеристики (DHC)":    "2020 Census, Demographic and Housing Characteristics (DHC)",
+	"Перепись 2010, данные для перераспределения округов (P.L. 94-171)": "2010 Census, Redistricting Data (P.L. 94-171)",
+	"Перепись 2010, Summary File 1":                                     "2010 Census, Summary File 1",
+
+	// Строки профилей ACS
+	"Социальные характеристики":                       "Social characteristics",
+	"Экономические характеристики":                    "Economic characteristics",
+	"Жилищные характеристики":                         "Housing characteristics",
+	"Демографические характеристики":                  "Demographic characteristics",
+	"Всего домохозяйств":                              "Total households",
+	"Средний размер домохозяйства":                    "Average household size",
+	"Средний размер семьи":                            "Average family size",
+	"Среднее образование или выше (25 лет и старше)":  "High school graduate or higher (25 years and over)",
+	"Степень бакалавра или выше (25 лет и старше)":    "Bachelor's degree or higher (25 years and over)",
+	"Домохозяйства с компьютером":                     "Households with a computer",
+	"Домохозяйства с широкополосным интернетом":       "Households with a broadband Internet subscription",
+	"Население 16 лет и старше":                       "Population 16 years and over",
+	"В составе рабочей силы":                          "In labor force",
+	"Уровень безработицы":                             "Unemployment rate",
+	"Среднее время в пути на работу (минут)":          "Mean travel time to work (minutes)",
+	"Медианный доход домохозяйства (долларов)":        "Median household income (dollars)",
+	"Средний доход домохозяйства (долларов)":          "Mean household income (dollars)",
+	"Доход на душу населения (долларов)":              "Per capita income (dollars)",
+	"С медицинской страховкой":                        "With health insurance coverage",
+	"Без медицинской страховки":                       "No health insurance coverage",
+	"Семьи с доходом ниже черты бедности":             "Families with income below the poverty level",
+	"Все жители с доходом ниже черты бедности":        "All people with income below the poverty level",
+	"Доля пустующего жилья владельцев":                "Homeowner vacancy rate",
+	"Доля пустующего арендного жилья":                 "Rental vacancy rate",
+	"Занято владельцами":                              "Owner-occupied",
+	"Занято арендаторами":                             "Renter-occupied",
+	"Медианная стоимость жилья (долларов)":            "Median home value (dollars)",
+	"Медианная валовая арендная плата (долларов)":     "Median gross rent (dollars)",
+	"Всего населения":                                 "Total population",
+	"Мужчины":                                         "Male",
+	"Женщины":                                         "Female",
+	"Медианный возраст (лет)":                         "Median age (years)",
+	"18 лет и старше":                                 "18 years and over",
+	"65 лет и старше":                                 "65 years and over",
+	"Испаноязычные или латиноамериканцы (любой расы)": "Hispanic or Latino (of any race)",
+	"Белые, не испаноязычные":                         "White, not Hispanic or Latino",
+	"Черные или афроамериканцы, не испаноязычные":     "Black or African American, not Hispanic or Latino",
+	"Азиаты, не испаноязычные":                        "Asian, not Hispanic or Latino",
+
+	// Оценки численности населения PEP
+	"Население":              "Population",
+	"Рождения":               "Births",
+	"Смерти":                 "Deaths",
+	"Естественный прирост":   "Natural increase",
+	"Международная миграция": "International migration",
+	"Внутренняя миграция":    "Domestic migration",
+	"Возрастная группа":      "Age group",
+	"Пол":                    "Sex",
+	"Раса":                   "Race",
+	"Испаноязычное происхождение": "Hispanic origin",
+	"Оба пола":        "Both sexes",
+	"Все":             "All",
+	"Испаноязычные":   "Hispanic",
+	"Все расы":        "All races",
+	"Все возрасты":    "All ages",
+	"0-4 лет":         "Age 0-4",
+	"5-9 лет":         "Age 5-9",
+	"10-14 лет":       "Age 10-14",
+	"15-19 лет":       "Age 15-19",
+	"20-24 лет":       "Age 20-24",
+	"25-29 лет":       "Age 25-29",
+	"30-34 лет":       "Age 30-34",
+	"35-39 лет":       "Age 35-39",
+	"40-44 лет":       "Age 40-44",
+	"45-49 лет":       "Age 45-49",
+	"50-54 лет":       "Age 50-54",
+	"55-59 лет":       "Age 55-59",
+	"60-64 лет":       "Age 60-64",
+	"65-69 лет":       "Age 65-69",
+	"70-74 лет":       "Age 70-74",
+	"75-79 лет":       "Age 75-79",
+	"80-84 лет":       "Age 80-84",
+	"85 лет и старше": "Age 85 and over",
+
+	// Причины исключения неаддитивных переменных
+	"медиана":                      "median",
+	"среднее значение":             "mean",
+	"показатель на душу населения": "per capita measure",
+	"процент":                      "percentage",
+	"коэффициент":                  "rate",
+	"отношение":                    "ratio",
+	"индекс":                       "index",
+
+	// Ошибки пакета census
+	"%s не является каталогом":                                    "%s is not a directory",
+	"API вернул пустой результат":                                 "API returned an empty result",
+	"API вернул статус %d":                                        "API returned status %d",
+	"API вернул статус %d для %s":                                 "API returned status %d for %s",
+	"GEOID %q не соответствует уровню %s (ожидается %d символов)": "GEOID %q does not match level %s (%d characters expected)",
+	"NAICS %s: %w": "NAICS %s: %w",
+	"агрегация не поддерживается для географического уровня %q": "aggregation is not supported for geography level %q",
+	"в архиве нет файла %s": "archive has no file %s",
+	"в выпуске %s %s ZCTA не вложены в штаты, уберите фильтр по штату":    "in release %s %s ZCTAs are not nested in states, remove the state filter",
+	"в выпуске %s используются округа %d-го созыва Конгресса, а не %d-го": "release %s uses districts of Congress %d, not %d",
+	"в данных нет числовых столбцов для диаграммы":                        "data has no numeric columns for the chart",
+	"в таблице NAICS нет строки заголовков":                               "NAICS table has no header row",
+	"в файле делимитации CBSA нет строки заголовков":                      "CBSA delineation file has no header row",
+	"в каталоге %s нет файлов границ TIGER/Line (shapefile или GeoJSON)":  "directory %s has no TIGER/Line boundary files (shapefile or GeoJSON)",
+	"вид данных %q недоступен в выпуске timeseries":                       "data kind %q is not available in the timeseries release",
+	"вторая оценка: %w": "second estimate: %w",
+	"выпуск переписи %s %s не поддерживается; доступны: %s":              "census release %s %s is not supported; available: %s",
+	"вычисляемый столбец должен иметь вид 'имя = выражение': %q":         "computed column must look like 'name = expression': %q",
+	"географический уровень %q не поддерживается для сравнения выпусков": "geography level %q is not supported for release comparison",
+	"данные по отраслям NAICS поддерживаются с 2012 года, запрошен %s":   "NAICS industry data is supported since 2012, requested %s",
+	"деление на ноль":                                                                                "division by zero",
+	"деление на нулевую оценку":                                                                      "division by a zero estimate",
+	"диаграмма %q недоступна для этого результата":                                                   "chart %q is not available for this result",
+	"для данных о населении доступна только столбчатая диаграмма":                                    "only the bar chart is available for population data",
+	"для линейной диаграммы нужен столбец времени (%s)":                                              "the line chart needs a time column (%s)",
+	"для половозрастной пирамиды нужны переменные B01001_003E-B01001_049E (например, group(B01001))": "the population pyramid needs variables B01001_003E-B01001_049E (for example, group(B01001))",
+	"для проверки значимости необходимы MOE обеих оценок":                                            "the significance test needs the MOE of both estimates",
+	"для сравнения выпусков доступна только линейная диаграмма":                                      "only the line chart is available for release comparison",
+	"для сравнения необходимо указать не менее двух лет":                                             "at least two years are required for comparison",
+	"для уровня block в фильтре географии необходимо указать конкретный код %s":                      "for the block level the geography filter must specify a particular %s code",
+	"запись %d shapefile повреждена":                                                                 "shapefile record %d is corrupted",
+	"запись %d shapefile: %w":                                                   "shapefile record %d: %w",
+	"запись %d файла атрибутов .dbf повреждена":                                 "record %d of the .dbf attribute file is corrupted",
+	"запись полигона повреждена":                                                "polygon record is corrupted",
+	"запросу %q соответствует несколько ареалов: %s":                            "query %q matches several areas: %s",
+	"каталог границ недоступен: %w":                                             "boundary directory is unavailable: %w",
+	"квантиль должен быть в интервале (0, 1): %v":                               "quantile must be in the interval (0, 1): %v",
+	"код NAICS должен состоять из 2-6 цифр: %q":                                 "NAICS code must have 2-6 digits: %q",
+	"код ZCTA должен состоять из 5 цифр: %q":                                    "ZCTA code must have 5 digits: %q",
+	"компоненты изменения численности доступны в Census API для выпусков до %d": "components of population change are available in the Census API for releases up to %d",
+	"конечный год %d меньше начального %d":                                      "end year %d is less than start year %d",
+	"координаты спроецированы, ожидаются долгота и широта":                      "coordinates are projected, longitude and latitude expected",
+	"коэффициент дизайна должен быть положительным":                             "design factor must be positive",
+	"миграционные потоки поддерживаются для уровней county и %s":                "migration flows are supported for the county and %s levels",
+	"набор данных %q не поддерживается; доступны: %s, %s":                       "dataset %q is not supported; available: %s, %s",
+	"набор данных %q не является профилем ACS (например, %q)":                   "dataset %q is not an ACS profile (for example, %q)",
+	"не удалось найти интервал квантиля %v":                                     "could not find the interval of quantile %v",
+	"не удалось определить долларовые переменные: %w":                           "could not determine dollar variables: %w",
+	"не удалось определить штат в идентификаторе округа %q":                     "could not determine the state in district identifier %q",
+	"не удалось получить данные ни для одной из указанных географий":            "could not get data for any of the specified geographies",
+	"не удалось получить данные ни за один из запрошенных годов":                "could not get data for any of the requested years",
+	"не удалось получить описания переменных: %w":                               "could not get variable descriptions: %w",
+	"неизвестная группа показателей %q; доступны: %s":                           "unknown indicator group %q; available: %s",
+	"неизвестная операция %q":                                                   "unknown operation %q",
+	"неизвестная функция %s":                                                    "unknown function %s",
+	"неизвестная характеристика %q; доступны: AGEGROUP, SEX, RACE, HISP":        "unknown characteristic %q; available: AGEGROUP, SEX, RACE, HISP",
+	"неизвестный вид данных PEP %q; доступны: %s":                               "unknown PEP data kind %q; available: %s",
+	"неизвестный вид диаграммы %q; доступны: bar, line, pyramid":                "unknown chart kind %q; available: bar, line, pyramid",
+	"неизвестный индекс цен %q; доступны: %s":                                   "unknown price index %q; available: %s",
+	"неизвестный метод интерполяции: %q":                                        "unknown interpolation method: %q",
+	"неизвестный тип округа: %q (допустимо: congressional, upper, lower)":       "unknown district type: %q (allowed: congressional, upper, lower)",
+	"неизвестный формат %q; доступны: %s":                                       "unknown format %q; available: %s",
+	"некорректная доля пересечения периодов: %v":                                "invalid period overlap share: %v",
+	"некорректная строка %d в таблице NAICS":                                    "invalid line %d in the NAICS table",
+	"некорректная строка %d в таблице индексов цен":                             "invalid line %d in the price index table",
+	"некорректная строка %d в файле делимитации CBSA":                           "invalid line %d in the CBSA delineation file",
+	"некорректное значение индекса в строке %d: %w":                             "invalid index value in line %d: %w",
+	"некорректное имя вычисляемого столбца: %q":                                 "invalid computed column name: %q",
+	"некорректное число %q":                                                     "invalid number %q",
+	"некорректный выпуск PEP %q: ожидается год или %q":                          "invalid PEP release %q: a year or %q expected",
+	"некорректный год: %q":                                                      "invalid year: %q",
+	"некорректный конечный год: %q":                                             "invalid end year: %q",
+	"некорректный начальный год: %q":                                            "invalid start year: %q",
+	"некорректный номер округа Конгресса в %q":                                  "invalid congressional district number in %q",
+	"некорректный номер округа легислатуры в %q":                                "invalid legislative district number in %q",
+	"нельзя суммировать неаддитивные переменные: %s":                            "non-additive variables cannot be summed: %s",
+	"необходимо указать географический уровень":                                 "geography level is required",
+	"необходимо указать год":                                                    "year is required",
+	"необходимо указать год или созыв Конгресса":                                "year or Congress is required",
+	"необходимо указать набор данных":                                           "dataset is required",
+	"необходимо указать набор данных и год":                                     "dataset and year are required",
+	"необходимо указать название статистического ареала":                        "statistical area name is required",
+	"необходимо указать текст запроса":                                          "query text is required",
+	"необходимо указать хотя бы один GEOID":                                     "at least one GEOID is required",
+	"необходимо указать хотя бы один код ZCTA":                                  "at least one ZCTA code is required",
+	"необходимо указать хотя бы один округ":                                     "at least one district is required",
+	"необходимо указать хотя бы один статистический ареал":                      "at least one statistical area is required",
+	"необходимо указать хотя бы одну переменную":                                "at least one variable is required",
+	"необходимо указать хотя бы одну переменную помимо NAME":                    "at least one variable besides NAME is required",
+	"необходимо указать числовую переменную для рейтинга":                       "a numeric variable for ranking is required",
+	"неожиданный конец выражения":                                               "unexpected end of expression",
+	"неожиданный символ %q":                                                     "unexpected character %q",
+	"неподдерживаемый тип фигуры %d: ожидаются полигоны":                        "unsupported shape type %d: polygons expected",
+	"нет выпусков ACS для %d-го созыва Конгресса":                               "no ACS releases for Congress %d",
+	"нет географий с числовым значением переменной %s":                          "no geographies with a numeric value of variable %s",
+	"нет данных для диаграммы":                                                  "no data for the chart",
+	"нет данных о миграционных потоках":                                         "no migration flow data",
+	"нет значения индекса %s за %s год (доступны %s-%s)":                        "no %s index value for %s (available %s-%s)",
+	"нет значения переменной %s":                                                "no value of variable %s",
+	"нет файла атрибутов .dbf: %w":                                              "no .dbf attribute file: %w",
+	"ожидалась закрывающая скобка":                                              "closing parenthesis expected",
+	"ожидалась запятая или закрывающая скобка в вызове %s":                      "comma or closing parenthesis expected in call to %s",
+	"оператор '/' между двумя оценками допустим только для части и итога ее таблицы (например, B17001_002E / B17001_001E); для других оценок укажите prop(a, b) для доли или ratio(a, b) для отношения": "the '/' operator between two estimates is allowed only for a part and the total of its table (for example, B17001_002E / B17001_001E); for other estimates use prop(a, b) for a proportion or ratio(a, b) for a ratio",
+	"оценка %s для %s подавлена или отсутствует":           "estimate %s for %s is suppressed or missing",
+	"ошибка в выражении столбца %s: %w":                    "error in the expression of column %s: %w",
+	"ошибка в выражении столбца %s: неожиданный символ %q": "error in the expression of column %s: unexpected character %q",
+	"ошибка при декодировании ответа: %w":                  "error decoding response: %w",
+	"ошибка при отправке запроса: %w":                      "error sending request: %w",
+	"ошибка при поиске файлов границ: %w":                  "error searching for boundary files: %w",
+	"ошибка при чтении таблицы NAICS: %w":                  "error reading the NAICS table: %w",
+	"ошибка при чтении таблицы индексов цен: %w":           "error reading the price index table: %w",
+	"ошибка при чтении файла делимитации CBSA: %w":         "error reading the CBSA delineation file: %w",
+	"ошибка разбора GeoJSON: %w":                           "error parsing GeoJSON: %w",
+	"первая оценка: %w":                                    "first estimate: %w",
+	"переменная %q отсутствует в сравнении выпусков":       "variable %q is missing from the release comparison",
+	"переменная %s не является оценкой ACS с MOE":          "variable %s is not an ACS estimate with MOE",
+	"переменная окружения CENSUS_API_KEY не установлена":   "environment variable CENSUS_API_KEY is not set",
+	"перепись %s: %w": "census %s: %w",
+	"половозрастная пирамида строится для одной географии, получено строк: %d": "the population pyramid is built for one geography, rows received: %d",
+	"распределение не содержит наблюдений":                                     "the distribution has no observations",
+	"слишком большой диапазон лет: не более %d выпусков":                       "year range is too large: at most %d releases",
+	"слишком много выпусков: не более %d":                                      "too many releases: at most %d",
+	"среди запрошенных переменных нет суммируемых":                             "none of the requested variables can be summed",
+	"статистический ареал не найден: %q":                                       "statistical area not found: %q",
+	"столбец %q отсутствует или не является числовым":                          "column %q is missing or not numeric",
+	"таблица %q не поддерживается; доступны: %s":                               "table %q is not supported; available: %s",
+	"таблица профиля %q не поддерживается; доступны: %s":                       "profile table %q is not supported; available: %s",
+	"файл атрибутов .dbf поврежден":                                            ".dbf attribute file is corrupted",
+	"файл не является shapefile":                                               "file is not a shapefile",
+	"фильтр географии должен выбирать одну географию уровня %s":                "the geography filter must select one geography of level %s",
+	"фильтр географии должен выбирать ровно одну географию, получено %d":       "the geography filter must select exactly one geography, got %d",
+	"функция %s принимает %d аргумента":                                        "function %s takes %d arguments",
+	"характеристики населения доступны в Census API для выпусков до %d":        "population characteristics are available in the Census API for releases up to %d",
+	"число фигур (%d) не совпадает с числом записей атрибутов (%d)":            "number of shapes (%d) does not match the number of attribute records (%d)",
+
+	// Предупреждения результатов
+	"Границы уровня %s пересматриваются к каждой переписи: географии сопоставлены по GEOID, и одинаковый GEOID не гарантирует одинаковую территорию. Для точного сравнения используйте файлы соответствия (relationship files) Census Bureau.": "Boundaries of level %s are revised for every census: geographies are matched by GEOID, and the same GEOID does not guarantee the same territory. For an exact comparison use the Census Bureau relationship files.",
+	"Медиану %s не удалось переоценить: %w": "Median %s could not be re-estimated: %w",
+	"Медиана %s переоценена по интервалам таблицы %s без MOE: для ее расчета укажите коэффициент дизайна ACS":           "Median %s was re-estimated from the brackets of table %s without a MOE: specify the ACS design factor to compute it",
+	"Медиану %s не удалось переоценить: интервалы таблицы %s подавлены или отсутствуют":                                 "Median %s could not be re-estimated: the brackets of table %s are suppressed or missing",
+	"Не удалось получить описания переменных: проверка на медианы и коэффициенты выполнена только по именам переменных": "Could not get variable descriptions: medians and ratios were detected by variable names only",
+	"Переменная %s подавлена или отсутствует для %d из %d географий: сумма неполная":                                    "Variable %s is suppressed or missing for %d of %d geographies: the sum is incomplete",
+	"значения интервалов подавлены или отсутствуют":                                                                     "bracket values are suppressed or missing",
+	"переменная отсутствует": "variable is absent",
+}
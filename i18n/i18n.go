@@ -0,0 +1,183 @@
+// Package i18n содержит каталог сообщений сервера на русском и английском языках.
+//
+// Каталог построен по принципу gettext: ключом сообщения служит исходный русский текст,
+// поэтому код остается читаемым, а для русского языка перевод не требуется. Строки
+// формата передаются в Sprintf целиком, и перевод должен сохранять порядок и типы
+// спецификаторов. Если перевод отсутствует, возвращается исходный текст.
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Константы для ключей логирования
+const (
+	key_locale  = "locale"
+	key_message = "message"
+)
+
+// Locale - язык сообщений
+type Locale string
+
+// Поддерживаемые языки
+const (
+	Russian Locale = "ru"
+	English Locale = "en"
+)
+
+// DefaultLocale - язык сообщений по умолчанию
+const DefaultLocale = Russian
+
+// catalogs - переводы сообщений по языкам; русский язык является исходным и каталога не имеет
+var catalogs = map[Locale]map[string]string{
+	English: english,
+}
+
+// Locales возвращает список поддерживаемых языков
+func Locales() []Locale {
+	return []Locale{Russian, English}
+}
+
+// ParseLocale разбирает код языка без учета регистра. Допускаются коды с регионом
+// ("en-US", "ru_RU"); пустая строка соответствует языку по умолчанию.
+func ParseLocale(value string) (Locale, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return DefaultLocale, nil
+	}
+
+	if i := strings.IndexAny(value, "-_"); i > 0 {
+		value = value[:i]
+	}
+
+	for _, locale := range Locales() {
+		if Locale(value) == locale {
+			return locale, nil
+		}
+	}
+	return "", fmt.Errorf("неподдерживаемый язык %q; доступны: ru, en", value)
+}
+
+// localeKey - ключ контекста для языка сообщений
+type localeKey struct{}
+
+// WithLocale возвращает контекст с языком сообщений
+func WithLocale(ctx context.Context, locale Locale) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// FromContext возвращает язык сообщений из контекста или язык по умолчанию
+func FromContext(ctx context.Context) Locale {
+	if locale, ok := ctx.Value(localeKey{}).(Locale); ok {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// Translate возвращает перевод сообщения на указанный язык
+func Translate(locale Locale, message string) string {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return message
+	}
+
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+
+	slog.Debug("Перевод сообщения не найден",
+		key_locale, locale,
+		key_message, message)
+	return message
+}
+
+// T возвращает перевод сообщения на язык из контекста
+func T(ctx context.Context, message string) string {
+	return Translate(FromContext(ctx), message)
+}
+
+// Sprintf переводит строку формата на язык из контекста и подставляет аргументы
+func Sprintf(ctx context.Context, format string, args ...interface{}) string {
+	return fmt.Sprintf(T(ctx, format), args...)
+}
+
+// Error - ошибка с сообщением из каталога. Error возвращает текст на исходном языке,
+// а ErrorMessage - на языке из контекста.
+type Error struct {
+	format string
+	args   []interface{}
+	err    error
+}
+
+// Errorf создает ошибку, строка формата которой является ключом каталога. Как и fmt.Errorf,
+// поддерживает %w для вложенной ошибки.
+func Errorf(format string, args ...interface{}) error {
+	return &Error{format: format, args: args, err: fmt.Errorf(format, args...)}
+}
+
+// Error возвращает текст ошибки на исходном языке
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap возвращает вложенную ошибку
+func (e *Error) Unwrap() error {
+	return errors.Unwrap(e.err)
+}
+
+// MarshalText возвращает текст ошибки на исходном языке, чтобы ошибки, сохраненные
+// в результатах, сериализовались в JSON строками
+func (e *Error) MarshalText() ([]byte, error) {
+	return []byte(e.Error()), nil
+}
+
+// localizedError - текст ошибки, уже переведенный на язык запроса
+type localizedError string
+
+// Error возвращает переведенный текст ошибки
+func (e localizedError) Error() string {
+	return string(e)
+}
+
+// MarshalText возвращает переведенный текст ошибки
+func (e localizedError) MarshalText() ([]byte, error) {
+	return []byte(e), nil
+}
+
+// Localize возвращает ошибку с текстом на языке из контекста; в JSON она сериализуется
+// переведенной строкой. Для nil возвращается nil.
+func Localize(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	return localizedError(ErrorMessage(ctx, err))
+}
+
+// ErrorMessage возвращает текст ошибки на языке из контекста. Ошибки Errorf переводятся
+// вместе с вложенными в аргументы ошибками; текст прочих ошибок возвращается без изменений.
+func ErrorMessage(ctx context.Context, err error) string {
+	return localizeError(FromContext(ctx), err)
+}
+
+// localizeError переводит текст ошибки на язык locale
+func localizeError(locale Locale, err error) string {
+	e, ok := err.(*Error)
+	if !ok {
+		return err.Error()
+	}
+
+	args := make([]interface{}, len(e.args))
+	for i, arg := range e.args {
+		if nested, ok := arg.(error); ok {
+			args[i] = localizeError(locale, nested)
+		} else {
+			args[i] = arg
+		}
+	}
+	// Вложенные ошибки уже переведены в строки, %w для Sprintf заменяется на %v
+	return fmt.Sprintf(strings.ReplaceAll(Translate(locale, e.format), "%w", "%v"), args...)
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"census_mcp/app"
+	"census_mcp/i18n"
 	"census_mcp/logger"
+	"census_mcp/mcp"
 	"flag"
 	"log/slog"
 	"os"
@@ -22,6 +24,13 @@ func main() {
 	var testMode bool
 	var apiKey string
 	var logLevelFlag string
+	var format string
+	var language string
+	var maxTokens int
+	var resource string
+	var boundaries string
+	var cbsaDelineation string
+	var naics string
 
 	flag.StringVar(&transport, "t", "stdio", "Transport type (stdio or sse)")
 	flag.StringVar(&transport, "transport", "stdio", "Transport type (stdio or sse)")
@@ -29,6 +38,13 @@ func main() {
 	flag.StringVar(&apiKey, "k", "", "Census API key (if not provided, will use CENSUS_API_KEY env var)")
 	flag.StringVar(&apiKey, "key", "", "Census API key (if not provided, will use CENSUS_API_KEY env var)")
 	flag.StringVar(&logLevelFlag, "log-level", "", "Log level (debug, info, warn, error)")
+	flag.StringVar(&format, "format", "markdown", "Default tool result format (markdown, plain, json, csv, tsv or geojson)")
+	flag.StringVar(&language, "lang", "ru", "Language of tool descriptions, results and messages (ru or en)")
+	flag.IntVar(&maxTokens, "max-tokens", mcp.DefaultMaxOutputTokens, "Approximate token budget of tabular tool results; longer results are paginated (0 disables)")
+	flag.StringVar(&resource, "resource", mcp.ResourceNone, "Default embedded resource with the full result data (none, json, csv, tsv or geojson)")
+	flag.StringVar(&boundaries, "boundaries", "", "Directory with TIGER/Line or cartographic boundary files (shapefile or GeoJSON) for geojson results")
+	flag.StringVar(&cbsaDelineation, "cbsa-delineation", "", "OMB CBSA delineation file (list1 saved as CSV) replacing the built-in extract of the largest areas")
+	flag.StringVar(&naics, "naics", "", "Census Bureau NAICS 2017 codes file (2-6 digit codes saved as CSV) replacing the built-in industry table")
 	flag.Parse()
 
 	// Настраиваем логирование
@@ -59,12 +75,37 @@ func main() {
 	slog.Info("- get_variables: получение списка переменных для набора данных")
 	slog.Info("- get_geography_levels: получение доступных географических уровней")
 	slog.Info("- get_custom_data: выполнение пользовательских запросов к Census API")
+	slog.Info("- get_zcta_data: получение данных по ZCTA (приближению почтовых индексов)")
+	slog.Info("- get_congressional_district_data: получение данных по округам Конгресса")
+	slog.Info("- get_state_legislative_district_data: получение данных по округам легислатур штатов")
+	slog.Info("- search_cbsa: поиск статистического ареала (CBSA) по названию")
+	slog.Info("- get_cbsa_data: получение данных по статистическим ареалам")
+	slog.Info("- get_cbsa_counties: получение списка округов статистического ареала")
+	slog.Info("- compare_years: сравнение переменных между выпусками данных")
+	slog.Info("- aggregate_geographies: объединение географий в пользовательский регион")
+	slog.Info("- estimate_quantiles: оценка медианы и квантилей по интервальным таблицам")
+	slog.Info("- rank_geographies: рейтинг географий по значению переменной")
+	slog.Info("- compare_estimates: проверка значимости различия двух оценок")
+	slog.Info("- get_profile: курируемая таблица профиля ACS (DP02-DP05)")
+	slog.Info("- get_decennial_counts: показатели десятилетней переписи вплоть до кварталов")
+	slog.Info("- compare_decennial: сравнение показателей переписей 2010 и 2020")
+	slog.Info("- get_population_estimates: ежегодные оценки численности населения PEP")
+	slog.Info("- search_naics: поиск отраслей NAICS по названию")
+	slog.Info("- get_business_patterns: статистика предприятий по отраслям NAICS (CBP, экономическая перепись)")
+	slog.Info("- get_migration_flows: миграционные потоки ACS между округами и ареалами")
 
 	// Конфигурация сервера
 	config := app.ServerConfig{
-		Transport: transport,
-		TestMode:  testMode,
-		APIKey:    apiKey,
+		Transport:       transport,
+		TestMode:        testMode,
+		APIKey:          apiKey,
+		Format:          format,
+		Locale:          i18n.Locale(language),
+		MaxOutputTokens: maxTokens,
+		Resource:        resource,
+		Boundaries:      boundaries,
+		CBSADelineation: cbsaDelineation,
+		NAICS:           naics,
 	}
 
 	slog.Debug("Создание сервера с конфигурацией",
@@ -2,8 +2,21 @@ package mcp
 
 import (
 	"census_mcp/census"
+	"census_mcp/i18n"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
@@ -22,8 +35,115 @@ const (
 	key_valid         = "valid"
 	key_dataset_valid = "dataset_valid"
 	key_year_valid    = "year_valid"
+	key_zctas         = "zctas"
+	key_chamber       = "chamber"
+	key_districts     = "districts"
+	key_congress      = "congress"
+	key_cbsas         = "cbsas"
+	key_cbsa          = "cbsa"
+	key_years         = "years"
+	key_geoids        = "geoids"
+	key_table         = "table"
+	key_quantiles     = "quantiles"
+	key_variable      = "variable"
+	key_limit         = "limit"
+	key_groups        = "groups"
+	key_kind          = "kind"
+	key_vintage       = "vintage"
+	key_naics         = "naics"
+	key_format        = "format"
+	key_language      = "language"
+	key_precision     = "precision"
+	key_offset        = "offset"
+	key_rows          = "rows"
+	key_tokens        = "tokens"
+	key_resource      = "resource"
+	key_chart         = "chart"
 )
 
+// Значения по умолчанию для инструмента данных по ZCTA
+const (
+	defaultZCTADataset = "acs/acs5"
+	defaultZCTAYear    = "2021"
+)
+
+// Значения по умолчанию для инструментов данных по избирательным округам
+const (
+	defaultDistrictDataset = "acs/acs5"
+	defaultDistrictYear    = "2022"
+)
+
+// Значения по умолчанию для инструмента данных по статистическим ареалам
+const (
+	defaultCBSADataset = "acs/acs1"
+	defaultCBSAYear    = "2021"
+)
+
+// Значения по умолчанию для инструмента агрегации географий
+const (
+	defaultAggregationDataset = "acs/acs5"
+	defaultAggregationYear    = "2021"
+)
+
+// Значения по умолчанию для инструмента оценки квантилей
+const (
+	defaultQuantileDataset = "acs/acs5"
+	defaultQuantileYear    = "2021"
+)
+
+// Значения по умолчанию для инструмента рейтинга географий
+const (
+	defaultRankDataset = "acs/acs5"
+	defaultRankYear    = "2021"
+)
+
+// Набор данных по умолчанию для инструмента проверки значимости
+const defaultSignificanceDataset = "acs/acs5"
+
+// Год по умолчанию для инструмента профилей ACS
+const defaultProfileYear = "2021"
+
+// Количество результатов поиска отраслей NAICS по умолчанию
+const defaultNAICSSearchLimit = 20
+
+// Значения по умолчанию для инструмента миграционных потоков
+const (
+	defaultFlowGeoLevel = "county"
+	defaultFlowYear     = "2020"
+)
+
+// DefaultMaxOutputTokens - примерный бюджет токенов результата табличных инструментов
+// по умолчанию; более длинные результаты усекаются и выдаются по страницам
+const DefaultMaxOutputTokens = 8000
+
+// charsPerToken - среднее число символов на токен для оценки размера результата
+const charsPerToken = 4
+
+// ResourceNone - значение аргумента "resource", отключающее встроенный ресурс с данными
+const ResourceNone = "none"
+
+// resourceMIMETypes - MIME-типы встроенных ресурсов с данными по машиночитаемым форматам
+var resourceMIMETypes = map[string]string{
+	census.FormatJSON:    "application/json",
+	census.FormatCSV:     "text/csv",
+	census.FormatTSV:     "text/tab-separated-values",
+	census.FormatGeoJSON: census.GeoJSONMIMEType,
+}
+
+// presentationArguments - аргументы, влияющие только на оформление результата. Они не входят
+// в отпечаток запроса, поэтому следующую страницу можно запросить в другом формате.
+var presentationArguments = map[string]bool{
+	"cursor":      true,
+	"maxTokens":   true,
+	"format":      true,
+	"precision":   true,
+	"language":    true,
+	"labels":      true,
+	"resource":    true,
+	"chart":       true,
+	"chartColumn": true,
+}
+
 // CensusToolHandler определяет интерфейс для обработчика инструментов Census MCP
 type CensusToolHandler interface {
 	// HandleGetStatePopulationTool обрабатывает запрос на получение данных о населении штата
@@ -40,20 +160,136 @@ type CensusToolHandler interface {
 	HandleGetGeographyLevelsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
 	// HandleGetCustomDataTool обрабатывает запрос на получение пользовательских данных
 	HandleGetCustomDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetZCTADataTool обрабатывает запрос на получение данных по ZCTA
+	HandleGetZCTADataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetCongressionalDistrictDataTool обрабатывает запрос на получение данных по округам Конгресса
+	HandleGetCongressionalDistrictDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetStateLegislativeDistrictDataTool обрабатывает запрос на получение данных по округам легислатур штатов
+	HandleGetStateLegislativeDistrictDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleSearchCBSATool обрабатывает запрос на поиск статистического ареала по названию
+	HandleSearchCBSATool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetCBSADataTool обрабатывает запрос на получение данных по статистическим ареалам
+	HandleGetCBSADataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetCBSACountiesTool обрабатывает запрос на получение списка округов статистического ареала
+	HandleGetCBSACountiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleCompareYearsTool обрабатывает запрос на сравнение переменных между выпусками
+	HandleCompareYearsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleAggregateGeographiesTool обрабатывает запрос на объединение географий в пользовательский регион
+	HandleAggregateGeographiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleEstimateQuantilesTool обрабатывает запрос на оценку медианы и квантилей по интервальной таблице
+	HandleEstimateQuantilesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleRankGeographiesTool обрабатывает запрос на построение рейтинга географий
+	HandleRankGeographiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleCompareEstimatesTool обрабатывает запрос на проверку значимости различия двух оценок
+	HandleCompareEstimatesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetProfileTool обрабатывает запрос на получение курируемой таблицы профиля ACS
+	HandleGetProfileTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetDecennialCountsTool обрабатывает запрос на получение показателей переписи
+	HandleGetDecennialCountsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleCompareDecennialTool обрабатывает запрос на сравнение показателей двух переписей
+	HandleCompareDecennialTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetPopulationEstimatesTool обрабатывает запрос на получение оценок численности населения PEP
+	HandleGetPopulationEstimatesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleSearchNAICSTool обрабатывает запрос на поиск отраслей NAICS
+	HandleSearchNAICSTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetBusinessPatternsTool обрабатывает запрос на получение статистики предприятий по отраслям
+	HandleGetBusinessPatternsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	// HandleGetMigrationFlowsTool обрабатывает запрос на получение миграционных потоков
+	HandleGetMigrationFlowsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
 }
 
 // CensusDefaultToolHandler - стандартная реализация обработчика инструментов
 type CensusDefaultToolHandler struct {
-	api       census.CensusAPIClient
-	formatter census.Formatter
+	api        census.CensusAPIClient
+	formatters *census.FormatterRegistry
+
+	// releases - описания и виды значений переменных по выпускам ("набор|год")
+	// для оформления чисел и подписей столбцов
+	releasesMu sync.Mutex
+	releases   map[string]*releaseVariables
+
+	// maxTokens - бюджет токенов результата табличных инструментов; 0 отключает усечение
+	maxTokens int
+	// resource - формат встроенного ресурса с данными по умолчанию (ResourceNone - без ресурса)
+	resource string
 }
 
-// NewCensusToolHandler создает новый экземпляр обработчика инструментов
-func NewCensusToolHandler(api census.CensusAPIClient, formatter census.Formatter) CensusToolHandler {
-	return &CensusDefaultToolHandler{
-		api:       api,
-		formatter: formatter,
+// HandlerOption задает необязательный параметр обработчика инструментов
+type HandlerOption func(*CensusDefaultToolHandler)
+
+// WithMaxOutputTokens задает примерный бюджет токенов результата табличных инструментов
+// (по умолчанию DefaultMaxOutputTokens); 0 отключает усечение результатов
+func WithMaxOutputTokens(tokens int) HandlerOption {
+	return func(h *CensusDefaultToolHandler) {
+		h.maxTokens = tokens
+	}
+}
+
+// releaseRetryInterval - время, в течение которого ошибка получения описаний переменных
+// выпуска хранится в кэше; после него описания запрашиваются снова
+const releaseRetryInterval = 5 * time.Minute
+
+// releaseVariables - описания переменных выпуска и виды их значений. Поля заполняются один раз
+// до закрытия канала ready; err содержит ошибку получения описаний.
+type releaseVariables struct {
+	ready       chan struct{}
+	definitions map[string]census.VariableInfo
+	kinds       map[string]census.ValueKind
+	err         error
+	fetched     time.Time
+}
+
+// expired сообщает, пора ли повторить запрос описаний после ошибки. Незавершенный
+// и успешный запросы не устаревают.
+func (r *releaseVariables) expired(now time.Time) bool {
+	select {
+	case <-r.ready:
+		return r.err != nil && now.Sub(r.fetched) >= releaseRetryInterval
+	default:
+		return false
+	}
+}
+
+// WithEmbeddedResource задает формат встроенного ресурса с полными данными результата
+// (census.FormatJSON, census.FormatCSV или census.FormatTSV) для вызовов без аргумента
+// "resource"; ResourceNone (по умолчанию) отключает ресурс
+func WithEmbeddedResource(format string) HandlerOption {
+	return func(h *CensusDefaultToolHandler) {
+		h.resource = format
+	}
+}
+
+// WithBoundaries подключает каталог границ географий к формату geojson: объекты FeatureCollection
+// получают геометрию из файлов TIGER/Line по GEOID
+func WithBoundaries(boundaries *census.BoundaryStore) HandlerOption {
+	return func(h *CensusDefaultToolHandler) {
+		h.formatters.Register(census.FormatGeoJSON, census.NewGeoJSONFormatter(boundaries))
+	}
+}
+
+// ValidateResourceFormat проверяет формат встроенного ресурса с данными
+func ValidateResourceFormat(format string) error {
+	if _, ok := resourceMIMETypes[format]; ok || format == ResourceNone || format == "" {
+		return nil
+	}
+	return i18n.Errorf("Неизвестный формат ресурса %q; доступны: %s", format,
+		strings.Join([]string{ResourceNone, census.FormatJSON, census.FormatCSV, census.FormatTSV, census.FormatGeoJSON}, ", "))
+}
+
+// NewCensusToolHandler создает новый экземпляр обработчика инструментов. formatter используется
+// для запросов без аргумента "format"; остальные форматы берутся из реестра census.FormatterRegistry.
+func NewCensusToolHandler(api census.CensusAPIClient, formatter census.Formatter, options ...HandlerOption) CensusToolHandler {
+	h := &CensusDefaultToolHandler{
+		api:        api,
+		formatters: census.NewFormatterRegistry(formatter),
+		releases:   make(map[string]*releaseVariables),
+		maxTokens:  DefaultMaxOutputTokens,
+		resource:   ResourceNone,
 	}
+	for _, option := range options {
+		option(h)
+	}
+	return h
 }
 
 // HandleGetStatePopulationTool обрабатывает запрос на получение данных о населении штата
@@ -63,6 +299,10 @@ func (h *CensusDefaultToolHandler) HandleGetStatePopulationTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента получения данных о населении штата")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	arguments := request.Params.Arguments
 	stateID, _ := arguments["stateID"].(string)
 
@@ -75,7 +315,7 @@ func (h *CensusDefaultToolHandler) HandleGetStatePopulationTool(
 		slog.ErrorContext(ctx, "Ошибка при получении данных о населении штата",
 			key_err, err,
 			key_state_id, stateID)
-		return mcp.NewToolResultError("Ошибка при получении данных о населении: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных о населении: ") + i18n.ErrorMessage(ctx, err)), nil
 	}
 
 	slog.DebugContext(ctx, "Получены данные о населении штата",
@@ -83,10 +323,7 @@ func (h *CensusDefaultToolHandler) HandleGetStatePopulationTool(
 		key_state_id, stateID)
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, population)
-
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	return h.formatResult(ctx, request, population), nil
 }
 
 // HandleGetCountyPopulationTool обрабатывает запрос на получение данных о населении округа
@@ -96,6 +333,10 @@ func (h *CensusDefaultToolHandler) HandleGetCountyPopulationTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента получения данных о населении округов")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	arguments := request.Params.Arguments
 	stateID, _ := arguments["stateID"].(string)
 
@@ -108,7 +349,7 @@ func (h *CensusDefaultToolHandler) HandleGetCountyPopulationTool(
 		slog.ErrorContext(ctx, "Ошибка при получении данных о населении округов",
 			key_err, err,
 			key_state_id, stateID)
-		return mcp.NewToolResultError("Ошибка при получении данных о населении округов: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных о населении округов: ") + i18n.ErrorMessage(ctx, err)), nil
 	}
 
 	slog.DebugContext(ctx, "Получены данные о населении округов",
@@ -116,10 +357,7 @@ func (h *CensusDefaultToolHandler) HandleGetCountyPopulationTool(
 		key_state_id, stateID)
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, population)
-
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	return h.formatResult(ctx, request, population), nil
 }
 
 // HandleSearchStateByNameTool обрабатывает запрос на поиск штата по названию
@@ -129,6 +367,10 @@ func (h *CensusDefaultToolHandler) HandleSearchStateByNameTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента поиска штата по названию")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	arguments := request.Params.Arguments
 	name, ok := arguments["name"].(string)
 
@@ -138,7 +380,7 @@ func (h *CensusDefaultToolHandler) HandleSearchStateByNameTool(
 
 	if !ok || name == "" {
 		slog.ErrorContext(ctx, "Отсутствует обязательный параметр name для поиска штата")
-		return mcp.NewToolResultError("Необходимо указать параметр 'name'"), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'name'")), nil
 	}
 
 	// Поиск штата по названию
@@ -147,7 +389,7 @@ func (h *CensusDefaultToolHandler) HandleSearchStateByNameTool(
 		slog.ErrorContext(ctx, "Ошибка при поиске штата по названию",
 			key_err, err,
 			key_search_name, name)
-		return mcp.NewToolResultError("Ошибка при поиске штата: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при поиске штата: ") + i18n.ErrorMessage(ctx, err)), nil
 	}
 
 	slog.DebugContext(ctx, "Результаты поиска штата по названию",
@@ -157,14 +399,12 @@ func (h *CensusDefaultToolHandler) HandleSearchStateByNameTool(
 	if len(states) == 0 {
 		slog.InfoContext(ctx, "Штаты не найдены по запросу",
 			key_name, name)
-		return mcp.NewToolResultText("Штаты не найдены по запросу: " + name), nil
+		// Пустой результат оформляется в запрошенном формате, пояснение передается отдельно
+		return h.formatResult(ctx, request, states, i18n.T(ctx, "Штаты не найдены по запросу: ")+name), nil
 	}
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, states)
-
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	return h.formatResult(ctx, request, states), nil
 }
 
 // HandleGetAvailableDatasetsTool обрабатывает запрос на получение доступных наборов данных
@@ -174,22 +414,23 @@ func (h *CensusDefaultToolHandler) HandleGetAvailableDatasetsTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента получения доступных наборов данных")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	// Получение данных о доступных наборах данных
 	datasets, err := h.api.GetAvailableDatasets()
 	if err != nil {
 		slog.ErrorContext(ctx, "Ошибка при получении данных о доступных наборах данных",
 			key_err, err)
-		return mcp.NewToolResultError("Ошибка при получении данных о доступных наборах данных: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных о доступных наборах данных: ") + i18n.ErrorMessage(ctx, err)), nil
 	}
 
 	slog.DebugContext(ctx, "Получены данные о доступных наборах данных",
 		key_count, len(datasets))
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, datasets)
-
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	return h.formatResult(ctx, request, datasets), nil
 }
 
 // HandleGetVariablesTool обрабатывает запрос на получение доступных переменных набора данных
@@ -199,6 +440,10 @@ func (h *CensusDefaultToolHandler) HandleGetVariablesTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента получения переменных набора данных")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	arguments := request.Params.Arguments
 	dataset, ok1 := arguments["dataset"].(string)
 	year, ok2 := arguments["year"].(string)
@@ -211,7 +456,7 @@ func (h *CensusDefaultToolHandler) HandleGetVariablesTool(
 
 	if !ok1 || !ok2 || dataset == "" || year == "" {
 		slog.ErrorContext(ctx, "Отсутствуют обязательные параметры для получения переменных набора данных")
-		return mcp.NewToolResultError("Необходимо указать параметры 'dataset' и 'year'"), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметры 'dataset' и 'year'")), nil
 	}
 
 	// Получение данных о доступных переменных
@@ -221,7 +466,7 @@ func (h *CensusDefaultToolHandler) HandleGetVariablesTool(
 			key_err, err,
 			key_dataset, dataset,
 			key_year, year)
-		return mcp.NewToolResultError("Ошибка при получении данных о доступных переменных: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных о доступных переменных: ") + i18n.ErrorMessage(ctx, err)), nil
 	}
 
 	slog.DebugContext(ctx, "Получены данные о доступных переменных",
@@ -230,10 +475,7 @@ func (h *CensusDefaultToolHandler) HandleGetVariablesTool(
 		key_year, year)
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, variables)
-
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	return h.formatResult(ctx, request, variables), nil
 }
 
 // HandleGetGeographyLevelsTool обрабатывает запрос на получение доступных географических уровней
@@ -243,6 +485,10 @@ func (h *CensusDefaultToolHandler) HandleGetGeographyLevelsTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента получения географических уровней")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	arguments := request.Params.Arguments
 	dataset, ok1 := arguments["dataset"].(string)
 	year, ok2 := arguments["year"].(string)
@@ -255,7 +501,7 @@ func (h *CensusDefaultToolHandler) HandleGetGeographyLevelsTool(
 
 	if !ok1 || !ok2 || dataset == "" || year == "" {
 		slog.ErrorContext(ctx, "Отсутствуют обязательные параметры для получения географических уровней")
-		return mcp.NewToolResultError("Необходимо указать параметры 'dataset' и 'year'"), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметры 'dataset' и 'year'")), nil
 	}
 
 	// Получение данных о доступных географических уровнях
@@ -265,7 +511,7 @@ func (h *CensusDefaultToolHandler) HandleGetGeographyLevelsTool(
 			key_err, err,
 			key_dataset, dataset,
 			key_year, year)
-		return mcp.NewToolResultError("Ошибка при получении данных о доступных географических уровнях: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных о доступных географических уровнях: ") + i18n.ErrorMessage(ctx, err)), nil
 	}
 
 	slog.DebugContext(ctx, "Получены данные о доступных географических уровнях",
@@ -274,10 +520,7 @@ func (h *CensusDefaultToolHandler) HandleGetGeographyLevelsTool(
 		key_year, year)
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, levels)
-
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	return h.formatResult(ctx, request, levels), nil
 }
 
 // HandleGetCustomDataTool обрабатывает запрос на получение пользовательских данных
@@ -287,6 +530,10 @@ func (h *CensusDefaultToolHandler) HandleGetCustomDataTool(
 ) (*mcp.CallToolResult, error) {
 	slog.InfoContext(ctx, "Обработка инструмента получения пользовательских данных")
 
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
 	arguments := request.Params.Arguments
 
 	// Извлечение обязательных параметров
@@ -296,7 +543,7 @@ func (h *CensusDefaultToolHandler) HandleGetCustomDataTool(
 	variables, ok4 := arguments["variables"].([]interface{})
 
 	if !ok1 || !ok2 || !ok3 || !ok4 || dataset == "" || year == "" || geoLevel == "" || len(variables) == 0 {
-		return mcp.NewToolResultError("Необходимо указать параметры 'dataset', 'year', 'geoLevel' и 'variables'"), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметры 'dataset', 'year', 'geoLevel' и 'variables'")), nil
 	}
 
 	// Преобразование списка переменных
@@ -307,19 +554,33 @@ func (h *CensusDefaultToolHandler) HandleGetCustomDataTool(
 		}
 	}
 
+	// Для профилей и тематических таблиц ACS к оценкам добавляются их процентные столбцы
+	// (DPxx_xxxxE -> DPxx_xxxxPE, S1701_C01_001E -> S1701_C03_001E); столбцы тематических
+	// таблиц определяются по описаниям переменных выпуска
+	var definitions map[string]census.VariableInfo
+	if census.IsSubjectDataset(dataset) {
+		if release := h.releaseVariables(ctx, dataset, year); release != nil {
+			definitions = release.definitions
+		}
+	}
+	varList = census.PairPercentVariables(dataset, varList, definitions)
+
 	// Извлечение и преобразование гео-фильтров
-	geoFilterMap := make(map[string]string)
-	if geoFilter, ok := arguments["geoFilter"].(map[string]interface{}); ok {
-		for k, v := range geoFilter {
-			if vs, ok := v.(string); ok {
-				geoFilterMap[k] = vs
-			}
+	geoFilterMap := geoFilterArgument(arguments["geoFilter"], geoLevel)
+
+	// Разбор вычисляемых столбцов
+	var derivedColumns []census.DerivedColumn
+	for _, definition := range stringList(arguments["derived"]) {
+		column, err := census.ParseDerivedColumn(definition)
+		if err != nil {
+			return mcp.NewToolResultError(i18n.T(ctx, "Ошибка в вычисляемом столбце: ") + i18n.ErrorMessage(ctx, err)), nil
 		}
-	} else {
-		// Если не указан фильтр, добавляем wildcard для текущего уровня
-		geoFilterMap[geoLevel] = "*"
+		derivedColumns = append(derivedColumns, column)
 	}
 
+	// Для вычисляемых столбцов запрашиваем исходные переменные и их MOE
+	varList, addedVariables := census.DerivedRequestVariables(varList, dataset, derivedColumns)
+
 	// Создание запроса пользовательских данных
 	customRequest := census.CustomDataRequest{
 		Variables: varList,
@@ -332,100 +593,2545 @@ func (h *CensusDefaultToolHandler) HandleGetCustomDataTool(
 	// Получение пользовательских данных
 	customData, err := h.api.GetCustomData(customRequest)
 	if err != nil {
-		return mcp.NewToolResultError("Ошибка при получении пользовательских данных: " + err.Error()), nil
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении пользовательских данных: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	// Пересчет долларовых переменных в постоянные доллары
+	var inflation *census.InflationAdjustment
+	if inflationYear, _ := arguments["inflationYear"].(string); inflationYear != "" {
+		series, _ := arguments["inflationSeries"].(string)
+		if series == "" {
+			series = census.CPISeriesURS
+		}
+
+		dollars, err := census.DollarVariables(h.api, dataset, year, varList)
+		if err != nil {
+			return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при пересчете в постоянные доллары: ") + i18n.ErrorMessage(ctx, err)), nil
+		}
+
+		factor, err := census.AdjustRowsForInflation(customData, dollars, series, year, inflationYear)
+		if err != nil {
+			return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при пересчете в постоянные доллары: ") + i18n.ErrorMessage(ctx, err)), nil
+		}
+
+		inflation = &census.InflationAdjustment{
+			Series:     series,
+			TargetYear: inflationYear,
+			Variables:  dollars,
+			Factors:    map[string]float64{year: factor},
+		}
+	}
+
+	// Вычисление производных показателей и удаление вспомогательных переменных
+	if len(derivedColumns) > 0 {
+		census.ApplyDerivedColumns(customData, derivedColumns)
+		for _, row := range customData {
+			for _, variable := range addedVariables {
+				delete(row, variable)
+			}
+		}
+	}
+
+	// Виды значений вычисляемых столбцов и их MOE для оформления чисел
+	derivedKinds := make(map[string]census.ValueKind, 2*len(derivedColumns))
+	columns := append([]string(nil), varList...)
+	for _, column := range derivedColumns {
+		derivedKinds[column.Name] = column.Kind()
+		derivedKinds[column.Name+"_moe"] = column.Kind()
+		columns = append(columns, column.Name, column.Name+"_moe")
 	}
+	ctx = h.withVariableKinds(ctx, request, dataset, year, derivedKinds)
+	ctx = h.withColumnLayout(ctx, request, dataset, year, columns)
 
 	// Форматирование результатов
-	result := h.formatter.Format(ctx, customData)
+	return h.formatResult(ctx, request, customData, census.InflationNote(ctx, inflation)), nil
+}
+
+// HandleGetZCTADataTool обрабатывает запрос на получение данных по ZCTA
+func (h *CensusDefaultToolHandler) HandleGetZCTADataTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения данных по ZCTA")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	zctas := stringList(arguments["zctas"])
+	if len(zctas) == 0 {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр zctas")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'zctas'")), nil
+	}
+
+	state, _ := arguments["state"].(string)
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultZCTADataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultZCTAYear
+	}
+
+	variables := stringList(arguments["variables"])
+	if len(variables) == 0 {
+		variables = []string{"NAME", "B01001_001E"}
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента получения данных по ZCTA",
+		key_zctas, zctas,
+		key_state_id, state,
+		key_dataset, dataset,
+		key_year, year)
+
+	// Получение данных по ZCTA
+	data, err := h.api.GetZCTAData(census.ZCTARequest{
+		ZCTAs:     zctas,
+		State:     state,
+		Variables: variables,
+		Dataset:   dataset,
+		Year:      year,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении данных по ZCTA",
+			key_err, err,
+			key_zctas, zctas)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных по ZCTA: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
 
-	// Возвращаем результат
-	return mcp.NewToolResultText(result), nil
+	slog.DebugContext(ctx, "Получены данные по ZCTA",
+		key_count, len(data),
+		key_zctas, zctas)
+
+	// Форматирование результатов с пояснением о природе ZCTA
+	ctx = h.withVariableKinds(ctx, request, dataset, year, nil)
+	ctx = h.withColumnLayout(ctx, request, dataset, year, variables)
+	return h.formatResult(ctx, request, data, i18n.T(ctx, census.ZCTANotice)), nil
 }
 
-// RegisterCensusTools регистрирует инструменты Census MCP
-func RegisterCensusTools(mcpServer *server.MCPServer, handler CensusToolHandler) {
-	// Функция RegisterCensusTools не имеет контекста в параметрах,
-	// поэтому используем background контекст
-	ctx := context.Background()
-	slog.InfoContext(ctx, "Регистрация инструментов Census API")
+// HandleGetCongressionalDistrictDataTool обрабатывает запрос на получение данных по округам Конгресса
+func (h *CensusDefaultToolHandler) HandleGetCongressionalDistrictDataTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения данных по округам Конгресса")
 
-	// Инструмент для получения данных о населении штата
-	mcpServer.AddTool(mcp.NewTool("get_state_population",
-		mcp.WithDescription("Получает данные о населении штата США"),
-		mcp.WithString("stateID",
-			mcp.Description("ID штата (например, '06' для Калифорнии). Если не указан, возвращает данные для всех штатов"),
-		),
-	), handler.HandleGetStatePopulationTool)
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
 
-	// Инструмент для получения данных о населении округа
-	mcpServer.AddTool(mcp.NewTool("get_county_population",
-		mcp.WithDescription("Получает данные о населении округов в штате США"),
-		mcp.WithString("stateID",
-			mcp.Description("ID штата (например, '06' для Калифорнии). Если не указан, возвращает данные для всех округов"),
-		),
-	), handler.HandleGetCountyPopulationTool)
+	return h.handleDistrictData(ctx, request, census.ChamberCongressional)
+}
 
-	// Инструмент для поиска штата по названию
-	mcpServer.AddTool(mcp.NewTool("search_state_by_name",
-		mcp.WithDescription("Ищет штат по названию (полному или частичному)"),
-		mcp.WithString("name",
-			mcp.Description("Название штата для поиска"),
-			mcp.Required(),
-		),
-	), handler.HandleSearchStateByNameTool)
+// HandleGetStateLegislativeDistrictDataTool обрабатывает запрос на получение данных по округам легислатур штатов
+func (h *CensusDefaultToolHandler) HandleGetStateLegislativeDistrictDataTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения данных по округам легислатур штатов")
 
-	// Инструмент для получения доступных наборов данных
-	mcpServer.AddTool(mcp.NewTool("get_available_datasets",
-		mcp.WithDescription("Получает список доступных наборов данных Census API"),
-	), handler.HandleGetAvailableDatasetsTool)
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
 
-	// Инструмент для получения переменных набора данных
-	mcpServer.AddTool(mcp.NewTool("get_variables",
-		mcp.WithDescription("Получает список доступных переменных для указанного набора данных"),
-		mcp.WithString("dataset",
-			mcp.Description("Набор данных (например, 'acs/acs1')"),
-			mcp.Required(),
-		),
-		mcp.WithString("year",
-			mcp.Description("Год данных (например, '2021')"),
-			mcp.Required(),
-		),
-	), handler.HandleGetVariablesTool)
+	arguments := request.Params.Arguments
+	chamber, _ := arguments["chamber"].(string)
+	if chamber != census.ChamberUpper && chamber != census.ChamberLower {
+		slog.ErrorContext(ctx, "Некорректная палата легислатуры штата",
+			key_chamber, chamber)
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'chamber' со значением 'upper' или 'lower'")), nil
+	}
 
-	// Инструмент для получения географических уровней
-	mcpServer.AddTool(mcp.NewTool("get_geography_levels",
-		mcp.WithDescription("Получает список доступных географических уровней для указанного набора данных"),
-		mcp.WithString("dataset",
-			mcp.Description("Набор данных (например, 'acs/acs1')"),
-			mcp.Required(),
-		),
-		mcp.WithString("year",
-			mcp.Description("Год данных (например, '2021')"),
-			mcp.Required(),
-		),
-	), handler.HandleGetGeographyLevelsTool)
+	return h.handleDistrictData(ctx, request, chamber)
+}
 
-	// Инструмент для получения пользовательских данных
-	mcpServer.AddTool(mcp.NewTool("get_custom_data",
-		mcp.WithDescription("Позволяет делать пользовательские запросы к Census API с указанием набора данных, года, переменных и географического уровня"),
-		mcp.WithString("dataset",
-			mcp.Description("Набор данных (например, 'acs/acs1')"),
-			mcp.Required(),
-		),
-		mcp.WithString("year",
-			mcp.Description("Год данных (например, '2021')"),
-			mcp.Required(),
-		),
-		mcp.WithString("geoLevel",
-			mcp.Description("Географический уровень (например, 'state' или 'county')"),
-			mcp.Required(),
-		),
-		mcp.WithArray("variables",
-			mcp.Description("Список переменных для запроса (например, ['NAME', 'B01001_001E'])"),
-			mcp.Required(),
-		),
-		mcp.WithObject("geoFilter",
-			mcp.Description("Фильтр географии (например, {\"state\": \"06\", \"county\": \"*\"}). Если не указан, будет использован wildcard для указанного географического уровня"),
+// handleDistrictData выполняет общую логику инструментов данных по избирательным округам
+func (h *CensusDefaultToolHandler) handleDistrictData(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	chamber string,
+) (*mcp.CallToolResult, error) {
+	arguments := request.Params.Arguments
+
+	districts := stringList(arguments["districts"])
+	if len(districts) == 0 {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр districts")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'districts'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultDistrictDataset
+	}
+
+	variables := stringList(arguments["variables"])
+	if len(variables) == 0 {
+		variables = []string{"NAME", "B01001_001E"}
+	}
+
+	year, _ := arguments["year"].(string)
+	congress := intArgument(arguments["congress"])
+	if year == "" && congress == 0 {
+		year = defaultDistrictYear
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента получения данных по избирательным округам",
+		key_chamber, chamber,
+		key_districts, districts,
+		key_congress, congress,
+		key_dataset, dataset,
+		key_year, year)
+
+	// Получение данных по округам
+	data, err := h.api.GetDistrictData(census.DistrictRequest{
+		Chamber:   chamber,
+		Districts: districts,
+		Variables: variables,
+		Dataset:   dataset,
+		Year:      year,
+		Congress:  congress,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении данных по избирательным округам",
+			key_err, err,
+			key_chamber, chamber,
+			key_districts, districts)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных по избирательным округам: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены данные по избирательным округам",
+		key_count, len(data),
+		key_chamber, chamber)
+
+	// Форматирование результатов с оформлением чисел по описаниям переменных
+	ctx = h.withVariableKinds(ctx, request, dataset, year, nil)
+	ctx = h.withColumnLayout(ctx, request, dataset, year, variables)
+	return h.formatResult(ctx, request, data), nil
+}
+
+// HandleSearchCBSATool обрабатывает запрос на поиск статистического ареала по названию
+func (h *CensusDefaultToolHandler) HandleSearchCBSATool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента поиска статистического ареала по названию")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+	name, ok := arguments["name"].(string)
+
+	slog.DebugContext(ctx, "Параметры инструмента поиска статистического ареала",
+		key_name, name,
+		key_valid, ok)
+
+	if !ok || name == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр name для поиска статистического ареала")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'name'")), nil
+	}
+
+	// Поиск ареала в делимитации CBSA
+	cbsas, err := census.SearchCBSAByName(name)
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при поиске статистического ареала",
+			key_err, err,
+			key_search_name, name)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при поиске статистического ареала: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	if len(cbsas) == 0 {
+		slog.InfoContext(ctx, "Статистические ареалы не найдены по запросу",
+			key_name, name)
+		return h.formatResult(ctx, request, cbsas, i18n.T(ctx, "Статистические ареалы не найдены по запросу: ")+name), nil
+	}
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, cbsas), nil
+}
+
+// HandleGetCBSADataTool обрабатывает запрос на получение данных по статистическим ареалам
+func (h *CensusDefaultToolHandler) HandleGetCBSADataTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения данных по статистическим ареалам")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	cbsas := stringList(arguments["cbsas"])
+	if len(cbsas) == 0 {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр cbsas")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'cbsas'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultCBSADataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultCBSAYear
+	}
+
+	variables := stringList(arguments["variables"])
+	if len(variables) == 0 {
+		variables = []string{"NAME", "B01001_001E"}
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента получения данных по статистическим ареалам",
+		key_cbsas, cbsas,
+		key_dataset, dataset,
+		key_year, year)
+
+	// Получение данных по статистическим ареалам
+	data, err := h.api.GetCBSAData(census.CBSARequest{
+		CBSAs:     cbsas,
+		Variables: variables,
+		Dataset:   dataset,
+		Year:      year,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении данных по статистическим ареалам",
+			key_err, err,
+			key_cbsas, cbsas)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении данных по статистическим ареалам: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены данные по статистическим ареалам",
+		key_count, len(data),
+		key_cbsas, cbsas)
+
+	// Форматирование результатов с оформлением чисел по описаниям переменных
+	ctx = h.withVariableKinds(ctx, request, dataset, year, nil)
+	ctx = h.withColumnLayout(ctx, request, dataset, year, variables)
+	return h.formatResult(ctx, request, data), nil
+}
+
+// HandleGetCBSACountiesTool обрабатывает запрос на получение списка округов статистического ареала
+func (h *CensusDefaultToolHandler) HandleGetCBSACountiesTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения округов статистического ареала")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+	cbsa, ok := arguments["cbsa"].(string)
+
+	slog.DebugContext(ctx, "Параметры инструмента получения округов статистического ареала",
+		key_cbsa, cbsa,
+		key_valid, ok)
+
+	if !ok || cbsa == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр cbsa")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'cbsa'")), nil
+	}
+
+	// Определяем код ареала по коду или названию
+	codes, err := census.ResolveCBSACodes([]string{cbsa})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при определении статистического ареала",
+			key_err, err,
+			key_cbsa, cbsa)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при определении статистического ареала: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	info, found := census.LookupCBSA(codes[0])
+	if !found {
+		slog.InfoContext(ctx, "Статистический ареал отсутствует в делимитации CBSA",
+			key_cbsa, cbsa)
+		return h.formatResult(ctx, request, []census.CBSAInfo{},
+			i18n.T(ctx, "Состав статистического ареала не найден в делимитации CBSA: ")+cbsa), nil
+	}
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, []census.CBSAInfo{info}), nil
+}
+
+// HandleCompareYearsTool обрабатывает запрос на сравнение переменных между выпусками
+func (h *CensusDefaultToolHandler) HandleCompareYearsTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента сравнения выпусков")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	dataset, _ := arguments["dataset"].(string)
+	geoLevel, _ := arguments["geoLevel"].(string)
+	variables := stringList(arguments["variables"])
+
+	if dataset == "" || geoLevel == "" || len(variables) == 0 {
+		slog.ErrorContext(ctx, "Отсутствуют обязательные параметры для сравнения выпусков")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметры 'dataset', 'geoLevel' и 'variables'")), nil
+	}
+
+	// Годы задаются списком или диапазоном
+	years := stringList(arguments["years"])
+	if len(years) == 0 {
+		startYear, _ := arguments["startYear"].(string)
+		endYear, _ := arguments["endYear"].(string)
+		if startYear == "" || endYear == "" {
+			slog.ErrorContext(ctx, "Не указаны годы для сравнения выпусков")
+			return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'years' или параметры 'startYear' и 'endYear'")), nil
+		}
+
+		var err error
+		years, err = census.YearRange(startYear, endYear)
+		if err != nil {
+			slog.ErrorContext(ctx, "Некорректный диапазон лет",
+				key_err, err)
+			return mcp.NewToolResultError(i18n.T(ctx, "Некорректный диапазон лет: ") + i18n.ErrorMessage(ctx, err)), nil
+		}
+	}
+
+	inflationYear, _ := arguments["inflationYear"].(string)
+	inflationSeries, _ := arguments["inflationSeries"].(string)
+
+	slog.DebugContext(ctx, "Параметры инструмента сравнения выпусков",
+		key_dataset, dataset,
+		key_years, years,
+		key_geo_level, geoLevel)
+
+	// Сравнение выпусков
+	comparison, err := census.CompareYears(h.api, census.YearComparisonRequest{
+		Variables: variables,
+		Dataset:   dataset,
+		Years:     years,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+
+		InflationYear:   inflationYear,
+		InflationSeries: inflationSeries,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при сравнении выпусков",
+			key_err, err,
+			key_years, years)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при сравнении выпусков: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получено сравнение выпусков",
+		key_count, len(comparison.Rows),
+		key_years, comparison.Years)
+
+	// Форматирование результатов; числа оформляются по описаниям переменных последнего выпуска
+	if len(comparison.Years) > 0 {
+		ctx = h.withVariableKinds(ctx, request, dataset, comparison.Years[len(comparison.Years)-1], nil)
+	}
+	return h.formatResult(ctx, request, comparison), nil
+}
+
+// HandleAggregateGeographiesTool обрабатывает запрос на объединение географий в пользовательский регион
+func (h *CensusDefaultToolHandler) HandleAggregateGeographiesTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента агрегации географий")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	geoIDs := stringList(arguments["geoids"])
+	if len(geoIDs) == 0 {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoids")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoids'")), nil
+	}
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	variables := stringList(arguments["variables"])
+	if len(variables) == 0 {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр variables")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'variables'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultAggregationDataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultAggregationYear
+	}
+
+	skipNonAdditive, _ := arguments["skipNonAdditive"].(bool)
+	designFactor, _ := arguments["designFactor"].(float64)
+
+	slog.DebugContext(ctx, "Параметры инструмента агрегации географий",
+		key_geoids, geoIDs,
+		key_geo_level, geoLevel,
+		key_dataset, dataset,
+		key_year, year)
+
+	// Агрегация географий
+	aggregation, err := census.AggregateGeographies(h.api, census.AggregationRequest{
+		GeoIDs:          geoIDs,
+		GeoLevel:        geoLevel,
+		Variables:       variables,
+		Dataset:         dataset,
+		Year:            year,
+		SkipNonAdditive: skipNonAdditive,
+		DesignFactor:    designFactor,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при агрегации географий",
+			key_err, err,
+			key_geoids, geoIDs)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при агрегации географий: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены итоги агрегации географий",
+		key_count, len(aggregation.Components))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, aggregation), nil
+}
+
+// HandleEstimateQuantilesTool обрабатывает запрос на оценку медианы и квантилей по интервальной таблице
+func (h *CensusDefaultToolHandler) HandleEstimateQuantilesTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента оценки квантилей")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	table, _ := arguments["table"].(string)
+	if table == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр table")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'table'")), nil
+	}
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultQuantileDataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultQuantileYear
+	}
+
+	method, _ := arguments["method"].(string)
+	designFactor, _ := arguments["designFactor"].(float64)
+	quantiles := floatList(arguments["quantiles"])
+
+	slog.DebugContext(ctx, "Параметры инструмента оценки квантилей",
+		key_table, table,
+		key_quantiles, quantiles,
+		key_dataset, dataset,
+		key_year, year,
+		key_geo_level, geoLevel)
+
+	// Оценка квантилей
+	estimates, err := census.EstimateQuantiles(h.api, census.QuantileRequest{
+		Table:        table,
+		Dataset:      dataset,
+		Year:         year,
+		GeoLevel:     geoLevel,
+		GeoFilter:    geoFilterArgument(arguments["geoFilter"], geoLevel),
+		Quantiles:    quantiles,
+		Method:       method,
+		DesignFactor: designFactor,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при оценке квантилей",
+			key_err, err,
+			key_table, table)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при оценке квантилей: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены оценки квантилей",
+		key_count, len(estimates.Rows))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, estimates), nil
+}
+
+// HandleRankGeographiesTool обрабатывает запрос на построение рейтинга географий
+func (h *CensusDefaultToolHandler) HandleRankGeographiesTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента рейтинга географий")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	variable, _ := arguments["variable"].(string)
+	if variable == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр variable")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'variable'")), nil
+	}
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultRankDataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultRankYear
+	}
+
+	order, _ := arguments["order"].(string)
+	if order != "" && order != "top" && order != "bottom" {
+		slog.ErrorContext(ctx, "Некорректный порядок рейтинга",
+			key_name, order)
+		return mcp.NewToolResultError(i18n.T(ctx, "Параметр 'order' должен быть 'top' или 'bottom'")), nil
+	}
+
+	limit := intArgument(arguments["limit"])
+
+	slog.DebugContext(ctx, "Параметры инструмента рейтинга географий",
+		key_variable, variable,
+		key_limit, limit,
+		key_dataset, dataset,
+		key_year, year,
+		key_geo_level, geoLevel)
+
+	// Построение рейтинга
+	ranking, err := census.RankGeographies(h.api, census.RankRequest{
+		Variable:  variable,
+		Dataset:   dataset,
+		Year:      year,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+		Limit:     limit,
+		Ascending: order == "bottom",
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при построении рейтинга географий",
+			key_err, err,
+			key_variable, variable)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при построении рейтинга: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получен рейтинг географий",
+		key_count, len(ranking.Rows))
+
+	// Форматирование результатов с оформлением чисел по описаниям переменных
+	ctx = h.withVariableKinds(ctx, request, dataset, year, nil)
+	return h.formatResult(ctx, request, ranking), nil
+}
+
+// HandleCompareEstimatesTool обрабатывает запрос на проверку значимости различия двух оценок
+func (h *CensusDefaultToolHandler) HandleCompareEstimatesTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента проверки значимости различия оценок")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	variable, _ := arguments["variable"].(string)
+	geoLevel, _ := arguments["geoLevel"].(string)
+	year, _ := arguments["year"].(string)
+	_, hasGeoFilter := arguments["geoFilter"].(map[string]interface{})
+
+	if variable == "" || geoLevel == "" || year == "" || !hasGeoFilter {
+		slog.ErrorContext(ctx, "Отсутствуют обязательные параметры для проверки значимости")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметры 'variable', 'geoLevel', 'geoFilter' и 'year'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = defaultSignificanceDataset
+	}
+
+	first := census.EstimateTarget{
+		Year:      year,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+	}
+
+	// Вторая оценка отличается годом и/или географией; неуказанные параметры берутся из первой
+	second := first
+	if secondYear, _ := arguments["secondYear"].(string); secondYear != "" {
+		second.Year = secondYear
+	}
+	if secondGeoLevel, _ := arguments["secondGeoLevel"].(string); secondGeoLevel != "" {
+		second.GeoLevel = secondGeoLevel
+	}
+	if _, ok := arguments["secondGeoFilter"].(map[string]interface{}); ok {
+		second.GeoFilter = geoFilterArgument(arguments["secondGeoFilter"], second.GeoLevel)
+	}
+
+	if reflect.DeepEqual(first, second) {
+		slog.ErrorContext(ctx, "Вторая оценка совпадает с первой")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать 'secondYear' или 'secondGeoFilter', отличающиеся от первой оценки")), nil
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента проверки значимости",
+		key_variable, variable,
+		key_dataset, dataset,
+		key_year, year,
+		key_geo_level, geoLevel)
+
+	// Проверка значимости
+	comparison, err := census.CompareEstimates(h.api, census.EstimateComparisonRequest{
+		Variable: variable,
+		Dataset:  dataset,
+		First:    first,
+		Second:   second,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при проверке значимости различия оценок",
+			key_err, err,
+			key_variable, variable)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при сравнении оценок: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	// Форматирование результатов с оформлением чисел по описаниям переменных
+	ctx = h.withVariableKinds(ctx, request, dataset, year, nil)
+	return h.formatResult(ctx, request, comparison), nil
+}
+
+// HandleGetProfileTool обрабатывает запрос на получение курируемой таблицы профиля ACS
+func (h *CensusDefaultToolHandler) HandleGetProfileTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения профиля ACS")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	table, _ := arguments["table"].(string)
+	if table == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр table")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'table'")), nil
+	}
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = census.DefaultProfileDataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultProfileYear
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента получения профиля ACS",
+		key_table, table,
+		key_dataset, dataset,
+		key_year, year,
+		key_geo_level, geoLevel)
+
+	// Получение профиля
+	profile, err := census.GetProfile(h.api, census.ProfileRequest{
+		Table:     table,
+		Dataset:   dataset,
+		Year:      year,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении профиля ACS",
+			key_err, err,
+			key_table, table)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении профиля: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получен профиль ACS",
+		key_count, len(profile.Geographies))
+
+	// Форматирование результатов с оформлением чисел по описаниям переменных
+	ctx = h.withVariableKinds(ctx, request, dataset, year, nil)
+	return h.formatResult(ctx, request, profile), nil
+}
+
+// HandleGetDecennialCountsTool обрабатывает запрос на получение показателей переписи
+func (h *CensusDefaultToolHandler) HandleGetDecennialCountsTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения показателей переписи")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = census.DefaultDecennialDataset
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = census.DefaultDecennialYear
+	}
+
+	groups := stringList(arguments["groups"])
+
+	slog.DebugContext(ctx, "Параметры инструмента получения показателей переписи",
+		key_dataset, dataset,
+		key_year, year,
+		key_geo_level, geoLevel,
+		key_groups, groups)
+
+	// Получение показателей
+	counts, err := census.GetDecennialCounts(h.api, census.DecennialRequest{
+		Dataset:   dataset,
+		Year:      year,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+		Groups:    groups,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении показателей переписи",
+			key_err, err,
+			key_dataset, dataset)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении показателей переписи: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены показатели переписи",
+		key_count, len(counts.Rows))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, counts), nil
+}
+
+// HandleCompareDecennialTool обрабатывает запрос на сравнение показателей двух переписей
+func (h *CensusDefaultToolHandler) HandleCompareDecennialTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента сравнения переписей")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	baseYear, _ := arguments["baseYear"].(string)
+	baseDataset, _ := arguments["baseDataset"].(string)
+	targetYear, _ := arguments["targetYear"].(string)
+	targetDataset, _ := arguments["targetDataset"].(string)
+	groups := stringList(arguments["groups"])
+
+	slog.DebugContext(ctx, "Параметры инструмента сравнения переписей",
+		key_years, []string{baseYear, targetYear},
+		key_geo_level, geoLevel,
+		key_groups, groups)
+
+	// Сравнение переписей
+	comparison, err := census.CompareDecennial(h.api, census.DecennialChangeRequest{
+		GeoLevel:      geoLevel,
+		GeoFilter:     geoFilterArgument(arguments["geoFilter"], geoLevel),
+		Groups:        groups,
+		BaseDataset:   baseDataset,
+		BaseYear:      baseYear,
+		TargetDataset: targetDataset,
+		TargetYear:    targetYear,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при сравнении переписей",
+			key_err, err)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при сравнении переписей: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получено сравнение переписей",
+		key_count, len(comparison.Rows))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, comparison), nil
+}
+
+// HandleGetPopulationEstimatesTool обрабатывает запрос на получение оценок численности населения PEP
+func (h *CensusDefaultToolHandler) HandleGetPopulationEstimatesTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения оценок численности населения")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	kind, _ := arguments["kind"].(string)
+	vintage, _ := arguments["vintage"].(string)
+	timeValue, _ := arguments["time"].(string)
+
+	characteristics := make(map[string]string)
+	if values, ok := arguments["characteristics"].(map[string]interface{}); ok {
+		for key, value := range values {
+			switch v := value.(type) {
+			case string:
+				characteristics[strings.ToUpper(key)] = v
+			case float64:
+				characteristics[strings.ToUpper(key)] = strconv.Itoa(int(v))
+			}
+		}
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента получения оценок численности населения",
+		key_kind, kind,
+		key_vintage, vintage,
+		key_geo_level, geoLevel)
+
+	// Получение оценок
+	estimates, err := census.GetPopulationEstimates(h.api, census.PEPRequest{
+		Kind:            kind,
+		Vintage:         vintage,
+		GeoLevel:        geoLevel,
+		GeoFilter:       geoFilterArgument(arguments["geoFilter"], geoLevel),
+		Time:            timeValue,
+		Characteristics: characteristics,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении оценок численности населения",
+			key_err, err,
+			key_kind, kind)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении оценок численности населения: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены оценки численности населения",
+		key_count, len(estimates.Rows))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, estimates), nil
+}
+
+// HandleSearchNAICSTool обрабатывает запрос на поиск отраслей NAICS
+func (h *CensusDefaultToolHandler) HandleSearchNAICSTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента поиска отраслей NAICS")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	query, _ := arguments["query"].(string)
+	if query == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр query")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'query'")), nil
+	}
+
+	limit := intArgument(arguments["limit"])
+	if limit <= 0 {
+		limit = defaultNAICSSearchLimit
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента поиска отраслей NAICS",
+		key_search_name, query,
+		key_limit, limit)
+
+	// Поиск отраслей
+	industries, err := census.SearchNAICS(query, limit)
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при поиске отраслей NAICS",
+			key_err, err,
+			key_search_name, query)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при поиске отраслей: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Найдены отрасли NAICS",
+		key_count, len(industries))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, industries), nil
+}
+
+// HandleGetBusinessPatternsTool обрабатывает запрос на получение статистики предприятий по отраслям
+func (h *CensusDefaultToolHandler) HandleGetBusinessPatternsTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения статистики предприятий")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoLevel")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoLevel'")), nil
+	}
+
+	dataset, _ := arguments["dataset"].(string)
+	if dataset == "" {
+		dataset = census.DatasetCBP
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = census.DefaultCBPYear
+		if dataset == census.DatasetEconomicCensus {
+			year = census.DefaultEconomicCensusYear
+		}
+	}
+
+	codes := stringList(arguments["naics"])
+
+	// Сопоставление названия отрасли с кодом NAICS
+	if industry, _ := arguments["industry"].(string); industry != "" {
+		industries, err := census.SearchNAICS(industry, 1)
+		if err != nil {
+			return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при поиске отрасли: ") + i18n.ErrorMessage(ctx, err)), nil
+		}
+		if len(industries) == 0 {
+			slog.ErrorContext(ctx, "Отрасль не найдена",
+				key_search_name, industry)
+			return mcp.NewToolResultError(i18n.Sprintf(ctx, "Отрасль '%s' не найдена в таблице NAICS; используйте search_naics для поиска кода", industry)), nil
+		}
+		codes = append(codes, industries[0].Code)
+	}
+
+	slog.DebugContext(ctx, "Параметры инструмента получения статистики предприятий",
+		key_dataset, dataset,
+		key_year, year,
+		key_geo_level, geoLevel,
+		key_naics, codes)
+
+	// Получение статистики
+	patterns, err := census.GetBusinessPatterns(h.api, census.BusinessRequest{
+		Dataset:   dataset,
+		Year:      year,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+		NAICS:     codes,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении статистики предприятий",
+			key_err, err,
+			key_dataset, dataset)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении статистики предприятий: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получена статистика предприятий",
+		key_count, len(patterns.Rows))
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, patterns), nil
+}
+
+// HandleGetMigrationFlowsTool обрабатывает запрос на получение миграционных потоков
+func (h *CensusDefaultToolHandler) HandleGetMigrationFlowsTool(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	slog.InfoContext(ctx, "Обработка инструмента получения миграционных потоков")
+
+	if result := h.validatePresentation(ctx, request); result != nil {
+		return result, nil
+	}
+
+	arguments := request.Params.Arguments
+
+	if _, ok := arguments["geoFilter"].(map[string]interface{}); !ok {
+		slog.ErrorContext(ctx, "Отсутствует обязательный параметр geoFilter")
+		return mcp.NewToolResultError(i18n.T(ctx, "Необходимо указать параметр 'geoFilter'")), nil
+	}
+
+	geoLevel, _ := arguments["geoLevel"].(string)
+	if geoLevel == "" {
+		geoLevel = defaultFlowGeoLevel
+	}
+
+	year, _ := arguments["year"].(string)
+	if year == "" {
+		year = defaultFlowYear
+	}
+
+	limit := intArgument(arguments["limit"])
+
+	slog.DebugContext(ctx, "Параметры инструмента получения миграционных потоков",
+		key_year, year,
+		key_geo_level, geoLevel,
+		key_limit, limit)
+
+	// Получение потоков
+	flows, err := census.GetMigrationFlows(h.api, census.MigrationFlowRequest{
+		Year:      year,
+		GeoLevel:  geoLevel,
+		GeoFilter: geoFilterArgument(arguments["geoFilter"], geoLevel),
+		Limit:     limit,
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Ошибка при получении миграционных потоков",
+			key_err, err)
+		return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при получении миграционных потоков: ") + i18n.ErrorMessage(ctx, err)), nil
+	}
+
+	slog.DebugContext(ctx, "Получены миграционные потоки",
+		key_count, flows.Counterparts)
+
+	// Форматирование результатов
+	return h.formatResult(ctx, request, flows), nil
+}
+
+// formatResult форматирует данные в формате из аргумента "format" запроса (или в формате
+// по умолчанию) и добавляет непустые пояснения. К текстовым форматам пояснения дописываются
+// после данных, для машиночитаемых форматов (JSON, CSV, TSV) передаются отдельными блоками
+// содержимого, чтобы не нарушать разбор результата. Если задан аргумент "resource" (или формат
+// ресурса по умолчанию), последним блоком добавляется встроенный ресурс с теми же данными.
+func (h *CensusDefaultToolHandler) formatResult(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	data interface{},
+	notes ...string,
+) *mcp.CallToolResult {
+	options, message := h.presentationOptions(ctx, request)
+	if message != "" {
+		return mcp.NewToolResultError(message)
+	}
+	formatter, resource, budget := options.formatter, options.resource, options.budget
+
+	// Точность чисел в текстовых форматах
+	if options.precision >= 0 {
+		numbers := census.NumberFormatFromContext(ctx)
+		numbers.Precision = options.precision
+		ctx = census.WithNumberFormat(ctx, numbers)
+	}
+
+	// Диаграмма строится по всем данным результата до разбиения на страницы
+	var chart mcp.Content
+	if value, _ := request.Params.Arguments["chart"].(string); value != "" {
+		svg, err := h.chart(ctx, request, value, data)
+		if err != nil {
+			slog.WarnContext(ctx, "Ошибка при построении диаграммы",
+				key_err, err,
+				key_chart, value)
+			return mcp.NewToolResultError(i18n.T(ctx, "Ошибка при построении диаграммы: ") + i18n.ErrorMessage(ctx, err))
+		}
+		chart = svg
+	}
+
+	// Табличные результаты, не помещающиеся в бюджет токенов, выдаются по страницам,
+	// встроенный ресурс содержит строки той же страницы. Прочие результаты сверх бюджета
+	// усекаются со сводкой.
+	full := data
+	rows, paginated := resultRows(data)
+	if paginated {
+		page, note, message := h.paginate(ctx, request, formatter, rows, options.offset, budget)
+		if message != "" {
+			return mcp.NewToolResultError(message)
+		}
+		data = page
+		notes = append(notes, note)
+	}
+
+	result := formatter.Format(ctx, data)
+	if !paginated {
+		if text, truncated := truncateToBudget(result, budget); truncated {
+			slog.DebugContext(ctx, "Результат усечен по бюджету токенов",
+				key_tokens, budget)
+			result = text
+			notes = append(notes, i18n.Sprintf(ctx, "**Результат усечен**: он превышает бюджет около %d токенов, показано начало. Сузьте запрос или увеличьте параметр maxTokens.\n", budget)+
+				census.FormatResultSummary(ctx, full))
+		}
+	}
+	structured := census.IsStructuredFormatter(formatter)
+
+	var separate []mcp.Content
+	for _, note := range notes {
+		if note == "" {
+			continue
+		}
+		if structured {
+			separate = append(separate, mcp.NewTextContent(note))
+		} else {
+			result += "\n" + note
+		}
+	}
+
+	toolResult := mcp.NewToolResultText(result)
+	toolResult.Content = append(toolResult.Content, separate...)
+	if resource != "" && resource != ResourceNone {
+		toolResult.Content = append(toolResult.Content, h.embeddedResource(ctx, request, resource, data, paginated, budget))
+	}
+	if chart != nil {
+		toolResult.Content = append(toolResult.Content, chart)
+	}
+	return toolResult
+}
+
+// chart строит диаграмму SVG вида kind по данным результата и возвращает ее встроенным
+// ресурсом с MIME-типом image/svg+xml
+func (h *CensusDefaultToolHandler) chart(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	kind string,
+	data interface{},
+) (mcp.Content, error) {
+	chartKind, err := census.ParseChartKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	column, _ := request.Params.Arguments["chartColumn"].(string)
+	chart, err := census.NewChart(ctx, data, chartKind, column)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.NewEmbeddedResource(mcp.TextResourceContents{
+		URI:      resourceURI(request, string(chartKind)+".svg"),
+		MIMEType: census.ChartMIMEType,
+		Text:     chart.SVG(ctx),
+	}), nil
+}
+
+// embeddedResource возвращает встроенный ресурс с данными в машиночитаемом формате для
+// программной обработки клиентом. Строки табличного результата уже ограничены страницей,
+// прочие данные сверх бюджета токенов усекаются, как и текст результата.
+func (h *CensusDefaultToolHandler) embeddedResource(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	format string,
+	data interface{},
+	paginated bool,
+	budget int,
+) mcp.EmbeddedResource {
+	// Формат проверен ValidateResourceFormat, все машиночитаемые форматы есть в реестре
+	formatter, _ := h.formatters.Lookup(format)
+
+	uri := resourceURI(request, format)
+
+	slog.DebugContext(ctx, "Добавление встроенного ресурса с данными",
+		key_resource, uri)
+
+	text := formatter.Format(ctx, data)
+	if !paginated {
+		text, _ = truncateToBudget(text, budget)
+	}
+
+	return mcp.NewEmbeddedResource(mcp.TextResourceContents{
+		URI:      uri,
+		MIMEType: resourceMIMETypes[format],
+		Text:     text,
+	})
+}
+
+// tableRows - строки табличного результата, разбиваемого на страницы
+type tableRows struct {
+	data  interface{}
+	count int
+	// slice возвращает результат того же типа со строками с start по end
+	slice func(start, end int) interface{}
+}
+
+// resultRows возвращает строки табличного результата: строк ответа Census API, данных
+// о населении, описаний переменных, оценок PEP, статистики предприятий, сравнения выпусков,
+// рейтинга, слагаемых агрегации и миграционных потоков. Для прочих данных возвращается false.
+func resultRows(data interface{}) (tableRows, bool) {
+	switch v := data.(type) {
+	case []map[string]string:
+		return tableRows{data, len(v), func(start, end int) interface{} { return v[start:end] }}, true
+	case []census.PopulationData:
+		return tableRows{data, len(v), func(start, end int) interface{} { return v[start:end] }}, true
+	case map[string]census.VariableInfo:
+		// Переменные выводятся в порядке имен, страницы делят тот же порядок
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return tableRows{data, len(names), func(start, end int) interface{} {
+			page := make(map[string]census.VariableInfo, end-start)
+			for _, name := range names[start:end] {
+				page[name] = v[name]
+			}
+			return page
+		}}, true
+	case *census.YearComparison:
+		if v == nil {
+			return tableRows{}, false
+		}
+		return tableRows{data, len(v.Rows), func(start, end int) interface{} {
+			page := *v
+			page.Rows = v.Rows[start:end]
+			return &page
+		}}, true
+	case *census.RankResult:
+		if v == nil {
+			return tableRows{}, false
+		}
+		return tableRows{data, len(v.Rows), func(start, end int) interface{} {
+			page := *v
+			page.Rows = v.Rows[start:end]
+			return &page
+		}}, true
+	case *census.AggregationResult:
+		if v == nil {
+			return tableRows{}, false
+		}
+		// Итоги и медианы рассчитаны по всем слагаемым и повторяются на каждой странице
+		return tableRows{data, len(v.Components), func(start, end int) interface{} {
+			page := *v
+			page.Components = v.Components[start:end]
+			return &page
+		}}, true
+	case *census.MigrationFlowResult:
+		if v == nil {
+			return tableRows{}, false
+		}
+		// Строки - потоки притока, за которыми следуют потоки оттока
+		inflows := len(v.Inflows)
+		return tableRows{data, inflows + len(v.Outflows), func(start, end int) interface{} {
+			page := *v
+			page.Inflows = v.Inflows[min(start, inflows):min(end, inflows)]
+			page.Outflows = v.Outflows[max(start-inflows, 0):max(end-inflows, 0)]
+			return &page
+		}}, true
+	case *census.PEPResult:
+		if v == nil {
+			return tableRows{}, false
+		}
+		return tableRows{data, len(v.Rows), func(start, end int) interface{} {
+			page := *v
+			page.Rows = v.Rows[start:end]
+			return &page
+		}}, true
+	case *census.BusinessResult:
+		if v == nil {
+			return tableRows{}, false
+		}
+		return tableRows{data, len(v.Rows), func(start, end int) interface{} {
+			page := *v
+			page.Rows = v.Rows[start:end]
+			return &page
+		}}, true
+	}
+	return tableRows{}, false
+}
+
+// paginate возвращает страницу строк, начиная с позиции offset из аргумента "cursor", размер
+// которой в формате formatter не превышает бюджет токенов budget. Если результат усечен, возвращается пояснение
+// с номерами строк, сводкой числовых столбцов по всем строкам и курсором следующей страницы.
+// Курсор не хранит данные на сервере: клиент повторяет вызов с теми же параметрами, и сервер
+// заново получает строки из Census API. При ошибке в аргументах возвращается сообщение об ошибке.
+func (h *CensusDefaultToolHandler) paginate(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	formatter census.Formatter,
+	rows tableRows,
+	offset, budget int,
+) (interface{}, string, string) {
+	fingerprint := requestFingerprint(request.Params.Arguments)
+
+	if offset > 0 && offset >= rows.count {
+		slog.WarnContext(ctx, "Курсор указывает за пределы результата",
+			key_offset, offset,
+			key_rows, rows.count)
+		return nil, "", i18n.Sprintf(ctx, "Параметр 'cursor' указывает за пределы результата из %d строк", rows.count)
+	}
+
+	if budget <= 0 {
+		if offset == 0 {
+			return rows.data, "", ""
+		}
+		return rows.slice(offset, rows.count), "", ""
+	}
+
+	// Подбираем размер страницы пропорционально превышению бюджета, оставляя запас на сводку
+	limit := rows.count - offset
+	tokens := estimateTokens(formatter.Format(ctx, rows.slice(offset, rows.count)))
+	for tokens > budget && limit > 1 {
+		limit = max(1, min(limit-1, limit*budget*9/(tokens*10)))
+		tokens = estimateTokens(formatter.Format(ctx, rows.slice(offset, offset+limit)))
+	}
+
+	if offset == 0 && limit == rows.count {
+		return rows.data, "", ""
+	}
+
+	end := offset + limit
+	slog.DebugContext(ctx, "Результат разбит на страницы",
+		key_offset, offset,
+		key_count, limit,
+		key_rows, rows.count,
+		key_tokens, budget)
+
+	var sb strings.Builder
+	if end < rows.count {
+		sb.WriteString(i18n.Sprintf(ctx, "**Показаны строки %d-%d из %d**: результат превышает бюджет около %d токенов. Чтобы получить следующие строки, повторите вызов с теми же параметрами и cursor=\"%s\".\n",
+			offset+1, end, rows.count, budget, encodeCursor(end, fingerprint)))
+	} else {
+		sb.WriteString(i18n.Sprintf(ctx, "**Показаны строки %d-%d из %d** (последняя страница).\n",
+			offset+1, end, rows.count))
+	}
+	sb.WriteString(census.FormatResultSummary(ctx, rows.data))
+
+	return rows.slice(offset, end), sb.String(), ""
+}
+
+// presentation - аргументы оформления результата
+type presentation struct {
+	formatter census.Formatter
+	// resource - формат встроенного ресурса или ResourceNone
+	resource string
+	// precision - число знаков после запятой; -1, если аргумент не задан
+	precision int
+	// offset - позиция страницы из аргумента "cursor"
+	offset int
+	// budget - бюджет токенов результата; 0 отключает усечение
+	budget int
+}
+
+// presentationOptions разбирает аргументы оформления результата: "format", "resource",
+// "precision", "cursor" и "maxTokens". Они не зависят от данных, поэтому обработчики проверяют
+// их до обращения к Census API (см. validatePresentation). При ошибке в аргументах
+// возвращается сообщение об ошибке.
+func (h *CensusDefaultToolHandler) presentationOptions(ctx context.Context, request mcp.CallToolRequest) (presentation, string) {
+	arguments := request.Params.Arguments
+	options := presentation{precision: -1, budget: h.maxTokens}
+
+	format, _ := arguments["format"].(string)
+	formatter, err := h.formatters.Lookup(format)
+	if err != nil {
+		slog.WarnContext(ctx, "Некорректный формат результатов",
+			key_err, err,
+			key_format, format)
+		return options, i18n.Sprintf(ctx, "Неизвестный формат %q; доступны: %s",
+			format, strings.Join(h.formatters.Names(), ", "))
+	}
+	options.formatter = formatter
+
+	resource, _ := arguments["resource"].(string)
+	if resource == "" {
+		resource = h.resource
+	}
+	if err := ValidateResourceFormat(resource); err != nil {
+		slog.WarnContext(ctx, "Некорректный формат встроенного ресурса",
+			key_err, err,
+			key_resource, resource)
+		return options, i18n.ErrorMessage(ctx, err)
+	}
+	options.resource = resource
+
+	if value, ok := arguments["precision"]; ok && value != nil {
+		precision := intArgument(value)
+		if precision < 0 || precision > census.MaxPrecision {
+			slog.WarnContext(ctx, "Некорректная точность чисел",
+				key_precision, value)
+			return options, i18n.Sprintf(ctx, "Параметр 'precision' должен быть от 0 до %d", census.MaxPrecision)
+		}
+		options.precision = precision
+	}
+
+	if cursor, _ := arguments["cursor"].(string); cursor != "" {
+		offset, fingerprint, ok := parseCursor(cursor)
+		if !ok {
+			slog.WarnContext(ctx, "Некорректный курсор страницы результата")
+			return options, i18n.T(ctx, "Некорректный параметр 'cursor'")
+		}
+		if fingerprint != requestFingerprint(arguments) {
+			slog.WarnContext(ctx, "Курсор относится к другому запросу",
+				key_offset, offset)
+			return options, i18n.T(ctx, "Параметр 'cursor' относится к другому запросу: повторите вызов с теми же параметрами, что и у предыдущей страницы")
+		}
+		options.offset = offset
+	}
+
+	if value, ok := arguments["maxTokens"]; ok && value != nil {
+		options.budget = intArgument(value)
+		if options.budget <= 0 {
+			return options, i18n.T(ctx, "Параметр 'maxTokens' должен быть положительным числом")
+		}
+	}
+
+	return options, ""
+}
+
+// validatePresentation проверяет аргументы оформления результата до обращения к Census API,
+// чтобы некорректный формат или курсор не стоил запроса данных. Возвращает результат
+// с ошибкой или nil.
+func (h *CensusDefaultToolHandler) validatePresentation(ctx context.Context, request mcp.CallToolRequest) *mcp.CallToolResult {
+	if _, message := h.presentationOptions(ctx, request); message != "" {
+		return mcp.NewToolResultError(message)
+	}
+	return nil
+}
+
+// truncateToBudget усекает текст, превышающий бюджет токенов, по границе строки.
+// Возвращает true, если текст усечен.
+func truncateToBudget(text string, budget int) (string, bool) {
+	if budget <= 0 || estimateTokens(text) <= budget {
+		return text, false
+	}
+	runes := []rune(text)[:budget*charsPerToken]
+	truncated := string(runes)
+	if cut := strings.LastIndexByte(truncated, '\n'); cut > 0 {
+		truncated = truncated[:cut+1]
+	}
+	return truncated, true
+}
+
+// resourceURI возвращает URI встроенного ресурса вида census://<инструмент>/<отпечаток>.<расширение>;
+// одинаковые запросы дают одинаковый URI
+func resourceURI(request mcp.CallToolRequest, extension string) string {
+	tool := request.Params.Name
+	if tool == "" {
+		tool = "result"
+	}
+	return fmt.Sprintf("census://%s/%s.%s", tool, requestFingerprint(request.Params.Arguments), extension)
+}
+
+// estimateTokens оценивает число токенов текста по числу символов
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// requestFingerprint возвращает отпечаток аргументов запроса без аргументов оформления,
+// связывающий курсор страницы с запросом
+func requestFingerprint(arguments map[string]interface{}) string {
+	filtered := make(map[string]interface{}, len(arguments))
+	for name, value := range arguments {
+		if !presentationArguments[name] {
+			filtered[name] = value
+		}
+	}
+
+	// json.Marshal сортирует ключи, поэтому отпечаток не зависит от порядка аргументов
+	encoded, _ := json.Marshal(filtered)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:6])
+}
+
+// encodeCursor кодирует позицию следующей страницы и отпечаток запроса в курсор
+func encodeCursor(offset int, fingerprint string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset) + ":" + fingerprint))
+}
+
+// parseCursor разбирает курсор, созданный encodeCursor
+func parseCursor(cursor string) (int, string, bool) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", false
+	}
+	position, fingerprint, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return 0, "", false
+	}
+	offset, err := strconv.Atoi(position)
+	if err != nil || offset < 0 {
+		return 0, "", false
+	}
+	return offset, fingerprint, true
+}
+
+// withVariableKinds возвращает контекст с видами значений переменных выпуска (счетчики, доллары,
+// проценты), по которым текстовые форматы оформляют числа. Описания переменных запрашиваются
+// один раз для выпуска и кэшируются; для машиночитаемых форматов запрос не выполняется.
+// extra дополняет виды переменных (например, для вычисляемых столбцов) и передается всем
+// форматам: по нему машиночитаемые форматы определяют типы столбцов.
+func (h *CensusDefaultToolHandler) withVariableKinds(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	dataset, year string,
+	extra map[string]census.ValueKind,
+) context.Context {
+	kinds := make(map[string]census.ValueKind)
+	format, _ := request.Params.Arguments["format"].(string)
+	if formatter, err := h.formatters.Lookup(format); err == nil && !census.IsStructuredFormatter(formatter) {
+		if release := h.releaseVariables(ctx, dataset, year); release != nil {
+			for variable, kind := range release.kinds {
+				kinds[variable] = kind
+			}
+		}
+	}
+	for variable, kind := range extra {
+		kinds[variable] = kind
+	}
+
+	numbers := census.NumberFormatFromContext(ctx)
+	numbers.Kinds = kinds
+	return census.WithNumberFormat(ctx, numbers)
+}
+
+// withColumnLayout возвращает контекст с порядком столбцов ответа Census API и, если задан
+// аргумент "labels", с подписями переменных выпуска вместо их кодов
+func (h *CensusDefaultToolHandler) withColumnLayout(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	dataset, year string,
+	columns []string,
+) context.Context {
+	layout := census.ColumnLayout{Order: columns}
+
+	if labels, _ := request.Params.Arguments["labels"].(bool); labels {
+		if release := h.releaseVariables(ctx, dataset, year); release != nil {
+			layout.Labels = census.VariableLabels(release.definitions, columns)
+		}
+	}
+
+	return census.WithColumnLayout(ctx, layout)
+}
+
+// releaseVariables возвращает описания и виды значений переменных выпуска из кэша или
+// из Census API. Описания выпуска запрашиваются одним вызовом без блокировки кэша: вызовы
+// для других выпусков не ждут загрузки, а одновременные вызовы для того же выпуска ждут
+// ее результата. Ошибка кэшируется на releaseRetryInterval; при ошибке возвращается nil,
+// и числа оформляются по именам переменных, а столбцы подписываются кодами.
+func (h *CensusDefaultToolHandler) releaseVariables(ctx context.Context, dataset, year string) *releaseVariables {
+	key := dataset + "|" + year
+
+	h.releasesMu.Lock()
+	release, ok := h.releases[key]
+	if !ok || release.expired(time.Now()) {
+		release = &releaseVariables{ready: make(chan struct{})}
+		if h.releases == nil {
+			h.releases = make(map[string]*releaseVariables)
+		}
+		h.releases[key] = release
+		h.releasesMu.Unlock()
+
+		release.definitions, release.err = h.api.GetVariables(dataset, year)
+		if release.err == nil {
+			release.kinds = census.VariableKinds(release.definitions)
+		}
+		release.fetched = time.Now()
+		close(release.ready)
+	} else {
+		h.releasesMu.Unlock()
+	}
+
+	select {
+	case <-release.ready:
+	case <-ctx.Done():
+		slog.WarnContext(ctx, "Описания переменных выпуска не получены до отмены запроса",
+			key_dataset, dataset,
+			key_year, year)
+		return nil
+	}
+
+	if release.err != nil {
+		slog.WarnContext(ctx, "Не удалось получить описания переменных выпуска",
+			key_err, release.err,
+			key_dataset, dataset,
+			key_year, year)
+		return nil
+	}
+	return release
+}
+
+// formatArgument возвращает параметр инструмента для выбора формата результатов
+func formatArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description(i18n.Translate(locale, "Формат результата: markdown (по умолчанию), plain - текст без разметки, json - JSON со стабильной схемой, csv или tsv - таблица для электронных таблиц, geojson - FeatureCollection с границами географий для карт")),
+		mcp.Enum(census.FormatMarkdown, census.FormatPlain, census.FormatJSON, census.FormatCSV, census.FormatTSV, census.FormatGeoJSON),
+	)
+}
+
+// labelsArgument возвращает параметр инструмента для вывода подписей переменных вместо кодов
+func labelsArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithBoolean("labels",
+		mcp.Description(i18n.Translate(locale, "Подписывать столбцы переменных их описаниями из Census API (например, 'Median household income' вместо 'B19013_001E')")),
+	)
+}
+
+// resourceArgument возвращает параметр инструмента для выбора встроенного ресурса с данными
+func resourceArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithString("resource",
+		mcp.Description(i18n.Translate(locale, "Добавить к результату встроенный ресурс с полными данными для программной обработки: json, csv, tsv, geojson - FeatureCollection с границами географий или none - без ресурса (по умолчанию задается сервером)")),
+		mcp.Enum(ResourceNone, census.FormatJSON, census.FormatCSV, census.FormatTSV, census.FormatGeoJSON),
+	)
+}
+
+// chartArgument возвращает параметр инструмента для построения диаграммы SVG доступных видов
+func chartArgument(locale i18n.Locale, kinds ...census.ChartKind) mcp.ToolOption {
+	values := make([]string, len(kinds))
+	for i, kind := range kinds {
+		values[i] = string(kind)
+	}
+	return mcp.WithString("chart",
+		mcp.Description(i18n.Translate(locale, "Построить по результату диаграмму SVG: bar - столбцы по географиям, line - временной ряд, pyramid - половозрастная пирамида по таблице B01001")),
+		mcp.Enum(values...),
+	)
+}
+
+// chartColumnArgument возвращает параметр инструмента для выбора значений диаграммы
+func chartColumnArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithString("chartColumn",
+		mcp.Description(i18n.Translate(locale, "Столбец или переменная значений диаграммы (по умолчанию первый числовой столбец)")),
+	)
+}
+
+// cursorArgument возвращает параметр инструмента для получения следующей страницы результата
+func cursorArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithString("cursor",
+		mcp.Description(i18n.Translate(locale, "Курсор следующей страницы из пояснения к усеченному результату; остальные параметры должны совпадать с предыдущим вызовом")),
+	)
+}
+
+// maxTokensArgument возвращает параметр инструмента для выбора бюджета токенов результата
+func maxTokensArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithNumber("maxTokens",
+		mcp.Description(i18n.Translate(locale, "Примерный бюджет токенов результата; более длинные результаты усекаются со сводкой, табличные - с курсором следующей страницы (по умолчанию задается сервером)")),
+	)
+}
+
+// precisionArgument возвращает параметр инструмента для выбора точности чисел
+func precisionArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithNumber("precision",
+		mcp.Description(i18n.Translate(locale, "Наибольшее число знаков после запятой в текстовых форматах (от 0 до 6, по умолчанию 2)")),
+	)
+}
+
+// languageArgument возвращает параметр инструмента для выбора языка результатов и сообщений
+func languageArgument(locale i18n.Locale) mcp.ToolOption {
+	return mcp.WithString("language",
+		mcp.Description(i18n.Translate(locale, "Язык результата и сообщений: ru - русский, en - английский (по умолчанию - язык сервера)")),
+		mcp.Enum(string(i18n.Russian), string(i18n.English)),
+	)
+}
+
+// localized возвращает обработчик, выполняющий запрос на языке из аргумента "language"
+// или на языке сервера по умолчанию. Язык передается форматтерам и сообщениям через контекст.
+func localized(locale i18n.Locale, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		requestLocale := locale
+		if language, _ := request.Params.Arguments["language"].(string); language != "" {
+			parsed, err := i18n.ParseLocale(language)
+			if err != nil {
+				slog.WarnContext(ctx, "Некорректный язык результатов",
+					key_err, err,
+					key_language, language)
+				return mcp.NewToolResultError(i18n.Translate(locale, "Неподдерживаемый язык; доступны: ru, en")), nil
+			}
+			requestLocale = parsed
+		}
+		return handler(i18n.WithLocale(ctx, requestLocale), request)
+	}
+}
+
+// geoFilterArgument преобразует аргумент-объект фильтра географии в карту строк.
+// Если фильтр не указан, используется wildcard для текущего уровня.
+func geoFilterArgument(value interface{}, geoLevel string) map[string]string {
+	geoFilterMap := make(map[string]string)
+	if geoFilter, ok := value.(map[string]interface{}); ok {
+		for k, v := range geoFilter {
+			if vs, ok := v.(string); ok {
+				geoFilterMap[k] = vs
+			}
+		}
+	} else {
+		geoFilterMap[geoLevel] = "*"
+	}
+	return geoFilterMap
+}
+
+// intArgument преобразует числовой или строковый аргумент инструмента в целое число
+func intArgument(value interface{}) int {
+	switch v := value.(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0
+		}
+		return n
+	default:
+		return 0
+	}
+}
+
+// stringList преобразует аргумент-массив инструмента в список строк
+func stringList(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
+
+// floatList преобразует аргумент-массив чисел инструмента в список float64
+func floatList(value interface{}) []float64 {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]float64, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			result = append(result, v)
+		case string:
+			if n, err := strconv.ParseFloat(v, 64); err == nil {
+				result = append(result, n)
+			}
+		}
+	}
+
+	return result
+}
+
+// RegisterCensusTools регистрирует инструменты Census MCP
+func RegisterCensusTools(mcpServer *server.MCPServer, handler CensusToolHandler, locale i18n.Locale) {
+	// Функция RegisterCensusTools не имеет контекста в параметрах,
+	// поэтому используем background контекст
+	ctx := context.Background()
+	slog.InfoContext(ctx, "Регистрация инструментов Census API")
+
+	// Инструмент для получения данных о населении штата
+	mcpServer.AddTool(mcp.NewTool("get_state_population",
+		mcp.WithDescription(i18n.Translate(locale, "Получает данные о населении штата США")),
+		mcp.WithString("stateID",
+			mcp.Description(i18n.Translate(locale, "ID штата (например, '06' для Калифорнии). Если не указан, возвращает данные для всех штатов")),
+		),
+		chartArgument(locale, census.ChartBar),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetStatePopulationTool))
+
+	// Инструмент для получения данных о населении округа
+	mcpServer.AddTool(mcp.NewTool("get_county_population",
+		mcp.WithDescription(i18n.Translate(locale, "Получает данные о населении округов в штате США")),
+		mcp.WithString("stateID",
+			mcp.Description(i18n.Translate(locale, "ID штата (например, '06' для Калифорнии). Если не указан, возвращает данные для всех округов")),
+		),
+		chartArgument(locale, census.ChartBar),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetCountyPopulationTool))
+
+	// Инструмент для поиска штата по названию
+	mcpServer.AddTool(mcp.NewTool("search_state_by_name",
+		mcp.WithDescription(i18n.Translate(locale, "Ищет штат по названию (полному или частичному)")),
+		mcp.WithString("name",
+			mcp.Description(i18n.Translate(locale, "Название штата для поиска")),
+			mcp.Required(),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleSearchStateByNameTool))
+
+	// Инструмент для получения доступных наборов данных
+	mcpServer.AddTool(mcp.NewTool("get_available_datasets",
+		mcp.WithDescription(i18n.Translate(locale, "Получает список доступных наборов данных Census API")),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetAvailableDatasetsTool))
+
+	// Инструмент для получения переменных набора данных
+	mcpServer.AddTool(mcp.NewTool("get_variables",
+		mcp.WithDescription(i18n.Translate(locale, "Получает список доступных переменных для указанного набора данных")),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (например, 'acs/acs1')")),
+			mcp.Required(),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (например, '2021')")),
+			mcp.Required(),
+		),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetVariablesTool))
+
+	// Инструмент для получения географических уровней
+	mcpServer.AddTool(mcp.NewTool("get_geography_levels",
+		mcp.WithDescription(i18n.Translate(locale, "Получает список доступных географических уровней для указанного набора данных")),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (например, 'acs/acs1')")),
+			mcp.Required(),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (например, '2021')")),
+			mcp.Required(),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetGeographyLevelsTool))
+
+	// Инструмент для получения пользовательских данных
+	mcpServer.AddTool(mcp.NewTool("get_custom_data",
+		mcp.WithDescription(i18n.Translate(locale, "Позволяет делать пользовательские запросы к Census API с указанием набора данных, года, переменных и географического уровня")),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (например, 'acs/acs1', 'acs/acs5/profile' для профилей DP02-DP05 или 'acs/acs5/subject' для тематических таблиц S). Сразу после каждой оценки профиля (DPxx_xxxxE) или тематической таблицы (например, S1701_C01_001E) добавляется ее процентный столбец (DPxx_xxxxPE, S1701_C03_001E), а после MOE - MOE процента")),
+			mcp.Required(),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (например, '2021')")),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state' или 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список переменных для запроса (например, ['NAME', 'B01001_001E'])")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\", \"county\": \"*\"}). Если не указан, будет использован wildcard для указанного географического уровня")),
+		),
+		mcp.WithArray("derived",
+			mcp.Description(i18n.Translate(locale, "Вычисляемые столбцы вида 'имя = выражение' с расчетом MOE по методике Census Bureau, например ['pct_poverty = B17001_002E / B17001_001E']. "+
+				"Доступны операторы + - * /, унарный минус, скобки и функции sum(a, ...), prop(a, b), pct(a, b), ratio(a, b), pct_change(from, to). "+
+				"Оператор / делит часть на итог ее таблицы (строку _001) по формуле доли; для других пар оценок укажите prop или ratio. Для каждого столбца добавляется столбец '<имя>_moe'")),
+		),
+		mcp.WithString("inflationYear",
+			mcp.Description(i18n.Translate(locale, "Год, в доллары которого пересчитываются долларовые переменные (например, '2022'). Долларовые переменные определяются по подписи переменной")),
+		),
+		mcp.WithString("inflationSeries",
+			mcp.Description(i18n.Translate(locale, "Индекс цен для пересчета: 'cpi-u-rs' (по умолчанию) или 'cpi-u'")),
+			mcp.Enum(census.CPISeriesURS, census.CPISeriesU),
+		),
+		chartArgument(locale, census.ChartKinds...),
+		chartColumnArgument(locale),
+		labelsArgument(locale),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetCustomDataTool))
+
+	// Инструмент для получения данных по ZCTA
+	mcpServer.AddTool(mcp.NewTool("get_zcta_data",
+		mcp.WithDescription(i18n.Translate(locale, "Получает данные по ZCTA (ZIP Code Tabulation Area) - статистическому приближению почтовых индексов USPS. ZCTA не равны почтовым индексам: границы различаются, а у части индексов нет ZCTA")),
+		mcp.WithArray("zctas",
+			mcp.Description(i18n.Translate(locale, "Список пятизначных кодов ZCTA (например, ['94110', '10001']) или ['*'] для всех")),
+			mcp.Required(),
+		),
+		mcp.WithString("state",
+			mcp.Description(i18n.Translate(locale, "ID штата для выпусков, где ZCTA вложены в штаты (ACS 5-year до 2018 года, перепись 2010 года)")),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список переменных для запроса (по умолчанию ['NAME', 'B01001_001E'])")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021')")),
+		),
+		chartArgument(locale, census.ChartKinds...),
+		chartColumnArgument(locale),
+		labelsArgument(locale),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetZCTADataTool))
+
+	// Инструмент для получения данных по округам Конгресса
+	mcpServer.AddTool(mcp.NewTool("get_congressional_district_data",
+		mcp.WithDescription(i18n.Translate(locale, "Получает данные по округам Палаты представителей Конгресса США. Округа задаются идентификаторами вида 'CA-12', 'VT-AL' (единственный округ), 'TX' (все округа штата) или '*' (все округа страны)")),
+		mcp.WithArray("districts",
+			mcp.Description(i18n.Translate(locale, "Список идентификаторов округов (например, ['CA-12', 'TX-07'])")),
+			mcp.Required(),
+		),
+		mcp.WithNumber("congress",
+			mcp.Description(i18n.Translate(locale, "Созыв Конгресса (например, 118). Если год не указан, выбирается последний выпуск ACS с округами этого созыва")),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список переменных для запроса (по умолчанию ['NAME', 'B01001_001E'])")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2022')")),
+		),
+		chartArgument(locale, census.ChartKinds...),
+		chartColumnArgument(locale),
+		labelsArgument(locale),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetCongressionalDistrictDataTool))
+
+	// Инструмент для получения данных по округам легислатур штатов
+	mcpServer.AddTool(mcp.NewTool("get_state_legislative_district_data",
+		mcp.WithDescription(i18n.Translate(locale, "Получает данные по округам верхней (сенат) или нижней (палата представителей, ассамблея) палаты легислатуры штата. Округа задаются идентификаторами вида 'CA-11', 'CA' (все округа штата) или '*'")),
+		mcp.WithString("chamber",
+			mcp.Description(i18n.Translate(locale, "Палата легислатуры: 'upper' (верхняя) или 'lower' (нижняя)")),
+			mcp.Enum(census.ChamberUpper, census.ChamberLower),
+			mcp.Required(),
+		),
+		mcp.WithArray("districts",
+			mcp.Description(i18n.Translate(locale, "Список идентификаторов округов (например, ['CA-11', 'CA-17'])")),
+			mcp.Required(),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список переменных для запроса (по умолчанию ['NAME', 'B01001_001E'])")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2022')")),
+		),
+		chartArgument(locale, census.ChartKinds...),
+		chartColumnArgument(locale),
+		labelsArgument(locale),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetStateLegislativeDistrictDataTool))
+
+	// Инструмент для поиска статистического ареала по названию
+	mcpServer.AddTool(mcp.NewTool("search_cbsa",
+		mcp.WithDescription(i18n.Translate(locale, "Ищет метрополитенский или микрополитенский статистический ареал (CBSA) по названию, например 'Seattle metro'. Возвращает код CBSA и входящие в ареал округа")),
+		mcp.WithString("name",
+			mcp.Description(i18n.Translate(locale, "Название ареала или его главного города")),
+			mcp.Required(),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleSearchCBSATool))
+
+	// Инструмент для получения данных по статистическим ареалам
+	mcpServer.AddTool(mcp.NewTool("get_cbsa_data",
+		mcp.WithDescription(i18n.Translate(locale, "Получает данные по метрополитенским и микрополитенским статистическим ареалам (CBSA). Ареалы не вложены в штаты и могут охватывать несколько штатов")),
+		mcp.WithArray("cbsas",
+			mcp.Description(i18n.Translate(locale, "Список кодов CBSA (например, ['42660']), названий (например, ['Seattle metro']) или ['*'] для всех ареалов")),
+			mcp.Required(),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список переменных для запроса (по умолчанию ['NAME', 'B01001_001E'])")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs1')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021')")),
+		),
+		chartArgument(locale, census.ChartKinds...),
+		chartColumnArgument(locale),
+		labelsArgument(locale),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetCBSADataTool))
+
+	// Инструмент для получения округов статистического ареала
+	mcpServer.AddTool(mcp.NewTool("get_cbsa_counties",
+		mcp.WithDescription(i18n.Translate(locale, "Получает список округов, входящих в статистический ареал (CBSA), по делимитации OMB")),
+		mcp.WithString("cbsa",
+			mcp.Description(i18n.Translate(locale, "Код CBSA (например, '42660') или название ареала (например, 'Seattle metro')")),
+			mcp.Required(),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetCBSACountiesTool))
+
+	// Инструмент для сравнения переменных между выпусками
+	mcpServer.AddTool(mcp.NewTool("compare_years",
+		mcp.WithDescription(i18n.Translate(locale, "Сравнивает значения переменных для набора географий за несколько лет: выравнивает строки по GEOID, вычисляет абсолютное и относительное изменение и предупреждает, если подпись или определение переменной менялись между выпусками")),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (например, 'acs/acs5')")),
+			mcp.Required(),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список сравниваемых переменных (например, ['B01001_001E', 'B19013_001E'])")),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state' или 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\", \"county\": \"*\"}). Если не указан, будет использован wildcard для указанного географического уровня")),
+		),
+		mcp.WithArray("years",
+			mcp.Description(i18n.Translate(locale, "Список лет для сравнения (например, ['2015', '2019', '2022'])")),
+		),
+		mcp.WithString("startYear",
+			mcp.Description(i18n.Translate(locale, "Начальный год диапазона (используется, если не указан 'years')")),
+		),
+		mcp.WithString("endYear",
+			mcp.Description(i18n.Translate(locale, "Конечный год диапазона (используется, если не указан 'years')")),
+		),
+		mcp.WithString("inflationYear",
+			mcp.Description(i18n.Translate(locale, "Год, в доллары которого пересчитываются долларовые переменные (например, '2022'). Долларовые переменные определяются по подписи переменной")),
+		),
+		mcp.WithString("inflationSeries",
+			mcp.Description(i18n.Translate(locale, "Индекс цен для пересчета: 'cpi-u-rs' (по умолчанию) или 'cpi-u'")),
+			mcp.Enum(census.CPISeriesURS, census.CPISeriesU),
+		),
+		chartArgument(locale, census.ChartLine),
+		chartColumnArgument(locale),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleCompareYearsTool))
+
+	// Инструмент для объединения географий в пользовательский регион
+	mcpServer.AddTool(mcp.NewTool("aggregate_geographies",
+		mcp.WithDescription(i18n.Translate(locale, "Объединяет список географий (по GEOID) в пользовательский регион: суммирует переменные-счетчики и вычисляет MOE суммы как корень из суммы квадратов. Медианы, проценты и коэффициенты суммировать нельзя - по умолчанию такой запрос отклоняется")),
+		mcp.WithArray("geoids",
+			mcp.Description(i18n.Translate(locale, "Список GEOID объединяемых географий (например, ['06037', '06059'] для округов)")),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень GEOID")),
+			mcp.Enum("state", "county", "tract", "block group", "block", "place", census.GeoLevelZCTA, census.GeoLevelCBSA),
+			mcp.Required(),
+		),
+		mcp.WithArray("variables",
+			mcp.Description(i18n.Translate(locale, "Список суммируемых переменных (например, ['B01001_001E', 'B25001_001E'])")),
+			mcp.Required(),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021')")),
+		),
+		mcp.WithBoolean("skipNonAdditive",
+			mcp.Description(i18n.Translate(locale, "Исключить медианы, проценты и коэффициенты с предупреждением вместо отказа (по умолчанию false)")),
+		),
+		mcp.WithNumber("designFactor",
+			mcp.Description(i18n.Translate(locale, "Коэффициент дизайна выборки ACS для расчета MOE переоцененных медиан (например, 1.5). Если не указан, медианы возвращаются без MOE")),
+		),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleAggregateGeographiesTool))
+
+	// Инструмент для оценки медианы и квантилей по интервальной таблице
+	mcpServer.AddTool(mcp.NewTool("estimate_quantiles",
+		mcp.WithDescription(i18n.Translate(locale, "Оценивает медиану и другие квантили по интервальной таблице ACS (например, B19001 - распределение доходов домохозяйств) линейной интерполяцией или интерполяцией Парето по методике Census Bureau")),
+		mcp.WithString("table",
+			mcp.Description(i18n.Translate(locale, "Интервальная таблица: B19001 (доход домохозяйств), B25075 (стоимость жилья), B25063 (валовая арендная плата)")),
+			mcp.Enum("B19001", "B25075", "B25063"),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state' или 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\", \"county\": \"*\"}). Если не указан, будет использован wildcard для указанного географического уровня")),
+		),
+		mcp.WithArray("quantiles",
+			mcp.Description(i18n.Translate(locale, "Список квантилей от 0 до 1 (по умолчанию [0.5] - медиана)")),
+		),
+		mcp.WithString("method",
+			mcp.Description(i18n.Translate(locale, "Метод интерполяции: 'linear' (по умолчанию), 'pareto' или 'auto' (Парето для интервалов шире $2,500)")),
+			mcp.Enum(census.MethodLinear, census.MethodPareto, census.MethodAuto),
+		),
+		mcp.WithNumber("designFactor",
+			mcp.Description(i18n.Translate(locale, "Коэффициент дизайна выборки ACS для расчета MOE квантилей (например, 1.5). Если не указан, MOE не вычисляется")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021')")),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleEstimateQuantilesTool))
+
+	// Инструмент для рейтинга географий
+	mcpServer.AddTool(mcp.NewTool("rank_geographies",
+		mcp.WithDescription(i18n.Translate(locale, "Строит рейтинг географий по значению переменной: запрашивает все географии уровня (при необходимости внутри родительской географии), сортирует их по числовому значению и возвращает первые или последние N с местом, значением и процентилем. Географии с подавленными значениями в рейтинг не входят")),
+		mcp.WithString("variable",
+			mcp.Description(i18n.Translate(locale, "Переменная для рейтинга (например, 'B19013_001E' - медианный доход домохозяйства)")),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень ранжируемых географий (например, 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр родительской географии (например, {\"state\": \"48\"} для округов Техаса). Если не указан, ранжируются все географии уровня")),
+		),
+		mcp.WithString("order",
+			mcp.Description(i18n.Translate(locale, "'top' - наибольшие значения (по умолчанию), 'bottom' - наименьшие")),
+			mcp.Enum("top", "bottom"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(i18n.Translate(locale, "Количество географий в результате (по умолчанию 10)")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021')")),
+		),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleRankGeographiesTool))
+
+	// Инструмент для проверки значимости различия двух оценок
+	mcpServer.AddTool(mcp.NewTool("compare_estimates",
+		mcp.WithDescription(i18n.Translate(locale, "Проверяет, различаются ли две оценки ACS статистически значимо: для двух географий или двух лет запрашивает оценки и MOE переменной и выполняет рекомендованный Census Bureau z-тест на уровне доверия 90%. Возвращает разность, ее MOE и вывод о значимости")),
+		mcp.WithString("variable",
+			mcp.Description(i18n.Translate(locale, "Переменная-оценка ACS (например, 'B19013_001E')")),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень первой оценки (например, 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр, выбирающий одну географию для первой оценки (например, {\"state\": \"06\", \"county\": \"037\"})")),
+			mcp.Required(),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год первой оценки (например, '2021')")),
+			mcp.Required(),
+		),
+		mcp.WithString("secondYear",
+			mcp.Description(i18n.Translate(locale, "Год второй оценки (по умолчанию совпадает с 'year')")),
+		),
+		mcp.WithString("secondGeoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень второй оценки (по умолчанию совпадает с 'geoLevel')")),
+		),
+		mcp.WithObject("secondGeoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр, выбирающий одну географию для второй оценки (по умолчанию совпадает с 'geoFilter')")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных (по умолчанию 'acs/acs5')")),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleCompareEstimatesTool))
+
+	// Инструмент для получения профиля ACS
+	mcpServer.AddTool(mcp.NewTool("get_profile",
+		mcp.WithDescription(i18n.Translate(locale, "Возвращает курируемую таблицу профиля ACS (DP02 - социальные, DP03 - экономические, DP04 - жилищные, DP05 - демографические характеристики) для географии: ключевые строки с оценками, процентами и их MOE")),
+		mcp.WithString("table",
+			mcp.Description(i18n.Translate(locale, "Таблица профиля")),
+			mcp.Enum("DP02", "DP03", "DP04", "DP05"),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state' или 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\"}). Если не указан, возвращаются все географии уровня")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных профиля (по умолчанию 'acs/acs5/profile')")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021'). Коды строк курируемых таблиц соответствуют выпускам 2019-2022")),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetProfileTool))
+
+	// Инструмент для получения показателей переписи
+	mcpServer.AddTool(mcp.NewTool("get_decennial_counts",
+		mcp.WithDescription(i18n.Translate(locale, "Возвращает показатели десятилетней переписи (P.L. 94-171, DHC, SF1): численность населения по расе и испаноязычному происхождению и занятость жилья с долями, вплоть до уровня кварталов (block). Данные переписи не имеют MOE")),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state', 'county', 'tract', 'block group' или 'block'). Для 'block' в фильтре необходимо указать state и county")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\", \"county\": \"037\"}). Если не указан, возвращаются все географии уровня")),
+		),
+		mcp.WithArray("groups",
+			mcp.Description(i18n.Translate(locale, "Группы показателей: 'race' (раса), 'ethnicity' (испаноязычное происхождение), 'housing' (занятость жилья). По умолчанию все")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных переписи (по умолчанию 'dec/pl')")),
+			mcp.Enum("dec/pl", "dec/dhc", "dec/sf1"),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год переписи: '2020' для dec/pl и dec/dhc, '2010' для dec/pl и dec/sf1 (по умолчанию '2020')")),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetDecennialCountsTool))
+
+	// Инструмент для сравнения показателей двух переписей
+	mcpServer.AddTool(mcp.NewTool("compare_decennial",
+		mcp.WithDescription(i18n.Translate(locale, "Сравнивает показатели расы, испаноязычного происхождения и занятости жилья двух переписей (по умолчанию 2010 и 2020) для одних и тех же GEOID, сопоставляя переменные выпусков с разной структурой таблиц. Возвращает значения, абсолютные и относительные изменения")),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state' или 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\"}). Если не указан, сравниваются все географии уровня")),
+		),
+		mcp.WithArray("groups",
+			mcp.Description(i18n.Translate(locale, "Группы показателей: 'race', 'ethnicity', 'housing'. По умолчанию все")),
+		),
+		mcp.WithString("baseYear",
+			mcp.Description(i18n.Translate(locale, "Год базовой переписи (по умолчанию '2010')")),
+		),
+		mcp.WithString("baseDataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных базовой переписи (по умолчанию 'dec/pl')")),
+			mcp.Enum("dec/pl", "dec/dhc", "dec/sf1"),
+		),
+		mcp.WithString("targetYear",
+			mcp.Description(i18n.Translate(locale, "Год сравниваемой переписи (по умолчанию '2020')")),
+		),
+		mcp.WithString("targetDataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных сравниваемой переписи (по умолчанию 'dec/pl')")),
+			mcp.Enum("dec/pl", "dec/dhc", "dec/sf1"),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleCompareDecennialTool))
+
+	// Инструмент для получения оценок численности населения
+	mcpServer.AddTool(mcp.NewTool("get_population_estimates",
+		mcp.WithDescription(i18n.Translate(locale, "Возвращает ежегодные оценки численности населения программы PEP (Population Estimates Program): годовые итоги на 1 июля, компоненты изменения (рождения, смерти, международная и внутренняя миграция) или численность по возрасту, полу, расе и испаноязычному происхождению. В отличие от ACS 1-year, оценки PEP доступны для всех округов")),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'us', 'state' или 'county')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\"}). Если не указан, возвращаются все географии уровня")),
+		),
+		mcp.WithString("kind",
+			mcp.Description(i18n.Translate(locale, "Вид данных: 'population' - годовые итоги (по умолчанию), 'components' - компоненты изменения, 'characteristics' - численность по характеристикам")),
+			mcp.Enum(census.PEPKindPopulation, census.PEPKindComponents, census.PEPKindCharacteristics),
+		),
+		mcp.WithString("vintage",
+			mcp.Description(i18n.Translate(locale, "Выпуск оценок (по умолчанию '2019'); 'timeseries' - межпереписные оценки 2000-2010. Выпуски с 2020 года содержат только годовые итоги")),
+		),
+		mcp.WithString("time",
+			mcp.Description(i18n.Translate(locale, "Год оценки для выпуска 'timeseries' (например, '2005'). Если не указан, возвращаются все годы")),
+		),
+		mcp.WithObject("characteristics",
+			mcp.Description(i18n.Translate(locale, "Фильтр характеристик для kind='characteristics': AGEGROUP (0 - все, 1-18 - пятилетние группы), SEX (0, 1, 2), RACE (0-6), HISP (0, 1, 2). Не указанные характеристики принимают значение 0 (итог), значение 'all' возвращает все коды (например, {\"SEX\": \"all\"})")),
+		),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetPopulationEstimatesTool))
+
+	// Инструмент для поиска отраслей NAICS
+	mcpServer.AddTool(mcp.NewTool("search_naics",
+		mcp.WithDescription(i18n.Translate(locale, "Ищет коды отраслей NAICS 2017 по названию на английском языке (например, 'restaurants', 'software') или по коду. Более общие отрасли (секторы) выводятся первыми")),
+		mcp.WithString("query",
+			mcp.Description(i18n.Translate(locale, "Текст запроса или код NAICS")),
+			mcp.Required(),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(i18n.Translate(locale, "Максимальное количество результатов (по умолчанию 20)")),
+		),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleSearchNAICSTool))
+
+	// Инструмент для получения статистики предприятий
+	mcpServer.AddTool(mcp.NewTool("get_business_patterns",
+		mcp.WithDescription(i18n.Translate(locale, "Возвращает число предприятий, занятость и годовой фонд оплаты труда по отраслям NAICS из County Business Patterns (cbp) или экономической переписи (ecnbasic, также выручка). Отрасль задается кодами NAICS или текстом, который сопоставляется с таблицей NAICS")),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень (например, 'state', 'county' или 'zip code')")),
+			mcp.Required(),
+		),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр географии (например, {\"state\": \"06\"}). Если не указан, возвращаются все географии уровня")),
+		),
+		mcp.WithArray("naics",
+			mcp.Description(i18n.Translate(locale, "Коды отраслей NAICS (например, ['72', '7225', '44-45']). Если не указаны вместе с 'industry', возвращается итог по всем секторам ('00')")),
+		),
+		mcp.WithString("industry",
+			mcp.Description(i18n.Translate(locale, "Название отрасли на английском языке (например, 'restaurants'); используется наиболее общая найденная отрасль")),
+		),
+		mcp.WithString("dataset",
+			mcp.Description(i18n.Translate(locale, "Набор данных: 'cbp' (по умолчанию) или 'ecnbasic'")),
+			mcp.Enum(census.DatasetCBP, census.DatasetEconomicCensus),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Год данных (по умолчанию '2021' для cbp и '2017' для ecnbasic)")),
+		),
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetBusinessPatternsTool))
+
+	// Инструмент для получения миграционных потоков
+	mcpServer.AddTool(mcp.NewTool("get_migration_flows",
+		mcp.WithDescription(i18n.Translate(locale, "Возвращает миграционные потоки ACS (acs/flows) для округа или статистического ареала: крупнейшие источники притока и направления оттока среди других географий с чистой миграцией и MOE, а также итоги по всем контрагентам")),
+		mcp.WithObject("geoFilter",
+			mcp.Description(i18n.Translate(locale, "Фильтр, выбирающий одну географию (например, {\"state\": \"06\", \"county\": \"037\"} или {\"metropolitan statistical area/micropolitan statistical area\": \"31080\"})")),
+			mcp.Required(),
+		),
+		mcp.WithString("geoLevel",
+			mcp.Description(i18n.Translate(locale, "Географический уровень: 'county' (по умолчанию) или уровень CBSA")),
+			mcp.Enum("county", census.GeoLevelCBSA),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(i18n.Translate(locale, "Количество контрагентов в списках притока и оттока (по умолчанию 10)")),
+		),
+		mcp.WithString("year",
+			mcp.Description(i18n.Translate(locale, "Последний год 5-летнего периода ACS (по умолчанию '2020' - период 2016-2020)")),
 		),
-	), handler.HandleGetCustomDataTool)
+		cursorArgument(locale),
+		maxTokensArgument(locale),
+		formatArgument(locale),
+		resourceArgument(locale),
+		precisionArgument(locale),
+		languageArgument(locale),
+	), localized(locale, handler.HandleGetMigrationFlowsTool))
 }
@@ -2,13 +2,25 @@ package mcp
 
 import (
 	"census_mcp/census"
+	"census_mcp/i18n"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // MockCensusAPIClient - мок для интерфейса CensusAPIClient
@@ -20,6 +32,9 @@ type MockCensusAPIClient struct {
 	GetVariablesFunc         func(dataset, year string) (map[string]census.VariableInfo, error)
 	GetGeographyLevelsFunc   func(dataset, year string) ([]census.GeographyLevel, error)
 	GetCustomDataFunc        func(request census.CustomDataRequest) ([]map[string]string, error)
+	GetZCTADataFunc          func(request census.ZCTARequest) ([]map[string]string, error)
+	GetDistrictDataFunc      func(request census.DistrictRequest) ([]map[string]string, error)
+	GetCBSADataFunc          func(request census.CBSARequest) ([]map[string]string, error)
 }
 
 // MockFormatter - мок для интерфейса Formatter
@@ -48,6 +63,11 @@ func (m *MockCensusAPIClient) GetAvailableDatasets() ([]census.DatasetInfo, erro
 }
 
 func (m *MockCensusAPIClient) GetVariables(dataset, year string) (map[string]census.VariableInfo, error) {
+	// Описания переменных также запрашиваются для оформления чисел; без мока числа
+	// оформляются по именам переменных
+	if m.GetVariablesFunc == nil {
+		return nil, errors.New("описания переменных недоступны")
+	}
 	return m.GetVariablesFunc(dataset, year)
 }
 
@@ -59,6 +79,18 @@ func (m *MockCensusAPIClient) GetCustomData(request census.CustomDataRequest) ([
 	return m.GetCustomDataFunc(request)
 }
 
+func (m *MockCensusAPIClient) GetZCTAData(request census.ZCTARequest) ([]map[string]string, error) {
+	return m.GetZCTADataFunc(request)
+}
+
+func (m *MockCensusAPIClient) GetDistrictData(request census.DistrictRequest) ([]map[string]string, error) {
+	return m.GetDistrictDataFunc(request)
+}
+
+func (m *MockCensusAPIClient) GetCBSAData(request census.CBSARequest) ([]map[string]string, error) {
+	return m.GetCBSADataFunc(request)
+}
+
 // CreateMockCallToolRequest создает моковый запрос для тестирования
 func CreateMockCallToolRequest(args map[string]interface{}) mcp.CallToolRequest {
 	mockRequest := mcp.CallToolRequest{}
@@ -380,88 +412,2106 @@ func TestCensusDefaultToolHandler_HandleSearchStateByNameTool(t *testing.T) {
 	}
 }
 
-func TestNewCensusToolHandler(t *testing.T) {
-	// Создаем мок-объекты
-	mockAPI := &MockCensusAPIClient{}
+func TestCensusDefaultToolHandler_HandleGetZCTADataTool(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            map[string]interface{}
+		expectedRequest census.ZCTARequest
+		mockData        []map[string]string
+		mockError       error
+		expectedText    []string
+	}{
+		{
+			name: "Успешное получение данных по ZCTA со значениями по умолчанию",
+			args: map[string]interface{}{
+				"zctas": []interface{}{"94110"},
+			},
+			expectedRequest: census.ZCTARequest{
+				ZCTAs:     []string{"94110"},
+				Variables: []string{"NAME", "B01001_001E"},
+				Dataset:   "acs/acs5",
+				Year:      "2021",
+			},
+			mockData: []map[string]string{
+				{"NAME": "ZCTA5 94110", "B01001_001E": "69333", census.GeoLevelZCTA: "94110"},
+			},
+			expectedText: []string{"Форматированные данные по ZCTA", "не совпадают"},
+		},
+		{
+			name: "Ошибка при получении данных",
+			args: map[string]interface{}{
+				"zctas":   []interface{}{"10001"},
+				"state":   "36",
+				"dataset": "acs/acs5",
+				"year":    "2022",
+			},
+			expectedRequest: census.ZCTARequest{
+				ZCTAs:     []string{"10001"},
+				State:     "36",
+				Variables: []string{"NAME", "B01001_001E"},
+				Dataset:   "acs/acs5",
+				Year:      "2022",
+			},
+			mockError:    errors.New("ZCTA не вложены в штаты"),
+			expectedText: []string{"Ошибка при получении данных по ZCTA", "ZCTA не вложены в штаты"},
+		},
+		{
+			name:         "Не указаны коды ZCTA",
+			args:         map[string]interface{}{},
+			expectedText: []string{"Необходимо указать параметр 'zctas'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := &MockCensusAPIClient{
+				GetZCTADataFunc: func(request census.ZCTARequest) ([]map[string]string, error) {
+					assert.Equal(t, tt.expectedRequest, request)
+					return tt.mockData, tt.mockError
+				},
+			}
+
+			mockFormatter := &MockFormatter{
+				FormatFunc: func(ctx context.Context, data interface{}) string {
+					assert.Equal(t, tt.mockData, data)
+					return "Форматированные данные по ZCTA"
+				},
+			}
+
+			handler := NewCensusToolHandler(mockAPI, mockFormatter)
+			result, err := handler.HandleGetZCTADataTool(context.Background(), CreateMockCallToolRequest(tt.args))
+
+			assert.NoError(t, err)
+			contentText := GetContentAsString(result.Content)
+			for _, expected := range tt.expectedText {
+				assert.Contains(t, contentText, expected)
+			}
+		})
+	}
+}
+
+func TestCensusDefaultToolHandler_HandleDistrictDataTools(t *testing.T) {
+	tests := []struct {
+		name            string
+		legislative     bool
+		args            map[string]interface{}
+		expectedRequest census.DistrictRequest
+		mockError       error
+		expectedText    string
+	}{
+		{
+			name: "Округа Конгресса по созыву",
+			args: map[string]interface{}{
+				"districts": []interface{}{"CA-12"},
+				"congress":  float64(118),
+			},
+			expectedRequest: census.DistrictRequest{
+				Chamber:   census.ChamberCongressional,
+				Districts: []string{"CA-12"},
+				Variables: []string{"NAME", "B01001_001E"},
+				Dataset:   "acs/acs5",
+				Congress:  118,
+			},
+			expectedText: "Форматированные данные по округам",
+		},
+		{
+			name:        "Округа нижней палаты легислатуры",
+			legislative: true,
+			args: map[string]interface{}{
+				"chamber":   "lower",
+				"districts": []interface{}{"CA-17"},
+				"year":      "2021",
+			},
+			expectedRequest: census.DistrictRequest{
+				Chamber:   census.ChamberLower,
+				Districts: []string{"CA-17"},
+				Variables: []string{"NAME", "B01001_001E"},
+				Dataset:   "acs/acs5",
+				Year:      "2021",
+			},
+			expectedText: "Форматированные данные по округам",
+		},
+		{
+			name:        "Некорректная палата",
+			legislative: true,
+			args: map[string]interface{}{
+				"chamber":   "senate",
+				"districts": []interface{}{"CA-11"},
+			},
+			expectedText: "Необходимо указать параметр 'chamber'",
+		},
+		{
+			name: "Ошибка API",
+			args: map[string]interface{}{
+				"districts": []interface{}{"ZZ-01"},
+			},
+			expectedRequest: census.DistrictRequest{
+				Chamber:   census.ChamberCongressional,
+				Districts: []string{"ZZ-01"},
+				Variables: []string{"NAME", "B01001_001E"},
+				Dataset:   "acs/acs5",
+				Year:      "2022",
+			},
+			mockError:    errors.New("не удалось определить штат"),
+			expectedText: "Ошибка при получении данных по избирательным округам: не удалось определить штат",
+		},
+		{
+			name:         "Не указаны округа",
+			args:         map[string]interface{}{},
+			expectedText: "Необходимо указать параметр 'districts'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAPI := &MockCensusAPIClient{
+				GetDistrictDataFunc: func(request census.DistrictRequest) ([]map[string]string, error) {
+					assert.Equal(t, tt.expectedRequest, request)
+					return []map[string]string{}, tt.mockError
+				},
+			}
+
+			mockFormatter := &MockFormatter{
+				FormatFunc: func(ctx context.Context, data interface{}) string {
+					return "Форматированные данные по округам"
+				},
+			}
+
+			handler := NewCensusToolHandler(mockAPI, mockFormatter)
+			request := CreateMockCallToolRequest(tt.args)
+
+			var result *mcp.CallToolResult
+			var err error
+			if tt.legislative {
+				result, err = handler.HandleGetStateLegislativeDistrictDataTool(context.Background(), request)
+			} else {
+				result, err = handler.HandleGetCongressionalDistrictDataTool(context.Background(), request)
+			}
+
+			assert.NoError(t, err)
+			assert.Contains(t, GetContentAsString(result.Content), tt.expectedText)
+		})
+	}
+}
+
+func TestCensusDefaultToolHandler_HandleGetCBSADataTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCBSADataFunc: func(request census.CBSARequest) ([]map[string]string, error) {
+			assert.Equal(t, census.CBSARequest{
+				CBSAs:     []string{"Seattle metro"},
+				Variables: []string{"NAME", "B19013_001E"},
+				Dataset:   "acs/acs1",
+				Year:      "2021",
+			}, request)
+			return []map[string]string{{"NAME": "Seattle-Tacoma-Bellevue, WA Metro Area"}}, nil
+		},
+	}
 	mockFormatter := &MockFormatter{
 		FormatFunc: func(ctx context.Context, data interface{}) string {
-			return "mock formatted data"
+			return "Форматированные данные по ареалам"
 		},
 	}
 
-	// Проверяем создание обработчика
 	handler := NewCensusToolHandler(mockAPI, mockFormatter)
 
-	// Проверяем, что обработчик не nil и имеет правильный тип
-	assert.NotNil(t, handler)
-	_, ok := handler.(*CensusDefaultToolHandler)
-	assert.True(t, ok)
+	result, err := handler.HandleGetCBSADataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"cbsas":     []interface{}{"Seattle metro"},
+		"variables": []interface{}{"NAME", "B19013_001E"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные данные по ареалам", GetContentAsString(result.Content))
+
+	result, err = handler.HandleGetCBSADataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Необходимо указать параметр 'cbsas'")
 }
 
-// Тест для RegisterCensusTools - проверка регистрации инструментов
-func TestRegisterCensusTools(t *testing.T) {
-	// Пропускаем тест для функции регистрации инструментов
-	// Тестирование RegisterCensusTools требует создания полного мока для server.MCPServer,
-	// что выходит за рамки данных тестов. В будущем можно реализовать этот тест при необходимости.
-	t.Skip("Требуется реализация моков для MCPServer")
+func TestCensusDefaultToolHandler_HandleCBSALookupTools(t *testing.T) {
+	var formatted []census.CBSAInfo
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			cbsas, ok := data.([]census.CBSAInfo)
+			assert.True(t, ok)
+			formatted = cbsas
+			return "Форматированные ареалы"
+		},
+	}
+
+	handler := NewCensusToolHandler(&MockCensusAPIClient{}, mockFormatter)
+
+	// Поиск по названию
+	result, err := handler.HandleSearchCBSATool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"name": "Seattle metro",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные ареалы", GetContentAsString(result.Content))
+	assert.Len(t, formatted, 1)
+	assert.Equal(t, "42660", formatted[0].Code)
+
+	// Ареал не найден
+	result, err = handler.HandleSearchCBSATool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"name": "Atlantis",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "не найдены")
+
+	// Состав ареала по коду
+	result, err = handler.HandleGetCBSACountiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"cbsa": "42660",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные ареалы", GetContentAsString(result.Content))
+	assert.Len(t, formatted[0].Counties, 3)
+
+	// Отсутствует обязательный параметр
+	result, err = handler.HandleGetCBSACountiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Необходимо указать параметр 'cbsa'")
 }
 
-// MockCensusToolHandler - мок для интерфейса CensusToolHandler
-type MockCensusToolHandler struct {
-	HandleGetStatePopulationToolFunc   func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
-	HandleGetCountyPopulationToolFunc  func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
-	HandleSearchStateByNameToolFunc    func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
-	HandleGetAvailableDatasetsToolFunc func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
-	HandleGetVariablesToolFunc         func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
-	HandleGetGeographyLevelsToolFunc   func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
-	HandleGetCustomDataToolFunc        func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+func TestCensusDefaultToolHandler_NotFoundFormat(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		SearchStateByNameFunc: func(name string) ([]census.PopulationData, error) {
+			return []census.PopulationData{}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+
+	tests := []struct {
+		name   string
+		handle func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error)
+		args   map[string]interface{}
+		note   string
+	}{
+		{"Штат", handler.HandleSearchStateByNameTool, map[string]interface{}{"name": "Atlantis"}, "Штаты не найдены по запросу: Atlantis"},
+		{"Статистический ареал", handler.HandleSearchCBSATool, map[string]interface{}{"name": "Atlantis"}, "Статистические ареалы не найдены по запросу: Atlantis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]interface{}{"format": census.FormatJSON}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+			result, err := tt.handle(context.Background(), CreateMockCallToolRequest(args))
+			assert.NoError(t, err)
+			assert.False(t, result.IsError)
+			require.Len(t, result.Content, 2)
+
+			// Пустой результат остается разбираемым документом, пояснение передается отдельно
+			text, ok := mcp.AsTextContent(result.Content[0])
+			require.True(t, ok)
+			var doc census.JSONDocument
+			require.NoError(t, json.Unmarshal([]byte(text.Text), &doc))
+			assert.Equal(t, 0, doc.RowCount)
+			assert.Equal(t, tt.note, GetContentAsString(result.Content[1:]))
+		})
+	}
 }
 
-func (m *MockCensusToolHandler) HandleGetStatePopulationTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleGetStatePopulationToolFunc != nil {
-		return m.HandleGetStatePopulationToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleCompareYearsTool(t *testing.T) {
+	values := map[string]string{"2019": "100", "2021": "110"}
+
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, []string{"NAME", "B01001_001E"}, request.Variables)
+			assert.Equal(t, map[string]string{"state": "06"}, request.GeoFilter)
+			value, ok := values[request.Year]
+			if !ok {
+				return nil, errors.New("выпуск недоступен")
+			}
+			return []map[string]string{{"NAME": "California", "B01001_001E": value, "state": "06"}}, nil
+		},
+		GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+			return map[string]census.VariableInfo{"B01001_001E": {Label: "Estimate!!Total"}}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	var comparison *census.YearComparison
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			comparison, ok = data.(*census.YearComparison)
+			assert.True(t, ok)
+			return "Форматированное сравнение"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleCompareYearsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"dataset":   "acs/acs5",
+		"variables": []interface{}{"B01001_001E"},
+		"geoLevel":  "state",
+		"geoFilter": map[string]interface{}{"state": "06"},
+		"startYear": "2019",
+		"endYear":   "2021",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированное сравнение", GetContentAsString(result.Content))
+	assert.Equal(t, []string{"2019", "2021"}, comparison.Years)
+	assert.Contains(t, comparison.MissingYears, "2020")
+	assert.Equal(t, float64(10), comparison.Rows[0].Changes["B01001_001E"].Absolute)
+
+	// Без лет сравнение невозможно
+	result, err = handler.HandleCompareYearsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"dataset":   "acs/acs5",
+		"variables": []interface{}{"B01001_001E"},
+		"geoLevel":  "state",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Необходимо указать параметр 'years'")
 }
 
-func (m *MockCensusToolHandler) HandleGetCountyPopulationTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleGetCountyPopulationToolFunc != nil {
-		return m.HandleGetCountyPopulationToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleGetCustomDataTool_Derived(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, []string{"NAME", "B17001_002E", "B17001_002M", "B17001_001E", "B17001_001M"}, request.Variables)
+			return []map[string]string{
+				{
+					"NAME":        "California",
+					"B17001_001E": "1000",
+					"B17001_001M": "60",
+					"B17001_002E": "200",
+					"B17001_002M": "40",
+					"state":       "06",
+				},
+			}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			rows, ok := data.([]map[string]string)
+			assert.True(t, ok)
+			assert.Equal(t, map[string]string{
+				"NAME":            "California",
+				"pct_poverty":     "20",
+				"pct_poverty_moe": "3.8158",
+				"state":           "06",
+			}, rows[0])
+			return "Форматированные данные с вычисляемыми столбцами"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"dataset":   "acs/acs5",
+		"year":      "2021",
+		"geoLevel":  "state",
+		"variables": []interface{}{"NAME"},
+		"derived":   []interface{}{"pct_poverty = pct(B17001_002E, B17001_001E)"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные данные с вычисляемыми столбцами", GetContentAsString(result.Content))
+
+	// Некорректное выражение
+	result, err = handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"dataset":   "acs/acs5",
+		"year":      "2021",
+		"geoLevel":  "state",
+		"variables": []interface{}{"NAME"},
+		"derived":   []interface{}{"pct_poverty = pct(B17001_002E"},
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Ошибка в вычисляемом столбце")
 }
 
-func (m *MockCensusToolHandler) HandleSearchStateByNameTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleSearchStateByNameToolFunc != nil {
-		return m.HandleSearchStateByNameToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleAggregateGeographiesTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+			return map[string]census.VariableInfo{
+				"B25001_001E": {Label: "Estimate!!Total", Concept: "HOUSING UNITS"},
+				"B25035_001E": {Label: "Estimate!!Median year structure built"},
+			}, nil
+		},
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, map[string]string{"state": "53", "county": "033,061"}, request.GeoFilter)
+			return []map[string]string{
+				{"NAME": "King County", "B25001_001E": "950000", "B25001_001M": "900", "state": "53", "county": "033"},
+				{"NAME": "Snohomish County", "B25001_001E": "330000", "B25001_001M": "1200", "state": "53", "county": "061"},
+			}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	var aggregation *census.AggregationResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			aggregation, ok = data.(*census.AggregationResult)
+			assert.True(t, ok)
+			return "Форматированные итоги"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleAggregateGeographiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoids":    []interface{}{"53033", "53061"},
+		"geoLevel":  "county",
+		"variables": []interface{}{"B25001_001E"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные итоги", GetContentAsString(result.Content))
+	assert.Equal(t, float64(1280000), aggregation.Totals[0].Value)
+	assert.InDelta(t, 1500, aggregation.Totals[0].MOE, 1e-9)
+
+	// Медиана без skipNonAdditive отклоняется
+	result, err = handler.HandleAggregateGeographiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoids":    []interface{}{"53033", "53061"},
+		"geoLevel":  "county",
+		"variables": []interface{}{"B25035_001E"},
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "нельзя суммировать")
+
+	// Без GEOID агрегация невозможна
+	result, err = handler.HandleAggregateGeographiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel":  "county",
+		"variables": []interface{}{"B25001_001E"},
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Необходимо указать параметр 'geoids'")
 }
 
-func (m *MockCensusToolHandler) HandleGetAvailableDatasetsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleGetAvailableDatasetsToolFunc != nil {
-		return m.HandleGetAvailableDatasetsToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleEstimateQuantilesTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, "acs/acs5", request.Dataset)
+			assert.Contains(t, request.Variables, "B25075_027E")
+			row := map[string]string{"NAME": "Alabama", "state": "01", "B25075_001E": "10"}
+			for _, variable := range census.BinnedTables["B25075"].Variables() {
+				if _, ok := row[variable]; !ok {
+					row[variable] = "0"
+				}
+			}
+			row["B25075_020E"] = "10" // 250 000 - 300 000
+			return []map[string]string{row}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	var estimates *census.QuantileResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			estimates, ok = data.(*census.QuantileResult)
+			assert.True(t, ok)
+			return "Форматированные квантили"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleEstimateQuantilesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"table":     "B25075",
+		"geoLevel":  "state",
+		"quantiles": []interface{}{0.5},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные квантили", GetContentAsString(result.Content))
+	assert.Equal(t, float64(275000), estimates.Rows[0].Quantiles[0].Value)
+
+	// Неподдерживаемая таблица
+	result, err = handler.HandleEstimateQuantilesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"table":    "B01001",
+		"geoLevel": "state",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Ошибка при оценке квантилей")
 }
 
-func (m *MockCensusToolHandler) HandleGetVariablesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleGetVariablesToolFunc != nil {
-		return m.HandleGetVariablesToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleRankGeographiesTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, map[string]string{"state": "48", "county": "*"}, request.GeoFilter)
+			return []map[string]string{
+				{"NAME": "Travis County", "B19013_001E": "85043", "state": "48", "county": "453"},
+				{"NAME": "Collin County", "B19013_001E": "104327", "state": "48", "county": "085"},
+				{"NAME": "Starr County", "B19013_001E": "35979", "state": "48", "county": "427"},
+			}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	var ranking *census.RankResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			ranking, ok = data.(*census.RankResult)
+			assert.True(t, ok)
+			return "Форматированный рейтинг"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleRankGeographiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"variable":  "B19013_001E",
+		"geoLevel":  "county",
+		"geoFilter": map[string]interface{}{"state": "48"},
+		"order":     "bottom",
+		"limit":     float64(2),
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированный рейтинг", GetContentAsString(result.Content))
+	assert.Len(t, ranking.Rows, 2)
+	assert.Equal(t, "Starr County", ranking.Rows[0].Name)
+
+	// Некорректный порядок
+	result, err = handler.HandleRankGeographiesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"variable": "B19013_001E",
+		"geoLevel": "county",
+		"order":    "middle",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "'order'")
 }
 
-func (m *MockCensusToolHandler) HandleGetGeographyLevelsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleGetGeographyLevelsToolFunc != nil {
-		return m.HandleGetGeographyLevelsToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleGetCustomDataTool_Inflation(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			return []map[string]string{
+				{"NAME": "California", "B19013_001E": "80000", "B01001_001E": "39000000", "state": "06"},
+			}, nil
+		},
+		GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+			return map[string]census.VariableInfo{
+				"B19013_001E": {Label: "Estimate!!Median household income in the past 12 months (in 2020 inflation-adjusted dollars)"},
+				"B01001_001E": {Label: "Estimate!!Total:"},
+			}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			rows, ok := data.([]map[string]string)
+			assert.True(t, ok)
+			assert.Equal(t, census.FormatNumber(80000*270.970/258.811), rows[0]["B19013_001E"])
+			assert.Equal(t, "39000000", rows[0]["B01001_001E"])
+			return "Форматированные данные"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"dataset":         "acs/acs5",
+		"year":            "2020",
+		"geoLevel":        "state",
+		"variables":       []interface{}{"NAME", "B19013_001E", "B01001_001E"},
+		"inflationYear":   "2021",
+		"inflationSeries": "cpi-u",
+	}))
+	assert.NoError(t, err)
+	content := GetContentAsString(result.Content)
+	assert.Contains(t, content, "Форматированные данные")
+	assert.Contains(t, content, "пересчитаны в доллары 2021 года по индексу CPI-U")
 }
 
-func (m *MockCensusToolHandler) HandleGetCustomDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	if m.HandleGetCustomDataToolFunc != nil {
-		return m.HandleGetCustomDataToolFunc(ctx, request)
+func TestCensusDefaultToolHandler_HandleCompareEstimatesTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, []string{"NAME", "B19013_001E", "B19013_001M"}, request.Variables)
+			if request.GeoFilter["county"] == "037" {
+				return []map[string]string{{"NAME": "Los Angeles County", "B19013_001E": "76367", "B19013_001M": "405", "state": "06", "county": "037"}}, nil
+			}
+			return []map[string]string{{"NAME": "Orange County", "B19013_001E": "100485", "B19013_001M": "755", "state": "06", "county": "059"}}, nil
+		},
 	}
-	return mcp.NewToolResultText("mock"), nil
+
+	var comparison *census.EstimateComparison
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			comparison, ok = data.(*census.EstimateComparison)
+			assert.True(t, ok)
+			return "Форматированное сравнение оценок"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleCompareEstimatesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"variable":        "B19013_001E",
+		"geoLevel":        "county",
+		"geoFilter":       map[string]interface{}{"state": "06", "county": "037"},
+		"secondGeoFilter": map[string]interface{}{"state": "06", "county": "059"},
+		"year":            "2021",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированное сравнение оценок", GetContentAsString(result.Content))
+	assert.Equal(t, "Orange County", comparison.Second.Name)
+	assert.True(t, comparison.Significant)
+
+	// Вторая оценка должна отличаться от первой
+	result, err = handler.HandleCompareEstimatesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"variable":  "B19013_001E",
+		"geoLevel":  "county",
+		"geoFilter": map[string]interface{}{"state": "06", "county": "037"},
+		"year":      "2021",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "'secondYear'")
+}
+
+func TestCensusDefaultToolHandler_HandleGetProfileTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, "acs/acs5/profile", request.Dataset)
+			assert.Equal(t, map[string]string{"state": "06"}, request.GeoFilter)
+			return []map[string]string{
+				{"NAME": "California", "state": "06", "DP05_0001E": "39346023", "DP05_0001M": "-555555555"},
+			}, nil
+		},
+	}
+
+	var profile *census.ProfileResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			profile, ok = data.(*census.ProfileResult)
+			assert.True(t, ok)
+			return "Форматированный профиль"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetProfileTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"table":     "DP05",
+		"geoLevel":  "state",
+		"geoFilter": map[string]interface{}{"state": "06"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированный профиль", GetContentAsString(result.Content))
+	assert.Equal(t, "2021", profile.Year)
+	assert.Equal(t, "39346023", profile.Geographies[0].Lines[0].Estimate)
+
+	// Отсутствует таблица
+	result, err = handler.HandleGetProfileTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel": "state",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "'table'")
+}
+
+func TestCensusDefaultToolHandler_HandleGetCustomDataTool_Profile(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, []string{"NAME", "DP05_0002E", "DP05_0002PE"}, request.Variables)
+			return []map[string]string{
+				{"NAME": "California", "state": "06", "DP05_0002E": "19595615", "DP05_0002PE": "49.8"},
+			}, nil
+		},
+	}
+
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			rows, ok := data.([]map[string]string)
+			assert.True(t, ok)
+			assert.Equal(t, "49.8", rows[0]["DP05_0002PE"])
+			return "Форматированные данные"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"dataset":   "acs/acs5/profile",
+		"year":      "2021",
+		"geoLevel":  "state",
+		"variables": []interface{}{"NAME", "DP05_0002E"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные данные", GetContentAsString(result.Content))
+}
+
+func TestCensusDefaultToolHandler_HandleGetDecennialCountsTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, "dec/pl", request.Dataset)
+			assert.Equal(t, "2020", request.Year)
+			assert.Equal(t, []string{"NAME", "H1_001N", "H1_002N", "H1_003N"}, request.Variables)
+			return []map[string]string{
+				{"NAME": "Alameda County", "state": "06", "county": "001", "H1_001N": "100", "H1_002N": "95", "H1_003N": "5"},
+			}, nil
+		},
+	}
+
+	var counts *census.DecennialResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			counts, ok = data.(*census.DecennialResult)
+			assert.True(t, ok)
+			return "Форматированные показатели переписи"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetDecennialCountsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel":  "county",
+		"geoFilter": map[string]interface{}{"state": "06"},
+		"groups":    []interface{}{"housing"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные показатели переписи", GetContentAsString(result.Content))
+	assert.Equal(t, "06001", counts.Rows[0].GeoID)
+
+	// Неподдерживаемый выпуск
+	result, err = handler.HandleGetDecennialCountsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel": "county",
+		"dataset":  "dec/dhc",
+		"year":     "2010",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "не поддерживается")
+}
+
+func TestCensusDefaultToolHandler_HandleCompareDecennialTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			if request.Year == "2010" {
+				return []map[string]string{{"NAME": "California", "state": "06", "P001001": "37253956"}}, nil
+			}
+			return []map[string]string{{"NAME": "California", "state": "06", "P1_001N": "39538223"}}, nil
+		},
+	}
+
+	var comparison *census.DecennialChangeResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			comparison, ok = data.(*census.DecennialChangeResult)
+			assert.True(t, ok)
+			return "Форматированное сравнение переписей"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleCompareDecennialTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel":  "state",
+		"geoFilter": map[string]interface{}{"state": "06"},
+		"groups":    []interface{}{"race"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированное сравнение переписей", GetContentAsString(result.Content))
+	assert.Equal(t, float64(2284267), comparison.Rows[0].Values[0].Change)
+
+	// Отсутствует географический уровень
+	result, err = handler.HandleCompareDecennialTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "'geoLevel'")
+}
+
+func TestCensusDefaultToolHandler_HandleGetPopulationEstimatesTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, "pep/charagegroups", request.Dataset)
+			assert.Equal(t, "1", request.Predicates["SEX"])
+			return []map[string]string{
+				{"NAME": "Texas", "state": "48", "POP": "14400000", "DATE_DESC": "7/1/2019 population estimate", "AGEGROUP": "0", "SEX": "1", "RACE": "0", "HISP": "0"},
+			}, nil
+		},
+	}
+
+	var estimates *census.PEPResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			estimates, ok = data.(*census.PEPResult)
+			assert.True(t, ok)
+			return "Форматированные оценки"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetPopulationEstimatesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel":        "state",
+		"geoFilter":       map[string]interface{}{"state": "48"},
+		"kind":            "characteristics",
+		"characteristics": map[string]interface{}{"sex": float64(1)},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные оценки", GetContentAsString(result.Content))
+	assert.Equal(t, "14400000", estimates.Rows[0].Values["POP"])
+
+	// Компоненты изменения недоступны в выпусках с 2020 года
+	result, err = handler.HandleGetPopulationEstimatesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel": "state",
+		"kind":     "components",
+		"vintage":  "2021",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "Ошибка при получении оценок")
+}
+
+func TestCensusDefaultToolHandler_HandleGetBusinessPatternsTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, "ecnbasic", request.Dataset)
+			assert.Equal(t, "2017", request.Year)
+			assert.Equal(t, map[string]string{"NAICS2017": "7225"}, request.Predicates)
+			return []map[string]string{
+				{"NAME": "California", "state": "06", "NAICS2017_LABEL": "Restaurants and other eating places", "ESTAB": "76000", "EMP": "1200000", "PAYANN": "30000000", "RCPTOT": "90000000"},
+			}, nil
+		},
+	}
+
+	var patterns *census.BusinessResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			patterns, ok = data.(*census.BusinessResult)
+			assert.True(t, ok)
+			return "Форматированная статистика предприятий"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetBusinessPatternsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel":  "state",
+		"geoFilter": map[string]interface{}{"state": "06"},
+		"industry":  "restaurants",
+		"dataset":   "ecnbasic",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированная статистика предприятий", GetContentAsString(result.Content))
+	assert.Equal(t, "Restaurants and other eating places", patterns.Rows[0].Industry)
+	assert.Equal(t, "90000000", patterns.Rows[0].Receipts)
+
+	// Неизвестная отрасль
+	result, err = handler.HandleGetBusinessPatternsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoLevel": "state",
+		"industry": "quantum teleportation",
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "search_naics")
+}
+
+func TestCensusDefaultToolHandler_HandleSearchNAICSTool(t *testing.T) {
+	var industries []census.NAICSIndustry
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			industries, ok = data.([]census.NAICSIndustry)
+			assert.True(t, ok)
+			return "Форматированный список отраслей"
+		},
+	}
+
+	handler := NewCensusToolHandler(&MockCensusAPIClient{}, mockFormatter)
+
+	result, err := handler.HandleSearchNAICSTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"query": "software",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированный список отраслей", GetContentAsString(result.Content))
+	assert.Equal(t, "5112", industries[0].Code)
+
+	// Отсутствует запрос
+	result, err = handler.HandleSearchNAICSTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "'query'")
+}
+
+func TestCensusDefaultToolHandler_HandleGetMigrationFlowsTool(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			assert.Equal(t, "acs/flows", request.Dataset)
+			assert.Equal(t, "2020", request.Year)
+			assert.Equal(t, map[string]string{"state": "06", "county": "037"}, request.GeoFilter)
+			return []map[string]string{
+				{"FULL1_NAME": "Los Angeles County, California", "GEOID1": "06037", "FULL2_NAME": "Orange County, California", "GEOID2": "06059",
+					"MOVEDIN": "30000", "MOVEDIN_M": "2000", "MOVEDOUT": "40000", "MOVEDOUT_M": "2500", "MOVEDNET": "-10000", "MOVEDNET_M": "3200"},
+			}, nil
+		},
+	}
+
+	var flows *census.MigrationFlowResult
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			var ok bool
+			flows, ok = data.(*census.MigrationFlowResult)
+			assert.True(t, ok)
+			return "Форматированные потоки"
+		},
+	}
+
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	result, err := handler.HandleGetMigrationFlowsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"geoFilter": map[string]interface{}{"state": "06", "county": "037"},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "Форматированные потоки", GetContentAsString(result.Content))
+	assert.Equal(t, "Orange County, California", flows.Outflows[0].Name)
+
+	// Отсутствует фильтр географии
+	result, err = handler.HandleGetMigrationFlowsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+	assert.NoError(t, err)
+	assert.Contains(t, GetContentAsString(result.Content), "'geoFilter'")
+}
+
+func TestCensusDefaultToolHandler_FormatArgument(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetZCTADataFunc: func(request census.ZCTARequest) ([]map[string]string, error) {
+			return []map[string]string{
+				{"NAME": "ZCTA5 94110", "B01001_001E": "69333", census.GeoLevelZCTA: "94110"},
+			}, nil
+		},
+	}
+
+	// Форматтер по умолчанию не должен вызываться, если формат указан явно
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			return "Форматтер по умолчанию"
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	t.Run("Формат по умолчанию", func(t *testing.T) {
+		result, err := handler.HandleGetZCTADataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"zctas": []interface{}{"94110"},
+		}))
+		assert.NoError(t, err)
+		require.Len(t, result.Content, 1)
+		contentText := GetContentAsString(result.Content)
+		assert.Contains(t, contentText, "Форматтер по умолчанию")
+		assert.Contains(t, contentText, "не совпадают")
+	})
+
+	t.Run("JSON с пояснением в отдельном блоке", func(t *testing.T) {
+		result, err := handler.HandleGetZCTADataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"zctas":  []interface{}{"94110"},
+			"format": "JSON",
+		}))
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		require.Len(t, result.Content, 2)
+
+		var doc census.JSONDocument
+		require.NoError(t, json.Unmarshal([]byte(GetContentAsString(result.Content)), &doc))
+		assert.Equal(t, census.JSONTypeCustomData, doc.Type)
+		assert.Equal(t, 1, doc.RowCount)
+		assert.Equal(t, census.ZCTANotice, result.Content[1].(mcp.TextContent).Text)
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		result, err := handler.HandleGetZCTADataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"zctas":  []interface{}{"94110"},
+			"format": census.FormatCSV,
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "NAME,B01001_001E,zip code tabulation area\nZCTA5 94110,69333,94110\n", GetContentAsString(result.Content))
+	})
+
+	t.Run("Неизвестный формат", func(t *testing.T) {
+		result, err := handler.HandleGetZCTADataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"zctas":  []interface{}{"94110"},
+			"format": "xml",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, GetContentAsString(result.Content), "Неизвестный формат \"xml\"")
+	})
+}
+
+func TestCensusDefaultToolHandler_PresentationArgumentsBeforeAPI(t *testing.T) {
+	calls := 0
+	mockAPI := &MockCensusAPIClient{
+		GetStatePopulationFunc: func(stateID string) ([]census.PopulationData, error) {
+			calls++
+			return []census.PopulationData{{Name: "California", Population: "39538223", State: "06"}}, nil
+		},
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			calls++
+			return []map[string]string{{"NAME": "California", "B01001_001E": "39538223", "state": "06"}}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+
+	// Некорректные аргументы оформления отклоняются без обращения к Census API
+	for _, extra := range []map[string]interface{}{
+		{"format": "xml"},
+		{"resource": "xml"},
+		{"precision": float64(census.MaxPrecision + 1)},
+		{"maxTokens": float64(0)},
+		{"cursor": "не курсор"},
+		{"cursor": encodeCursor(10, "другой")},
+	} {
+		args := map[string]interface{}{"stateID": "06"}
+		for k, v := range extra {
+			args[k] = v
+		}
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), CreateMockCallToolRequest(args))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError, "%v", extra)
+
+		args = map[string]interface{}{
+			"dataset":   "acs/acs5",
+			"year":      "2021",
+			"geoLevel":  "state",
+			"variables": []interface{}{"NAME", "B01001_001E"},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		result, err = handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(args))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError, "%v", extra)
+	}
+	assert.Zero(t, calls)
+
+	// Корректные аргументы не мешают запросу
+	result, err := handler.HandleGetStatePopulationTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+		"stateID":   "06",
+		"format":    census.FormatCSV,
+		"precision": float64(1),
+	}))
+	assert.NoError(t, err)
+	assert.False(t, result.IsError)
+	assert.Equal(t, 1, calls)
+}
+
+func TestNewCensusToolHandler(t *testing.T) {
+	// Создаем мок-объекты
+	mockAPI := &MockCensusAPIClient{}
+	mockFormatter := &MockFormatter{
+		FormatFunc: func(ctx context.Context, data interface{}) string {
+			return "mock formatted data"
+		},
+	}
+
+	// Проверяем создание обработчика
+	handler := NewCensusToolHandler(mockAPI, mockFormatter)
+
+	// Проверяем, что обработчик не nil и имеет правильный тип
+	assert.NotNil(t, handler)
+	_, ok := handler.(*CensusDefaultToolHandler)
+	assert.True(t, ok)
+}
+
+// Тест для RegisterCensusTools - проверка регистрации инструментов
+func TestRegisterCensusTools(t *testing.T) {
+	// Пропускаем тест для функции регистрации инструментов
+	// Тестирование RegisterCensusTools требует создания полного мока для server.MCPServer,
+	// что выходит за рамки данных тестов. В будущем можно реализовать этот тест при необходимости.
+	t.Skip("Требуется реализация моков для MCPServer")
+}
+
+// MockCensusToolHandler - мок для интерфейса CensusToolHandler
+type MockCensusToolHandler struct {
+	HandleGetStatePopulationToolFunc              func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetCountyPopulationToolFunc             func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleSearchStateByNameToolFunc               func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetAvailableDatasetsToolFunc            func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetVariablesToolFunc                    func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetGeographyLevelsToolFunc              func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetCustomDataToolFunc                   func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetZCTADataToolFunc                     func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetCongressionalDistrictDataToolFunc    func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetStateLegislativeDistrictDataToolFunc func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleSearchCBSAToolFunc                      func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetCBSADataToolFunc                     func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetCBSACountiesToolFunc                 func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleCompareYearsToolFunc                    func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleAggregateGeographiesToolFunc            func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleEstimateQuantilesToolFunc               func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleRankGeographiesToolFunc                 func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleCompareEstimatesToolFunc                func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetProfileToolFunc                      func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetDecennialCountsToolFunc              func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleCompareDecennialToolFunc                func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetPopulationEstimatesToolFunc          func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleSearchNAICSToolFunc                     func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetBusinessPatternsToolFunc             func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+	HandleGetMigrationFlowsToolFunc               func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error)
+}
+
+func (m *MockCensusToolHandler) HandleGetStatePopulationTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetStatePopulationToolFunc != nil {
+		return m.HandleGetStatePopulationToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetCountyPopulationTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetCountyPopulationToolFunc != nil {
+		return m.HandleGetCountyPopulationToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleSearchStateByNameTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleSearchStateByNameToolFunc != nil {
+		return m.HandleSearchStateByNameToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetAvailableDatasetsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetAvailableDatasetsToolFunc != nil {
+		return m.HandleGetAvailableDatasetsToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetVariablesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetVariablesToolFunc != nil {
+		return m.HandleGetVariablesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetGeographyLevelsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetGeographyLevelsToolFunc != nil {
+		return m.HandleGetGeographyLevelsToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetCustomDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetCustomDataToolFunc != nil {
+		return m.HandleGetCustomDataToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetZCTADataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetZCTADataToolFunc != nil {
+		return m.HandleGetZCTADataToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetCongressionalDistrictDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetCongressionalDistrictDataToolFunc != nil {
+		return m.HandleGetCongressionalDistrictDataToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetStateLegislativeDistrictDataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetStateLegislativeDistrictDataToolFunc != nil {
+		return m.HandleGetStateLegislativeDistrictDataToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleSearchCBSATool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleSearchCBSAToolFunc != nil {
+		return m.HandleSearchCBSAToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetCBSADataTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetCBSADataToolFunc != nil {
+		return m.HandleGetCBSADataToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetCBSACountiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetCBSACountiesToolFunc != nil {
+		return m.HandleGetCBSACountiesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleCompareYearsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleCompareYearsToolFunc != nil {
+		return m.HandleCompareYearsToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleAggregateGeographiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleAggregateGeographiesToolFunc != nil {
+		return m.HandleAggregateGeographiesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleEstimateQuantilesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleEstimateQuantilesToolFunc != nil {
+		return m.HandleEstimateQuantilesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleRankGeographiesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleRankGeographiesToolFunc != nil {
+		return m.HandleRankGeographiesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleCompareEstimatesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleCompareEstimatesToolFunc != nil {
+		return m.HandleCompareEstimatesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetProfileTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetProfileToolFunc != nil {
+		return m.HandleGetProfileToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetDecennialCountsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetDecennialCountsToolFunc != nil {
+		return m.HandleGetDecennialCountsToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleCompareDecennialTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleCompareDecennialToolFunc != nil {
+		return m.HandleCompareDecennialToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetPopulationEstimatesTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetPopulationEstimatesToolFunc != nil {
+		return m.HandleGetPopulationEstimatesToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleSearchNAICSTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleSearchNAICSToolFunc != nil {
+		return m.HandleSearchNAICSToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetBusinessPatternsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetBusinessPatternsToolFunc != nil {
+		return m.HandleGetBusinessPatternsToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func (m *MockCensusToolHandler) HandleGetMigrationFlowsTool(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if m.HandleGetMigrationFlowsToolFunc != nil {
+		return m.HandleGetMigrationFlowsToolFunc(ctx, request)
+	}
+	return mcp.NewToolResultText("mock"), nil
+}
+
+func TestLocalized_LanguageArgument(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetZCTADataFunc: func(request census.ZCTARequest) ([]map[string]string, error) {
+			return []map[string]string{
+				{"NAME": "ZCTA5 94110", "B01001_001E": "69333", census.GeoLevelZCTA: "94110"},
+			}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+
+	t.Run("Язык сервера", func(t *testing.T) {
+		tool := localized(i18n.English, handler.HandleGetZCTADataTool)
+		result, err := tool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "The 'zctas' parameter is required", GetContentAsString(result.Content))
+	})
+
+	t.Run("Язык запроса", func(t *testing.T) {
+		tool := localized(i18n.Russian, handler.HandleGetZCTADataTool)
+		result, err := tool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"zctas":    []interface{}{"94110"},
+			"language": "en",
+		}))
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		contentText := GetContentAsString(result.Content)
+		assert.Contains(t, contentText, "ZCTA5 94110")
+		assert.Contains(t, contentText, "**Note**")
+	})
+
+	t.Run("Неподдерживаемый язык", func(t *testing.T) {
+		tool := localized(i18n.Russian, handler.HandleGetZCTADataTool)
+		result, err := tool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"zctas":    []interface{}{"94110"},
+			"language": "de",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "Неподдерживаемый язык; доступны: ru, en", GetContentAsString(result.Content))
+	})
+
+	t.Run("Ошибка пакета census", func(t *testing.T) {
+		tool := localized(i18n.English, handler.HandleGetProfileTool)
+		result, err := tool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"table":    "DP05",
+			"dataset":  "acs/acs5",
+			"geoLevel": "state",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, `Error getting the profile: dataset "acs/acs5" is not an ACS profile (for example, "acs/acs5/profile")`,
+			GetContentAsString(result.Content))
+	})
+}
+
+func TestCensusDefaultToolHandler_NumberFormatting(t *testing.T) {
+	variablesCalls := 0
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			return []map[string]string{
+				{"NAME": "Texas", "B19013_001E": "66963.5", "B01001_001E": "29145505", "state": "48"},
+			}, nil
+		},
+		GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+			variablesCalls++
+			return map[string]census.VariableInfo{
+				"B19013_001E": {Label: "Estimate!!Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"},
+				"B01001_001E": {Label: "Estimate!!Total:"},
+			}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+	tool := localized(i18n.English, handler.HandleGetCustomDataTool)
+
+	arguments := func(extra map[string]interface{}) map[string]interface{} {
+		args := map[string]interface{}{
+			"dataset":   "acs/acs1",
+			"year":      "2021",
+			"geoLevel":  "state",
+			"variables": []interface{}{"NAME", "B19013_001E", "B01001_001E"},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+
+	t.Run("Виды значений по описаниям переменных", func(t *testing.T) {
+		result, err := tool(context.Background(), CreateMockCallToolRequest(arguments(nil)))
+		assert.NoError(t, err)
+		assert.Contains(t, GetContentAsString(result.Content), "| Texas | $66,963.50 | 29,145,505 | 48 |")
+	})
+
+	t.Run("Точность", func(t *testing.T) {
+		result, err := tool(context.Background(), CreateMockCallToolRequest(arguments(map[string]interface{}{
+			"precision": float64(0),
+		})))
+		assert.NoError(t, err)
+		assert.Contains(t, GetContentAsString(result.Content), "| Texas | $66,964 | 29,145,505 | 48 |")
+		// Описания переменных выпуска кэшируются
+		assert.Equal(t, 1, variablesCalls)
+	})
+
+	t.Run("Некорректная точность", func(t *testing.T) {
+		result, err := tool(context.Background(), CreateMockCallToolRequest(arguments(map[string]interface{}{
+			"precision": float64(12),
+		})))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Equal(t, "The 'precision' parameter must be between 0 and 6", GetContentAsString(result.Content))
+	})
+
+	t.Run("Машиночитаемые форматы без оформления", func(t *testing.T) {
+		result, err := tool(context.Background(), CreateMockCallToolRequest(arguments(map[string]interface{}{
+			"format": census.FormatCSV,
+		})))
+		assert.NoError(t, err)
+		assert.Equal(t, "NAME,B19013_001E,B01001_001E,state\nTexas,66963.5,29145505,48\n", GetContentAsString(result.Content))
+	})
+}
+
+func TestCensusDefaultToolHandler_HandleGetCustomDataTool_ColumnLayout(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			return []map[string]string{
+				{"NAME": "Travis County, Texas", "B19013_001E": "85043", "B01001_001E": "1290188", "state": "48", "county": "453"},
+			}, nil
+		},
+		GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+			return map[string]census.VariableInfo{
+				"B19013_001E": {Label: "Estimate!!Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"},
+				"B01001_001E": {Label: "Estimate!!Total:", Concept: "Sex by Age"},
+			}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+
+	arguments := func(labels bool) map[string]interface{} {
+		return map[string]interface{}{
+			"dataset":   "acs/acs5",
+			"year":      "2021",
+			"geoLevel":  "county",
+			"variables": []interface{}{"B19013_001E", "NAME", "B01001_001E"},
+			"geoFilter": map[string]interface{}{"state": "48"},
+			"labels":    labels,
+		}
+	}
+
+	t.Run("Порядок столбцов запроса", func(t *testing.T) {
+		result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(false)))
+		assert.NoError(t, err)
+		assert.Contains(t, GetContentAsString(result.Content), "| B19013_001E | NAME | B01001_001E | state | county | \n")
+	})
+
+	t.Run("Подписи переменных", func(t *testing.T) {
+		result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(true)))
+		assert.NoError(t, err)
+		assert.Contains(t, GetContentAsString(result.Content),
+			"| Median household income in the past 12 months (in 2021 inflation-adjusted dollars) | NAME | Sex by Age - Total | state | county | \n")
+	})
+
+	t.Run("Подписи в JSON", func(t *testing.T) {
+		args := arguments(true)
+		args["format"] = census.FormatJSON
+		result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(args))
+		assert.NoError(t, err)
+
+		var doc census.JSONDocument
+		assert.NoError(t, json.Unmarshal([]byte(GetContentAsString(result.Content)), &doc))
+		assert.Equal(t, []census.JSONColumn{
+			{Name: "B19013_001E", Type: census.JSONColumnNumber, Label: "Median household income in the past 12 months (in 2021 inflation-adjusted dollars)"},
+			{Name: "NAME", Type: census.JSONColumnString},
+			{Name: "B01001_001E", Type: census.JSONColumnNumber, Label: "Sex by Age - Total"},
+			{Name: "state", Type: census.JSONColumnGeography},
+			{Name: "county", Type: census.JSONColumnGeography},
+		}, doc.Columns)
+	})
+}
+
+func TestCensusDefaultToolHandler_ReleaseVariables(t *testing.T) {
+	t.Run("Загрузка выпуска не блокирует другие выпуски", func(t *testing.T) {
+		release := make(chan struct{})
+		var calls atomic.Int32
+		mockAPI := &MockCensusAPIClient{
+			GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+				calls.Add(1)
+				if year == "2021" {
+					<-release
+				}
+				return map[string]census.VariableInfo{"B19013_001E": {Label: "Estimate!!Median household income"}}, nil
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter()).(*CensusDefaultToolHandler)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NotNil(t, handler.releaseVariables(context.Background(), "acs/acs5", "2021"))
+			}()
+		}
+
+		done := make(chan struct{})
+		go func() {
+			assert.NotNil(t, handler.releaseVariables(context.Background(), "acs/acs5", "2022"))
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("запрос другого выпуска ждет загрузки")
+		}
+
+		close(release)
+		wg.Wait()
+		// Одновременные вызовы для одного выпуска выполняют один запрос
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Ошибка кэшируется до повторной попытки", func(t *testing.T) {
+		var calls int
+		mockAPI := &MockCensusAPIClient{
+			GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+				calls++
+				return nil, errors.New("сервис недоступен")
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter()).(*CensusDefaultToolHandler)
+
+		assert.Nil(t, handler.releaseVariables(context.Background(), "acs/acs5", "2021"))
+		assert.Nil(t, handler.releaseVariables(context.Background(), "acs/acs5", "2021"))
+		assert.Equal(t, 1, calls)
+
+		handler.releases["acs/acs5|2021"].fetched = time.Now().Add(-releaseRetryInterval)
+		assert.Nil(t, handler.releaseVariables(context.Background(), "acs/acs5", "2021"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Виды значений по именам переменных", func(t *testing.T) {
+		mockAPI := &MockCensusAPIClient{
+			GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+				return []map[string]string{{"NAME": "Texas", "B01001_001E": "29527941", "state": "48"}}, nil
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+
+		result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"dataset":   "acs/acs5",
+			"year":      "2021",
+			"geoLevel":  "state",
+			"variables": []interface{}{"NAME", "B01001_001E"},
+		}))
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+		assert.Contains(t, GetContentAsString(result.Content), "| Texas | 29\u00a0527\u00a0941 |")
+	})
+}
+
+func TestCensusDefaultToolHandler_Pagination(t *testing.T) {
+	rows := make([]map[string]string, 300)
+	for i := range rows {
+		rows[i] = map[string]string{
+			"NAME":        fmt.Sprintf("County %03d", i+1),
+			"B01001_001E": strconv.Itoa(1000 + i),
+			"state":       "48",
+			"county":      fmt.Sprintf("%03d", i+1),
+		}
+	}
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			return rows, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(), WithMaxOutputTokens(500))
+
+	arguments := func(extra map[string]interface{}) map[string]interface{} {
+		args := map[string]interface{}{
+			"dataset":   "acs/acs5",
+			"year":      "2021",
+			"geoLevel":  "county",
+			"variables": []interface{}{"NAME", "B01001_001E"},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		return args
+	}
+	cursorPattern := regexp.MustCompile(`cursor="([^"]+)"`)
+
+	t.Run("Обход всех страниц", func(t *testing.T) {
+		seen := make(map[string]bool)
+		cursor := ""
+		for page := 0; page < 100; page++ {
+			args := arguments(nil)
+			if cursor != "" {
+				args["cursor"] = cursor
+			}
+			result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(args))
+			assert.NoError(t, err)
+			assert.False(t, result.IsError)
+
+			content := GetContentAsString(result.Content)
+			assert.LessOrEqual(t, utf8.RuneCountInString(content), 500*charsPerToken+1000)
+			assert.Contains(t, content, "**Сводка по всем строкам (300)**:\n")
+			assert.Contains(t, content, "- B01001_001E: мин. 1\u00a0000, макс. 1\u00a0299, медиана 1\u00a0149,5 (значений: 300)\n")
+			for _, row := range rows {
+				if strings.Contains(content, "| "+row["NAME"]+" |") {
+					assert.False(t, seen[row["NAME"]], "строка %s повторяется", row["NAME"])
+					seen[row["NAME"]] = true
+				}
+			}
+
+			match := cursorPattern.FindStringSubmatch(content)
+			if match == nil {
+				assert.Contains(t, content, "из 300** (последняя страница)")
+				break
+			}
+			cursor = match[1]
+		}
+		assert.Len(t, seen, len(rows))
+	})
+
+	t.Run("Бюджет вызова", func(t *testing.T) {
+		result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(map[string]interface{}{
+			"maxTokens": float64(100000),
+		})))
+		assert.NoError(t, err)
+		content := GetContentAsString(result.Content)
+		assert.Contains(t, content, "| County 300 |")
+		assert.NotContains(t, content, "Показаны строки")
+	})
+
+	t.Run("Курсор другого запроса", func(t *testing.T) {
+		result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(nil)))
+		assert.NoError(t, err)
+		match := cursorPattern.FindStringSubmatch(GetContentAsString(result.Content))
+		assert.NotNil(t, match)
+
+		// Формат можно сменить, а изменение запроса делает курсор недействительным
+		result, err = handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(map[string]interface{}{
+			"cursor": match[1],
+			"format": census.FormatCSV,
+		})))
+		assert.NoError(t, err)
+		assert.False(t, result.IsError)
+
+		result, err = handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(map[string]interface{}{
+			"cursor": match[1],
+			"year":   "2022",
+		})))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Contains(t, GetContentAsString(result.Content), "относится к другому запросу")
+	})
+
+	t.Run("Некорректные параметры", func(t *testing.T) {
+		for _, extra := range []map[string]interface{}{
+			{"cursor": "не курсор"},
+			{"cursor": encodeCursor(500, requestFingerprint(arguments(nil)))},
+			{"maxTokens": float64(0)},
+		} {
+			result, err := handler.HandleGetCustomDataTool(context.Background(), CreateMockCallToolRequest(arguments(extra)))
+			assert.NoError(t, err)
+			assert.True(t, result.IsError, "%v", extra)
+		}
+	})
+}
+
+func TestCensusDefaultToolHandler_PaginationTypedRows(t *testing.T) {
+	t.Run("Округа всех штатов", func(t *testing.T) {
+		counties := make([]census.PopulationData, 3200)
+		for i := range counties {
+			counties[i] = census.PopulationData{
+				Name:       fmt.Sprintf("County %04d", i+1),
+				Population: strconv.Itoa(1000 + i),
+				State:      fmt.Sprintf("%02d", i/100+1),
+				County:     fmt.Sprintf("%03d", i%100+1),
+			}
+		}
+		mockAPI := &MockCensusAPIClient{
+			GetCountyPopulationFunc: func(stateID string) ([]census.PopulationData, error) {
+				assert.Empty(t, stateID)
+				return counties, nil
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(), WithMaxOutputTokens(500))
+		cursorPattern := regexp.MustCompile(`cursor="([^"]+)"`)
+
+		seen := 0
+		cursor := ""
+		for page := 0; page < 1000; page++ {
+			args := map[string]interface{}{}
+			if cursor != "" {
+				args["cursor"] = cursor
+			}
+			result, err := handler.HandleGetCountyPopulationTool(context.Background(), CreateMockCallToolRequest(args))
+			assert.NoError(t, err)
+			require.False(t, result.IsError)
+
+			content := GetContentAsString(result.Content)
+			assert.LessOrEqual(t, utf8.RuneCountInString(content), 500*charsPerToken+1000)
+			assert.Contains(t, content, "**Сводка по всем строкам (3200)**:\n")
+			assert.Contains(t, content, "- B01001_001E: мин. 1\u00a0000, макс. 4\u00a0199")
+			seen += strings.Count(content, "| County ")
+
+			match := cursorPattern.FindStringSubmatch(content)
+			if match == nil {
+				assert.Contains(t, content, "из 3200** (последняя страница)")
+				break
+			}
+			cursor = match[1]
+		}
+		assert.Equal(t, len(counties), seen)
+	})
+
+	t.Run("Оценки PEP по округам", func(t *testing.T) {
+		mockAPI := &MockCensusAPIClient{
+			GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+				rows := make([]map[string]string, 500)
+				for i := range rows {
+					rows[i] = map[string]string{
+						"NAME":      fmt.Sprintf("County %03d", i+1),
+						"POP":       strconv.Itoa(5000 + i),
+						"DATE_DESC": "7/1/2019 population estimate",
+						"state":     "48",
+						"county":    fmt.Sprintf("%03d", i+1),
+					}
+				}
+				return rows, nil
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(), WithMaxOutputTokens(500))
+
+		result, err := handler.HandleGetPopulationEstimatesTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"geoLevel": "county",
+		}))
+		assert.NoError(t, err)
+		require.False(t, result.IsError)
+
+		content := GetContentAsString(result.Content)
+		assert.LessOrEqual(t, utf8.RuneCountInString(content), 500*charsPerToken+1000)
+		assert.Contains(t, content, "**Показаны строки 1-")
+		assert.Contains(t, content, "- Население: мин. 5\u00a0000, макс. 5\u00a0499, медиана 5\u00a0249,5 (значений: 500)\n")
+		assert.NotContains(t, content, "| County 500 |")
+	})
+}
+
+func TestCensusDefaultToolHandler_PaginationAnalyticResults(t *testing.T) {
+	cursorPattern := regexp.MustCompile(`cursor="([^"]+)"`)
+
+	t.Run("Переменные выпуска", func(t *testing.T) {
+		variables := make(map[string]census.VariableInfo, 3000)
+		for i := 0; i < 3000; i++ {
+			variables[fmt.Sprintf("B%05d_001E", i)] = census.VariableInfo{Label: "Estimate!!Total:", Concept: "TEST"}
+		}
+		mockAPI := &MockCensusAPIClient{
+			GetVariablesFunc: func(dataset, year string) (map[string]census.VariableInfo, error) {
+				return variables, nil
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(), WithMaxOutputTokens(500))
+
+		seen := 0
+		cursor := ""
+		for page := 0; page < 1000; page++ {
+			args := map[string]interface{}{"dataset": "acs/acs5", "year": "2021"}
+			if cursor != "" {
+				args["cursor"] = cursor
+			}
+			result, err := handler.HandleGetVariablesTool(context.Background(), CreateMockCallToolRequest(args))
+			assert.NoError(t, err)
+			require.False(t, result.IsError)
+
+			content := GetContentAsString(result.Content)
+			assert.LessOrEqual(t, utf8.RuneCountInString(content), 500*charsPerToken+1000)
+			seen += strings.Count(content, "## B")
+
+			match := cursorPattern.FindStringSubmatch(content)
+			if match == nil {
+				assert.Contains(t, content, "из 3000** (последняя страница)")
+				break
+			}
+			cursor = match[1]
+		}
+		assert.Equal(t, len(variables), seen)
+	})
+
+	t.Run("Миграционные потоки", func(t *testing.T) {
+		flows := &census.MigrationFlowResult{
+			Inflows:  []census.MigrationCounterpart{{Name: "A"}, {Name: "B"}, {Name: "C"}},
+			Outflows: []census.MigrationCounterpart{{Name: "D"}, {Name: "E"}},
+		}
+		rows, ok := resultRows(flows)
+		require.True(t, ok)
+		assert.Equal(t, 5, rows.count)
+
+		// Страница на границе притока и оттока содержит строки обоих списков
+		page := rows.slice(2, 4).(*census.MigrationFlowResult)
+		assert.Equal(t, []census.MigrationCounterpart{{Name: "C"}}, page.Inflows)
+		assert.Equal(t, []census.MigrationCounterpart{{Name: "D"}}, page.Outflows)
+
+		page = rows.slice(3, 5).(*census.MigrationFlowResult)
+		assert.Empty(t, page.Inflows)
+		assert.Len(t, page.Outflows, 2)
+	})
+
+	t.Run("Слагаемые агрегации", func(t *testing.T) {
+		aggregation := &census.AggregationResult{
+			Totals:     []census.AggregatedVariable{{Variable: "B01001_001E"}},
+			Components: []map[string]string{{"NAME": "A"}, {"NAME": "B"}, {"NAME": "C"}},
+		}
+		rows, ok := resultRows(aggregation)
+		require.True(t, ok)
+		assert.Equal(t, 3, rows.count)
+
+		// Итоги повторяются на каждой странице
+		page := rows.slice(1, 2).(*census.AggregationResult)
+		assert.Equal(t, aggregation.Totals, page.Totals)
+		assert.Equal(t, []map[string]string{{"NAME": "B"}}, page.Components)
+	})
+
+	t.Run("Усечение прочих результатов", func(t *testing.T) {
+		datasets := make([]census.DatasetInfo, 500)
+		for i := range datasets {
+			datasets[i] = census.DatasetInfo{
+				Title:          fmt.Sprintf("Dataset %03d", i+1),
+				Dataset:        fmt.Sprintf("test/%03d", i+1),
+				YearsAvailable: []string{"2021"},
+			}
+		}
+		mockAPI := &MockCensusAPIClient{
+			GetAvailableDatasetsFunc: func() ([]census.DatasetInfo, error) {
+				return datasets, nil
+			},
+		}
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(),
+			WithMaxOutputTokens(300), WithEmbeddedResource(census.FormatJSON))
+
+		result, err := handler.HandleGetAvailableDatasetsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{}))
+		assert.NoError(t, err)
+		require.False(t, result.IsError)
+
+		text := GetContentAsString(result.Content[:1])
+		assert.LessOrEqual(t, utf8.RuneCountInString(text), 300*charsPerToken+1000)
+		assert.Contains(t, text, "**Результат усечен**")
+		assert.NotContains(t, text, "Dataset 500")
+
+		// Встроенный ресурс ограничен тем же бюджетом
+		embedded, ok := mcp.AsEmbeddedResource(result.Content[len(result.Content)-1])
+		require.True(t, ok)
+		contents, ok := mcp.AsTextResourceContents(embedded.Resource)
+		require.True(t, ok)
+		assert.LessOrEqual(t, utf8.RuneCountInString(contents.Text), 300*charsPerToken)
+
+		// Бюджет вызова снимает усечение
+		result, err = handler.HandleGetAvailableDatasetsTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"maxTokens": float64(1000000),
+		}))
+		assert.NoError(t, err)
+		assert.Contains(t, GetContentAsString(result.Content[:1]), "Dataset 500")
+	})
+}
+
+func TestCensusDefaultToolHandler_EmbeddedResource(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetStatePopulationFunc: func(stateID string) ([]census.PopulationData, error) {
+			return []census.PopulationData{{Name: "California", Population: "39538223", State: "06"}}, nil
+		},
+	}
+	request := func(args map[string]interface{}) mcp.CallToolRequest {
+		request := CreateMockCallToolRequest(args)
+		request.Params.Name = "get_state_population"
+		return request
+	}
+
+	t.Run("Ресурс JSON", func(t *testing.T) {
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), request(map[string]interface{}{
+			"stateID":  "06",
+			"resource": census.FormatJSON,
+		}))
+		assert.NoError(t, err)
+		require.Len(t, result.Content, 2)
+		assert.Contains(t, GetContentAsString(result.Content), "| California (штат 06) | 39\u00a0538\u00a0223 |")
+
+		embedded, ok := mcp.AsEmbeddedResource(result.Content[1])
+		require.True(t, ok)
+		contents, ok := mcp.AsTextResourceContents(embedded.Resource)
+		require.True(t, ok)
+		assert.Equal(t, "application/json", contents.MIMEType)
+		assert.Regexp(t, `^census://get_state_population/[0-9a-f]{12}\.json$`, contents.URI)
+
+		var doc census.JSONDocument
+		require.NoError(t, json.Unmarshal([]byte(contents.Text), &doc))
+		assert.Equal(t, census.JSONTypePopulation, doc.Type)
+		assert.Equal(t, 1, doc.RowCount)
+	})
+
+	t.Run("Без ресурса по умолчанию", func(t *testing.T) {
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), request(map[string]interface{}{"stateID": "06"}))
+		assert.NoError(t, err)
+		assert.Len(t, result.Content, 1)
+	})
+
+	t.Run("Ресурс сервера и отключение в вызове", func(t *testing.T) {
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(), WithEmbeddedResource(census.FormatCSV))
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), request(map[string]interface{}{"stateID": "06"}))
+		assert.NoError(t, err)
+		require.Len(t, result.Content, 2)
+		embedded, ok := mcp.AsEmbeddedResource(result.Content[1])
+		require.True(t, ok)
+		contents, ok := mcp.AsTextResourceContents(embedded.Resource)
+		require.True(t, ok)
+		assert.Equal(t, "text/csv", contents.MIMEType)
+		assert.Contains(t, contents.Text, "California,06,06,,39538223")
+
+		result, err = handler.HandleGetStatePopulationTool(context.Background(), request(map[string]interface{}{
+			"stateID":  "06",
+			"resource": ResourceNone,
+		}))
+		assert.NoError(t, err)
+		assert.Len(t, result.Content, 1)
+	})
+
+	t.Run("Неизвестный формат ресурса", func(t *testing.T) {
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), request(map[string]interface{}{
+			"stateID":  "06",
+			"resource": "xml",
+		}))
+		assert.NoError(t, err)
+		assert.True(t, result.IsError)
+		assert.Error(t, ValidateResourceFormat("xml"))
+
+		// Сообщение об ошибке переводится на язык запроса
+		tool := localized(i18n.English, handler.HandleGetStatePopulationTool)
+		result, err = tool(context.Background(), request(map[string]interface{}{
+			"stateID":  "06",
+			"resource": "xml",
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, `Unknown resource format "xml"; available: none, json, csv, tsv, geojson`, GetContentAsString(result.Content))
+	})
+
+	t.Run("Ресурс содержит строки страницы усеченного результата", func(t *testing.T) {
+		rows := make([]map[string]string, 200)
+		for i := range rows {
+			rows[i] = map[string]string{"NAME": fmt.Sprintf("County %03d", i+1), "B01001_001E": strconv.Itoa(1000 + i), "state": "48"}
+		}
+		api := &MockCensusAPIClient{
+			GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+				return rows, nil
+			},
+		}
+		handler := NewCensusToolHandler(api, census.NewTextFormatter(), WithMaxOutputTokens(300))
+		result, err := handler.HandleGetCustomDataTool(context.Background(), request(map[string]interface{}{
+			"dataset":   "acs/acs5",
+			"year":      "2021",
+			"geoLevel":  "county",
+			"variables": []interface{}{"NAME", "B01001_001E"},
+			"resource":  census.FormatCSV,
+		}))
+		assert.NoError(t, err)
+		text := GetContentAsString(result.Content[:1])
+		assert.NotContains(t, text, "| County 200 |")
+
+		// Ресурс ограничен той же страницей, что и текст результата
+		embedded, ok := mcp.AsEmbeddedResource(result.Content[len(result.Content)-1])
+		require.True(t, ok)
+		contents, ok := mcp.AsTextResourceContents(embedded.Resource)
+		require.True(t, ok)
+		assert.Equal(t, strings.Count(text, "| County ")+1, strings.Count(contents.Text, "\n"))
+		assert.NotContains(t, contents.Text, "County 200")
+	})
+}
+
+func TestCensusDefaultToolHandler_Chart(t *testing.T) {
+	mockAPI := &MockCensusAPIClient{
+		GetCustomDataFunc: func(request census.CustomDataRequest) ([]map[string]string, error) {
+			return []map[string]string{
+				{"NAME": "Texas", "B01001_001E": "29145505", "B19013_001E": "66963", "state": "48"},
+				{"NAME": "Utah", "B01001_001E": "3271616", "B19013_001E": "79133", "state": "49"},
+			}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+
+	arguments := func(extra map[string]interface{}) mcp.CallToolRequest {
+		args := map[string]interface{}{
+			"dataset":   "acs/acs1",
+			"year":      "2021",
+			"geoLevel":  "state",
+			"variables": []interface{}{"NAME", "B01001_001E", "B19013_001E"},
+		}
+		for k, v := range extra {
+			args[k] = v
+		}
+		request := CreateMockCallToolRequest(args)
+		request.Params.Name = "get_custom_data"
+		return request
+	}
+
+	t.Run("Столбчатая диаграмма", func(t *testing.T) {
+		result, err := handler.HandleGetCustomDataTool(context.Background(), arguments(map[string]interface{}{
+			"chart":       "bar",
+			"chartColumn": "B19013_001E",
+		}))
+		assert.NoError(t, err)
+		require.False(t, result.IsError)
+		require.Len(t, result.Content, 2)
+		assert.Contains(t, GetContentAsString(result.Content), "| Texas |")
+
+		embedded, ok := mcp.AsEmbeddedResource(result.Content[1])
+		require.True(t, ok)
+		contents, ok := mcp.AsTextResourceContents(embedded.Resource)
+		require.True(t, ok)
+		assert.Equal(t, "image/svg+xml", contents.MIMEType)
+		assert.Regexp(t, `^census://get_custom_data/[0-9a-f]{12}\.bar\.svg$`, contents.URI)
+		assert.Contains(t, contents.Text, ">B19013_001E</text>")
+		assert.Contains(t, contents.Text, ">Utah</text>")
+	})
+
+	t.Run("Диаграмма вместе с ресурсом данных", func(t *testing.T) {
+		result, err := handler.HandleGetCustomDataTool(context.Background(), arguments(map[string]interface{}{
+			"chart":    "bar",
+			"resource": census.FormatJSON,
+		}))
+		assert.NoError(t, err)
+		require.Len(t, result.Content, 3)
+	})
+
+	t.Run("Ошибки построения", func(t *testing.T) {
+		for _, extra := range []map[string]interface{}{
+			{"chart": "pie"},
+			{"chart": "line"},
+			{"chart": "pyramid"},
+			{"chart": "bar", "chartColumn": "NAME"},
+		} {
+			result, err := handler.HandleGetCustomDataTool(context.Background(), arguments(extra))
+			assert.NoError(t, err)
+			assert.True(t, result.IsError, "%v", extra)
+			assert.Contains(t, GetContentAsString(result.Content), "Ошибка при построении диаграммы: ")
+		}
+	})
+}
+
+func TestCensusDefaultToolHandler_GeoJSON(t *testing.T) {
+	dir := t.TempDir()
+	collection := `{"type": "FeatureCollection", "features": [{"type": "Feature", "properties": {"GEOID": "06"},
+		"geometry": {"type": "Polygon", "coordinates": [[[-124.4, 32.5], [-114.1, 32.5], [-114.1, 42], [-124.4, 42], [-124.4, 32.5]]]}}]}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cb_2021_us_state_20m.geojson"), []byte(collection), 0o644))
+	boundaries, err := census.NewBoundaryStore(dir)
+	require.NoError(t, err)
+
+	mockAPI := &MockCensusAPIClient{
+		GetStatePopulationFunc: func(stateID string) ([]census.PopulationData, error) {
+			return []census.PopulationData{{Name: "California", Population: "39538223", State: "06"}}, nil
+		},
+	}
+	handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter(), WithBoundaries(boundaries))
+
+	t.Run("Формат geojson", func(t *testing.T) {
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"stateID": "06",
+			"format":  census.FormatGeoJSON,
+		}))
+		assert.NoError(t, err)
+		require.Len(t, result.Content, 1)
+
+		var features census.GeoJSONFeatureCollection
+		require.NoError(t, json.Unmarshal([]byte(GetContentAsString(result.Content)), &features))
+		require.Len(t, features.Features, 1)
+		assert.Equal(t, "06", features.Features[0].ID)
+		assert.Contains(t, string(features.Features[0].Geometry), `"Polygon"`)
+	})
+
+	t.Run("Ресурс geojson", func(t *testing.T) {
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"stateID":  "06",
+			"resource": census.FormatGeoJSON,
+		}))
+		assert.NoError(t, err)
+		require.Len(t, result.Content, 2)
+
+		embedded, ok := mcp.AsEmbeddedResource(result.Content[1])
+		require.True(t, ok)
+		contents, ok := mcp.AsTextResourceContents(embedded.Resource)
+		require.True(t, ok)
+		assert.Equal(t, census.GeoJSONMIMEType, contents.MIMEType)
+		assert.Regexp(t, `\.geojson$`, contents.URI)
+		assert.Contains(t, contents.Text, `"FeatureCollection"`)
+		assert.Contains(t, contents.Text, `[-124.4,32.5]`)
+	})
+
+	t.Run("Без каталога границ", func(t *testing.T) {
+		handler := NewCensusToolHandler(mockAPI, census.NewTextFormatter())
+		result, err := handler.HandleGetStatePopulationTool(context.Background(), CreateMockCallToolRequest(map[string]interface{}{
+			"stateID": "06",
+			"format":  census.FormatGeoJSON,
+		}))
+		assert.NoError(t, err)
+		assert.Contains(t, GetContentAsString(result.Content), `"geometry":null`)
+	})
 }